@@ -0,0 +1,197 @@
+// Package client typed go client of ProtoDbSrv, messages are marshalled and
+// query batches are reassembled by the client
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"connectrpc.com/connect"
+	"github.com/ygrpc/protodb"
+	"google.golang.org/protobuf/proto"
+)
+
+// Client wraps a ProtoDbSrvClient
+type Client struct {
+	Srv protodb.ProtoDbSrvClient
+	// SchemeName db schema of requests
+	SchemeName string
+	// MsgFormat 0:protobuf 1:protobuf json
+	MsgFormat int32
+	// ErrHeader ask server to put error text in Ygrpc-Err header, ErrMax > 0 limits its length
+	ErrHeader bool
+	ErrMax    int
+}
+
+// Option client option of New
+type Option func(c *Client)
+
+// WithSchema db schema of requests
+func WithSchema(schemeName string) Option {
+	return func(c *Client) {
+		c.SchemeName = schemeName
+	}
+}
+
+// WithJSON messages are sent as protobuf json
+func WithJSON() Option {
+	return func(c *Client) {
+		c.MsgFormat = 1
+	}
+}
+
+// WithErrHeader ask server to put error text in Ygrpc-Err header, max > 0 limits its length
+func WithErrHeader(max int) Option {
+	return func(c *Client) {
+		c.ErrHeader = true
+		c.ErrMax = max
+	}
+}
+
+// New create client of srv, like client.New(protodb.NewProtoDbSrvClient(http.DefaultClient, url))
+func New(srv protodb.ProtoDbSrvClient, opts ...Option) *Client {
+	c := &Client{Srv: srv}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Error error reported by server in ErrInfo or Ygrpc-Err header
+type Error struct {
+	// Info error text from server
+	Info string
+	// Err the connect error, nil for ErrInfo of response
+	Err error
+}
+
+func (e *Error) Error() string {
+	return e.Info
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Insert insert msg and return the inserted row
+func Insert[T proto.Message](ctx context.Context, c *Client, msg T) (T, error) {
+	newMsg, _, err := crudMsg(ctx, c, msg, &protodb.CrudReq{Code: protodb.CrudReqCode_INSERT, ResultType: protodb.CrudResultType_NewMsg})
+	return newMsg, err
+}
+
+// Update update msg by primary key and return the updated row
+func Update[T proto.Message](ctx context.Context, c *Client, msg T) (T, error) {
+	newMsg, _, err := crudMsg(ctx, c, msg, &protodb.CrudReq{Code: protodb.CrudReqCode_UPDATE, ResultType: protodb.CrudResultType_NewMsg})
+	return newMsg, err
+}
+
+// UpdateOldNew update msg by primary key and return the rows before and after update
+func UpdateOldNew[T proto.Message](ctx context.Context, c *Client, msg T) (oldMsg T, newMsg T, err error) {
+	newMsg, oldMsg, err = crudMsg(ctx, c, msg, &protodb.CrudReq{Code: protodb.CrudReqCode_UPDATE, ResultType: protodb.CrudResultType_OldMsgAndNewMsg})
+	return oldMsg, newMsg, err
+}
+
+// PartialUpdate update fields of msg by primary key and return the updated row
+func PartialUpdate[T proto.Message](ctx context.Context, c *Client, msg T, fields ...string) (T, error) {
+	newMsg, _, err := crudMsg(ctx, c, msg, &protodb.CrudReq{
+		Code:                protodb.CrudReqCode_PARTIALUPDATE,
+		ResultType:          protodb.CrudResultType_NewMsg,
+		PartialUpdateFields: fields,
+	})
+	return newMsg, err
+}
+
+// Delete delete msg by primary key and return the deleted row
+func Delete[T proto.Message](ctx context.Context, c *Client, msg T) (T, error) {
+	newMsg, _, err := crudMsg(ctx, c, msg, &protodb.CrudReq{Code: protodb.CrudReqCode_DELETE, ResultType: protodb.CrudResultType_NewMsg})
+	return newMsg, err
+}
+
+// SelectOne select one row by keyFields of msg(primary key if empty)
+func SelectOne[T proto.Message](ctx context.Context, c *Client, msg T, keyFields ...string) (T, error) {
+	newMsg, _, err := crudMsg(ctx, c, msg, &protodb.CrudReq{Code: protodb.CrudReqCode_SELECTONE, SelectOneKeyFields: keyFields})
+	return newMsg, err
+}
+
+// DescribeSchema tables and queries visible to the caller, tableNames limit the described tables
+func (c *Client) DescribeSchema(ctx context.Context, tableNames ...string) (*protodb.DescribeSchemaResp, error) {
+	connectReq := connect.NewRequest(&protodb.DescribeSchemaReq{SchemeName: c.SchemeName, TableNames: tableNames})
+	c.setErrHeader(connectReq.Header())
+	resp, err := c.Srv.DescribeSchema(ctx, connectReq)
+	if err != nil {
+		return nil, fmt.Errorf("describe schema err: %w", serverError(err))
+	}
+	return resp.Msg, nil
+}
+
+// crudMsg send crud req of msg and decode new and old msg of response
+func crudMsg[T proto.Message](ctx context.Context, c *Client, msg T, req *protodb.CrudReq) (newMsg T, oldMsg T, err error) {
+	tableName := msgTableName(msg)
+	msgBytes, err := protodb.MsgMarshal(msg, c.MsgFormat)
+	if err != nil {
+		return newMsg, oldMsg, err
+	}
+	req.SchemeName = c.SchemeName
+	req.TableName = tableName
+	req.MsgBytes = msgBytes
+	req.MsgFormat = c.MsgFormat
+
+	connectReq := connect.NewRequest(req)
+	c.setErrHeader(connectReq.Header())
+	resp, err := c.Srv.Crud(ctx, connectReq)
+	if err != nil {
+		return newMsg, oldMsg, fmt.Errorf("%v %s err: %w", req.Code, tableName, serverError(err))
+	}
+	if len(resp.Msg.ErrInfo) > 0 {
+		return newMsg, oldMsg, fmt.Errorf("%v %s err: %w", req.Code, tableName, &Error{Info: resp.Msg.ErrInfo})
+	}
+
+	if len(resp.Msg.NewMsgBytes) > 0 {
+		newMsg = newMsgOf(msg)
+		if err := protodb.MsgUnmarshal(newMsg, resp.Msg.NewMsgBytes, resp.Msg.MsgFormat); err != nil {
+			return newMsg, oldMsg, err
+		}
+	}
+	if len(resp.Msg.OldMsgBytes) > 0 {
+		oldMsg = newMsgOf(msg)
+		if err := protodb.MsgUnmarshal(oldMsg, resp.Msg.OldMsgBytes, resp.Msg.MsgFormat); err != nil {
+			return newMsg, oldMsg, err
+		}
+	}
+	return newMsg, oldMsg, nil
+}
+
+// setErrHeader set Ygrpc-Err-Header and Ygrpc-Err-Max of request header
+func (c *Client) setErrHeader(header http.Header) {
+	if !c.ErrHeader {
+		return
+	}
+	header.Set(protodb.YgrpcErrHeader, "1")
+	if c.ErrMax > 0 {
+		header.Set(protodb.YgrpcErrMax, strconv.Itoa(c.ErrMax))
+	}
+}
+
+// serverError the Ygrpc-Err text of connect error as Error
+func serverError(err error) error {
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		if info := connectErr.Meta().Get(protodb.YgrpcErr); len(info) > 0 {
+			return &Error{Info: info, Err: err}
+		}
+	}
+	return err
+}
+
+// msgTableName name of msg in msgstore
+func msgTableName(msg proto.Message) string {
+	return string(msg.ProtoReflect().Descriptor().Name())
+}
+
+// newMsgOf new empty message of the type of msg, msg can be a nil pointer of generated message
+func newMsgOf[T proto.Message](msg T) T {
+	return msg.ProtoReflect().Type().New().Interface().(T)
+}
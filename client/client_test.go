@@ -0,0 +1,167 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/crud"
+	"github.com/ygrpc/protodb/service"
+	"google.golang.org/protobuf/proto"
+)
+
+type testSrv struct {
+	protodb.UnimplementedProtoDbSrvHandler
+	batches []*protodb.QueryResp
+}
+
+func (s *testSrv) Crud(ctx context.Context, req *connect.Request[protodb.CrudReq]) (*connect.Response[protodb.CrudResp], error) {
+	if req.Msg.TableName != "PDBField" {
+		err := connect.NewError(connect.CodeNotFound, errors.New("unknown table"))
+		err.Meta().Set(service.YgrpcErr, "no table "+req.Msg.TableName)
+		return nil, err
+	}
+	msg := &protodb.PDBField{}
+	if err := crud.MsgUnmarshal(msg, req.Msg.MsgBytes, req.Msg.MsgFormat); err != nil {
+		return nil, err
+	}
+	msg.Comment = append(msg.Comment, req.Msg.Code.String())
+	msg.SQLAppend = req.Msg.PartialUpdateFields
+	newMsgBytes, err := crud.MsgMarshal(msg, req.Msg.MsgFormat)
+	if err != nil {
+		return nil, err
+	}
+	resp := &protodb.CrudResp{RowsAffected: 1, NewMsgBytes: newMsgBytes, MsgFormat: req.Msg.MsgFormat}
+	if req.Msg.ResultType == protodb.CrudResultType_OldMsgAndNewMsg {
+		resp.OldMsgBytes = req.Msg.MsgBytes
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (s *testSrv) TableQuery(ctx context.Context, req *connect.Request[protodb.TableQueryReq], ss *connect.ServerStream[protodb.QueryResp]) error {
+	for _, resp := range s.batches {
+		if len(resp.ErrInfo) > 0 && len(req.Header().Get(service.YgrpcErrHeader)) > 0 {
+			ss.ResponseHeader().Set(service.YgrpcErr, resp.ErrInfo)
+		}
+		if err := ss.Send(resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newTestClient(t *testing.T, srv *testSrv, opts ...Option) *Client {
+	t.Helper()
+	path, handler := protodb.NewProtoDbSrvHandler(srv)
+	mux := http.NewServeMux()
+	mux.Handle(path, handler)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return New(protodb.NewProtoDbSrvClient(server.Client(), server.URL), opts...)
+}
+
+func marshalRows(t *testing.T, names ...string) [][]byte {
+	t.Helper()
+	var rows [][]byte
+	for _, name := range names {
+		b, err := proto.Marshal(&protodb.PDBField{ColumnName: name})
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		rows = append(rows, b)
+	}
+	return rows
+}
+
+func TestCrud(t *testing.T) {
+	for _, c := range []*Client{newTestClient(t, &testSrv{}), newTestClient(t, &testSrv{}, WithJSON())} {
+		ctx := context.Background()
+
+		inserted, err := Insert(ctx, c, &protodb.PDBField{ColumnName: "id"})
+		if err != nil {
+			t.Fatalf("Insert: %v", err)
+		}
+		if inserted.ColumnName != "id" || inserted.Comment[0] != "INSERT" {
+			t.Fatalf("unexpected inserted: %v", inserted)
+		}
+
+		updated, err := PartialUpdate(ctx, c, &protodb.PDBField{ColumnName: "id"}, "ColumnName")
+		if err != nil {
+			t.Fatalf("PartialUpdate: %v", err)
+		}
+		if updated.Comment[0] != "PARTIALUPDATE" || len(updated.SQLAppend) != 1 || updated.SQLAppend[0] != "ColumnName" {
+			t.Fatalf("unexpected updated: %v", updated)
+		}
+
+		oldMsg, newMsg, err := UpdateOldNew(ctx, c, &protodb.PDBField{ColumnName: "id"})
+		if err != nil {
+			t.Fatalf("UpdateOldNew: %v", err)
+		}
+		if len(oldMsg.Comment) != 0 || newMsg.Comment[0] != "UPDATE" {
+			t.Fatalf("unexpected old/new: %v %v", oldMsg, newMsg)
+		}
+	}
+}
+
+func TestCrudYgrpcErr(t *testing.T) {
+	c := newTestClient(t, &testSrv{})
+	_, err := SelectOne(context.Background(), c, &protodb.PDBMsg{})
+	var srvErr *Error
+	if !errors.As(err, &srvErr) || srvErr.Info != "no table PDBMsg" {
+		t.Fatalf("expected Ygrpc-Err error, got %v", err)
+	}
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Fatalf("expected connect code of error, got %v", connect.CodeOf(err))
+	}
+}
+
+func TestTableQuery(t *testing.T) {
+	srv := &testSrv{batches: []*protodb.QueryResp{
+		{ResponseNo: 0, MsgBytes: marshalRows(t, "a", "b")},
+		{ResponseNo: 1},
+		{ResponseNo: 2, MsgBytes: marshalRows(t, "c"), ResponseEnd: true},
+	}}
+	c := newTestClient(t, srv)
+
+	msgs, err := Collect(TableQuery[*protodb.PDBField](context.Background(), c, &protodb.TableQueryReq{}))
+	if err != nil {
+		t.Fatalf("TableQuery: %v", err)
+	}
+	var names []string
+	for _, msg := range msgs {
+		names = append(names, msg.ColumnName)
+	}
+	if strings.Join(names, ",") != "a,b,c" {
+		t.Fatalf("unexpected rows: %v", names)
+	}
+}
+
+func TestTableQueryErrors(t *testing.T) {
+	cases := []struct {
+		name    string
+		batches []*protodb.QueryResp
+		want    string
+	}{
+		{"order", []*protodb.QueryResp{{ResponseNo: 1, ResponseEnd: true}}, "got response 1, want 0"},
+		{"errinfo", []*protodb.QueryResp{{ResponseNo: 0, MsgBytes: marshalRows(t, "a")}, {ResponseNo: 0, ErrInfo: "scan failed", ResponseEnd: true}}, "scan failed"},
+		{"noend", []*protodb.QueryResp{{ResponseNo: 0, MsgBytes: marshalRows(t, "a")}}, "before ResponseEnd"},
+	}
+	for _, tc := range cases {
+		c := newTestClient(t, &testSrv{batches: tc.batches}, WithErrHeader(0))
+		it, err := TableQuery[*protodb.PDBField](context.Background(), c, &protodb.TableQueryReq{})
+		if err != nil {
+			t.Fatalf("%s TableQuery: %v", tc.name, err)
+		}
+		for it.Next() {
+		}
+		if it.Err() == nil || !strings.Contains(it.Err().Error(), tc.want) {
+			t.Fatalf("%s unexpected err: %v", tc.name, it.Err())
+		}
+		it.Close()
+	}
+}
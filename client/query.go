@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"connectrpc.com/connect"
+	"github.com/ygrpc/protodb"
+	"google.golang.org/protobuf/proto"
+)
+
+// QueryIter iterator of rows from QueryResp batches,
+// batches must arrive in ResponseNo order and end with ResponseEnd
+//
+//	it, err := client.TableQuery[*pb.User](ctx, c, &protodb.TableQueryReq{Limit: 10})
+//	defer it.Close()
+//	for it.Next() {
+//		user := it.Msg()
+//	}
+//	err = it.Err()
+type QueryIter[T proto.Message] struct {
+	stream *connect.ServerStreamForClient[protodb.QueryResp]
+	name   string
+	newMsg func() T
+
+	batch   [][]byte
+	format  int32
+	nextNo  int64
+	ended   bool
+	current T
+	err     error
+}
+
+// TableQuery query rows of table of T, TableName of req is the name of T if empty.
+// T must be a generated message type
+func TableQuery[T proto.Message](ctx context.Context, c *Client, req *protodb.TableQueryReq) (*QueryIter[T], error) {
+	var zero T
+	if len(req.TableName) == 0 {
+		req.TableName = msgTableName(zero)
+	}
+	if len(req.SchemeName) == 0 {
+		req.SchemeName = c.SchemeName
+	}
+	req.MsgFormat = c.MsgFormat
+
+	connectReq := connect.NewRequest(req)
+	c.setErrHeader(connectReq.Header())
+	stream, err := c.Srv.TableQuery(ctx, connectReq)
+	if err != nil {
+		return nil, fmt.Errorf("tablequery %s err: %w", req.TableName, serverError(err))
+	}
+	return newQueryIter(stream, req.TableName, func() T { return newMsgOf(zero) }), nil
+}
+
+// Query run the named query of querystore, rows are decoded as T
+func Query[T proto.Message](ctx context.Context, c *Client, req *protodb.QueryReq) (*QueryIter[T], error) {
+	var zero T
+	req.MsgFormat = c.MsgFormat
+
+	connectReq := connect.NewRequest(req)
+	c.setErrHeader(connectReq.Header())
+	stream, err := c.Srv.Query(ctx, connectReq)
+	if err != nil {
+		return nil, fmt.Errorf("query %s err: %w", req.QueryName, serverError(err))
+	}
+	return newQueryIter(stream, req.QueryName, func() T { return newMsgOf(zero) }), nil
+}
+
+func newQueryIter[T proto.Message](stream *connect.ServerStreamForClient[protodb.QueryResp], name string, newMsg func() T) *QueryIter[T] {
+	return &QueryIter[T]{
+		stream: stream,
+		name:   name,
+		newMsg: newMsg,
+	}
+}
+
+// Next decode the next row, false at the end or on error
+func (it *QueryIter[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	for len(it.batch) == 0 {
+		if it.ended || !it.receive() {
+			return false
+		}
+	}
+
+	msg := it.newMsg()
+	if err := protodb.MsgUnmarshal(msg, it.batch[0], it.format); err != nil {
+		it.err = fmt.Errorf("query %s response %d err: %w", it.name, it.nextNo-1, err)
+		return false
+	}
+	it.batch = it.batch[1:]
+	it.current = msg
+	return true
+}
+
+// receive read next QueryResp batch
+func (it *QueryIter[T]) receive() bool {
+	if !it.stream.Receive() {
+		if err := it.stream.Err(); err != nil {
+			it.err = fmt.Errorf("query %s err: %w", it.name, serverError(err))
+		} else {
+			it.err = fmt.Errorf("query %s err: stream closed before ResponseEnd", it.name)
+		}
+		return false
+	}
+
+	resp := it.stream.Msg()
+	if len(resp.ErrInfo) > 0 {
+		it.err = fmt.Errorf("query %s err: %w", it.name, &Error{Info: resp.ErrInfo})
+		return false
+	}
+	if info := it.stream.ResponseHeader().Get(protodb.YgrpcErr); len(info) > 0 {
+		it.err = fmt.Errorf("query %s err: %w", it.name, &Error{Info: info})
+		return false
+	}
+	if resp.ResponseNo != it.nextNo {
+		it.err = fmt.Errorf("query %s err: got response %d, want %d", it.name, resp.ResponseNo, it.nextNo)
+		return false
+	}
+	it.nextNo++
+	it.batch = resp.MsgBytes
+	it.format = resp.MsgFormat
+	it.ended = resp.ResponseEnd
+	return true
+}
+
+// Msg current row of Next
+func (it *QueryIter[T]) Msg() T {
+	return it.current
+}
+
+// Err error of iteration, nil at normal end
+func (it *QueryIter[T]) Err() error {
+	return it.err
+}
+
+// Close close the response stream
+func (it *QueryIter[T]) Close() error {
+	return it.stream.Close()
+}
+
+// All range over rows, the stream is closed after the loop, iteration stops at the first error
+func (it *QueryIter[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		defer it.Close()
+		for it.Next() {
+			if !yield(it.current, nil) {
+				return
+			}
+		}
+		if it.err != nil {
+			var zero T
+			yield(zero, it.err)
+		}
+	}
+}
+
+// Collect all rows of iter and close it
+func Collect[T proto.Message](it *QueryIter[T], err error) ([]T, error) {
+	if err != nil {
+		return nil, err
+	}
+	var msgs []T
+	for msg, err := range it.All() {
+		if err != nil {
+			return msgs, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
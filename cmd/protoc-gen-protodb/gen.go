@@ -0,0 +1,395 @@
+package main
+
+import (
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ygrpc/protodb/pdbutil"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	contextPackage      = protogen.GoImportPath("context")
+	crudPackage         = protogen.GoImportPath("github.com/ygrpc/protodb/crud")
+	protodbPackage      = protogen.GoImportPath("github.com/ygrpc/protodb")
+	msgstorePackage     = protogen.GoImportPath("github.com/ygrpc/protodb/msgstore")
+	repoPackage         = protogen.GoImportPath("github.com/ygrpc/protodb/repo")
+	sqldbPackage        = protogen.GoImportPath("github.com/ygrpc/protodb/sqldb")
+	protoPackage        = protogen.GoImportPath("google.golang.org/protobuf/proto")
+	protoreflectPackage = protogen.GoImportPath("google.golang.org/protobuf/reflect/protoreflect")
+)
+
+// tableMsg message with pdbm option and its db fields
+type tableMsg struct {
+	msg     *protogen.Message
+	fields  []*protogen.Field
+	primary []*protogen.Field
+	uniques [][]*protogen.Field
+}
+
+// tableMsgs top level messages with pdbm option, NotDB messages and fields are skipped
+func tableMsgs(file *protogen.File) []*tableMsg {
+	var tables []*tableMsg
+	for _, msg := range file.Messages {
+		pdbm, found := pdbutil.GetPDBM(msg.Desc)
+		if !found || pdbm.NotDB {
+			continue
+		}
+
+		table := &tableMsg{msg: msg}
+		uniqueGroups := make(map[string][]*protogen.Field)
+		var uniqueNames []string
+		for _, field := range msg.Fields {
+			pdb, _ := pdbutil.GetPDB(field.Desc)
+			if pdb.NotDB {
+				continue
+			}
+			table.fields = append(table.fields, field)
+			switch {
+			case pdb.IsPrimary():
+				table.primary = append(table.primary, field)
+			case pdb.Unique:
+				uniqueName := pdb.UniqueName
+				if len(uniqueName) == 0 {
+					uniqueName = string(field.Desc.Name())
+				}
+				if _, ok := uniqueGroups[uniqueName]; !ok {
+					uniqueNames = append(uniqueNames, uniqueName)
+				}
+				uniqueGroups[uniqueName] = append(uniqueGroups[uniqueName], field)
+			}
+		}
+		for _, uniqueName := range uniqueNames {
+			table.uniques = append(table.uniques, uniqueGroups[uniqueName])
+		}
+		sort.SliceStable(table.uniques, func(i, j int) bool {
+			return table.uniques[i][0].Desc.Number() < table.uniques[j][0].Desc.Number()
+		})
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+// generateFile generate <file>.protodb.go, nil if the file has no table message
+func generateFile(gen *protogen.Plugin, file *protogen.File) *protogen.GeneratedFile {
+	tables := tableMsgs(file)
+	if len(tables) == 0 {
+		return nil
+	}
+
+	g := gen.NewGeneratedFile(file.GeneratedFilenamePrefix+".protodb.go", file.GoImportPath)
+	g.P("// Code generated by protoc-gen-protodb. DO NOT EDIT.")
+	g.P("// source: ", file.Desc.Path())
+	g.P()
+	g.P("package ", file.GoPackageName)
+	g.P()
+
+	g.P("func init() {")
+	for _, table := range tables {
+		g.P(msgstorePackage.Ident("RegisterMsg"), "(", quote(string(table.msg.Desc.Name())), ", func(new bool) ", protoPackage.Ident("Message"), " {")
+		g.P("return &", table.msg.GoIdent, "{}")
+		g.P("})")
+		g.P(crudPackage.Ident("RegisterRowBinder"), "(&", table.msg.GoIdent, "{}, bind", table.msg.GoIdent.GoName, "Column)")
+	}
+	g.P("}")
+	g.P()
+
+	for _, table := range tables {
+		genTable(g, table)
+		genRowBinder(g, table)
+	}
+	return g
+}
+
+func genTable(g *protogen.GeneratedFile, table *tableMsg) {
+	name := table.msg.GoIdent.GoName
+	fieldType := name + "Field"
+	repoType := name + "Repo"
+	queryType := name + "Query"
+	ctxParam := "ctx " + g.QualifiedGoIdent(contextPackage.Ident("Context")) + ", db " + g.QualifiedGoIdent(sqldbPackage.Ident("DB"))
+
+	g.P("// ", fieldType, " field of ", name, " for UpdateFields and queries")
+	g.P("type ", fieldType, " string")
+	g.P()
+	g.P("const (")
+	for _, field := range table.fields {
+		g.P(fieldConst(name, field), " ", fieldType, " = ", quote(string(field.Desc.Name())))
+	}
+	g.P(")")
+	g.P()
+
+	g.P("// ", repoType, " typed crud of ", name)
+	g.P("type ", repoType, " struct {")
+	g.P("// SchemeName db schema of the table")
+	g.P("SchemeName string")
+	g.P("}")
+	g.P()
+
+	for _, op := range []struct{ method, fn, doc string }{
+		{"Insert", "Insert", "insert msg and return the inserted row"},
+		{"Update", "Update", "update all fields of msg by primary key and return the updated row"},
+		{"Delete", "Delete", "delete msg by primary key and return the deleted row"},
+	} {
+		g.P("// ", op.method, " ", op.doc)
+		g.P("func (r ", repoType, ") ", op.method, "(", ctxParam, ", msg *", name, ") (*", name, ", error) {")
+		g.P("return ", repoPackage.Ident(op.fn), "(ctx, db, msg, r.SchemeName)")
+		g.P("}")
+		g.P()
+	}
+
+	g.P("// UpdateFields update fields of msg by primary key and return the updated row")
+	g.P("func (r ", repoType, ") UpdateFields(", ctxParam, ", msg *", name, ", fields ...", fieldType, ") (*", name, ", error) {")
+	g.P("return ", repoPackage.Ident("UpdateFields"), "(ctx, db, msg, r.SchemeName, fields...)")
+	g.P("}")
+	g.P()
+
+	if keyFieldsSupported(table.primary) {
+		genGetBy(g, table, "GetByID", "select the row by primary key", table.primary, false)
+	}
+	for _, unique := range table.uniques {
+		if !keyFieldsSupported(unique) {
+			continue
+		}
+		method := "GetBy"
+		for _, field := range unique {
+			method += field.GoName
+		}
+		genGetBy(g, table, method, "select the row by unique key", unique, true)
+	}
+
+	g.P("// Query typed table query of ", name)
+	g.P("func (r ", repoType, ") Query() *", queryType, " {")
+	g.P("return &", queryType, "{q: ", repoPackage.Ident("NewQuery"), "[*", name, "](r.SchemeName)}")
+	g.P("}")
+	g.P()
+
+	g.P("// ", queryType, " typed table query of ", name, ", one condition per field")
+	g.P("type ", queryType, " struct {")
+	g.P("q *", repoPackage.Ident("Query"), "[*", name, "]")
+	g.P("}")
+	g.P()
+
+	whereOp := g.QualifiedGoIdent(protodbPackage.Ident("WhereOperator"))
+	for _, field := range table.fields {
+		paramType, ok := goType(g, field)
+		if !ok {
+			// repeated, map and message fields take the value of FormatWhereValue
+			paramType = "any"
+		}
+		g.P("// Where", field.GoName, " condition on ", field.Desc.Name())
+		g.P("func (q *", queryType, ") Where", field.GoName, "(op ", whereOp, ", v ", paramType, ") *", queryType, " {")
+		g.P("q.q.Where(string(", fieldConst(name, field), "), op, v)")
+		g.P("return q")
+		g.P("}")
+		g.P()
+	}
+
+	g.P("// Columns result fields, all fields if not set")
+	g.P("func (q *", queryType, ") Columns(fields ...", fieldType, ") *", queryType, " {")
+	g.P("names := make([]string, len(fields))")
+	g.P("for i, field := range fields {")
+	g.P("names[i] = string(field)")
+	g.P("}")
+	g.P("q.q.Columns(names...)")
+	g.P("return q")
+	g.P("}")
+	g.P()
+
+	g.P("func (q *", queryType, ") Limit(limit int32) *", queryType, " {")
+	g.P("q.q.Limit(limit)")
+	g.P("return q")
+	g.P("}")
+	g.P()
+
+	g.P("func (q *", queryType, ") Offset(offset int64) *", queryType, " {")
+	g.P("q.q.Offset(offset)")
+	g.P("return q")
+	g.P("}")
+	g.P()
+
+	g.P("// All rows of query")
+	g.P("func (q *", queryType, ") All(", ctxParam, ") ([]*", name, ", error) {")
+	g.P("return q.q.All(ctx, db)")
+	g.P("}")
+	g.P()
+
+	g.P("// First first row of query, sql.ErrNoRows if none")
+	g.P("func (q *", queryType, ") First(", ctxParam, ") (*", name, ", error) {")
+	g.P("return q.q.First(ctx, db)")
+	g.P("}")
+	g.P()
+}
+
+// genRowBinder scan binder of scalar fields, the other fields are scanned by protoreflect
+func genRowBinder(g *protogen.GeneratedFile, table *tableMsg) {
+	name := table.msg.GoIdent.GoName
+	columnBinder := g.QualifiedGoIdent(crudPackage.Ident("ColumnBinder"))
+	protoMsg := g.QualifiedGoIdent(protoPackage.Ident("Message"))
+
+	g.P("// bind", name, "Column scan binder of ", name, " columns, see crud.RowBinder")
+	g.P("func bind", name, "Column(field ", protoreflectPackage.Ident("FieldNumber"), ") (", columnBinder, ", bool) {")
+	g.P("switch field {")
+	for _, field := range table.fields {
+		bind, ok := bindFunc(field)
+		if !ok {
+			continue
+		}
+		fieldType, _ := goType(g, field)
+		ptr := "*"
+		if field.Desc.HasPresence() {
+			bind = "Optional" + bind
+			ptr = "**"
+		}
+		g.P("case ", field.Desc.Number(), ":")
+		g.P("return ", crudPackage.Ident("Bind"+bind), "(func(msg ", protoMsg, ") ", ptr, fieldType, " { return &msg.(*", name, ").", field.GoName, " }), true")
+	}
+	g.P("}")
+	g.P("return ", columnBinder, "{}, false")
+	g.P("}")
+	g.P()
+}
+
+// bindFunc crud.Bind<X> of the field, false if the field is scanned by protoreflect
+func bindFunc(field *protogen.Field) (string, bool) {
+	fd := field.Desc
+	if fd.IsList() || fd.IsMap() || (field.Oneof != nil && !field.Oneof.Desc.IsSynthetic()) {
+		return "", false
+	}
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return "Bool", true
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return "Int", true
+	case protoreflect.EnumKind:
+		// enum stored by value name is looked up by the descriptor
+		return "Int", !pdbutil.IsEnumStoredAsName(fd)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return "Uint", true
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return "Float", true
+	case protoreflect.StringKind:
+		// numeric strings are normalized by the scanner
+		pdb, _ := pdbutil.GetPDB(fd)
+		return "String", !pdb.IsNumeric()
+	}
+	return "", false
+}
+
+// genGetBy select one row by key fields, unique keys pass their field names
+func genGetBy(g *protogen.GeneratedFile, table *tableMsg, method string, doc string, keys []*protogen.Field, byFieldNames bool) {
+	name := table.msg.GoIdent.GoName
+	params := []string{
+		"ctx " + g.QualifiedGoIdent(contextPackage.Ident("Context")),
+		"db " + g.QualifiedGoIdent(sqldbPackage.Ident("DB")),
+	}
+	var values, keyNames []string
+	for _, field := range keys {
+		paramName := paramName(field.GoName)
+		paramType, _ := goType(g, field)
+		params = append(params, paramName+" "+paramType)
+		if field.Desc.HasPresence() && field.Desc.Kind() != protoreflect.BytesKind {
+			values = append(values, field.GoName+": &"+paramName)
+		} else {
+			values = append(values, field.GoName+": "+paramName)
+		}
+		keyNames = append(keyNames, "string("+fieldConst(name, field)+")")
+	}
+
+	g.P("// ", method, " ", doc)
+	g.P("func (r ", name, "Repo) ", method, "(", strings.Join(params, ", "), ") (*", name, ", error) {")
+	selectArgs := "ctx, db, &" + name + "{" + strings.Join(values, ", ") + "}, r.SchemeName"
+	if byFieldNames {
+		selectArgs += ", " + strings.Join(keyNames, ", ")
+	}
+	g.P("return ", repoPackage.Ident("SelectOne"), "(", selectArgs, ")")
+	g.P("}")
+	g.P()
+}
+
+// keyFieldsSupported key fields are scalars outside of oneof, they are set in a struct literal
+func keyFieldsSupported(fields []*protogen.Field) bool {
+	if len(fields) == 0 {
+		return false
+	}
+	for _, field := range fields {
+		if field.Oneof != nil && !field.Oneof.Desc.IsSynthetic() {
+			return false
+		}
+		if _, ok := goTypeOfKind(field.Desc); !ok && field.Desc.Kind() != protoreflect.EnumKind {
+			return false
+		}
+		if field.Desc.IsList() || field.Desc.IsMap() {
+			return false
+		}
+	}
+	return true
+}
+
+// goType go type of singular scalar or enum field
+func goType(g *protogen.GeneratedFile, field *protogen.Field) (string, bool) {
+	if field.Desc.IsList() || field.Desc.IsMap() {
+		return "", false
+	}
+	if field.Desc.Kind() == protoreflect.EnumKind {
+		return g.QualifiedGoIdent(field.Enum.GoIdent), true
+	}
+	return goTypeOfKind(field.Desc)
+}
+
+func goTypeOfKind(fd protoreflect.FieldDescriptor) (string, bool) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return "bool", true
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return "int32", true
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return "uint32", true
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return "int64", true
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return "uint64", true
+	case protoreflect.FloatKind:
+		return "float32", true
+	case protoreflect.DoubleKind:
+		return "float64", true
+	case protoreflect.StringKind:
+		return "string", true
+	case protoreflect.BytesKind:
+		return "[]byte", true
+	}
+	return "", false
+}
+
+func fieldConst(msgName string, field *protogen.Field) string {
+	return msgName + "Field" + field.GoName
+}
+
+// paramName lower camel case of go name, the leading initialism is lowered too(URLPath -> urlPath)
+func paramName(goName string) string {
+	runes := []rune(goName)
+	n := 0
+	for n < len(runes) && unicode.IsUpper(runes[n]) {
+		n++
+	}
+	if n > 1 && n < len(runes) {
+		// keep the upper start of next word
+		n--
+	}
+	for i := 0; i < n; i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	name := string(runes)
+	switch {
+	case token.IsKeyword(name), name == "ctx", name == "db", name == "r":
+		return name + "Val"
+	}
+	return name
+}
+
+func quote(s string) string {
+	return `"` + s + `"`
+}
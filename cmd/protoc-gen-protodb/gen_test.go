@@ -0,0 +1,193 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/internal/example/userpb"
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+var update = flag.Bool("update", false, "update the generated example in internal/example/userpb")
+
+const examplePath = "../../internal/example/userpb/user.protodb.go"
+
+// runGenerator run the plugin on file, the result is nil if no file is generated
+func runGenerator(t *testing.T, file *descriptorpb.FileDescriptorProto) *pluginpb.CodeGeneratorResponse_File {
+	t.Helper()
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{file.GetName()},
+		Parameter:      proto.String("paths=source_relative"),
+		ProtoFile: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(descriptorpb.File_google_protobuf_descriptor_proto),
+			protodesc.ToFileDescriptorProto(timestamppb.File_google_protobuf_timestamp_proto),
+			protodesc.ToFileDescriptorProto(protodb.File_protodb_proto),
+			file,
+		},
+	}
+	gen, err := protogen.Options{}.New(req)
+	if err != nil {
+		t.Fatalf("protogen: %v", err)
+	}
+	for _, f := range gen.Files {
+		if f.Generate {
+			generateFile(gen, f)
+		}
+	}
+	resp := gen.Response()
+	if resp.Error != nil {
+		t.Fatalf("generate: %s", resp.GetError())
+	}
+	if len(resp.File) == 0 {
+		return nil
+	}
+	return resp.File[0]
+}
+
+func TestGenerateExample(t *testing.T) {
+	got := runGenerator(t, protodesc.ToFileDescriptorProto(userpb.File_internal_example_userpb_user_proto))
+	if got == nil {
+		t.Fatal("no file generated")
+	}
+	if got.GetName() != "internal/example/userpb/user.protodb.go" {
+		t.Fatalf("unexpected file name %s", got.GetName())
+	}
+	if *update {
+		if err := os.WriteFile(examplePath, []byte(got.GetContent()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(examplePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetContent() != string(want) {
+		t.Fatalf("%s is out of date, run go test ./cmd/protoc-gen-protodb -update", examplePath)
+	}
+
+	for _, s := range []string{
+		`msgstore.RegisterMsg("User"`,
+		"func (r UserRepo) GetByID(ctx context.Context, db sqldb.DB, id int64) (*User, error)",
+		"func (r UserRepo) GetByUsername(ctx context.Context, db sqldb.DB, username string) (*User, error)",
+		"func (r UserRepo) GetByEmailTenantId(ctx context.Context, db sqldb.DB, email string, tenantId int64) (*User, error)",
+		"func (q *UserQuery) WhereStatus(op protodb.WhereOperator, v UserStatus) *UserQuery",
+		"func (q *UserQuery) WhereTags(op protodb.WhereOperator, v any) *UserQuery",
+	} {
+		if !strings.Contains(got.GetContent(), s) {
+			t.Errorf("generated code has no %q", s)
+		}
+	}
+	// NotDB field has no constant
+	if strings.Contains(got.GetContent(), "UserFieldPassword") {
+		t.Error("NotDB field password is generated")
+	}
+}
+
+func TestGenerateSkip(t *testing.T) {
+	notDB := &descriptorpb.MessageOptions{}
+	proto.SetExtension(notDB, protodb.E_Pdbm, &protodb.PDBMsg{NotDB: true})
+	file := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("skip.proto"),
+		Package:    proto.String("skip"),
+		Dependency: []string{"protodb.proto"},
+		Syntax:     proto.String("proto3"),
+		Options:    &descriptorpb.FileOptions{GoPackage: proto.String("example.com/skip")},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("Plain")},
+			{Name: proto.String("Hidden"), Options: notDB},
+		},
+	}
+	if got := runGenerator(t, file); got != nil {
+		t.Fatalf("expected no file, got %s", got.GetName())
+	}
+}
+
+func TestGenerateOneofKey(t *testing.T) {
+	pdbm := &descriptorpb.MessageOptions{}
+	proto.SetExtension(pdbm, protodb.E_Pdbm, &protodb.PDBMsg{})
+	primary := &descriptorpb.FieldOptions{}
+	proto.SetExtension(primary, protodb.E_Pdb, &protodb.PDBField{Primary: true})
+	unique := &descriptorpb.FieldOptions{}
+	proto.SetExtension(unique, protodb.E_Pdb, &protodb.PDBField{Unique: true})
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	file := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("order.proto"),
+		Package:    proto.String("order"),
+		Dependency: []string{"protodb.proto"},
+		Syntax:     proto.String("proto3"),
+		Options:    &descriptorpb.FileOptions{GoPackage: proto.String("example.com/order")},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name:    proto.String("Order"),
+			Options: pdbm,
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("type"), Number: proto.Int32(1), Label: optional, Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Options: primary},
+				{Name: proto.String("code"), Number: proto.Int32(2), Label: optional, Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Options: unique, OneofIndex: proto.Int32(0)},
+			},
+			OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: proto.String("ref")}},
+		}},
+	}
+	got := runGenerator(t, file)
+	if got == nil {
+		t.Fatal("no file generated")
+	}
+	// go keyword param is renamed, oneof member has no key method
+	if !strings.Contains(got.GetContent(), "GetByID(ctx context.Context, db sqldb.DB, typeVal string)") {
+		t.Errorf("unexpected GetByID:\n%s", got.GetContent())
+	}
+	if strings.Contains(got.GetContent(), "GetByCode") {
+		t.Error("key method of oneof member is generated")
+	}
+}
+
+func TestGenerateUniqueNameOnly(t *testing.T) {
+	pdbm := &descriptorpb.MessageOptions{}
+	proto.SetExtension(pdbm, protodb.E_Pdbm, &protodb.PDBMsg{})
+	uniqueName := &descriptorpb.FieldOptions{}
+	proto.SetExtension(uniqueName, protodb.E_Pdb, &protodb.PDBField{UniqueName: "uk_code"})
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	file := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("coupon.proto"),
+		Package:    proto.String("coupon"),
+		Dependency: []string{"protodb.proto"},
+		Syntax:     proto.String("proto3"),
+		Options:    &descriptorpb.FileOptions{GoPackage: proto.String("example.com/coupon")},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name:    proto.String("Coupon"),
+			Options: pdbm,
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("code"), Number: proto.Int32(1), Label: optional, Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Options: uniqueName},
+			},
+		}},
+	}
+	got := runGenerator(t, file)
+	if got == nil {
+		t.Fatal("no file generated")
+	}
+	// ddl creates no unique index without Unique, so there is no key method
+	if strings.Contains(got.GetContent(), "GetByCode") {
+		t.Error("key method of UniqueName without Unique is generated")
+	}
+}
+
+func TestParamName(t *testing.T) {
+	for goName, want := range map[string]string{
+		"Id":       "id",
+		"URLPath":  "urlPath",
+		"ID":       "id",
+		"TenantId": "tenantId",
+		"Func":     "funcVal",
+		"Ctx":      "ctxVal",
+	} {
+		if got := paramName(goName); got != want {
+			t.Errorf("paramName(%s) = %s, want %s", goName, got, want)
+		}
+	}
+}
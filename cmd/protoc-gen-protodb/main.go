@@ -0,0 +1,26 @@
+// protoc-gen-protodb generate typed repositories of messages with pdbm options,
+// the generated file registers the messages in msgstore
+//
+//	protoc --go_out=paths=source_relative:. --protodb_out=paths=source_relative:. user.proto
+//
+// for message User it generates UserField constants, UserRepo with
+// Insert/Update/UpdateFields/Delete/GetByID/GetBy<UniqueKey> and a typed UserQuery
+package main
+
+import (
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+func main() {
+	protogen.Options{}.Run(func(gen *protogen.Plugin) error {
+		gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+		for _, f := range gen.Files {
+			if !f.Generate {
+				continue
+			}
+			generateFile(gen, f)
+		}
+		return nil
+	})
+}
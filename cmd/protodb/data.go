@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/crud"
+	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/service"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// maxNdjsonLine max size of a row of import
+const maxNdjsonLine = 64 << 20
+
+func runExport(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	opts := newOptions(fs)
+	tableName := fs.String("table", "", "table or message name")
+	out := fs.String("out", "", "output file, empty for stdout")
+	_ = fs.Parse(args)
+
+	msgDesc, db, err := openTable(opts, *tableName)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	w := stdout
+	if len(*out) > 0 {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+	req := &protodb.TableQueryReq{MsgFormat: 1, PreferBatchSize: 1000}
+	if err := tableQuery(db, opts.schema, msgDesc, req, bw); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func runImport(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	opts := newOptions(fs)
+	tableName := fs.String("table", "", "table or message name")
+	in := fs.String("in", "", "input file, empty for stdin")
+	_ = fs.Parse(args)
+
+	msgDesc, db, err := openTable(opts, *tableName)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	r := stdin
+	if len(*in) > 0 {
+		f, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	count, err := importRows(sqldb.NewTxWithDialect(tx, db), opts.schema, msgDesc, r)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(stdout, "%d rows imported into %s\n", count, pdbutil.GetTableName(msgDesc))
+	return err
+}
+
+func runQuery(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("query", flag.ExitOnError)
+	opts := newOptions(fs)
+	tableName := fs.String("table", "", "table or message name")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: protodb query -table t [flags] [field=op:value ...] [order=a,-b] [limit=n] [offset=n] [fields=a,b]")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	values := url.Values{}
+	for _, arg := range fs.Args() {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return fmt.Errorf("condition %q is not key=value", arg)
+		}
+		values.Add(key, value)
+	}
+	req, err := service.ParseRestTableQuery(values)
+	if err != nil {
+		return err
+	}
+
+	msgDesc, db, err := openTable(opts, *tableName)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	bw := bufio.NewWriter(stdout)
+	if err := tableQuery(db, opts.schema, msgDesc, req, bw); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// openTable message of -table and the database
+func openTable(opts *tOptions, tableName string) (protoreflect.MessageDescriptor, *sql.DB, error) {
+	if len(tableName) == 0 {
+		return nil, nil, errors.New("-table is required")
+	}
+	opts.tables = tableName
+	msgDescs, err := opts.loadMsgs()
+	if err != nil {
+		return nil, nil, err
+	}
+	db, err := opts.openDB(true)
+	if err != nil {
+		return nil, nil, err
+	}
+	return msgDescs[0], db, nil
+}
+
+// tableQuery run req on the table of msgDesc by service.HandleTableQuery, rows are written as NDJSON
+func tableQuery(db *sql.DB, dbschema string, msgDesc protoreflect.MessageDescriptor, req *protodb.TableQueryReq, w io.Writer) error {
+	req.SchemeName = dbschema
+	req.TableName = pdbutil.GetTableName(msgDesc)
+
+	var queryErr error
+	err := service.HandleTableQuery(context.Background(), nil, req,
+		func(meta http.Header, schemaName string, tableName string, writable bool) (sqldb.DB, error) {
+			return db, nil
+		},
+		service.FnTableQueryPermissionEmpty,
+		func(resp *protodb.QueryResp) error {
+			if len(resp.ErrInfo) > 0 {
+				queryErr = errors.New(resp.ErrInfo)
+				return nil
+			}
+			for _, msgBytes := range resp.MsgBytes {
+				if _, err := w.Write(msgBytes); err != nil {
+					return err
+				}
+				if _, err := io.WriteString(w, "\n"); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	if queryErr != nil {
+		return queryErr
+	}
+	return err
+}
+
+// importRows insert each protojson line of r, blank lines are skipped. return the inserted rows count
+func importRows(db sqldb.DB, dbschema string, msgDesc protoreflect.MessageDescriptor, r io.Reader) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNdjsonLine)
+	count := 0
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+		msg := dynamicpb.NewMessage(msgDesc)
+		if err := protojson.Unmarshal(line, msg); err != nil {
+			return count, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		if _, err := crud.DbInsert(db, msg, 0, dbschema); err != nil {
+			return count, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		count++
+	}
+	return count, scanner.Err()
+}
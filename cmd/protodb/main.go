@@ -0,0 +1,179 @@
+// protodb manages the tables of the protodb messages in descriptor sets from the command line.
+//
+// usage:
+//
+//	protoc --include_imports --descriptor_set_out=tables.pb tables.proto
+//	protodb ddl -descriptor_set tables.pb
+//	protodb migrate -descriptor_set tables.pb -dsn app.db -dry-run
+//	protodb diff -descriptor_set tables.pb -dsn app.db
+//	protodb drift -descriptor_set tables.pb -dsn app.db -fail-on warning
+//	protodb describe -descriptor_set tables.pb -tables t_user
+//	protodb export -descriptor_set tables.pb -dsn app.db -table t_user > t_user.ndjson
+//	protodb import -descriptor_set tables.pb -dsn app.db -table t_user < t_user.ndjson
+//	protodb query -descriptor_set tables.pb -dsn app.db -table t_user age=gte:18 order=-id limit=10
+//
+// the default driver is sqlite(modernc.org/sqlite, pure go), pgx is linked in too.
+// add a blank import of your mysql driver to use other databases.
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/ygrpc/protodb/msgstore"
+	"github.com/ygrpc/protodb/pdbutil"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	_ "modernc.org/sqlite"
+)
+
+// errDiffFound diff found changes and -exit-code is set, or drift found items of -fail-on
+var errDiffFound = errors.New("schema differs")
+
+// tCommand sub command of protodb
+type tCommand struct {
+	summary string
+	run     func(args []string, stdin io.Reader, stdout io.Writer) error
+}
+
+var commands = map[string]tCommand{
+	"ddl":      {"print create table sql of the messages, referenced tables first", runDdl},
+	"migrate":  {"create or migrate the tables of the messages", runMigrate},
+	"diff":     {"print the sql needed to migrate each table", runDiff},
+	"drift":    {"compare the tables in db with the messages, report drift by severity", runDrift},
+	"describe": {"print columns, keys and where operators of the tables", runDescribe},
+	"export":   {"write rows of a table as protojson NDJSON", runExport},
+	"import":   {"insert protojson NDJSON rows into a table in one transaction", runImport},
+	"query":    {"run a TableQuery, conditions are field=op:value like the rest gateway", runQuery},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	err := run(os.Args[1:], os.Stdin, os.Stdout)
+	switch {
+	case err == nil:
+	case errors.Is(err, errDiffFound):
+		os.Exit(1)
+	case errors.Is(err, flag.ErrHelp):
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "protodb %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+// run the sub command args[0]
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	cmd, ok := commands[args[0]]
+	if !ok {
+		usage()
+		return flag.ErrHelp
+	}
+	return cmd.run(args[1:], stdin, stdout)
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(os.Stderr, "usage: protodb <command> [flags]")
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-9s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(os.Stderr, "run protodb <command> -h for the flags of a command")
+}
+
+// tOptions flags shared by the sub commands
+type tOptions struct {
+	descriptorSets string
+	driver         string
+	dsn            string
+	schema         string
+	tables         string
+}
+
+func newOptions(fs *flag.FlagSet) *tOptions {
+	opts := &tOptions{}
+	fs.StringVar(&opts.descriptorSets, "descriptor_set", "", "comma separated FileDescriptorSet files, built by protoc --include_imports --descriptor_set_out")
+	fs.StringVar(&opts.driver, "driver", "sqlite", "database/sql driver name")
+	fs.StringVar(&opts.dsn, "dsn", "", "database dsn")
+	fs.StringVar(&opts.schema, "schema", "", "postgres schema(default public), or table name prefix for mysql/sqlite")
+	fs.StringVar(&opts.tables, "tables", "", "comma separated table or message names, empty for all tables")
+	return opts
+}
+
+// loadMsgs register the table messages of the descriptor sets to msgstore.GlobalMsgStore,
+// return the ones selected by -tables
+func (this *tOptions) loadMsgs() ([]protoreflect.MessageDescriptor, error) {
+	if len(this.descriptorSets) == 0 {
+		return nil, errors.New("-descriptor_set is required")
+	}
+
+	var msgDescs []protoreflect.MessageDescriptor
+	for _, fileName := range splitList(this.descriptorSets) {
+		b, err := os.ReadFile(fileName)
+		if err != nil {
+			return nil, err
+		}
+		fileMsgDescs, err := msgstore.RegisterFileDescriptorSetBytes(b)
+		if err != nil {
+			return nil, fmt.Errorf("load %s: %w", fileName, err)
+		}
+		msgDescs = append(msgDescs, fileMsgDescs...)
+	}
+
+	names := splitList(this.tables)
+	if len(names) == 0 {
+		return msgDescs, nil
+	}
+	selected := make([]protoreflect.MessageDescriptor, 0, len(names))
+	for _, name := range names {
+		msgDesc := findMsg(msgDescs, name)
+		if msgDesc == nil {
+			return nil, fmt.Errorf("table %s not found in descriptor sets", name)
+		}
+		selected = append(selected, msgDesc)
+	}
+	return selected, nil
+}
+
+// openDB open the database, dsn can be empty when the db is only used for its dialect
+func (this *tOptions) openDB(needDsn bool) (*sql.DB, error) {
+	if needDsn && len(this.dsn) == 0 {
+		return nil, errors.New("-dsn is required")
+	}
+	return sql.Open(this.driver, this.dsn)
+}
+
+// findMsg message of table name or message name
+func findMsg(msgDescs []protoreflect.MessageDescriptor, name string) protoreflect.MessageDescriptor {
+	for _, msgDesc := range msgDescs {
+		if pdbutil.GetTableName(msgDesc) == name || string(msgDesc.Name()) == name || string(msgDesc.FullName()) == name {
+			return msgDesc
+		}
+	}
+	return nil
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ygrpc/protodb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// writeDescriptorSet write a descriptor set of package pkg with org and member(reference org) tables,
+// member has the extra column note if withNote
+func writeDescriptorSet(t *testing.T, dir string, pkg string, withNote bool) string {
+	t.Helper()
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, pdb *protodb.PDBField) *descriptorpb.FieldDescriptorProto {
+		opts := &descriptorpb.FieldOptions{}
+		proto.SetExtension(opts, protodb.E_Pdb, pdb)
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     typ.Enum(),
+			Options:  opts,
+		}
+	}
+	table := func(msgName string, tableName string, fields ...*descriptorpb.FieldDescriptorProto) *descriptorpb.DescriptorProto {
+		opts := &descriptorpb.MessageOptions{}
+		proto.SetExtension(opts, protodb.E_Pdbm, &protodb.PDBMsg{TableName: tableName})
+		return &descriptorpb.DescriptorProto{Name: proto.String(msgName), Field: fields, Options: opts}
+	}
+
+	memberFields := []*descriptorpb.FieldDescriptorProto{
+		field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64, &protodb.PDBField{Primary: true, SerialType: 8}),
+		field("org_id", 2, descriptorpb.FieldDescriptorProto_TYPE_INT64, &protodb.PDBField{Reference: "t_org(id)"}),
+		field("name", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING, &protodb.PDBField{NotNull: true}),
+	}
+	if withNote {
+		memberFields = append(memberFields, field("note", 4, descriptorpb.FieldDescriptorProto_TYPE_STRING, &protodb.PDBField{}))
+	}
+	fds := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{{
+		Syntax:     proto.String("proto3"),
+		Name:       proto.String(pkg + "/tables.proto"),
+		Package:    proto.String(pkg),
+		Dependency: []string{"protodb.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{
+			table("Member", "t_member", memberFields...),
+			table("Org", "t_org",
+				field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64, &protodb.PDBField{Primary: true}),
+				field("name", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, &protodb.PDBField{Unique: true})),
+		},
+	}}}
+	b, err := proto.Marshal(fds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileName := filepath.Join(dir, pkg+".pb")
+	if err := os.WriteFile(fileName, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return fileName
+}
+
+func runCmd(t *testing.T, stdin string, args ...string) string {
+	t.Helper()
+	var stdout bytes.Buffer
+	if err := run(args, strings.NewReader(stdin), &stdout); err != nil {
+		t.Fatalf("protodb %s: %v", strings.Join(args, " "), err)
+	}
+	return stdout.String()
+}
+
+func TestSQLiteRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	fdsV1 := writeDescriptorSet(t, dir, "clitest", false)
+	dsn := filepath.Join(dir, "app.db")
+
+	ddlOut := runCmd(t, "", "ddl", "-descriptor_set", fdsV1)
+	org, member := strings.Index(ddlOut, "CREATE TABLE"), strings.LastIndex(ddlOut, "CREATE TABLE")
+	if org < 0 || !strings.Contains(ddlOut[org:member], `"t_org"`) || !strings.Contains(ddlOut[member:], `"t_member"`) {
+		t.Fatalf("referenced table should be created first:\n%s", ddlOut)
+	}
+	if out := runCmd(t, "", "ddl", "-descriptor_set", fdsV1, "-comment=false"); strings.Count(out, "CREATE TABLE") != 2 {
+		t.Fatalf("ddl -comment=false:\n%s", out)
+	}
+
+	if out := runCmd(t, "", "migrate", "-descriptor_set", fdsV1, "-dsn", dsn, "-dry-run"); !strings.Contains(out, "CREATE TABLE") {
+		t.Fatalf("migrate -dry-run:\n%s", out)
+	}
+	if out := runCmd(t, "", "diff", "-descriptor_set", fdsV1, "-dsn", dsn); !strings.Contains(out, "-- t_member: missing") {
+		t.Fatalf("diff before migrate:\n%s", out)
+	}
+	runCmd(t, "", "migrate", "-descriptor_set", fdsV1, "-dsn", dsn)
+	if out := runCmd(t, "", "diff", "-descriptor_set", fdsV1, "-dsn", dsn); strings.Count(out, "up to date") != 2 {
+		t.Fatalf("diff after migrate:\n%s", out)
+	}
+
+	out := runCmd(t, `{"id":"1","name":"acme"}`+"\n\n"+`{"id":"2","name":"globex"}`+"\n", "import", "-descriptor_set", fdsV1, "-dsn", dsn, "-table", "t_org")
+	if !strings.HasPrefix(out, "2 rows imported") {
+		t.Fatalf("import: %s", out)
+	}
+	if out := runCmd(t, "", "export", "-descriptor_set", fdsV1, "-dsn", dsn, "-table", "Org"); strings.Count(out, "\n") != 2 || !strings.Contains(out, `"acme"`) {
+		t.Fatalf("export:\n%s", out)
+	}
+	out = runCmd(t, "", "query", "-descriptor_set", fdsV1, "-dsn", dsn, "-table", "t_org", "name=like:g%", "order=-id", "limit=5")
+	if strings.TrimSpace(out) == "" || strings.Contains(out, "acme") || !strings.Contains(out, "globex") {
+		t.Fatalf("query:\n%s", out)
+	}
+
+	fdsV2 := writeDescriptorSet(t, dir, "clitest2", true)
+	var stdout bytes.Buffer
+	err := run([]string{"diff", "-descriptor_set", fdsV2, "-dsn", dsn, "-tables", "t_member", "-exit-code"}, nil, &stdout)
+	if err != errDiffFound || !strings.Contains(stdout.String(), "-- t_member: 1 changes") || !strings.Contains(stdout.String(), `"note"`) {
+		t.Fatalf("diff of new column = %v:\n%s", err, stdout.String())
+	}
+
+	if out := runCmd(t, "", "drift", "-descriptor_set", fdsV1, "-dsn", dsn); strings.TrimSpace(out) != "" {
+		t.Fatalf("drift after migrate:\n%s", out)
+	}
+	stdout.Reset()
+	err = run([]string{"drift", "-descriptor_set", fdsV2, "-dsn", dsn, "-tables", "t_member"}, nil, &stdout)
+	if err != errDiffFound || !strings.Contains(stdout.String(), "critical t_member.note missing_column") {
+		t.Fatalf("drift of new column = %v:\n%s", err, stdout.String())
+	}
+
+	out = runCmd(t, "", "describe", "-descriptor_set", fdsV1, "-tables", "t_member")
+	if !strings.Contains(out, "t_member (clitest.Member)") || !strings.Contains(out, "ref:t_org(id)") || !strings.Contains(out, "primary key (id)") {
+		t.Fatalf("describe:\n%s", out)
+	}
+}
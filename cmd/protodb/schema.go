@@ -0,0 +1,360 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/ddl"
+	"github.com/ygrpc/protodb/service"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func runDdl(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("ddl", flag.ExitOnError)
+	opts := newOptions(fs)
+	withComment := fs.Bool("comment", true, "add comments of the messages and fields")
+	_ = fs.Parse(args)
+
+	msgDescs, err := opts.loadMsgs()
+	if err != nil {
+		return err
+	}
+	// only the dialect of the driver is used, -dsn is not needed
+	db, err := opts.openDB(false)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	msgs := make([]proto.Message, 0, len(msgDescs))
+	for _, msgDesc := range msgDescs {
+		msgs = append(msgs, dynamicpb.NewMessage(msgDesc))
+	}
+	ddlOfMsgs := *ddl.DefaultDdl
+	ddlOfMsgs.NoComment = !*withComment
+	items, err := ddlOfMsgs.GenerateSchemaSql(sqldb.GetDBDialect(db), opts.schema, msgs...)
+	if err != nil {
+		return err
+	}
+	return writeTablesSql(stdout, items, false)
+}
+
+func runMigrate(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	opts := newOptions(fs)
+	dryRun := fs.Bool("dry-run", false, "print the sql without executing it")
+	_ = fs.Parse(args)
+
+	msgDescs, err := opts.loadMsgs()
+	if err != nil {
+		return err
+	}
+	db, err := opts.openDB(true)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	items, err := migrateTablesSql(db, msgDescs, opts.schema)
+	if err != nil {
+		return err
+	}
+	if err := writeTablesSql(stdout, items, true); err != nil {
+		return err
+	}
+	if *dryRun {
+		return nil
+	}
+	return ddl.ExecSql(db, items)
+}
+
+func runDiff(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	opts := newOptions(fs)
+	exitCode := fs.Bool("exit-code", false, "exit with 1 if any table differs")
+	_ = fs.Parse(args)
+
+	msgDescs, err := opts.loadMsgs()
+	if err != nil {
+		return err
+	}
+	db, err := opts.openDB(true)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	items, err := migrateTablesSql(db, msgDescs, opts.schema)
+	if err != nil {
+		return err
+	}
+	changed := 0
+	for _, item := range items {
+		status := "up to date"
+		switch {
+		case !item.TableExists:
+			status = "missing"
+		case len(item.SqlStr) > 0:
+			status = fmt.Sprintf("%d changes", len(item.SqlStr))
+		}
+		if len(item.SqlStr) > 0 {
+			changed++
+		}
+		if _, err := fmt.Fprintf(stdout, "-- %s: %s\n", item.TableName, status); err != nil {
+			return err
+		}
+		if err := writeStatements(stdout, item.SqlStr); err != nil {
+			return err
+		}
+	}
+	if changed > 0 && *exitCode {
+		return errDiffFound
+	}
+	return nil
+}
+
+func runDrift(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("drift", flag.ExitOnError)
+	opts := newOptions(fs)
+	asJson := fs.Bool("json", false, "print the report as json")
+	failOn := fs.String("fail-on", "critical", "exit with 1 if any item is of this severity or above: info, warning, critical, none")
+	_ = fs.Parse(args)
+
+	minSeverity, fail := ddl.DriftCritical, true
+	switch *failOn {
+	case "info":
+		minSeverity = ddl.DriftInfo
+	case "warning":
+		minSeverity = ddl.DriftWarning
+	case "critical":
+	case "none":
+		fail = false
+	default:
+		return fmt.Errorf("unknown -fail-on %q", *failOn)
+	}
+
+	msgDescs, err := opts.loadMsgs()
+	if err != nil {
+		return err
+	}
+	db, err := opts.openDB(true)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	msgs := make([]proto.Message, 0, len(msgDescs))
+	for _, msgDesc := range msgDescs {
+		msgs = append(msgs, dynamicpb.NewMessage(msgDesc))
+	}
+	report, err := ddl.CheckDriftInSchema(db, opts.schema, msgs...)
+	if err != nil {
+		return err
+	}
+
+	if *asJson {
+		b, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(stdout, string(b)); err != nil {
+			return err
+		}
+	} else {
+		for _, item := range report.Items {
+			if _, err := fmt.Fprintln(stdout, item.String()); err != nil {
+				return err
+			}
+		}
+	}
+	if fail && report.Err(minSeverity) != nil {
+		return errDiffFound
+	}
+	return nil
+}
+
+func runDescribe(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("describe", flag.ExitOnError)
+	opts := newOptions(fs)
+	asJson := fs.Bool("json", false, "print DescribeSchemaResp as protojson")
+	_ = fs.Parse(args)
+
+	msgDescs, err := opts.loadMsgs()
+	if err != nil {
+		return err
+	}
+	// sql types and where operators only depend on the dialect
+	db, err := opts.openDB(false)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	req := &protodb.DescribeSchemaReq{SchemeName: opts.schema}
+	for _, msgDesc := range msgDescs {
+		req.TableNames = append(req.TableNames, string(msgDesc.FullName()))
+	}
+	resp, err := service.HandleDescribeSchema(context.Background(), nil, req,
+		func(meta http.Header, schemaName string, tableName string, writable bool) (sqldb.DB, error) {
+			return db, nil
+		},
+		func(meta http.Header, schemaName string, tableName string, db sqldb.DB, dbmsg proto.Message) error {
+			return nil
+		},
+		func(meta http.Header, schemaName string, queryName string) error {
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	if *asJson {
+		b, err := protojson.MarshalOptions{Multiline: true}.Marshal(resp)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(stdout, string(b))
+		return err
+	}
+	for _, table := range resp.Tables {
+		writeTableInfo(stdout, table)
+	}
+	return nil
+}
+
+// migrateTablesSql ddl.DbMigrateTable of msgDescs, the tables they reference are included. dependencies first
+func migrateTablesSql(db *sql.DB, msgDescs []protoreflect.MessageDescriptor, dbschema string) ([]*ddl.TDbTableInitSql, error) {
+	builtInitSqlMap := make(map[string]*ddl.TDbTableInitSql)
+	items := make([]*ddl.TDbTableInitSql, 0, len(msgDescs))
+	for _, msgDesc := range msgDescs {
+		item, err := ddl.DbMigrateTable(db, dynamicpb.NewMessage(msgDesc), dbschema, true, false, builtInitSqlMap)
+		if err != nil {
+			return nil, fmt.Errorf("table %s: %w", msgDesc.FullName(), err)
+		}
+		items = append(items, item)
+	}
+	return orderTablesSql(items), nil
+}
+
+// orderTablesSql flatten items with their dependencies, dependencies first and each table once
+func orderTablesSql(items []*ddl.TDbTableInitSql) []*ddl.TDbTableInitSql {
+	ordered := make([]*ddl.TDbTableInitSql, 0, len(items))
+	visited := make(map[string]bool)
+	var visit func(item *ddl.TDbTableInitSql)
+	visit = func(item *ddl.TDbTableInitSql) {
+		if visited[item.TableName] {
+			return
+		}
+		visited[item.TableName] = true
+		for _, depName := range item.DepTableNames {
+			if dep := item.DepTableSqlItemMap[depName]; dep != nil {
+				visit(dep)
+			}
+		}
+		ordered = append(ordered, item)
+	}
+	for _, item := range items {
+		visit(item)
+	}
+	return ordered
+}
+
+// writeTablesSql statements of items, tables without statements are skipped if skipEmpty
+func writeTablesSql(w io.Writer, items []*ddl.TDbTableInitSql, skipEmpty bool) error {
+	for _, item := range items {
+		if skipEmpty && len(item.SqlStr) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "-- %s\n", item.TableName); err != nil {
+			return err
+		}
+		if err := writeStatements(w, item.SqlStr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeStatements(w io.Writer, stmts []string) error {
+	for _, stmt := range stmts {
+		stmt = strings.TrimSpace(stmt)
+		if !strings.HasSuffix(stmt, ";") {
+			stmt += ";"
+		}
+		if _, err := fmt.Fprintln(w, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTableInfo table info as aligned text
+func writeTableInfo(w io.Writer, table *protodb.TableInfo) {
+	fmt.Fprintf(w, "%s (%s)\n", table.TableName, table.MsgName)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "  COLUMN\tSQL TYPE\tPROTO\tFLAGS\tOPERATORS")
+	for _, column := range table.Columns {
+		protoType := column.ProtoKind
+		if len(column.TypeName) > 0 {
+			protoType = column.TypeName
+		}
+		switch {
+		case column.IsList:
+			protoType = "repeated " + protoType
+		case column.IsMap:
+			protoType = "map<" + protoType + ">"
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\n", column.Name, column.SQLType, protoType, strings.Join(columnFlags(column), ","), strings.Join(operatorNames(column.Operators), ","))
+	}
+	_ = tw.Flush()
+	if len(table.PrimaryKey) > 0 {
+		fmt.Fprintf(w, "  primary key (%s)\n", strings.Join(table.PrimaryKey, ", "))
+	}
+	for _, uniqueKey := range table.UniqueKeys {
+		fmt.Fprintf(w, "  unique %s(%s)\n", uniqueKey.Name, strings.Join(uniqueKey.Columns, ", "))
+	}
+	fmt.Fprintln(w)
+}
+
+func columnFlags(column *protodb.ColumnInfo) []string {
+	var flags []string
+	for _, flag := range []struct {
+		set  bool
+		name string
+	}{
+		{column.Primary, "primary"},
+		{column.Unique, "unique"},
+		{column.NotNull, "not_null"},
+		{column.NoInsert, "no_insert"},
+		{column.NoUpdate, "no_update"},
+	} {
+		if flag.set {
+			flags = append(flags, flag.name)
+		}
+	}
+	if len(column.Reference) > 0 {
+		flags = append(flags, "ref:"+column.Reference)
+	}
+	return flags
+}
+
+// operatorNames where operator names without WOP_, the form of query conditions
+func operatorNames(ops []protodb.WhereOperator) []string {
+	names := make([]string, 0, len(ops))
+	for _, op := range ops {
+		names = append(names, strings.ToLower(strings.TrimPrefix(op.String(), "WOP_")))
+	}
+	return names
+}
@@ -187,13 +187,13 @@ func TestTableQueryBuildSql_AllowsLowercaseTableAndWhereFields(t *testing.T) {
 	if err != nil {
 		t.Fatalf("TableQueryBuildSql: %v", err)
 	}
-	if !strings.Contains(sqlStr, " FROM tablequeryreq") {
+	if !strings.Contains(sqlStr, ` FROM "tablequeryreq"`) {
 		t.Fatalf("expected lowercase table name in sql, got %s", sqlStr)
 	}
-	if !strings.Contains(sqlStr, "tablename = $1") {
+	if !strings.Contains(sqlStr, `"tablename" = $1`) {
 		t.Fatalf("expected lowercase where column in sql, got %s", sqlStr)
 	}
-	if !strings.Contains(sqlStr, "limit > $2") {
+	if !strings.Contains(sqlStr, `"limit" > $2`) {
 		t.Fatalf("expected lowercase where2 column in sql, got %s", sqlStr)
 	}
 	if len(vals) != 2 || vals[0] != "abc" || vals[1] != "10" {
@@ -47,7 +47,7 @@ func TestTableQueryBuildSql_ArrayContains_Postgres(t *testing.T) {
 	if err != nil {
 		t.Fatalf("TableQueryBuildSql: %v", err)
 	}
-	if !strings.Contains(sqlStr, "ResultColumnNames @> ARRAY[$1]::text[]") {
+	if !strings.Contains(sqlStr, `"resultcolumnnames" @> ARRAY[$1]::text[]`) {
 		t.Fatalf("unexpected sql: %s", sqlStr)
 	}
 	if len(vals) != 1 || vals[0] != "abc" {
@@ -74,7 +74,7 @@ func TestTableQueryBuildSql_ArrayContains_Mysql(t *testing.T) {
 	if err != nil {
 		t.Fatalf("TableQueryBuildSql: %v", err)
 	}
-	if !strings.Contains(sqlStr, "JSON_CONTAINS(ResultColumnNames, JSON_ARRAY(?))") {
+	if !strings.Contains(sqlStr, "JSON_CONTAINS(`ResultColumnNames`, JSON_ARRAY(?))") {
 		t.Fatalf("unexpected sql: %s", sqlStr)
 	}
 	if len(vals) != 1 || vals[0] != "abc" {
@@ -101,7 +101,7 @@ func TestTableQueryBuildSql_ArrayOverlap_SQLite(t *testing.T) {
 	if err != nil {
 		t.Fatalf("TableQueryBuildSql: %v", err)
 	}
-	if !strings.Contains(sqlStr, "json_each(ResultColumnNames)") {
+	if !strings.Contains(sqlStr, `json_each("ResultColumnNames")`) {
 		t.Fatalf("unexpected sql: %s", sqlStr)
 	}
 	if len(vals) != 1 || vals[0] != "[\"a\",\"b\"]" {
@@ -128,7 +128,7 @@ func TestTableQueryBuildSql_ArrayOverlap_Mysql(t *testing.T) {
 	if err != nil {
 		t.Fatalf("TableQueryBuildSql: %v", err)
 	}
-	if !strings.Contains(sqlStr, "JSON_OVERLAPS(ResultColumnNames, CAST(? AS JSON))") {
+	if !strings.Contains(sqlStr, "JSON_OVERLAPS(`ResultColumnNames`, CAST(? AS JSON))") {
 		t.Fatalf("unexpected sql: %s", sqlStr)
 	}
 	if len(vals) != 1 || vals[0] != "[\"a\",\"b\"]" {
@@ -155,7 +155,7 @@ func TestTableQueryBuildSql_ArrayOverlap_Postgres(t *testing.T) {
 	if err != nil {
 		t.Fatalf("TableQueryBuildSql: %v", err)
 	}
-	if !strings.Contains(sqlStr, "ResultColumnNames && $1") {
+	if !strings.Contains(sqlStr, `"resultcolumnnames" && $1`) {
 		t.Fatalf("unexpected sql: %s", sqlStr)
 	}
 	if len(vals) != 1 {
@@ -185,7 +185,7 @@ func TestTableQueryBuildSql_ArrayContainsAll_Postgres(t *testing.T) {
 	if err != nil {
 		t.Fatalf("TableQueryBuildSql: %v", err)
 	}
-	if !strings.Contains(sqlStr, "ResultColumnNames @> $1") {
+	if !strings.Contains(sqlStr, `"resultcolumnnames" @> $1`) {
 		t.Fatalf("unexpected sql: %s", sqlStr)
 	}
 	if len(vals) != 1 {
@@ -215,7 +215,7 @@ func TestTableQueryBuildSql_ArrayContainsAll_Mysql(t *testing.T) {
 	if err != nil {
 		t.Fatalf("TableQueryBuildSql: %v", err)
 	}
-	if !strings.Contains(sqlStr, "JSON_CONTAINS(ResultColumnNames, CAST(? AS JSON))") {
+	if !strings.Contains(sqlStr, "JSON_CONTAINS(`ResultColumnNames`, CAST(? AS JSON))") {
 		t.Fatalf("unexpected sql: %s", sqlStr)
 	}
 	if len(vals) != 1 || vals[0] != "[\"a\",\"b\"]" {
@@ -242,7 +242,7 @@ func TestTableQueryBuildSql_ArrayLenGT_Postgres(t *testing.T) {
 	if err != nil {
 		t.Fatalf("TableQueryBuildSql: %v", err)
 	}
-	if !strings.Contains(sqlStr, "cardinality(ResultColumnNames) > $1") {
+	if !strings.Contains(sqlStr, `cardinality("resultcolumnnames") > $1`) {
 		t.Fatalf("unexpected sql: %s", sqlStr)
 	}
 	if len(vals) != 1 || !reflect.DeepEqual(vals[0], int64(2)) {
@@ -269,7 +269,7 @@ func TestTableQueryBuildSql_ArrayLenGT_Mysql(t *testing.T) {
 	if err != nil {
 		t.Fatalf("TableQueryBuildSql: %v", err)
 	}
-	if !strings.Contains(sqlStr, "JSON_LENGTH(ResultColumnNames) > ?") {
+	if !strings.Contains(sqlStr, "JSON_LENGTH(`ResultColumnNames`) > ?") {
 		t.Fatalf("unexpected sql: %s", sqlStr)
 	}
 	if len(vals) != 1 || !reflect.DeepEqual(vals[0], int64(2)) {
@@ -296,7 +296,7 @@ func TestTableQueryBuildSql_ArrayLenGTE_Postgres(t *testing.T) {
 	if err != nil {
 		t.Fatalf("TableQueryBuildSql: %v", err)
 	}
-	if !strings.Contains(sqlStr, "cardinality(ResultColumnNames) >= $1") {
+	if !strings.Contains(sqlStr, `cardinality("resultcolumnnames") >= $1`) {
 		t.Fatalf("unexpected sql: %s", sqlStr)
 	}
 	if len(vals) != 1 || !reflect.DeepEqual(vals[0], int64(3)) {
@@ -323,7 +323,7 @@ func TestTableQueryBuildSql_ArrayLenLT_SQLite(t *testing.T) {
 	if err != nil {
 		t.Fatalf("TableQueryBuildSql: %v", err)
 	}
-	if !strings.Contains(sqlStr, "json_array_length(ResultColumnNames) < ?") {
+	if !strings.Contains(sqlStr, `json_array_length("ResultColumnNames") < ?`) {
 		t.Fatalf("unexpected sql: %s", sqlStr)
 	}
 	if len(vals) != 1 || !reflect.DeepEqual(vals[0], int64(4)) {
@@ -350,7 +350,7 @@ func TestTableQueryBuildSql_ArrayLenLTE_SQLite(t *testing.T) {
 	if err != nil {
 		t.Fatalf("TableQueryBuildSql: %v", err)
 	}
-	if !strings.Contains(sqlStr, "json_array_length(ResultColumnNames) <= ?") {
+	if !strings.Contains(sqlStr, `json_array_length("ResultColumnNames") <= ?`) {
 		t.Fatalf("unexpected sql: %s", sqlStr)
 	}
 	if len(vals) != 1 || !reflect.DeepEqual(vals[0], int64(5)) {
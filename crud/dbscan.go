@@ -13,12 +13,29 @@ import (
 
 	"github.com/ygrpc/protodb/msgstore"
 	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/sqldb"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/dynamicpb"
 )
 
+// msgStoreOr store, msgstore.GlobalMsgStore if nil
+func msgStoreOr(store sqldb.MsgStore) sqldb.MsgStore {
+	if store == nil {
+		return msgstore.GlobalMsgStore
+	}
+	return store
+}
+
+// msgStoreOf msg store of executor, set by DBWithDialect.MsgStore
+func msgStoreOf(executor sqldb.DB) sqldb.MsgStore {
+	if e, ok := executor.(*sqldb.DBWithDialect); ok {
+		return e.MsgStore
+	}
+	return nil
+}
+
 // interface FieldProtoMsg
 type FieldProtoMsg interface {
 	FieldProtoMsg(fieldName string) (proto.Message, bool)
@@ -26,21 +43,22 @@ type FieldProtoMsg interface {
 
 // allocScanDest 根据 protobuf 字段类型选择最优的 scan dest 类型，消除 interface{} 装箱
 func allocScanDest(fd protoreflect.FieldDescriptor) any {
-	if fd.IsMap() {
-		return new(sql.NullString)
-	}
-	if fd.IsList() {
-		return new(sql.NullString)
+	if fd.IsMap() || fd.IsList() {
+		return new(nullComposite)
 	}
 	switch fd.Kind() {
 	case protoreflect.StringKind:
 		return new(sql.NullString)
 	case protoreflect.BoolKind:
 		return new(sql.NullBool)
+	case protoreflect.EnumKind:
+		if pdbutil.IsEnumStoredAsName(fd) {
+			return new(sql.NullString)
+		}
+		return new(sql.NullInt64)
 	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
 		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
-		protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
-		protoreflect.EnumKind:
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
 		return new(sql.NullInt64)
 	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
 		return new(nullUint64)
@@ -49,7 +67,10 @@ func allocScanDest(fd protoreflect.FieldDescriptor) any {
 	case protoreflect.BytesKind:
 		return new(nullBytes)
 	case protoreflect.MessageKind:
-		return new(sql.NullString)
+		if isWellKnownField(fd) {
+			return allocWellKnownScanDest(fd)
+		}
+		return new(nullJSONText)
 	default:
 		return new(any)
 	}
@@ -60,6 +81,53 @@ type nullBytes struct {
 	Valid bool
 }
 
+// nullComposite scan dest of list/map column, json or array text, or native list/map of the driver(duckdb)
+type nullComposite struct {
+	Value any
+	Valid bool
+}
+
+func (n *nullComposite) Scan(src any) error {
+	switch x := src.(type) {
+	case nil:
+		n.Value = nil
+		n.Valid = false
+		return nil
+	case []byte:
+		// the driver may reuse the buffer
+		n.Value = string(x)
+	case json.Marshaler:
+		// map type of the driver like duckdb.OrderedMap, scanned as json text
+		b, err := x.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		n.Value = string(b)
+	default:
+		n.Value = x
+	}
+	n.Valid = true
+	return nil
+}
+
+// nullJSONText scan dest of message column, json text or the json value decoded by the driver(duckdb JSON)
+type nullJSONText struct {
+	sql.NullString
+}
+
+func (n *nullJSONText) Scan(src any) error {
+	switch src.(type) {
+	case nil, string, []byte:
+		return n.NullString.Scan(src)
+	}
+	b, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	n.String, n.Valid = string(b), true
+	return nil
+}
+
 type nullUint64 struct {
 	Uint64 uint64
 	Valid  bool
@@ -117,7 +185,7 @@ func canFallbackDirectError(err error) bool {
 }
 
 // setProtoMsgFieldDirect 直接通过 protoreflect API 设置字段，绕过 pdbutil.SetField 的反射开销
-func setProtoMsgFieldDirect(msg proto.Message, fd protoreflect.FieldDescriptor, v any) error {
+func setProtoMsgFieldDirect(store sqldb.MsgStore, msg proto.Message, fd protoreflect.FieldDescriptor, v any) error {
 	if fd.IsMap() {
 		return setProtoMsgMapField(msg, fd, v)
 	}
@@ -125,15 +193,27 @@ func setProtoMsgFieldDirect(msg proto.Message, fd protoreflect.FieldDescriptor,
 		return setProtoMsgListField(msg, fd, v)
 	}
 	if fd.Kind() == protoreflect.MessageKind {
-		return setProtoMsgFieldMessage(msg, fd, unwrapScanVal(v))
+		if isWellKnownField(fd) {
+			return setProtoMsgWellKnownField(msg, fd, unwrapScanVal(v))
+		}
+		val := unwrapScanVal(v)
+		if val == nil && pdbutil.GetRealOneof(fd) != nil {
+			// NULL oneof member is not set, keep the other member
+			return nil
+		}
+		return setProtoMsgFieldMessage(store, msg, fd, val)
 	}
 
+	pm := msg.ProtoReflect()
 	val := unwrapScanVal(v)
 	if val == nil {
+		// NULL leaves optional field unset
+		if hasScalarPresence(fd) {
+			pm.Clear(fd)
+		}
 		return nil
 	}
 
-	pm := msg.ProtoReflect()
 	switch fd.Kind() {
 	case protoreflect.BoolKind:
 		switch x := val.(type) {
@@ -187,12 +267,23 @@ func setProtoMsgFieldDirect(msg proto.Message, fd protoreflect.FieldDescriptor,
 		}
 		pm.Set(fd, protoreflect.ValueOfFloat64(f))
 	case protoreflect.StringKind:
+		var str string
 		switch x := val.(type) {
 		case string:
-			pm.Set(fd, protoreflect.ValueOfString(x))
+			str = x
+		case []byte:
+			str = string(x)
 		default:
-			pm.Set(fd, protoreflect.ValueOfString(fmt.Sprint(val)))
+			str = fmt.Sprint(val)
 		}
+		if numericPdb, ok := numericFieldPdb(fd); ok {
+			normalized, err := NormalizeNumericString(str, 0, numericPdb.NumericScale)
+			if err != nil {
+				return noFallback(fmt.Errorf("numeric field %s: %w", fd.Name(), err))
+			}
+			str = normalized
+		}
+		pm.Set(fd, protoreflect.ValueOfString(str))
 	case protoreflect.BytesKind:
 		switch x := val.(type) {
 		case []byte:
@@ -207,6 +298,16 @@ func setProtoMsgFieldDirect(msg proto.Message, fd protoreflect.FieldDescriptor,
 			return fmt.Errorf("bytes field expects []byte/string, got %T", val)
 		}
 	case protoreflect.EnumKind:
+		switch x := val.(type) {
+		case string, []byte:
+			// enum stored by value name
+			n, err := enumNumberOf(fd, x)
+			if err != nil {
+				return noFallback(err)
+			}
+			pm.Set(fd, protoreflect.ValueOfEnum(n))
+			return nil
+		}
 		i, err := toInt64(val)
 		if err != nil {
 			return err
@@ -222,7 +323,7 @@ func setProtoMsgFieldDirect(msg proto.Message, fd protoreflect.FieldDescriptor,
 }
 
 // setProtoMsgFieldMessage 设置嵌套消息字段（直接路径的辅助函数）
-func setProtoMsgFieldMessage(msg proto.Message, fd protoreflect.FieldDescriptor, v any) error {
+func setProtoMsgFieldMessage(store sqldb.MsgStore, msg proto.Message, fd protoreflect.FieldDescriptor, v any) error {
 	fieldName := fd.TextName()
 	filedProtoMsg, ok := msg.(FieldProtoMsg)
 	if ok {
@@ -241,9 +342,9 @@ func setProtoMsgFieldMessage(msg proto.Message, fd protoreflect.FieldDescriptor,
 
 	fieldMsgProto := fd.Message()
 	fieldMsgName := string(fieldMsgProto.Name())
-	fieldMsg, fieldMsgOk := msgstore.GetFieldMsg(fieldMsgName, true)
-	if !fieldMsgOk {
-		return fmt.Errorf("can't get filed proto msg for field %s.%s, you can register using msgstore.RegisterFieldMsg", fieldMsgProto.Name(), fieldName)
+	fieldMsg, fieldMsgOk := msgStoreOr(store).GetFieldMsg(fieldMsgName, true)
+	if !fieldMsgOk || isDynamicMsg(msg) {
+		return setProtoMsgNewFieldMsg(msg, fd, v)
 	}
 	err := Val2ProtoMsgByJson(fieldMsg, v)
 	if err != nil {
@@ -253,18 +354,41 @@ func setProtoMsgFieldMessage(msg proto.Message, fd protoreflect.FieldDescriptor,
 }
 
 type DbRowScanner struct {
+	// MsgStore field messages are got from it, msgstore.GlobalMsgStore if nil
+	MsgStore sqldb.MsgStore
+
 	columnNames []string
 	fieldDescs  []protoreflect.FieldDescriptor
-	rowVals     []any
+	// field path of flattened sub message columns, nil for normal columns
+	flatPaths [][]protoreflect.FieldDescriptor
+	// oneof of discriminator columns, nil for normal columns
+	oneofs []protoreflect.OneofDescriptor
+	// setters of columns bound by the generated RowBinder, nil if msg has none
+	setters []func(msg proto.Message) error
+	rowVals []any
 }
 
 type DbRowPairScanner struct {
+	// MsgStore field messages are got from it, msgstore.GlobalMsgStore if nil
+	MsgStore sqldb.MsgStore
+
 	columnNames     []string
 	fieldDescs      []protoreflect.FieldDescriptor
+	flatPaths       [][]protoreflect.FieldDescriptor
+	oneofs          []protoreflect.OneofDescriptor
 	rowVals         []any
 	halfColumnCount int
 }
 
+// scanFlatPath field path if the column is a flattened sub message column
+func scanFlatPath(msgDesc protoreflect.MessageDescriptor, columnName string, fd protoreflect.FieldDescriptor) []protoreflect.FieldDescriptor {
+	column, ok := pdbutil.FindFlatColumn(msgDesc, columnName)
+	if !ok || column.Field() != fd {
+		return nil
+	}
+	return column.Path
+}
+
 func NewDbRowScanner(rows *sql.Rows, msg proto.Message, columnNames []string, msgFieldsMap map[string]protoreflect.FieldDescriptor) (*DbRowScanner, error) {
 	var err error
 	if columnNames == nil {
@@ -276,13 +400,39 @@ func NewDbRowScanner(rows *sql.Rows, msg proto.Message, columnNames []string, ms
 	if msgFieldsMap == nil {
 		msgFieldsMap = pdbutil.BuildMsgFieldsMap(columnNames, msg.ProtoReflect().Descriptor().Fields(), true)
 	}
+	msgDesc := msg.ProtoReflect().Descriptor()
+	rowBinder := getRowBinder(msg)
 	fieldDescs := make([]protoreflect.FieldDescriptor, len(columnNames))
+	var flatPaths [][]protoreflect.FieldDescriptor
+	var oneofs []protoreflect.OneofDescriptor
+	var setters []func(msg proto.Message) error
 	rowVals := make([]any, len(columnNames))
 	for i := range rowVals {
 		fd, ok := msgFieldsMap[strings.ToLower(columnNames[i])]
 		if ok {
 			fieldDescs[i] = fd
+			if path := scanFlatPath(msgDesc, columnNames[i], fd); path != nil {
+				if flatPaths == nil {
+					flatPaths = make([][]protoreflect.FieldDescriptor, len(columnNames))
+				}
+				flatPaths[i] = path
+			} else if rowBinder != nil && fd.ContainingMessage() == msgDesc {
+				if binder, bound := rowBinder(fd.Number()); bound {
+					if setters == nil {
+						setters = make([]func(msg proto.Message) error, len(columnNames))
+					}
+					setters[i] = binder.Set
+					rowVals[i] = binder.Dest
+					continue
+				}
+			}
 			rowVals[i] = allocScanDest(fd)
+		} else if oneofDesc, isDiscriminator := pdbutil.FindOneofByDiscriminator(msgDesc, columnNames[i]); isDiscriminator {
+			if oneofs == nil {
+				oneofs = make([]protoreflect.OneofDescriptor, len(columnNames))
+			}
+			oneofs[i] = oneofDesc
+			rowVals[i] = new(sql.NullString)
 		} else {
 			rowVals[i] = new(any)
 		}
@@ -290,6 +440,9 @@ func NewDbRowScanner(rows *sql.Rows, msg proto.Message, columnNames []string, ms
 	return &DbRowScanner{
 		columnNames: columnNames,
 		fieldDescs:  fieldDescs,
+		flatPaths:   flatPaths,
+		oneofs:      oneofs,
+		setters:     setters,
 		rowVals:     rowVals,
 	}, nil
 }
@@ -303,31 +456,59 @@ func (s *DbRowScanner) Scan(rows *sql.Rows, msg proto.Message) error {
 	for i := 0; i < len(s.columnNames); i++ {
 		fieldDesc := s.fieldDescs[i]
 		if fieldDesc == nil {
-			fmt.Println("DbRowScanner field not found in msgFieldsMap :", s.columnNames[i])
+			if s.oneofs == nil || s.oneofs[i] == nil {
+				fmt.Println("DbRowScanner field not found in msgFieldsMap :", s.columnNames[i])
+			}
+			continue
+		}
+		if s.setters != nil && s.setters[i] != nil {
+			if err = s.setters[i](msg); err != nil {
+				return fmt.Errorf("column %s: %w", s.columnNames[i], err)
+			}
+			continue
+		}
+		if s.flatPaths != nil && s.flatPaths[i] != nil {
+			if err = setFlatColumnValue(s.MsgStore, msg, s.flatPaths[i], s.rowVals[i]); err != nil {
+				return err
+			}
 			continue
 		}
-		err = setProtoMsgFieldDirect(msg, fieldDesc, s.rowVals[i])
+		err = setProtoMsgFieldDirect(s.MsgStore, msg, fieldDesc, s.rowVals[i])
 		if err != nil {
 			fmt.Println("DbRowScanner setProtoMsgFieldDirect err:", err)
 			if !canFallbackDirectError(err) {
 				return err
 			}
-			err = SetProtoMsgField(msg, fieldDesc, unwrapScanVal(s.rowVals[i]))
+			err = SetProtoMsgFieldOfStore(s.MsgStore, msg, fieldDesc, unwrapScanVal(s.rowVals[i]))
 			if err != nil {
 				fmt.Println("DbRowScanner SetProtoMsgField err:", err)
 				return err
 			}
 		}
 	}
+	for i, oneofDesc := range s.oneofs {
+		if oneofDesc == nil {
+			continue
+		}
+		if err = applyOneofDiscriminator(msg, oneofDesc, s.rowVals[i]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // DbScan2ProtoMsg scan db rows to proto message, the proto msg has no nested message
 func DbScan2ProtoMsg(rows *sql.Rows, msg proto.Message, columnNames []string, msgFieldsMap map[string]protoreflect.FieldDescriptor) (err error) {
+	return dbScan2ProtoMsg(nil, rows, msg, columnNames, msgFieldsMap)
+}
+
+// dbScan2ProtoMsg DbScan2ProtoMsg with field messages got from store
+func dbScan2ProtoMsg(store sqldb.MsgStore, rows *sql.Rows, msg proto.Message, columnNames []string, msgFieldsMap map[string]protoreflect.FieldDescriptor) (err error) {
 	scanner, err := NewDbRowScanner(rows, msg, columnNames, msgFieldsMap)
 	if err != nil {
 		return err
 	}
+	scanner.MsgStore = store
 	return scanner.Scan(rows, msg)
 }
 
@@ -375,7 +556,24 @@ func Val2ProtoMsgByJson(msg proto.Message, value interface{}) error {
 	return unmarshalOpts.Unmarshal(b, msg) // Using configured options
 }
 
+// setProtoMsgNewFieldMsg decode v into a new message of the field type of msg, used when the field message
+// is not registered by RegisterFieldMsg of the msg store and for dynamicpb messages
+func setProtoMsgNewFieldMsg(msg proto.Message, fd protoreflect.FieldDescriptor, v any) error {
+	pm := msg.ProtoReflect()
+	fieldMsg := pm.NewField(fd).Message()
+	if err := Val2ProtoMsgByJson(fieldMsg.Interface(), v); err != nil {
+		return fmt.Errorf("Val2ProtoMsgByJson err:%s for field %s.%s val:%v", err.Error(), fd.Message().Name(), fd.TextName(), v)
+	}
+	pm.Set(fd, protoreflect.ValueOfMessage(fieldMsg))
+	return nil
+}
+
 func SetProtoMsgField(msg proto.Message, fieldDesc protoreflect.FieldDescriptor, fieldVal interface{}) error {
+	return SetProtoMsgFieldOfStore(nil, msg, fieldDesc, fieldVal)
+}
+
+// SetProtoMsgFieldOfStore SetProtoMsgField with field messages got from store, msgstore.GlobalMsgStore if nil
+func SetProtoMsgFieldOfStore(store sqldb.MsgStore, msg proto.Message, fieldDesc protoreflect.FieldDescriptor, fieldVal interface{}) error {
 	fieldName := fieldDesc.TextName()
 	if fieldDesc.IsMap() {
 		return setProtoMsgMapField(msg, fieldDesc, fieldVal)
@@ -383,6 +581,9 @@ func SetProtoMsgField(msg proto.Message, fieldDesc protoreflect.FieldDescriptor,
 	if fieldDesc.IsList() {
 		return setProtoMsgListField(msg, fieldDesc, fieldVal)
 	}
+	if isWellKnownField(fieldDesc) {
+		return setProtoMsgWellKnownField(msg, fieldDesc, unwrapScanVal(fieldVal))
+	}
 	if fieldDesc.Kind() == protoreflect.MessageKind {
 		// get filed proto msg by filedprotomsg interface
 		filedProtoMsg, ok := msg.(FieldProtoMsg)
@@ -403,9 +604,9 @@ func SetProtoMsgField(msg proto.Message, fieldDesc protoreflect.FieldDescriptor,
 		//get filed proto msg by filedmsgstore
 		fieldMsgProto := fieldDesc.Message()
 		fieldMsgName := string(fieldMsgProto.Name())
-		fieldMsg, fieldMsgOk := msgstore.GetFieldMsg(fieldMsgName, true)
-		if !fieldMsgOk {
-			return fmt.Errorf("can't get filed proto msg for field %s.%s, you can register using msgstore.RegisterFieldMsg", fieldMsgProto.Name(), fieldName)
+		fieldMsg, fieldMsgOk := msgStoreOr(store).GetFieldMsg(fieldMsgName, true)
+		if !fieldMsgOk || isDynamicMsg(msg) {
+			return setProtoMsgNewFieldMsg(msg, fieldDesc, fieldVal)
 		}
 		err := Val2ProtoMsgByJson(fieldMsg, fieldVal)
 		if err != nil {
@@ -454,6 +655,11 @@ func unwrapScanVal(v any) any {
 			return nil
 		}
 		return x.String
+	case *nullJSONText:
+		if x == nil || !x.Valid {
+			return nil
+		}
+		return x.String
 	case sql.NullString:
 		if !x.Valid {
 			return nil
@@ -499,6 +705,26 @@ func unwrapScanVal(v any) any {
 			return nil
 		}
 		return x.Bytes
+	case *sql.NullTime:
+		if x == nil || !x.Valid {
+			return nil
+		}
+		return x.Time
+	case sql.NullTime:
+		if !x.Valid {
+			return nil
+		}
+		return x.Time
+	case *nullComposite:
+		if x == nil || !x.Valid {
+			return nil
+		}
+		return x.Value
+	case nullComposite:
+		if !x.Valid {
+			return nil
+		}
+		return x.Value
 	case *nullUint64:
 		if x == nil || !x.Valid {
 			return nil
@@ -540,7 +766,10 @@ func setProtoMsgMapField(msg proto.Message, fieldDesc protoreflect.FieldDescript
 	case []byte:
 		b = x
 	default:
-		return fmt.Errorf("map scan expects json string/bytes, got %T", v)
+		if mv := reflect.ValueOf(v); mv.Kind() == reflect.Map {
+			return setNativeMapField(m, fieldDesc, mv)
+		}
+		return fmt.Errorf("map scan expects json string/bytes or map, got %T", v)
 	}
 	if len(bytes.TrimSpace(b)) == 0 {
 		return nil
@@ -584,6 +813,44 @@ func setProtoMsgMapField(msg proto.Message, fieldDesc protoreflect.FieldDescript
 	return nil
 }
 
+// setNativeMapField set map field from native map of the driver(duckdb MAP), message values are json text
+func setNativeMapField(m protoreflect.Map, fieldDesc protoreflect.FieldDescriptor, mv reflect.Value) error {
+	keyDesc := fieldDesc.MapKey()
+	valDesc := fieldDesc.MapValue()
+	unmarshalOpts := protojson.UnmarshalOptions{DiscardUnknown: true}
+	iter := mv.MapRange()
+	for iter.Next() {
+		mk, err := parseMapKeyFromString(keyDesc.Kind(), fmt.Sprint(iter.Key().Interface()))
+		if err != nil {
+			return err
+		}
+		val := iter.Value().Interface()
+		if valDesc.Kind() == protoreflect.MessageKind {
+			var raw []byte
+			switch x := val.(type) {
+			case string:
+				raw = []byte(x)
+			case []byte:
+				raw = x
+			default:
+				return fmt.Errorf("map message value expects json string/bytes, got %T", val)
+			}
+			elemMsg := dynamicpb.NewMessage(valDesc.Message())
+			if err := unmarshalOpts.Unmarshal(raw, elemMsg); err != nil {
+				return err
+			}
+			m.Set(mk, protoreflect.ValueOfMessage(elemMsg.ProtoReflect()))
+			continue
+		}
+		pv, err := scalarElemToProtoreflectValue(valDesc.Kind(), val)
+		if err != nil {
+			return err
+		}
+		m.Set(mk, pv)
+	}
+	return nil
+}
+
 func decodeJSONAny(raw json.RawMessage) (any, error) {
 	dec := json.NewDecoder(bytes.NewReader(raw))
 	dec.UseNumber()
@@ -1005,7 +1272,10 @@ func NewDbRowPairScanner(rows *sql.Rows, oldMsg proto.Message, columnNames []str
 	}
 
 	halfColumnCount := len(columnNames) / 2
+	msgDesc := oldMsg.ProtoReflect().Descriptor()
 	fieldDescs := make([]protoreflect.FieldDescriptor, halfColumnCount)
+	var flatPaths [][]protoreflect.FieldDescriptor
+	var oneofs []protoreflect.OneofDescriptor
 	rowVals := make([]any, len(columnNames))
 	for i := 0; i < halfColumnCount; i++ {
 		// x2 模式下列数翻倍，前半给 oldMsg，后半给 newMsg。
@@ -1016,6 +1286,19 @@ func NewDbRowPairScanner(rows *sql.Rows, oldMsg proto.Message, columnNames []str
 			fieldDescs[i] = fd
 			rowVals[i] = allocScanDest(fd)
 			rowVals[i+halfColumnCount] = allocScanDest(fd)
+			if path := scanFlatPath(msgDesc, columnName, fd); path != nil {
+				if flatPaths == nil {
+					flatPaths = make([][]protoreflect.FieldDescriptor, halfColumnCount)
+				}
+				flatPaths[i] = path
+			}
+		} else if oneofDesc, isDiscriminator := pdbutil.FindOneofByDiscriminator(msgDesc, columnName); isDiscriminator {
+			if oneofs == nil {
+				oneofs = make([]protoreflect.OneofDescriptor, halfColumnCount)
+			}
+			oneofs[i] = oneofDesc
+			rowVals[i] = new(sql.NullString)
+			rowVals[i+halfColumnCount] = new(sql.NullString)
 		} else {
 			rowVals[i] = new(any)
 			rowVals[i+halfColumnCount] = new(any)
@@ -1025,6 +1308,8 @@ func NewDbRowPairScanner(rows *sql.Rows, oldMsg proto.Message, columnNames []str
 	return &DbRowPairScanner{
 		columnNames:     columnNames,
 		fieldDescs:      fieldDescs,
+		flatPaths:       flatPaths,
+		oneofs:          oneofs,
 		rowVals:         rowVals,
 		halfColumnCount: halfColumnCount,
 	}, nil
@@ -1044,30 +1329,42 @@ func (s *DbRowPairScanner) Scan(rows *sql.Rows, oldMsg proto.Message, newMsg pro
 	for i := 0; i < len(columnNamesOld); i++ {
 		fieldDesc := s.fieldDescs[i]
 		if fieldDesc == nil {
-			fmt.Println("DbScan2ProtoMsgx2 field not found in msgFieldsMap :", columnNamesOld[i])
+			if s.oneofs == nil || s.oneofs[i] == nil {
+				fmt.Println("DbScan2ProtoMsgx2 field not found in msgFieldsMap :", columnNamesOld[i])
+			}
 			continue
 		}
 
-		err = setProtoMsgFieldDirect(oldMsg, fieldDesc, oldVals[i])
+		if s.flatPaths != nil && s.flatPaths[i] != nil {
+			if err = setFlatColumnValue(s.MsgStore, oldMsg, s.flatPaths[i], oldVals[i]); err != nil {
+				return err
+			}
+			if err = setFlatColumnValue(s.MsgStore, newMsg, s.flatPaths[i], newVals[i]); err != nil {
+				return err
+			}
+			continue
+		}
+
+		err = setProtoMsgFieldDirect(s.MsgStore, oldMsg, fieldDesc, oldVals[i])
 		if err != nil {
 			fmt.Println("DbScan2ProtoMsgx2 setProtoMsgFieldDirect old err:", err)
 			if !canFallbackDirectError(err) {
 				return err
 			}
-			err = SetProtoMsgField(oldMsg, fieldDesc, unwrapScanVal(oldVals[i]))
+			err = SetProtoMsgFieldOfStore(s.MsgStore, oldMsg, fieldDesc, unwrapScanVal(oldVals[i]))
 			if err != nil {
 				fmt.Println("DbScan2ProtoMsgx2 SetProtoMsgField old err:", err)
 				return err
 			}
 		}
 
-		err = setProtoMsgFieldDirect(newMsg, fieldDesc, newVals[i])
+		err = setProtoMsgFieldDirect(s.MsgStore, newMsg, fieldDesc, newVals[i])
 		if err != nil {
 			fmt.Println("DbScan2ProtoMsgx2 setProtoMsgFieldDirect new err:", err)
 			if !canFallbackDirectError(err) {
 				return err
 			}
-			err = SetProtoMsgField(newMsg, fieldDesc, unwrapScanVal(newVals[i]))
+			err = SetProtoMsgFieldOfStore(s.MsgStore, newMsg, fieldDesc, unwrapScanVal(newVals[i]))
 			if err != nil {
 				fmt.Println("DbScan2ProtoMsgx2 SetProtoMsgField new err:", err)
 				return err
@@ -1080,9 +1377,15 @@ func (s *DbRowPairScanner) Scan(rows *sql.Rows, oldMsg proto.Message, newMsg pro
 
 // DbScan2ProtoMsgx2 scan db rows to proto message(oldMsg and newMsg), the proto msg has no nested message
 func DbScan2ProtoMsgx2(rows *sql.Rows, oldMsg proto.Message, newMsg proto.Message, columnNames []string, msgFieldsMap map[string]protoreflect.FieldDescriptor) (err error) {
+	return dbScan2ProtoMsgx2(nil, rows, oldMsg, newMsg, columnNames, msgFieldsMap)
+}
+
+// dbScan2ProtoMsgx2 DbScan2ProtoMsgx2 with field messages got from store
+func dbScan2ProtoMsgx2(store sqldb.MsgStore, rows *sql.Rows, oldMsg proto.Message, newMsg proto.Message, columnNames []string, msgFieldsMap map[string]protoreflect.FieldDescriptor) (err error) {
 	scanner, err := NewDbRowPairScanner(rows, oldMsg, columnNames, msgFieldsMap)
 	if err != nil {
 		return err
 	}
+	scanner.MsgStore = store
 	return scanner.Scan(rows, oldMsg, newMsg)
 }
@@ -1,9 +1,14 @@
 package crud
 
 import (
+	"database/sql"
 	"strings"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/pdbutil"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protodesc"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/descriptorpb"
@@ -66,16 +71,16 @@ func BenchmarkSetProtoMsgField_Direct(b *testing.B) {
 	fields := benchMsgDesc.Fields()
 	for i := 0; i < b.N; i++ {
 		msg := dynamicpb.NewMessage(benchMsgDesc)
-		_ = setProtoMsgFieldDirect(msg, fields.ByName("id"), int64(42))
-		_ = setProtoMsgFieldDirect(msg, fields.ByName("name"), "hello")
-		_ = setProtoMsgFieldDirect(msg, fields.ByName("active"), true)
-		_ = setProtoMsgFieldDirect(msg, fields.ByName("score"), float64(3.14))
-		_ = setProtoMsgFieldDirect(msg, fields.ByName("amount"), float64(2.718))
-		_ = setProtoMsgFieldDirect(msg, fields.ByName("count"), int64(100))
-		_ = setProtoMsgFieldDirect(msg, fields.ByName("flags"), int64(7))
-		_ = setProtoMsgFieldDirect(msg, fields.ByName("hash"), int64(12345))
-		_ = setProtoMsgFieldDirect(msg, fields.ByName("data"), []byte("raw"))
-		_ = setProtoMsgFieldDirect(msg, fields.ByName("status"), int64(1))
+		_ = setProtoMsgFieldDirect(nil, msg, fields.ByName("id"), int64(42))
+		_ = setProtoMsgFieldDirect(nil, msg, fields.ByName("name"), "hello")
+		_ = setProtoMsgFieldDirect(nil, msg, fields.ByName("active"), true)
+		_ = setProtoMsgFieldDirect(nil, msg, fields.ByName("score"), float64(3.14))
+		_ = setProtoMsgFieldDirect(nil, msg, fields.ByName("amount"), float64(2.718))
+		_ = setProtoMsgFieldDirect(nil, msg, fields.ByName("count"), int64(100))
+		_ = setProtoMsgFieldDirect(nil, msg, fields.ByName("flags"), int64(7))
+		_ = setProtoMsgFieldDirect(nil, msg, fields.ByName("hash"), int64(12345))
+		_ = setProtoMsgFieldDirect(nil, msg, fields.ByName("data"), []byte("raw"))
+		_ = setProtoMsgFieldDirect(nil, msg, fields.ByName("status"), int64(1))
 	}
 }
 
@@ -111,7 +116,7 @@ func BenchmarkDbRowScannerAssign_MapLookup(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		for j, columnName := range benchScanColumnNames {
 			fieldDesc := msgFieldsMap[strings.ToLower(columnName)]
-			if err := setProtoMsgFieldDirect(msg, fieldDesc, benchScanRowVals[j]); err != nil {
+			if err := setProtoMsgFieldDirect(nil, msg, fieldDesc, benchScanRowVals[j]); err != nil {
 				b.Fatal(err)
 			}
 		}
@@ -136,7 +141,119 @@ func BenchmarkDbRowScannerAssign_PrecomputedDescriptors(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		for j, fieldDesc := range fieldDescs {
-			if err := setProtoMsgFieldDirect(msg, fieldDesc, benchScanRowVals[j]); err != nil {
+			if err := setProtoMsgFieldDirect(nil, msg, fieldDesc, benchScanRowVals[j]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// bindPDBFieldColumn binder in the form protoc-gen-protodb generates
+func bindPDBFieldColumn(field protoreflect.FieldNumber) (ColumnBinder, bool) {
+	switch field {
+	case 2:
+		return BindBool(func(msg proto.Message) *bool { return &msg.(*protodb.PDBField).Primary }), true
+	case 3:
+		return BindBool(func(msg proto.Message) *bool { return &msg.(*protodb.PDBField).Unique }), true
+	case 5:
+		return BindString(func(msg proto.Message) *string { return &msg.(*protodb.PDBField).Reference }), true
+	case 6:
+		return BindString(func(msg proto.Message) *string { return &msg.(*protodb.PDBField).DefaultValue }), true
+	case 10:
+		return BindInt(func(msg proto.Message) *int32 { return &msg.(*protodb.PDBField).SerialType }), true
+	case 11:
+		return BindInt(func(msg proto.Message) *protodb.FieldDbType { return &msg.(*protodb.PDBField).DbType }), true
+	case 12:
+		return BindString(func(msg proto.Message) *string { return &msg.(*protodb.PDBField).DbTypeStr }), true
+	case 16:
+		return BindString(func(msg proto.Message) *string { return &msg.(*protodb.PDBField).UniqueName }), true
+	case 17:
+		return BindInt(func(msg proto.Message) *int32 { return &msg.(*protodb.PDBField).NumericPrecision }), true
+	case 19:
+		return BindInt(func(msg proto.Message) *protodb.EnumStorage { return &msg.(*protodb.PDBField).EnumStorage }), true
+	}
+	return ColumnBinder{}, false
+}
+
+// registerPDBFieldBinder register bindPDBFieldColumn until the test ends
+func registerPDBFieldBinder(tb testing.TB) {
+	tb.Helper()
+	RegisterRowBinder(&protodb.PDBField{}, bindPDBFieldColumn)
+	tb.Cleanup(func() {
+		rowBindersMu.Lock()
+		delete(rowBinders, (&protodb.PDBField{}).ProtoReflect().Descriptor().FullName())
+		rowBindersMu.Unlock()
+	})
+}
+
+var benchPDBFieldColumns = []string{"Primary", "Unique", "Reference", "DefaultValue", "SerialType", "DbType", "DbTypeStr", "UniqueName", "NumericPrecision", "EnumStorage"}
+
+// benchScanPDBFieldRows scan 1000 rows of sqlmock into PDBField messages per iteration
+func benchScanPDBFieldRows(b *testing.B) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		b.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+	msgFieldsMap := pdbutil.BuildMsgFieldsMap(benchPDBFieldColumns, (&protodb.PDBField{}).ProtoReflect().Descriptor().Fields(), true)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		mockRows := sqlmock.NewRows(benchPDBFieldColumns)
+		for j := 0; j < 1000; j++ {
+			mockRows.AddRow(true, false, "t_user(id)", "0", int64(8), int64(3), "", "uk_name", int64(12), int64(1))
+		}
+		mock.ExpectQuery("SELECT").WillReturnRows(mockRows)
+		rows, err := db.Query("SELECT")
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+
+		var scanner *DbRowScanner
+		for rows.Next() {
+			msg := &protodb.PDBField{}
+			if scanner == nil {
+				if scanner, err = NewDbRowScanner(rows, msg, nil, msgFieldsMap); err != nil {
+					b.Fatal(err)
+				}
+			}
+			if err := scanner.Scan(rows, msg); err != nil {
+				b.Fatal(err)
+			}
+		}
+		rows.Close()
+	}
+}
+
+func BenchmarkDbRowScannerScan_Reflect(b *testing.B) {
+	benchScanPDBFieldRows(b)
+}
+
+func BenchmarkDbRowScannerScan_Generated(b *testing.B) {
+	registerPDBFieldBinder(b)
+	benchScanPDBFieldRows(b)
+}
+
+// BenchmarkDbRowScannerAssign_Generated assign cost of the generated setters, compare with _PrecomputedDescriptors
+func BenchmarkDbRowScannerAssign_Generated(b *testing.B) {
+	var binders []ColumnBinder
+	for _, field := range []protoreflect.FieldNumber{2, 3, 5, 6, 10, 11, 12, 16} {
+		binder, _ := bindPDBFieldColumn(field)
+		if err := binder.Dest.(sql.Scanner).Scan(int64(1)); err != nil {
+			b.Fatal(err)
+		}
+		binders = append(binders, binder)
+	}
+	msg := &protodb.PDBField{}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, binder := range binders {
+			if err := binder.Set(msg); err != nil {
 				b.Fatal(err)
 			}
 		}
@@ -21,7 +21,7 @@ func DbDeleteReturn(db sqldb.DB, msg proto.Message, dbschema string) (returnMsg
 	msgPm := msg.ProtoReflect()
 	msgDesc := msgPm.Descriptor()
 	msgFieldDescs := msgDesc.Fields()
-	tableName := string(msgDesc.Name())
+	tableName := pdbutil.GetTableName(msgDesc)
 
 	return dbDeleteReturn(db, msg, dbschema, tableName, msgDesc, msgFieldDescs)
 
@@ -29,7 +29,7 @@ func DbDeleteReturn(db sqldb.DB, msg proto.Message, dbschema string) (returnMsg
 
 func dbDeleteReturn(db sqldb.DB, msg proto.Message, dbschema string, tableName string, msgDesc protoreflect.MessageDescriptor, msgFieldDescs protoreflect.FieldDescriptors) (returnMsg proto.Message, err error) {
 	dbdialect := sqldb.GetExecutorDialect(db)
-	if dbdialect == sqldb.Mysql {
+	if !sqldb.GetExecutorCapabilities(db).DeleteReturning {
 		oldMsg, err := dbSelectOne(db, msg, nil, nil, dbschema, tableName, msgDesc, msgFieldDescs, dbdialect, true)
 		if err != nil {
 			return nil, err
@@ -58,7 +58,7 @@ func dbDeleteReturn(db sqldb.DB, msg proto.Message, dbschema string, tableName s
 
 	returnMsg = msg.ProtoReflect().New().Interface()
 
-	err = DbScan2ProtoMsg(rows, returnMsg, nil, nil)
+	err = dbScan2ProtoMsg(msgStoreOf(db), rows, returnMsg, nil, nil)
 
 	return returnMsg, err
 }
@@ -69,7 +69,7 @@ func DbDelete(db sqldb.DB, msg proto.Message, dbschema string) (dmlResult *proto
 	msgPm := msg.ProtoReflect()
 	msgDesc := msgPm.Descriptor()
 	msgFieldDescs := msgDesc.Fields()
-	tableName := string(msgDesc.Name())
+	tableName := pdbutil.GetTableName(msgDesc)
 
 	dbdialect := sqldb.GetExecutorDialect(db)
 
@@ -106,10 +106,10 @@ func dbBuildSqlDelete(msgobj proto.Message, dbschema string, tableName string,
 	sb.WriteString("DELETE FROM ")
 
 	if len(tableName) == 0 {
-		tableName = string(msgDesc.Name())
+		tableName = pdbutil.GetTableName(msgDesc)
 	}
 
-	dbtableName := sqldb.BuildDbTableName(tableName, dbschema, dbdialect)
+	dbtableName := sqldb.BuildQuotedDbTableName(tableName, dbschema, dbdialect)
 	sb.WriteString(dbtableName)
 
 	sb.WriteString(protosql.SQL_WHERE)
@@ -123,14 +123,14 @@ func dbBuildSqlDelete(msgobj proto.Message, dbschema string, tableName string,
 		return "", nil, fmt.Errorf("no primary key field found for table %s", tableName)
 	}
 
-	for _, fieldDesc := range primaryKeyFieldNames {
+	for fieldName, fieldDesc := range primaryKeyFieldNames {
 		if firstPlaceholder {
 			firstPlaceholder = false
 		} else {
 			sb.WriteString(protosql.SQL_AND)
 		}
 
-		sb.WriteString(fieldDesc.TextName())
+		sb.WriteString(dbdialect.QuoteIdentifier(fieldName))
 		sb.WriteString(protosql.SQL_EQUEAL)
 		if placeholder == protosql.SQL_QUESTION {
 			sb.WriteString(string(protosql.SQL_QUESTION))
@@ -148,7 +148,7 @@ func dbBuildSqlDelete(msgobj proto.Message, dbschema string, tableName string,
 
 	}
 
-	if returnDeleted && mysqlSupportsReturning(dbdialect) {
+	if returnDeleted {
 		sb.WriteString(" RETURNING * ")
 	}
 	sb.WriteString(protosql.SQL_SEMICOLON)
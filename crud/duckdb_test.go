@@ -0,0 +1,160 @@
+package crud
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestTableQueryBuildSql_DuckDBListAndMapOperators(t *testing.T) {
+	db := &sqldb.DBWithDialect{Executor: dummyDB{}, Dialect: sqldb.DuckDB}
+	msgDesc := (&protodb.TableQueryReq{}).ProtoReflect().Descriptor()
+
+	cases := []struct {
+		column string
+		op     protodb.WhereOperator
+		value  string
+		cond   string
+		arg    any
+	}{
+		{"ResultColumnNames", protodb.WhereOperator_WOP_CONTAINS, "abc", `list_contains("ResultColumnNames", ?)`, "abc"},
+		{"ResultColumnNames", protodb.WhereOperator_WOP_OVERLAP, `["a","b"]`, `list_has_any("ResultColumnNames", ?)`, []string{"a", "b"}},
+		{"ResultColumnNames", protodb.WhereOperator_WOP_CONTAINS_ALL, `["a"]`, `list_has_all("ResultColumnNames", ?)`, []string{"a"}},
+		{"ResultColumnNames", protodb.WhereOperator_WOP_LEN_GT, "2", `len("ResultColumnNames") > ?`, int64(2)},
+		{"Where2", protodb.WhereOperator_WOP_HAS_KEY, "id", `map_contains("Where2", ?)`, "id"},
+	}
+	for _, c := range cases {
+		req := &protodb.TableQueryReq{
+			TableName:      string(msgDesc.Name()),
+			Where2:         map[string]string{c.column: c.value},
+			Where2Operator: map[string]protodb.WhereOperator{c.column: c.op},
+		}
+		sqlStr, vals, err := TableQueryBuildSql(db, msgDesc, req, "", nil)
+		if err != nil {
+			t.Fatalf("%v TableQueryBuildSql: %v", c.op, err)
+		}
+		if !strings.Contains(sqlStr, c.cond) {
+			t.Fatalf("%v unexpected sql: %s", c.op, sqlStr)
+		}
+		if len(vals) != 1 || !reflect.DeepEqual(vals[0], c.arg) {
+			t.Fatalf("%v unexpected vals: %#v", c.op, vals)
+		}
+	}
+
+	req := &protodb.TableQueryReq{
+		TableName:      string(msgDesc.Name()),
+		Where2:         map[string]string{"Where2": `{"a":"b"}`},
+		Where2Operator: map[string]protodb.WhereOperator{"Where2": protodb.WhereOperator_WOP_CONTAINS},
+	}
+	if _, _, err := TableQueryBuildSql(db, msgDesc, req, "", nil); err == nil {
+		t.Fatal("expected unsupported map CONTAINS on duckdb")
+	}
+}
+
+func TestDbBuildSqlInsert_DuckDBReturning(t *testing.T) {
+	msg := &protodb.TableQueryReq{
+		TableName:         "user",
+		ResultColumnNames: []string{"id"},
+		Where2:            map[string]string{"id": "1"},
+	}
+	msgDesc := msg.ProtoReflect().Descriptor()
+
+	sqlStr, vals, err := dbBuildSqlInsert(msg, 0, "", "TableQueryReq", msgDesc, msgDesc.Fields(), sqldb.DuckDB, true)
+	if err != nil {
+		t.Fatalf("dbBuildSqlInsert: %v", err)
+	}
+	if !strings.Contains(sqlStr, `INSERT INTO "TableQueryReq"`) || !strings.Contains(sqlStr, "RETURNING *") || !strings.Contains(sqlStr, "CAST(? AS JSON)") {
+		t.Fatalf("unexpected insert: %s", sqlStr)
+	}
+	var hasList, hasMap bool
+	for _, v := range vals {
+		switch x := v.(type) {
+		case []string:
+			hasList = hasList || reflect.DeepEqual(x, []string{"id"})
+		case string:
+			hasMap = hasMap || x == `{"id":"1"}`
+		}
+	}
+	if !hasList || !hasMap {
+		t.Fatalf("expected native list and map args: %#v", vals)
+	}
+}
+
+func TestEncodeSQLArg_DuckDBNativeListAndMap(t *testing.T) {
+	_, mInt64Str, mStrSub, _, subMsgDesc := buildTestMapDescriptors(t)
+
+	v, err := EncodeSQLArg(mInt64Str, sqldb.DuckDB, map[int64]string{1: "a"})
+	if err != nil {
+		t.Fatalf("EncodeSQLArg: %v", err)
+	}
+	if v != `{"1":"a"}` {
+		t.Fatalf("unexpected map arg: %#v", v)
+	}
+
+	sub := dynamicpb.NewMessage(subMsgDesc)
+	sub.Set(subMsgDesc.Fields().ByName("name"), protoreflect.ValueOfString("x"))
+	v, err = EncodeSQLArg(mStrSub, sqldb.DuckDB, map[string]any{"k": sub})
+	if err != nil {
+		t.Fatalf("EncodeSQLArg: %v", err)
+	}
+	m, ok := v.(string)
+	if !ok || !strings.Contains(m, `"k":{"name":"x"}`) {
+		t.Fatalf("unexpected map message arg: %#v", v)
+	}
+
+	listField := (&protodb.TableQueryReq{}).ProtoReflect().Descriptor().Fields().ByName("ResultColumnNames")
+	v, err = EncodeSQLArg(listField, sqldb.DuckDB, []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("EncodeSQLArg: %v", err)
+	}
+	if !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Fatalf("unexpected list arg: %#v", v)
+	}
+}
+
+func TestSetProtoMsgFieldDirect_DuckDBNativeListAndMap(t *testing.T) {
+	msgDesc, mInt64Str, mStrSub, _, subMsgDesc := buildTestMapDescriptors(t)
+	msg := dynamicpb.NewMessage(msgDesc)
+
+	dest := allocScanDest(mInt64Str).(*nullComposite)
+	if err := dest.Scan(map[any]any{int64(1): "a", int64(2): "b"}); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if err := setProtoMsgFieldDirect(nil, msg, mInt64Str, dest); err != nil {
+		t.Fatalf("setProtoMsgFieldDirect: %v", err)
+	}
+	m := msg.Get(mInt64Str).Map()
+	if m.Len() != 2 || m.Get(protoreflect.ValueOfInt64(2).MapKey()).String() != "b" {
+		t.Fatalf("unexpected map: %v", m)
+	}
+
+	dest = allocScanDest(mStrSub).(*nullComposite)
+	if err := dest.Scan(map[any]any{"k": `{"name":"x"}`}); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if err := setProtoMsgFieldDirect(nil, msg, mStrSub, dest); err != nil {
+		t.Fatalf("setProtoMsgFieldDirect: %v", err)
+	}
+	sub := msg.Get(mStrSub).Map().Get(protoreflect.ValueOfString("k").MapKey()).Message()
+	if sub.Get(subMsgDesc.Fields().ByName("name")).String() != "x" {
+		t.Fatalf("unexpected map message: %v", sub)
+	}
+
+	req := &protodb.TableQueryReq{}
+	listField := req.ProtoReflect().Descriptor().Fields().ByName("ResultColumnNames")
+	dest = allocScanDest(listField).(*nullComposite)
+	if err := dest.Scan([]any{"a", "b"}); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if err := setProtoMsgFieldDirect(nil, req, listField, dest); err != nil {
+		t.Fatalf("setProtoMsgFieldDirect: %v", err)
+	}
+	if !reflect.DeepEqual(req.ResultColumnNames, []string{"a", "b"}) {
+		t.Fatalf("unexpected list: %v", req.ResultColumnNames)
+	}
+}
@@ -0,0 +1,237 @@
+package crud
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/msgstore"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// dynamicItemFileSet descriptor set of a table only known at runtime
+func dynamicItemFileSet() *descriptorpb.FileDescriptorSet {
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	typ := func(t descriptorpb.FieldDescriptorProto_Type) *descriptorpb.FieldDescriptorProto_Type {
+		return t.Enum()
+	}
+	pdbm := &descriptorpb.MessageOptions{}
+	proto.SetExtension(pdbm, protodb.E_Pdbm, &protodb.PDBMsg{TableName: "dyn_item"})
+	primary := &descriptorpb.FieldOptions{}
+	proto.SetExtension(primary, protodb.E_Pdb, &protodb.PDBField{Primary: true, SerialType: 8})
+	return &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{{
+		Name:       strPtr("dyn/item.proto"),
+		Package:    strPtr("dyn"),
+		Dependency: []string{"protodb.proto", "google/protobuf/timestamp.proto"},
+		Syntax:     strPtr("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name:    strPtr("Item"),
+				Options: pdbm,
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: strPtr("id"), JsonName: strPtr("id"), Number: int32Ptr(1), Label: optional, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_INT64), Options: primary},
+					{Name: strPtr("name"), JsonName: strPtr("name"), Number: int32Ptr(2), Label: optional, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_STRING)},
+					{Name: strPtr("tags"), JsonName: strPtr("tags"), Number: int32Ptr(3), Label: repeated, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_STRING)},
+					{Name: strPtr("attrs"), JsonName: strPtr("attrs"), Number: int32Ptr(4), Label: repeated, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_MESSAGE), TypeName: strPtr(".dyn.Item.AttrsEntry")},
+					{Name: strPtr("status"), JsonName: strPtr("status"), Number: int32Ptr(5), Label: optional, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_ENUM), TypeName: strPtr(".dyn.Status")},
+					{Name: strPtr("profile"), JsonName: strPtr("profile"), Number: int32Ptr(6), Label: optional, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_MESSAGE), TypeName: strPtr(".dyn.Profile")},
+					{Name: strPtr("created_at"), JsonName: strPtr("createdAt"), Number: int32Ptr(7), Label: optional, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_MESSAGE), TypeName: strPtr(".google.protobuf.Timestamp")},
+					{Name: strPtr("scores"), JsonName: strPtr("scores"), Number: int32Ptr(8), Label: repeated, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_INT32)},
+				},
+				NestedType: []*descriptorpb.DescriptorProto{{
+					Name:    strPtr("AttrsEntry"),
+					Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+					Field: []*descriptorpb.FieldDescriptorProto{
+						{Name: strPtr("key"), JsonName: strPtr("key"), Number: int32Ptr(1), Label: optional, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_STRING)},
+						{Name: strPtr("value"), JsonName: strPtr("value"), Number: int32Ptr(2), Label: optional, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_STRING)},
+					},
+				}},
+			},
+			{
+				Name: strPtr("Profile"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: strPtr("city"), JsonName: strPtr("city"), Number: int32Ptr(1), Label: optional, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_STRING)},
+				},
+			},
+		},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: strPtr("Status"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: strPtr("STATUS_UNKNOWN"), Number: int32Ptr(0)},
+				{Name: strPtr("STATUS_OK"), Number: int32Ptr(1)},
+			},
+		}},
+	}}}
+}
+
+// passthroughConverter keep native array args(pgx accepts go slices)
+type passthroughConverter struct{}
+
+func (passthroughConverter) ConvertValue(v any) (driver.Value, error) {
+	return v, nil
+}
+
+func newDynamicItem(t *testing.T) (proto.Message, protoreflect.MessageDescriptor) {
+	t.Helper()
+	msgDescs, err := msgstore.RegisterFileDescriptorSet(dynamicItemFileSet())
+	if err != nil {
+		t.Fatalf("RegisterFileDescriptorSet: %v", err)
+	}
+	if len(msgDescs) != 1 || msgDescs[0].Name() != "Item" {
+		t.Fatalf("unexpected table messages: %v", msgDescs)
+	}
+	msg, ok := msgstore.GetMsg("dyn_item", true)
+	if !ok {
+		t.Fatal("dynamic table is not registered by table name")
+	}
+	return msg, msgDescs[0]
+}
+
+func TestDynamicMsgInsertAndScan(t *testing.T) {
+	msg, msgDesc := newDynamicItem(t)
+	fields := msgDesc.Fields()
+	pm := msg.ProtoReflect()
+	pm.Set(fields.ByName("name"), protoreflect.ValueOfString("box"))
+	tags := pm.Mutable(fields.ByName("tags")).List()
+	tags.Append(protoreflect.ValueOfString("a"))
+	tags.Append(protoreflect.ValueOfString("b"))
+	attrs := pm.Mutable(fields.ByName("attrs")).Map()
+	attrs.Set(protoreflect.ValueOfString("color").MapKey(), protoreflect.ValueOfString("red"))
+	pm.Set(fields.ByName("status"), protoreflect.ValueOfEnum(1))
+	profile := pm.Mutable(fields.ByName("profile")).Message()
+	profile.Set(profile.Descriptor().Fields().ByName("city"), protoreflect.ValueOfString("Paris"))
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	pm.Set(fields.ByName("created_at"), protoreflect.ValueOfMessage(timestamppb.New(created).ProtoReflect()))
+	pm.Mutable(fields.ByName("scores")).List().Append(protoreflect.ValueOfInt32(9))
+
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(passthroughConverter{}))
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+	executor := &sqldb.DBWithDialect{Executor: db, Dialect: sqldb.Postgres}
+
+	mock.ExpectQuery("SHOW server_version").WillReturnRows(sqlmock.NewRows([]string{"server_version"}).AddRow("16.2"))
+	columns := []string{"id", "name", "tags", "attrs", "status", "profile", "created_at", "scores"}
+	mock.ExpectQuery(`INSERT INTO "dyn_item" \( "name" , "tags" , "attrs" , "status" , "profile" , "created_at" , "scores" \) VALUES \( \$1 , \$2 , \$3 , \$4 , \$5 , \$6 , \$7 \) RETURNING \*`).
+		WithArgs("box", []string{"a", "b"}, `{"color":"red"}`, int32(1), `{"city":"Paris"}`, created, []int32{9}).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(int64(3), "box", "{a,b}", `{"color":"red"}`, int64(1), `{"city":"Paris"}`, created, "{9}"))
+
+	got, err := DbInsertReturn(executor, msg, 0, "")
+	if err != nil {
+		t.Fatalf("DbInsertReturn: %v", err)
+	}
+	pm.Set(fields.ByName("id"), protoreflect.ValueOfInt64(3))
+	if !proto.Equal(got, msg) {
+		t.Fatalf("returned %v, want %v", got, msg)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("ExpectationsWereMet: %v", err)
+	}
+}
+
+func TestDynamicMsgPartialUpdateAndQuery(t *testing.T) {
+	msg, msgDesc := newDynamicItem(t)
+	fields := msgDesc.Fields()
+	pm := msg.ProtoReflect()
+	pm.Set(fields.ByName("id"), protoreflect.ValueOfInt64(3))
+	pm.Mutable(fields.ByName("tags")).List().Append(protoreflect.ValueOfString("c"))
+
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(passthroughConverter{}))
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+	executor := &sqldb.DBWithDialect{Executor: db, Dialect: sqldb.Postgres}
+
+	mock.ExpectQuery("SHOW server_version").WillReturnRows(sqlmock.NewRows([]string{"server_version"}).AddRow("16.2"))
+	mock.ExpectQuery(`UPDATE "dyn_item" SET "tags" = \$1 WHERE "id" = \$2 RETURNING \*`).
+		WithArgs([]string{"c"}, int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "tags"}).AddRow(int64(3), "{c}"))
+	got, err := DbUpdatePartialReturnNew(executor, msg, []string{"tags"}, "")
+	if err != nil {
+		t.Fatalf("DbUpdatePartialReturnNew: %v", err)
+	}
+	if !proto.Equal(got, msg) {
+		t.Fatalf("returned %v, want %v", got, msg)
+	}
+
+	sqlStr, sqlVals, err := TableQueryBuildSql(executor, msgDesc, &protodb.TableQueryReq{
+		TableName:      "dyn_item",
+		Where2:         map[string]string{"tags": `["c"]`},
+		Where2Operator: map[string]protodb.WhereOperator{"tags": protodb.WhereOperator_WOP_CONTAINS},
+	}, "", nil)
+	if err != nil {
+		t.Fatalf("TableQueryBuildSql: %v", err)
+	}
+	mock.ExpectQuery(`SELECT \* FROM "dyn_item" WHERE`).WillReturnRows(sqlmock.NewRows([]string{"id", "profile", "status"}).AddRow(int64(3), `{"city":"Rome"}`, int64(1)))
+	rows, err := executor.Query(sqlStr, sqlVals...)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		t.Fatal("expected one row")
+	}
+	row, _ := msgstore.GetMsg("Item", true)
+	if err := DbScan2ProtoMsg(rows, row, nil, nil); err != nil {
+		t.Fatalf("DbScan2ProtoMsg: %v", err)
+	}
+	profile := row.ProtoReflect().Get(fields.ByName("profile")).Message()
+	if city := profile.Get(profile.Descriptor().Fields().ByName("city")).String(); city != "Rome" {
+		t.Fatalf("unexpected profile: %v", row)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("ExpectationsWereMet: %v", err)
+	}
+}
+
+// recordingMsgStore records the field messages asked by the scanner
+type recordingMsgStore struct {
+	*msgstore.TMsgStore
+	fieldMsgNames []string
+}
+
+func (this *recordingMsgStore) GetFieldMsg(msgName string, new bool) (proto.Message, bool) {
+	this.fieldMsgNames = append(this.fieldMsgNames, msgName)
+	return this.TMsgStore.GetFieldMsg(msgName, new)
+}
+
+func TestDbRowScannerMsgStore(t *testing.T) {
+	msg, msgDesc := newDynamicItem(t)
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+	store := &recordingMsgStore{TMsgStore: msgstore.NewMsgStore()}
+	executor := &sqldb.DBWithDialect{Executor: db, Dialect: sqldb.Postgres, MsgStore: store}
+
+	mock.ExpectQuery(`SELECT \* FROM "dyn_item"`).WillReturnRows(sqlmock.NewRows([]string{"id", "profile"}).AddRow(int64(3), `{"city":"Rome"}`))
+	rows, err := executor.Query(`SELECT * FROM "dyn_item"`)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		t.Fatal("expected one row")
+	}
+	if err := dbScan2ProtoMsg(msgStoreOf(executor), rows, msg, nil, nil); err != nil {
+		t.Fatalf("dbScan2ProtoMsg: %v", err)
+	}
+	profile := msg.ProtoReflect().Get(msgDesc.Fields().ByName("profile")).Message()
+	if city := profile.Get(profile.Descriptor().Fields().ByName("city")).String(); city != "Rome" {
+		t.Fatalf("unexpected profile: %v", msg)
+	}
+	if len(store.fieldMsgNames) != 1 || store.fieldMsgNames[0] != "Profile" {
+		t.Fatalf("field messages of the executor store = %v, want [Profile]", store.fieldMsgNames)
+	}
+}
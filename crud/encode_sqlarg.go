@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"reflect"
 
+	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/protosql"
 	"github.com/ygrpc/protodb/sqldb"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
@@ -33,7 +35,6 @@ func EncodeSQLArg(fieldDesc protoreflect.FieldDescriptor, dialect sqldb.TDBDiale
 		if v.IsNil() {
 			v = reflect.MakeMap(v.Type())
 		}
-
 		out := make(map[string]json.RawMessage, v.Len())
 		valDesc := fieldDesc.MapValue()
 		for _, k := range v.MapKeys() {
@@ -79,8 +80,9 @@ func EncodeSQLArg(fieldDesc protoreflect.FieldDescriptor, dialect sqldb.TDBDiale
 			goValue = reflect.MakeSlice(v.Type(), 0, 0).Interface()
 		}
 
-		// For Postgres, our DDL maps uint32/uint64 to bigint, so convert to []int64 for arrays.
-		if dialect == sqldb.Postgres {
+		// native arrays of unsigned ints are bigint[] in DDL, so convert to []int64.
+		nativeArrays := dialect.Dialect().NativeArrays()
+		if nativeArrays {
 			switch fieldDesc.Kind() {
 			case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
 				if s, ok := goValue.([]uint32); ok {
@@ -129,7 +131,7 @@ func EncodeSQLArg(fieldDesc protoreflect.FieldDescriptor, dialect sqldb.TDBDiale
 			return string(b), nil
 		}
 
-		if dialect == sqldb.SQLite || dialect == sqldb.Mysql {
+		if !nativeArrays && dialect != sqldb.Unknown {
 			b, err := json.Marshal(goValue)
 			if err != nil {
 				return nil, err
@@ -139,6 +141,18 @@ func EncodeSQLArg(fieldDesc protoreflect.FieldDescriptor, dialect sqldb.TDBDiale
 		return goValue, nil
 	}
 
+	if isWellKnownField(fieldDesc) {
+		return encodeWellKnownSQLArg(fieldDesc, dialect, goValue)
+	}
+
+	if pdb, ok := numericFieldPdb(fieldDesc); ok {
+		return encodeNumericSQLArg(pdb, goValue)
+	}
+
+	if pdbutil.IsEnumStoredAsName(fieldDesc) {
+		return encodeEnumNameSQLArg(fieldDesc, goValue)
+	}
+
 	if fieldDesc.Kind() == protoreflect.MessageKind {
 		pm, ok := goValue.(proto.Message)
 		if !ok {
@@ -153,3 +167,31 @@ func EncodeSQLArg(fieldDesc protoreflect.FieldDescriptor, dialect sqldb.TDBDiale
 
 	return goValue, nil
 }
+
+// mapArgColumns map columns of msgFieldDescs of a dialect with native maps, nil otherwise.
+// map args are json text, their placeholders are wrapped by Dialect.MapArg
+func mapArgColumns(dialect sqldb.TDBDialect, msgFieldDescs protoreflect.FieldDescriptors) map[string]bool {
+	if !dialect.Dialect().NativeMaps() {
+		return nil
+	}
+	var columns map[string]bool
+	for i := 0; i < msgFieldDescs.Len(); i++ {
+		field := msgFieldDescs.Get(i)
+		if !field.IsMap() {
+			continue
+		}
+		if columns == nil {
+			columns = make(map[string]bool)
+		}
+		columns[pdbutil.GetColumnName(field)] = true
+	}
+	return columns
+}
+
+// columnArgPlaceholder placeholder of the arg set to column
+func columnArgPlaceholder(dialect sqldb.TDBDialect, mapColumns map[string]bool, column string, placeholder protosql.SQLPlaceholder, paraNo int) string {
+	if mapColumns[column] {
+		return dialect.Dialect().MapArg(buildPlaceholder(placeholder, paraNo))
+	}
+	return buildPlaceholder(placeholder, paraNo)
+}
@@ -0,0 +1,80 @@
+package crud
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ygrpc/protodb/pdbutil"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// enumNumberOf get enum number from generated enum, int or enum name
+func enumNumberOf(fd protoreflect.FieldDescriptor, goValue any) (protoreflect.EnumNumber, error) {
+	switch v := goValue.(type) {
+	case protoreflect.Enum:
+		return v.Number(), nil
+	case protoreflect.EnumNumber:
+		return v, nil
+	case int32:
+		return protoreflect.EnumNumber(v), nil
+	case int64:
+		return protoreflect.EnumNumber(v), nil
+	case int:
+		return protoreflect.EnumNumber(v), nil
+	case string:
+		return enumNumberFromString(fd, v)
+	case []byte:
+		return enumNumberFromString(fd, string(v))
+	default:
+		return 0, fmt.Errorf("enum field %s unsupported value type %T", fd.Name(), goValue)
+	}
+}
+
+// enumNumberFromString accept enum value name or number
+func enumNumberFromString(fd protoreflect.FieldDescriptor, s string) (protoreflect.EnumNumber, error) {
+	s = strings.TrimSpace(s)
+	if enumValue := fd.Enum().Values().ByName(protoreflect.Name(s)); enumValue != nil {
+		return enumValue.Number(), nil
+	}
+	n, err := strconv.ParseInt(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("enum field %s invalid value %q", fd.Name(), s)
+	}
+	return protoreflect.EnumNumber(n), nil
+}
+
+// enumNameOf get enum value name, the number must be defined in enum
+func enumNameOf(fd protoreflect.FieldDescriptor, n protoreflect.EnumNumber) (string, error) {
+	enumValue := fd.Enum().Values().ByNumber(n)
+	if enumValue == nil {
+		return "", fmt.Errorf("enum field %s value %d not defined in %s", fd.Name(), n, fd.Enum().FullName())
+	}
+	return string(enumValue.Name()), nil
+}
+
+// encodeEnumNameSQLArg enum stored by name: generated enum or number -> value name
+func encodeEnumNameSQLArg(fd protoreflect.FieldDescriptor, goValue any) (any, error) {
+	n, err := enumNumberOf(fd, goValue)
+	if err != nil {
+		return nil, err
+	}
+	return enumNameOf(fd, n)
+}
+
+// enumWhereArg accept name or number in filters, convert to the stored form
+func enumWhereArg(fd protoreflect.FieldDescriptor, s string) (any, error) {
+	n, err := enumNumberFromString(fd, s)
+	if err != nil {
+		return nil, err
+	}
+	if pdbutil.IsEnumStoredAsName(fd) {
+		return enumNameOf(fd, n)
+	}
+	return int32(n), nil
+}
+
+// isScalarEnumField single enum field
+func isScalarEnumField(fd protoreflect.FieldDescriptor) bool {
+	return fd.Kind() == protoreflect.EnumKind && !fd.IsList() && !fd.IsMap()
+}
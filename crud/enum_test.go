@@ -0,0 +1,180 @@
+package crud
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/protosql"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func buildEnumMsgDesc(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+
+	fileOpts := &descriptorpb.FileOptions{}
+	proto.SetExtension(fileOpts, protodb.E_Pdbf, &protodb.PDBFile{EnumStorage: protodb.EnumStorage_EnumAsName})
+	levelOpts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(levelOpts, protodb.E_Pdb, &protodb.PDBField{EnumStorage: protodb.EnumStorage_EnumAsInt})
+
+	enumField := func(name string, number int32, opts *descriptorpb.FieldOptions) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     strPtr(name),
+			Number:   int32Ptr(number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum(),
+			TypeName: strPtr(".test.Status"),
+			Options:  opts,
+		}
+	}
+
+	fdp := &descriptorpb.FileDescriptorProto{
+		Syntax:  strPtr("proto3"),
+		Name:    strPtr("enum_test.proto"),
+		Package: strPtr("test"),
+		Options: fileOpts,
+		EnumType: []*descriptorpb.EnumDescriptorProto{
+			{
+				Name: strPtr("Status"),
+				Value: []*descriptorpb.EnumValueDescriptorProto{
+					{Name: strPtr("UNKNOWN"), Number: int32Ptr(0)},
+					{Name: strPtr("ACTIVE"), Number: int32Ptr(1)},
+					{Name: strPtr("BANNED"), Number: int32Ptr(2)},
+				},
+			},
+		},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: strPtr("Account"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: strPtr("id"), Number: int32Ptr(1), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum()},
+					enumField("status", 2, nil),
+					enumField("level", 3, levelOpts),
+				},
+			},
+		},
+	}
+
+	fd, err := protodesc.NewFile(fdp, nil)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile: %v", err)
+	}
+	return fd.Messages().ByName("Account")
+}
+
+func TestGetEnumStorage_FieldOverridesFile(t *testing.T) {
+	msgDesc := buildEnumMsgDesc(t)
+	if got := pdbutil.GetEnumStorage(msgDesc.Fields().ByName("status")); got != protodb.EnumStorage_EnumAsName {
+		t.Fatalf("status storage should follow file option, got %v", got)
+	}
+	if got := pdbutil.GetEnumStorage(msgDesc.Fields().ByName("level")); got != protodb.EnumStorage_EnumAsInt {
+		t.Fatalf("level storage should use field option, got %v", got)
+	}
+	// generated enums without options are stored as integer
+	if got := pdbutil.GetEnumStorage((&protodb.TableQueryReq{}).ProtoReflect().Descriptor().Fields().ByName("MsgFormat")); got != protodb.EnumStorage_EnumAsInt {
+		t.Fatalf("default storage should be int, got %v", got)
+	}
+}
+
+func TestEncodeSQLArg_EnumAsName(t *testing.T) {
+	msgDesc := buildEnumMsgDesc(t)
+	msg := dynamicpb.NewMessage(msgDesc)
+	status := msgDesc.Fields().ByName("status")
+	msg.Set(status, protoreflect.ValueOfEnum(2))
+
+	val, err := getSQLFieldValue(msg, status)
+	if err != nil {
+		t.Fatalf("getSQLFieldValue: %v", err)
+	}
+	v, err := EncodeSQLArg(status, sqldb.Postgres, val)
+	if err != nil || v != "BANNED" {
+		t.Fatalf("unexpected enum arg: %#v err:%v", v, err)
+	}
+
+	if _, err := EncodeSQLArg(status, sqldb.Postgres, int32(9)); err == nil {
+		t.Fatal("expected error for undefined enum number")
+	}
+
+	v, err = EncodeSQLArg(msgDesc.Fields().ByName("level"), sqldb.Postgres, int32(1))
+	if err != nil || v != int32(1) {
+		t.Fatalf("int enum should stay number: %#v err:%v", v, err)
+	}
+}
+
+func TestDbScan2ProtoMsg_EnumAsName(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	columns := []string{"id", "status", "level"}
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows(columns).AddRow(int64(1), []byte("ACTIVE"), int64(2)))
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatalf("db.Query: %v", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		t.Fatal("expected one row")
+	}
+
+	msgDesc := buildEnumMsgDesc(t)
+	msg := dynamicpb.NewMessage(msgDesc)
+	msgFieldsMap := pdbutil.BuildMsgFieldsMap(columns, msgDesc.Fields(), true)
+	if err := DbScan2ProtoMsg(rows, msg, columns, msgFieldsMap); err != nil {
+		t.Fatalf("DbScan2ProtoMsg: %v", err)
+	}
+	if got := msg.Get(msgDesc.Fields().ByName("status")).Enum(); got != 1 {
+		t.Fatalf("unexpected status: %v", got)
+	}
+	if got := msg.Get(msgDesc.Fields().ByName("level")).Enum(); got != 2 {
+		t.Fatalf("unexpected level: %v", got)
+	}
+}
+
+func TestBuildWhere2Condition_EnumNameOrNumber(t *testing.T) {
+	msgDesc := buildEnumMsgDesc(t)
+	status := msgDesc.Fields().ByName("status")
+	level := msgDesc.Fields().ByName("level")
+
+	cases := []struct {
+		field protoreflect.FieldDescriptor
+		value string
+		want  any
+	}{
+		{status, "2", "BANNED"},
+		{status, "ACTIVE", "ACTIVE"},
+		{level, "BANNED", int32(2)},
+		{level, "1", int32(1)},
+	}
+	for _, c := range cases {
+		_, args, _, err := buildWhere2Condition(sqldb.Postgres, protosql.SQL_DOLLAR, 1, c.field, protodb.WhereOperator_WOP_EQ, c.value)
+		if err != nil {
+			t.Fatalf("buildWhere2Condition %s=%s: %v", c.field.Name(), c.value, err)
+		}
+		if args[0] != c.want {
+			t.Fatalf("%s=%s: got %#v want %#v", c.field.Name(), c.value, args[0], c.want)
+		}
+	}
+
+	if _, _, _, err := buildWhere2Condition(sqldb.Postgres, protosql.SQL_DOLLAR, 1, status, protodb.WhereOperator_WOP_EQ, "9"); err == nil {
+		t.Fatal("expected error for undefined enum number")
+	}
+
+	db := &sqldb.DBWithDialect{Executor: dummyDB{}, Dialect: sqldb.Postgres}
+	_, vals, err := TableQueryBuildSql(db, msgDesc, &protodb.TableQueryReq{TableName: "Account", Where: map[string]string{"status": "1"}}, "", nil)
+	if err != nil {
+		t.Fatalf("TableQueryBuildSql: %v", err)
+	}
+	if vals[0] != "ACTIVE" {
+		t.Fatalf("unexpected where arg: %#v", vals[0])
+	}
+}
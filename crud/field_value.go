@@ -1,21 +1,51 @@
 package crud
 
 import (
+	"reflect"
+
 	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/sqldb"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
 )
 
 func getSQLFieldValue(msg proto.Message, fieldDesc protoreflect.FieldDescriptor) (any, error) {
 	fieldName := string(fieldDesc.Name())
+	if isWellKnownField(fieldDesc) {
+		// unset well-known message is NULL in db
+		pm := msg.ProtoReflect()
+		if !pm.Has(fieldDesc) {
+			return nil, nil
+		}
+		return pm.Get(fieldDesc).Message().Interface(), nil
+	}
+	if oneofDesc := pdbutil.GetRealOneof(fieldDesc); oneofDesc != nil {
+		pm := msg.ProtoReflect()
+		member := pm.WhichOneof(oneofDesc)
+		if member == nil || member.Number() != fieldDesc.Number() {
+			// only the set member of oneof is stored, others are NULL
+			return pdbutil.NullValue, nil
+		}
+		if fieldDesc.Kind() == protoreflect.MessageKind {
+			return pm.Get(fieldDesc).Message().Interface(), nil
+		}
+	}
 	if fieldDesc.IsMap() || fieldDesc.IsList() || fieldDesc.Kind() == protoreflect.MessageKind {
-		return pdbutil.GetField(msg, fieldName)
+		if !isDynamicMsg(msg) {
+			if goVal, err := pdbutil.GetField(msg, fieldName); err == nil {
+				return goVal, nil
+			}
+		}
+		// dynamicpb message, or proto field name differs from the go field name
+		return protoFieldGoValue(msg.ProtoReflect(), fieldDesc), nil
 	}
-	if fieldDesc.HasPresence() {
-		return pdbutil.GetField(msg, fieldName)
+	pm := msg.ProtoReflect()
+	if hasScalarPresence(fieldDesc) && !pm.Has(fieldDesc) {
+		// unset optional field is NULL in db, a set zero value is kept as is
+		return nil, nil
 	}
 
-	pm := msg.ProtoReflect()
 	val := pm.Get(fieldDesc)
 	switch fieldDesc.Kind() {
 	case protoreflect.BoolKind:
@@ -38,11 +68,130 @@ func getSQLFieldValue(msg proto.Message, fieldDesc protoreflect.FieldDescriptor)
 		return val.Bytes(), nil
 	case protoreflect.EnumKind:
 		// Preserve generated enum values and their String behavior for existing zero/default checks.
-		if goVal, err := pdbutil.GetField(msg, fieldName); err == nil {
-			return goVal, nil
+		if !fieldDesc.HasPresence() && !isDynamicMsg(msg) {
+			if goVal, err := pdbutil.GetField(msg, fieldName); err == nil {
+				return goVal, nil
+			}
 		}
 		return int32(val.Enum()), nil
 	default:
 		return pdbutil.GetField(msg, fieldName)
 	}
 }
+
+// isDynamicMsg dynamicpb message has no go struct fields
+func isDynamicMsg(msg proto.Message) bool {
+	_, ok := msg.(*dynamicpb.Message)
+	return ok
+}
+
+var protoMessageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
+// protoFieldGoValue go value of list, map or message field in the shape of generated code:
+// []T for lists, map[K]V for maps, enums as int32 and messages as proto.Message
+func protoFieldGoValue(pm protoreflect.Message, fieldDesc protoreflect.FieldDescriptor) any {
+	switch {
+	case fieldDesc.IsList():
+		list := pm.Get(fieldDesc).List()
+		out := reflect.MakeSlice(reflect.SliceOf(goTypeOfField(fieldDesc)), list.Len(), list.Len())
+		for i := 0; i < list.Len(); i++ {
+			if v := goValueOfField(fieldDesc, list.Get(i)); v != nil {
+				out.Index(i).Set(reflect.ValueOf(v))
+			}
+		}
+		return out.Interface()
+	case fieldDesc.IsMap():
+		m := pm.Get(fieldDesc).Map()
+		keyDesc, valDesc := fieldDesc.MapKey(), fieldDesc.MapValue()
+		out := reflect.MakeMapWithSize(reflect.MapOf(goTypeOfField(keyDesc), goTypeOfField(valDesc)), m.Len())
+		m.Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			out.SetMapIndex(reflect.ValueOf(goValueOfField(keyDesc, k.Value())), reflect.ValueOf(goValueOfField(valDesc, v)))
+			return true
+		})
+		return out.Interface()
+	default:
+		return pm.Get(fieldDesc).Message().Interface()
+	}
+}
+
+func goTypeOfField(fd protoreflect.FieldDescriptor) reflect.Type {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return reflect.TypeOf(false)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind, protoreflect.EnumKind:
+		return reflect.TypeOf(int32(0))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return reflect.TypeOf(int64(0))
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return reflect.TypeOf(uint32(0))
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return reflect.TypeOf(uint64(0))
+	case protoreflect.FloatKind:
+		return reflect.TypeOf(float32(0))
+	case protoreflect.DoubleKind:
+		return reflect.TypeOf(float64(0))
+	case protoreflect.StringKind:
+		return reflect.TypeOf("")
+	case protoreflect.BytesKind:
+		return reflect.TypeOf([]byte(nil))
+	default:
+		return protoMessageType
+	}
+}
+
+func goValueOfField(fd protoreflect.FieldDescriptor, v protoreflect.Value) any {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return v.Bool()
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return int32(v.Int())
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return v.Int()
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return uint32(v.Uint())
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return v.Uint()
+	case protoreflect.FloatKind:
+		return float32(v.Float())
+	case protoreflect.DoubleKind:
+		return v.Float()
+	case protoreflect.StringKind:
+		return v.String()
+	case protoreflect.BytesKind:
+		return v.Bytes()
+	case protoreflect.EnumKind:
+		return int32(v.Enum())
+	default:
+		return v.Message().Interface()
+	}
+}
+
+// hasScalarPresence scalar field with explicit presence (proto3 optional, proto2 optional, oneof member)
+func hasScalarPresence(fieldDesc protoreflect.FieldDescriptor) bool {
+	if fieldDesc.IsList() || fieldDesc.IsMap() {
+		return false
+	}
+	switch fieldDesc.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return false
+	}
+	return fieldDesc.HasPresence()
+}
+
+// isSQLValZero is the field value treated as zero for ZeroAsNull/DefaultValue,
+// fields with explicit presence are zero only when unset
+func isSQLValZero(fieldDesc protoreflect.FieldDescriptor, val any) bool {
+	if hasScalarPresence(fieldDesc) {
+		return val == nil
+	}
+	return pdbutil.IsZeroValue(val)
+}
+
+// FieldSQLArg sql arg of the field of msg in dialect, encoded like the insert/update statements
+func FieldSQLArg(msg proto.Message, fieldDesc protoreflect.FieldDescriptor, dialect sqldb.TDBDialect) (any, error) {
+	val, err := getSQLFieldValue(msg, fieldDesc)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeSQLArg(fieldDesc, dialect, val)
+}
@@ -0,0 +1,97 @@
+package crud
+
+import (
+	"fmt"
+
+	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// flatColumnParent get the sub message holding the column field,
+// isSet is false when any message in path is unset, then an empty message is returned
+func flatColumnParent(msgobj proto.Message, column *pdbutil.FlatColumn) (parent proto.Message, isSet bool) {
+	pm := msgobj.ProtoReflect()
+	isSet = true
+	for _, fd := range column.Path[:len(column.Path)-1] {
+		if isSet && pm.Has(fd) {
+			pm = pm.Get(fd).Message()
+			continue
+		}
+		isSet = false
+		pm = pm.Get(fd).Message().Type().New()
+	}
+	return pm.Interface(), isSet
+}
+
+// flatFieldSQLArgs column names and encoded sql args of flattened sub message field,
+// include select columns by dotted path(address.city) or column name, nil for all columns.
+// columns of unset sub message are NULL unless the column is NotNull or has default value
+func flatFieldSQLArgs(msgobj proto.Message, field protoreflect.FieldDescriptor, dbdialect sqldb.TDBDialect,
+	isUpdate bool, include map[string]bool) (columnNames []string, vals []any, err error) {
+	for _, column := range pdbutil.GetFlatColumns(field) {
+		if include != nil && !include[column.DottedPath()] && !include[column.Name] {
+			continue
+		}
+
+		colField := column.Field()
+		colPdb, _ := pdbutil.GetPDB(colField)
+		if isUpdate && !colPdb.NeedInUpdate() || !isUpdate && !colPdb.NeedInInsert() {
+			continue
+		}
+
+		parent, isSet := flatColumnParent(msgobj, column)
+		val, err := getSQLFieldValue(parent, colField)
+		if err != nil {
+			return nil, nil, fmt.Errorf("get field err: %s %w", column.DottedPath(), err)
+		}
+
+		isValZero := isSQLValZero(colField, val)
+		_, hasDefaultValue := colPdb.HasDefaultValue()
+		switch {
+		case !isSet && !colPdb.IsNotNull() && !hasDefaultValue && !colField.IsList() && !colField.IsMap():
+			val = pdbutil.NullValue
+		case !colPdb.IsNotNull() && (colPdb.IsReference() || colPdb.IsZeroAsNull()) && isValZero:
+			val = pdbutil.NullValue
+		case isValZero && hasDefaultValue:
+			val = colPdb.DefaultValue2SQLArgs()
+		default:
+			val, err = EncodeSQLArg(colField, dbdialect, val)
+			if err != nil {
+				return nil, nil, fmt.Errorf("encode sql arg field:%s err: %w", column.DottedPath(), err)
+			}
+		}
+
+		columnNames = append(columnNames, column.Name)
+		vals = append(vals, val)
+	}
+	return columnNames, vals, nil
+}
+
+// flatColumnMutableParent get the sub message holding the column field, create messages in path if unset
+func flatColumnMutableParent(msg proto.Message, path []protoreflect.FieldDescriptor) proto.Message {
+	pm := msg.ProtoReflect()
+	for _, fd := range path[:len(path)-1] {
+		pm = pm.Mutable(fd).Message()
+	}
+	return pm.Interface()
+}
+
+// setFlatColumnValue set scanned column value to the flattened sub message field,
+// NULL leaves the sub message untouched so all NULL columns keep it unset
+func setFlatColumnValue(store sqldb.MsgStore, msg proto.Message, path []protoreflect.FieldDescriptor, v any) error {
+	if unwrapScanVal(v) == nil {
+		return nil
+	}
+	parent := flatColumnMutableParent(msg, path)
+	fieldDesc := path[len(path)-1]
+	err := setProtoMsgFieldDirect(store, parent, fieldDesc, v)
+	if err != nil {
+		if !canFallbackDirectError(err) {
+			return err
+		}
+		return SetProtoMsgFieldOfStore(store, parent, fieldDesc, unwrapScanVal(v))
+	}
+	return nil
+}
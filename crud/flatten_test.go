@@ -0,0 +1,242 @@
+package crud
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func buildFlattenMsgDesc(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+
+	idOpts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(idOpts, protodb.E_Pdb, &protodb.PDBField{Primary: true})
+	flatOpts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(flatOpts, protodb.E_Pdb, &protodb.PDBField{Flatten: true})
+
+	scalar := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, opts *descriptorpb.FieldOptions) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:    strPtr(name),
+			Number:  int32Ptr(number),
+			Label:   descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:    typ.Enum(),
+			Options: opts,
+		}
+	}
+	message := func(name string, number int32, typeName string, opts *descriptorpb.FieldOptions) *descriptorpb.FieldDescriptorProto {
+		fd := scalar(name, number, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, opts)
+		fd.TypeName = strPtr(typeName)
+		return fd
+	}
+
+	fdp := &descriptorpb.FileDescriptorProto{
+		Syntax:  strPtr("proto3"),
+		Name:    strPtr("flatten_test.proto"),
+		Package: strPtr("test"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: strPtr("Geo"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					scalar("lat", 1, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, nil),
+					scalar("lng", 2, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, nil),
+				},
+			},
+			{
+				Name: strPtr("Address"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					scalar("city", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, nil),
+					scalar("zip", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, nil),
+					message("geo", 3, ".test.Geo", flatOpts),
+				},
+			},
+			{
+				Name: strPtr("Customer"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					scalar("id", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64, idOpts),
+					message("home", 2, ".test.Address", flatOpts),
+					scalar("note", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING, nil),
+				},
+			},
+		},
+	}
+
+	fd, err := protodesc.NewFile(fdp, nil)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile: %v", err)
+	}
+	return fd.Messages().ByName("Customer")
+}
+
+func newFlattenCustomer(msgDesc protoreflect.MessageDescriptor) *dynamicpb.Message {
+	msg := dynamicpb.NewMessage(msgDesc)
+	msg.Set(msgDesc.Fields().ByName("id"), protoreflect.ValueOfInt64(1))
+	home := msg.Mutable(msgDesc.Fields().ByName("home")).Message()
+	homeFields := home.Descriptor().Fields()
+	home.Set(homeFields.ByName("city"), protoreflect.ValueOfString("Paris"))
+	geo := home.Mutable(homeFields.ByName("geo")).Message()
+	geo.Set(geo.Descriptor().Fields().ByName("lat"), protoreflect.ValueOfFloat64(48.85))
+	return msg
+}
+
+func TestGetFlatColumns(t *testing.T) {
+	msgDesc := buildFlattenMsgDesc(t)
+	columns := pdbutil.GetFlatColumns(msgDesc.Fields().ByName("home"))
+
+	var names []string
+	for _, column := range columns {
+		names = append(names, column.Name)
+	}
+	if got := strings.Join(names, ","); got != "home_city,home_zip,home_geo_lat,home_geo_lng" {
+		t.Fatalf("unexpected flat columns: %s", got)
+	}
+	if got := columns[2].DottedPath(); got != "home.geo.lat" {
+		t.Fatalf("unexpected dotted path: %s", got)
+	}
+	if _, ok := pdbutil.FindFlatColumn(msgDesc, "HOME.City"); !ok {
+		t.Fatal("dotted path lookup should be case insensitive")
+	}
+}
+
+func TestDbBuildSqlInsert_Flatten(t *testing.T) {
+	msgDesc := buildFlattenMsgDesc(t)
+	msg := newFlattenCustomer(msgDesc)
+
+	sqlStr, vals, err := dbBuildSqlInsert(msg, 0, "", "", msgDesc, msgDesc.Fields(), sqldb.Postgres, false)
+	if err != nil {
+		t.Fatalf("dbBuildSqlInsert: %v", err)
+	}
+	if !strings.Contains(sqlStr, `( "id" , "home_city" , "home_zip" , "home_geo_lat" , "home_geo_lng" , "note" )`) {
+		t.Fatalf("unexpected insert columns: %s", sqlStr)
+	}
+	if len(vals) != 6 || vals[1] != "Paris" || vals[3] != 48.85 {
+		t.Fatalf("unexpected insert vals: %#v", vals)
+	}
+
+	// unset sub message is stored as NULL columns
+	msg.Clear(msgDesc.Fields().ByName("home"))
+	_, vals, err = dbBuildSqlInsert(msg, 0, "", "", msgDesc, msgDesc.Fields(), sqldb.Postgres, false)
+	if err != nil {
+		t.Fatalf("dbBuildSqlInsert: %v", err)
+	}
+	for i := 1; i <= 4; i++ {
+		if vals[i] != pdbutil.NullValue {
+			t.Fatalf("column %d of unset sub message should be NULL, got %#v", i, vals[i])
+		}
+	}
+}
+
+func TestDbBuildSqlUpdatePartial_Flatten(t *testing.T) {
+	msgDesc := buildFlattenMsgDesc(t)
+	msg := newFlattenCustomer(msgDesc)
+
+	sqlStr, vals, err := dbBuildSqlUpdatePartial(msg, []string{"home.city"}, "", "Customer", msgDesc, msgDesc.Fields(), sqldb.Postgres, false)
+	if err != nil {
+		t.Fatalf("dbBuildSqlUpdatePartial: %v", err)
+	}
+	if !strings.Contains(sqlStr, `"home_city" = $1`) || strings.Contains(sqlStr, "home_zip") || len(vals) != 2 {
+		t.Fatalf("unexpected partial update: %s %#v", sqlStr, vals)
+	}
+
+	sqlStr, _, err = dbBuildSqlUpdatePartial(msg, []string{"home"}, "", "Customer", msgDesc, msgDesc.Fields(), sqldb.Postgres, false)
+	if err != nil {
+		t.Fatalf("dbBuildSqlUpdatePartial: %v", err)
+	}
+	for _, column := range []string{"home_city", "home_zip", "home_geo_lat", "home_geo_lng"} {
+		if !strings.Contains(sqlStr, `"`+column+`" = `) {
+			t.Fatalf("expected %s in: %s", column, sqlStr)
+		}
+	}
+}
+
+func TestDbScan2ProtoMsg_Flatten(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	columns := []string{"id", "home_city", "home_zip", "home_geo_lat", "home_geo_lng", "note"}
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(int64(1), "Paris", nil, 48.85, nil, "n1").
+		AddRow(int64(2), nil, nil, nil, nil, "n2"))
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatalf("db.Query: %v", err)
+	}
+	defer rows.Close()
+
+	msgDesc := buildFlattenMsgDesc(t)
+	home := msgDesc.Fields().ByName("home")
+	msgFieldsMap := pdbutil.BuildMsgFieldsMap(nil, msgDesc.Fields(), true)
+	scanner, err := NewDbRowScanner(rows, dynamicpb.NewMessage(msgDesc), columns, msgFieldsMap)
+	if err != nil {
+		t.Fatalf("NewDbRowScanner: %v", err)
+	}
+
+	if !rows.Next() {
+		t.Fatal("expected first row")
+	}
+	msg := dynamicpb.NewMessage(msgDesc)
+	if err := scanner.Scan(rows, msg); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	homeMsg := msg.Get(home).Message()
+	if got := homeMsg.Get(homeMsg.Descriptor().Fields().ByName("city")).String(); got != "Paris" {
+		t.Fatalf("unexpected home.city: %q", got)
+	}
+	geoMsg := homeMsg.Get(homeMsg.Descriptor().Fields().ByName("geo")).Message()
+	if got := geoMsg.Get(geoMsg.Descriptor().Fields().ByName("lat")).Float(); got != 48.85 {
+		t.Fatalf("unexpected home.geo.lat: %v", got)
+	}
+
+	if !rows.Next() {
+		t.Fatal("expected second row")
+	}
+	msg = dynamicpb.NewMessage(msgDesc)
+	if err := scanner.Scan(rows, msg); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if msg.Has(home) {
+		t.Fatal("all NULL columns should keep sub message unset")
+	}
+}
+
+func TestTableQueryBuildSql_FlattenPath(t *testing.T) {
+	msgDesc := buildFlattenMsgDesc(t)
+	db := &sqldb.DBWithDialect{Executor: dummyDB{}, Dialect: sqldb.Postgres}
+
+	sqlStr, vals, err := TableQueryBuildSql(db, msgDesc, &protodb.TableQueryReq{
+		TableName:      "Customer",
+		Where:          map[string]string{"home.city": "Paris"},
+		Where2:         map[string]string{"home_geo_lat": "40"},
+		Where2Operator: map[string]protodb.WhereOperator{"home_geo_lat": protodb.WhereOperator_WOP_GT},
+	}, "", nil)
+	if err != nil {
+		t.Fatalf("TableQueryBuildSql: %v", err)
+	}
+	if !strings.Contains(sqlStr, `"home_city" = $1`) || !strings.Contains(sqlStr, `"home_geo_lat" > $2`) {
+		t.Fatalf("unexpected where: %s", sqlStr)
+	}
+	if vals[0] != "Paris" {
+		t.Fatalf("unexpected where args: %#v", vals)
+	}
+
+	_, _, err = TableQueryBuildSql(db, msgDesc, &protodb.TableQueryReq{TableName: "Customer", Where: map[string]string{"home": "x"}}, "", nil)
+	if err == nil {
+		t.Fatal("flattened field itself has no column")
+	}
+	_, _, err = TableQueryBuildSql(db, msgDesc, &protodb.TableQueryReq{TableName: "Customer", Where: map[string]string{"home.street": "x"}}, "", nil)
+	if err == nil {
+		t.Fatal("unknown sub field path should be rejected")
+	}
+}
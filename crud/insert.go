@@ -3,7 +3,6 @@ package crud
 import (
 	"database/sql"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/ygrpc/protodb"
@@ -20,7 +19,7 @@ func DbInsertReturn(db sqldb.DB, msg proto.Message, msgLastFieldNo int32, dbsche
 	msgPm := msg.ProtoReflect()
 	msgDesc := msgPm.Descriptor()
 	msgFieldDescs := msgDesc.Fields()
-	tableName := string(msgDesc.Name())
+	tableName := pdbutil.GetTableName(msgDesc)
 
 	return dbInsertReturn(db, msg, msgLastFieldNo, dbschema, tableName, msgDesc, msgFieldDescs)
 }
@@ -31,7 +30,7 @@ func DbInsert(db sqldb.DB, msg proto.Message, msgLastFieldNo int32, dbschema str
 	msgPm := msg.ProtoReflect()
 	msgDesc := msgPm.Descriptor()
 	msgFieldDescs := msgDesc.Fields()
-	tableName := string(msgDesc.Name())
+	tableName := pdbutil.GetTableName(msgDesc)
 
 	return dbInsert(db, msg, msgLastFieldNo, dbschema, tableName, msgDesc, msgFieldDescs)
 }
@@ -62,7 +61,7 @@ func dbInsertReturn(db sqldb.DB, msg proto.Message, msgLastFieldNo int32, dbsche
 	msgFieldDescs protoreflect.FieldDescriptors) (returnMsg proto.Message, err error) {
 
 	dbdialect := sqldb.GetExecutorDialect(db)
-	if dbdialect == sqldb.Mysql {
+	if !sqldb.GetExecutorCapabilities(db).InsertReturning {
 		sqlStr, sqlVals, err := dbBuildSqlInsert(msg, msgLastFieldNo, dbschema, tableName, msgDesc, msgFieldDescs, dbdialect, false)
 		if err != nil {
 			return nil, err
@@ -71,10 +70,10 @@ func dbInsertReturn(db sqldb.DB, msg proto.Message, msgLastFieldNo int32, dbsche
 		if err != nil {
 			return nil, err
 		}
-		if err := mysqlPopulateInsertPrimaryKey(msg, msgDesc, msgFieldDescs, result); err != nil {
+		if err := populateInsertPrimaryKey(msg, msgDesc, msgFieldDescs, result); err != nil {
 			return nil, err
 		}
-		return mysqlSelectReturnedMsg(db, msg, dbschema, tableName, msgDesc, msgFieldDescs)
+		return selectReturnedMsg(db, msg, dbschema, tableName, msgDesc, msgFieldDescs, dbdialect)
 	}
 
 	sqlStr, sqlVals, err := dbBuildSqlInsert(msg, msgLastFieldNo, dbschema, tableName, msgDesc, msgFieldDescs, dbdialect, true)
@@ -96,7 +95,7 @@ func dbInsertReturn(db sqldb.DB, msg proto.Message, msgLastFieldNo int32, dbsche
 
 	msgFieldsMap := pdbutil.BuildMsgFieldsMap(nil, msgDesc.Fields(), true)
 
-	err = DbScan2ProtoMsg(rows, returnMsg, nil, msgFieldsMap)
+	err = dbScan2ProtoMsg(msgStoreOf(db), rows, returnMsg, nil, msgFieldsMap)
 
 	return returnMsg, err
 }
@@ -138,14 +137,15 @@ func dbBuildSqlInsert(msgobj proto.Message, msgLastFieldNo int32, dbschema strin
 	sb.WriteString(protosql.SQL_INSERT_INTO)
 
 	if len(tableName) == 0 {
-		tableName = string(msgDesc.Name())
+		tableName = pdbutil.GetTableName(msgDesc)
 	}
 
-	dbtableName := sqldb.BuildDbTableName(tableName, dbschema, dbdialect)
+	dbtableName := sqldb.BuildQuotedDbTableName(tableName, dbschema, dbdialect)
 	sb.WriteString(dbtableName)
 	sb.WriteString(protosql.SQL_LEFT_PARENTHESES)
 	firstCoumn := true
 	columntCount := 0
+	columnNames := make([]string, 0, msgFieldDescs.Len())
 
 	for fi := 0; fi < msgFieldDescs.Len(); fi++ {
 		field := msgFieldDescs.Get(fi)
@@ -167,7 +167,26 @@ func dbBuildSqlInsert(msgobj proto.Message, msgLastFieldNo int32, dbschema strin
 			}
 		}
 
-		fieldName := string(field.Name())
+		fieldName := pdbutil.GetColumnName(field)
+
+		if pdbutil.IsFlattenField(field) {
+			flatColumnNames, flatVals, err := flatFieldSQLArgs(msgobj, field, dbdialect, false, nil)
+			if err != nil {
+				return "", nil, fmt.Errorf("msg:%s field:%s %w", msgDesc.Name(), fieldName, err)
+			}
+			for _, columnName := range flatColumnNames {
+				if firstCoumn {
+					firstCoumn = false
+				} else {
+					sb.WriteString(protosql.SQL_COMMA)
+				}
+				columntCount++
+				columnNames = append(columnNames, columnName)
+				sb.WriteString(dbdialect.QuoteIdentifier(columnName))
+			}
+			vals = append(vals, flatVals...)
+			continue
+		}
 
 		val, err := getSQLFieldValue(msgobj, field)
 		if err != nil {
@@ -181,8 +200,9 @@ func dbBuildSqlInsert(msgobj proto.Message, msgLastFieldNo int32, dbschema strin
 			sb.WriteString(protosql.SQL_COMMA)
 		}
 		columntCount++
-		sb.WriteString(fieldName)
-		isValZero := pdbutil.IsZeroValue(val)
+		columnNames = append(columnNames, fieldName)
+		sb.WriteString(dbdialect.QuoteIdentifier(fieldName))
+		isValZero := isSQLValZero(field, val)
 		hasSetDefaultValue := false
 		_, hasDefaultValue := fieldPdb.HasDefaultValue()
 		if !fieldPdb.IsNotNull() && (fieldPdb.IsReference() || fieldPdb.IsZeroAsNull()) && isValZero {
@@ -200,6 +220,19 @@ func dbBuildSqlInsert(msgobj proto.Message, msgLastFieldNo int32, dbschema strin
 		}
 		vals = append(vals, val)
 	}
+
+	discriminatorNames, discriminatorVals := oneofDiscriminatorSQLArgs(msgobj, nil)
+	for _, columnName := range discriminatorNames {
+		if firstCoumn {
+			firstCoumn = false
+		} else {
+			sb.WriteString(protosql.SQL_COMMA)
+		}
+		columntCount++
+		columnNames = append(columnNames, columnName)
+		sb.WriteString(dbdialect.QuoteIdentifier(columnName))
+	}
+	vals = append(vals, discriminatorVals...)
 	//sb.WriteString(protosql.SQL_RIGHT_PARENTHESES)
 	//sb.WriteString(protosql.SQL_INSERT_VALUES)
 	//sb.WriteString(protosql.SQL_LEFT_PARENTHESES)
@@ -208,6 +241,7 @@ func dbBuildSqlInsert(msgobj proto.Message, msgLastFieldNo int32, dbschema strin
 	firstPlaceholder := true
 
 	placeholder := dbdialect.Placeholder()
+	mapColumns := mapArgColumns(dbdialect, msgFieldDescs)
 
 	for i := 0; i < columntCount; i++ {
 		if firstPlaceholder {
@@ -215,16 +249,11 @@ func dbBuildSqlInsert(msgobj proto.Message, msgLastFieldNo int32, dbschema strin
 		} else {
 			sb.WriteString(protosql.SQL_COMMA)
 		}
-		if placeholder == protosql.SQL_QUESTION {
-			sb.WriteString(string(protosql.SQL_QUESTION))
-		} else {
-			sb.WriteString(string(protosql.SQL_DOLLAR))
-			sb.WriteString(strconv.Itoa(i + 1))
-		}
+		sb.WriteString(columnArgPlaceholder(dbdialect, mapColumns, columnNames[i], placeholder, i+1))
 	}
 
 	sb.WriteString(protosql.SQL_RIGHT_PARENTHESES)
-	if returnInserted && mysqlSupportsReturning(dbdialect) {
+	if returnInserted {
 		sb.WriteString(" RETURNING * ")
 
 	}
@@ -10,16 +10,13 @@ import (
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
-func mysqlSupportsReturning(dialect sqldb.TDBDialect) bool {
-	return dialect != sqldb.Mysql
+func selectReturnedMsg(db sqldb.DB, msg proto.Message, dbschema string, tableName string,
+	msgDesc protoreflect.MessageDescriptor, msgFieldDescs protoreflect.FieldDescriptors, dialect sqldb.TDBDialect) (proto.Message, error) {
+	return dbSelectOne(db, msg, nil, nil, dbschema, tableName, msgDesc, msgFieldDescs, dialect, true)
 }
 
-func mysqlSelectReturnedMsg(db sqldb.DB, msg proto.Message, dbschema string, tableName string,
-	msgDesc protoreflect.MessageDescriptor, msgFieldDescs protoreflect.FieldDescriptors) (proto.Message, error) {
-	return dbSelectOne(db, msg, nil, nil, dbschema, tableName, msgDesc, msgFieldDescs, sqldb.Mysql, true)
-}
-
-func mysqlPopulateInsertPrimaryKey(msg proto.Message, msgDesc protoreflect.MessageDescriptor, msgFieldDescs protoreflect.FieldDescriptors, result sql.Result) error {
+// populateInsertPrimaryKey set the auto increment primary key from LastInsertId for db without RETURNING
+func populateInsertPrimaryKey(msg proto.Message, msgDesc protoreflect.MessageDescriptor, msgFieldDescs protoreflect.FieldDescriptors, result sql.Result) error {
 	primaryKeyFields := pdbutil.GetPrimaryKeyFieldDescs(msgDesc, msgFieldDescs, false)
 	if len(primaryKeyFields) == 0 {
 		return fmt.Errorf("no primary key field")
@@ -32,7 +29,7 @@ func mysqlPopulateInsertPrimaryKey(msg proto.Message, msgDesc protoreflect.Messa
 		if isZeroPrimaryKeyValue(pm.Get(field), field) {
 			allKeysPresent = false
 			if missingField != nil {
-				return fmt.Errorf("mysql insert return requires a single retrievable primary key")
+				return fmt.Errorf("insert return requires a single retrievable primary key")
 			}
 			missingField = field
 		}
@@ -41,12 +38,12 @@ func mysqlPopulateInsertPrimaryKey(msg proto.Message, msgDesc protoreflect.Messa
 		return nil
 	}
 	if missingField == nil {
-		return fmt.Errorf("mysql insert return cannot determine primary key")
+		return fmt.Errorf("insert return cannot determine primary key")
 	}
 
 	lastInsertID, err := result.LastInsertId()
 	if err != nil {
-		return fmt.Errorf("mysql insert return requires LastInsertId: %w", err)
+		return fmt.Errorf("insert return requires LastInsertId: %w", err)
 	}
 	switch missingField.Kind() {
 	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
@@ -55,16 +52,16 @@ func mysqlPopulateInsertPrimaryKey(msg proto.Message, msgDesc protoreflect.Messa
 		pm.Set(missingField, protoreflect.ValueOfInt64(lastInsertID))
 	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
 		if lastInsertID < 0 {
-			return fmt.Errorf("mysql insert returned negative id %d for unsigned primary key", lastInsertID)
+			return fmt.Errorf("insert returned negative id %d for unsigned primary key", lastInsertID)
 		}
 		pm.Set(missingField, protoreflect.ValueOfUint32(uint32(lastInsertID)))
 	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
 		if lastInsertID < 0 {
-			return fmt.Errorf("mysql insert returned negative id %d for unsigned primary key", lastInsertID)
+			return fmt.Errorf("insert returned negative id %d for unsigned primary key", lastInsertID)
 		}
 		pm.Set(missingField, protoreflect.ValueOfUint64(uint64(lastInsertID)))
 	default:
-		return fmt.Errorf("mysql insert return unsupported primary key kind %v", missingField.Kind())
+		return fmt.Errorf("insert return unsupported primary key kind %v", missingField.Kind())
 	}
 
 	return nil
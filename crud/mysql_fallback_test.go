@@ -60,6 +60,46 @@ func buildCRUDMySQLMessageDesc(t *testing.T) (protoreflect.MessageDescriptor, pr
 	return msgDesc, msgDesc.Fields().ByName("id"), msgDesc.Fields().ByName("name"), msgDesc.Fields().ByName("tags")
 }
 
+// buildCRUDAccountMessageDesc User without the repeated field
+func buildCRUDAccountMessageDesc(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+
+	idOpts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(idOpts, protodb.E_Pdb, &protodb.PDBField{Primary: true, NoInsert: true})
+
+	fdp := &descriptorpb.FileDescriptorProto{
+		Syntax:  strPtr("proto3"),
+		Name:    strPtr("crud_account_test.proto"),
+		Package: strPtr("test"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: strPtr("Account"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{
+						Name:    strPtr("id"),
+						Number:  int32Ptr(1),
+						Label:   descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+						Type:    descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum(),
+						Options: idOpts,
+					},
+					{
+						Name:   strPtr("name"),
+						Number: int32Ptr(2),
+						Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+						Type:   descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+					},
+				},
+			},
+		},
+	}
+
+	fd, err := protodesc.NewFile(fdp, nil)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile: %v", err)
+	}
+	return fd.Messages().ByName("Account")
+}
+
 func newUserMessage(t *testing.T, id int64, name string, tags []string) (proto.Message, protoreflect.MessageDescriptor) {
 	t.Helper()
 
@@ -95,33 +135,76 @@ func TestEncodeSQLArg_RepeatedString_MysqlJSON(t *testing.T) {
 	}
 }
 
-func TestMysqlSupportsReturning(t *testing.T) {
-	if mysqlSupportsReturning(sqldb.Mysql) {
+func TestSupportsReturning(t *testing.T) {
+	if sqldb.DefaultCapabilities(sqldb.Mysql).InsertReturning {
 		t.Fatalf("mysql should not use RETURNING")
 	}
-	if !mysqlSupportsReturning(sqldb.Postgres) {
+	if !sqldb.DefaultCapabilities(sqldb.Postgres).InsertReturning {
 		t.Fatalf("postgres should support RETURNING")
 	}
 }
 
-func TestMysqlPopulateInsertPrimaryKey_FromLastInsertID(t *testing.T) {
+func TestDbInsertReturn_ByServerCapabilities(t *testing.T) {
+	cases := []struct {
+		version   string
+		returning bool
+	}{
+		{"10.11.6-MariaDB-log", true},
+		{"10.4.32-MariaDB", false},
+		{"8.0.36", false},
+	}
+	for _, c := range cases {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("sqlmock.New: %v", err)
+		}
+		executor := &sqldb.DBWithDialect{Executor: db, Dialect: sqldb.Mysql, SQLDB: db}
+		msgDesc := buildCRUDAccountMessageDesc(t)
+		msg := dynamicpb.NewMessage(msgDesc)
+		msg.Set(msgDesc.Fields().ByName("name"), protoreflect.ValueOfString("alice"))
+		rows := sqlmock.NewRows([]string{"id", "name"}).AddRow(int64(7), "alice")
+
+		mock.ExpectQuery(`SELECT VERSION\(\)`).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(c.version))
+		if c.returning {
+			mock.ExpectQuery("INSERT INTO `Account` .* RETURNING \\*").WillReturnRows(rows)
+		} else {
+			mock.ExpectExec("INSERT INTO `Account`").WillReturnResult(sqlmock.NewResult(7, 1))
+			mock.ExpectQuery("SELECT .* FROM `Account`").WillReturnRows(rows)
+		}
+
+		got, err := dbInsertReturn(executor, msg, 0, "", "Account", msgDesc, msgDesc.Fields())
+		if err != nil {
+			t.Fatalf("%s dbInsertReturn: %v", c.version, err)
+		}
+		if id := got.ProtoReflect().Get(msgDesc.Fields().ByName("id")).Int(); id != 7 {
+			t.Fatalf("%s unexpected id: %d", c.version, id)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Fatalf("%s expectations: %v", c.version, err)
+		}
+		sqldb.ClearDBDialectCache(db)
+		db.Close()
+	}
+}
+
+func TestPopulateInsertPrimaryKey_FromLastInsertID(t *testing.T) {
 	msg, msgDesc := newUserMessage(t, 0, "alice", []string{"a", "b"})
 	idField := msgDesc.Fields().ByName("id")
 
-	if err := mysqlPopulateInsertPrimaryKey(msg, msgDesc, msgDesc.Fields(), sqlmock.NewResult(7, 1)); err != nil {
-		t.Fatalf("mysqlPopulateInsertPrimaryKey: %v", err)
+	if err := populateInsertPrimaryKey(msg, msgDesc, msgDesc.Fields(), sqlmock.NewResult(7, 1)); err != nil {
+		t.Fatalf("populateInsertPrimaryKey: %v", err)
 	}
 	if got := msg.ProtoReflect().Get(idField).Int(); got != 7 {
 		t.Fatalf("unexpected id: %d", got)
 	}
 }
 
-func TestMysqlPopulateInsertPrimaryKey_KeepsExistingKey(t *testing.T) {
+func TestPopulateInsertPrimaryKey_KeepsExistingKey(t *testing.T) {
 	msg, msgDesc := newUserMessage(t, 9, "alice", nil)
 	idField := msgDesc.Fields().ByName("id")
 
-	if err := mysqlPopulateInsertPrimaryKey(msg, msgDesc, msgDesc.Fields(), sqlmock.NewResult(7, 1)); err != nil {
-		t.Fatalf("mysqlPopulateInsertPrimaryKey: %v", err)
+	if err := populateInsertPrimaryKey(msg, msgDesc, msgDesc.Fields(), sqlmock.NewResult(7, 1)); err != nil {
+		t.Fatalf("populateInsertPrimaryKey: %v", err)
 	}
 	if got := msg.ProtoReflect().Get(idField).Int(); got != 9 {
 		t.Fatalf("unexpected id overwrite: %d", got)
@@ -0,0 +1,215 @@
+package crud
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func buildSnakeMsgDesc(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+
+	fileOpts := &descriptorpb.FileOptions{}
+	proto.SetExtension(fileOpts, protodb.E_Pdbf, &protodb.PDBFile{NameStyle: pdbutil.NameStyleSnake})
+	msgOpts := &descriptorpb.MessageOptions{}
+	proto.SetExtension(msgOpts, protodb.E_Pdbm, &protodb.PDBMsg{TableName: "t_user_account"})
+	idOpts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(idOpts, protodb.E_Pdb, &protodb.PDBField{Primary: true})
+	mailOpts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(mailOpts, protodb.E_Pdb, &protodb.PDBField{ColumnName: "mail"})
+
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, opts *descriptorpb.FieldOptions) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:    strPtr(name),
+			Number:  int32Ptr(number),
+			Label:   descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:    typ.Enum(),
+			Options: opts,
+		}
+	}
+
+	fdp := &descriptorpb.FileDescriptorProto{
+		Syntax:  strPtr("proto3"),
+		Name:    strPtr("names_test.proto"),
+		Package: strPtr("test"),
+		Options: fileOpts,
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name:    strPtr("UserAccount"),
+				Options: msgOpts,
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("UserID", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64, idOpts),
+					field("DisplayName", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, nil),
+					field("EMail", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING, mailOpts),
+				},
+			},
+		},
+	}
+
+	fd, err := protodesc.NewFile(fdp, nil)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile: %v", err)
+	}
+	return fd.Messages().ByName("UserAccount")
+}
+
+func newSnakeUser(msgDesc protoreflect.MessageDescriptor) *dynamicpb.Message {
+	msg := dynamicpb.NewMessage(msgDesc)
+	msg.Set(msgDesc.Fields().ByName("UserID"), protoreflect.ValueOfInt64(7))
+	msg.Set(msgDesc.Fields().ByName("DisplayName"), protoreflect.ValueOfString("Ann"))
+	msg.Set(msgDesc.Fields().ByName("EMail"), protoreflect.ValueOfString("ann@example.com"))
+	return msg
+}
+
+func TestCrudSql_ColumnAndTableNames(t *testing.T) {
+	msgDesc := buildSnakeMsgDesc(t)
+	msg := newSnakeUser(msgDesc)
+	tableName := pdbutil.GetTableName(msgDesc)
+
+	sqlStr, _, err := dbBuildSqlInsert(msg, 0, "", tableName, msgDesc, msgDesc.Fields(), sqldb.Postgres, false)
+	if err != nil {
+		t.Fatalf("dbBuildSqlInsert: %v", err)
+	}
+	if !strings.Contains(sqlStr, `INSERT INTO "t_user_account" ( "user_id" , "display_name" , "mail" )`) {
+		t.Fatalf("unexpected insert: %s", sqlStr)
+	}
+
+	sqlStr, _, err = dbBuildSqlUpdate(msg, 0, "", tableName, msgDesc, msgDesc.Fields(), sqldb.Postgres, false)
+	if err != nil {
+		t.Fatalf("dbBuildSqlUpdate: %v", err)
+	}
+	if !strings.Contains(sqlStr, `"display_name" = $1`) || !strings.Contains(sqlStr, `"mail" = $2`) || !strings.Contains(sqlStr, `WHERE "user_id" = $3`) {
+		t.Fatalf("unexpected update: %s", sqlStr)
+	}
+
+	// partial update fields accept field names and column names
+	for _, updateField := range []string{"DisplayName", "display_name"} {
+		sqlStr, _, err = dbBuildSqlUpdatePartial(msg, []string{updateField}, "", tableName, msgDesc, msgDesc.Fields(), sqldb.Postgres, false)
+		if err != nil {
+			t.Fatalf("dbBuildSqlUpdatePartial %s: %v", updateField, err)
+		}
+		if !strings.Contains(sqlStr, `SET "display_name" = $1`) || strings.Contains(sqlStr, "mail") {
+			t.Fatalf("unexpected partial update by %s: %s", updateField, sqlStr)
+		}
+	}
+
+	sqlStr, _, err = dbBuildSqlDelete(msg, "", tableName, msgDesc, msgDesc.Fields(), sqldb.Postgres, false)
+	if err != nil {
+		t.Fatalf("dbBuildSqlDelete: %v", err)
+	}
+	if !strings.Contains(sqlStr, `DELETE FROM "t_user_account" WHERE "user_id" = $1`) {
+		t.Fatalf("unexpected delete: %s", sqlStr)
+	}
+
+	sqlStr, vals, err := dbBuildSqlSelectOne(msg, []string{"EMail"}, nil, "", tableName, msgDesc, msgDesc.Fields(), sqldb.Postgres, false)
+	if err != nil {
+		t.Fatalf("dbBuildSqlSelectOne: %v", err)
+	}
+	if !strings.Contains(sqlStr, `WHERE "mail" = $1`) || vals[0] != "ann@example.com" {
+		t.Fatalf("unexpected select one: %s %#v", sqlStr, vals)
+	}
+}
+
+func TestTableQueryBuildSql_ColumnAndTableNames(t *testing.T) {
+	msgDesc := buildSnakeMsgDesc(t)
+	db := &sqldb.DBWithDialect{Executor: dummyDB{}, Dialect: sqldb.Postgres}
+
+	for _, tableName := range []string{"UserAccount", "t_user_account"} {
+		sqlStr, _, err := TableQueryBuildSql(db, msgDesc, &protodb.TableQueryReq{
+			TableName: tableName,
+			Where:     map[string]string{"DisplayName": "Ann"},
+			Where2:    map[string]string{"mail": "%@example.com"},
+			Where2Operator: map[string]protodb.WhereOperator{
+				"mail": protodb.WhereOperator_WOP_LIKE,
+			},
+		}, "", nil)
+		if err != nil {
+			t.Fatalf("TableQueryBuildSql %s: %v", tableName, err)
+		}
+		if !strings.Contains(sqlStr, `FROM "t_user_account" WHERE "display_name" = $1 AND "mail" LIKE $2`) {
+			t.Fatalf("unexpected table query for %s: %s", tableName, sqlStr)
+		}
+	}
+
+	_, _, err := TableQueryBuildSql(db, msgDesc, &protodb.TableQueryReq{TableName: "user_account"}, "", nil)
+	if err == nil {
+		t.Fatal("table name overridden by TableName should not match snake message name")
+	}
+}
+
+func TestTableQueryBuildSql_OrderBy(t *testing.T) {
+	msgDesc := buildSnakeMsgDesc(t)
+	db := &sqldb.DBWithDialect{Executor: dummyDB{}, Dialect: sqldb.Postgres}
+
+	sqlStr, _, err := TableQueryBuildSql(db, msgDesc, &protodb.TableQueryReq{
+		TableName: "UserAccount",
+		OrderBy:   []string{"-DisplayName", "user_id"},
+		Limit:     10,
+	}, "", nil)
+	if err != nil {
+		t.Fatalf("TableQueryBuildSql: %v", err)
+	}
+	if !strings.Contains(sqlStr, `ORDER BY "display_name" DESC , "user_id" LIMIT 10`) {
+		t.Fatalf("unexpected order by: %s", sqlStr)
+	}
+
+	_, _, err = TableQueryBuildSql(db, msgDesc, &protodb.TableQueryReq{TableName: "UserAccount", OrderBy: []string{"nope; drop table x"}}, "", nil)
+	if err == nil {
+		t.Fatal("unknown order by field should fail")
+	}
+}
+
+func TestCrudSql_ReservedWordColumns(t *testing.T) {
+	idOpts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(idOpts, protodb.E_Pdb, &protodb.PDBField{Primary: true})
+	fdp := &descriptorpb.FileDescriptorProto{
+		Syntax:  strPtr("proto3"),
+		Name:    strPtr("reserved_test.proto"),
+		Package: strPtr("test"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: strPtr("Group"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: strPtr("order"), Number: int32Ptr(1), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum(), Options: idOpts},
+					{Name: strPtr("limit"), Number: int32Ptr(2), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum()},
+				},
+			},
+		},
+	}
+	fd, err := protodesc.NewFile(fdp, nil)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile: %v", err)
+	}
+	msgDesc := fd.Messages().ByName("Group")
+	msg := dynamicpb.NewMessage(msgDesc)
+	msg.Set(msgDesc.Fields().ByName("order"), protoreflect.ValueOfInt64(1))
+
+	sqlStr, _, err := dbBuildSqlInsert(msg, 0, "", "Group", msgDesc, msgDesc.Fields(), sqldb.Mysql, false)
+	if err != nil {
+		t.Fatalf("dbBuildSqlInsert: %v", err)
+	}
+	if !strings.Contains(sqlStr, "INSERT INTO `Group` ( `order` , `limit` )") {
+		t.Fatalf("unexpected mysql insert: %s", sqlStr)
+	}
+
+	db := &sqldb.DBWithDialect{Executor: dummyDB{}, Dialect: sqldb.SQLite}
+	sqlStr, _, err = TableQueryBuildSql(db, msgDesc, &protodb.TableQueryReq{
+		TableName:         "Group",
+		ResultColumnNames: []string{"order", "count(1)"},
+		Where:             map[string]string{"order": "1"},
+	}, "", nil)
+	if err != nil {
+		t.Fatalf("TableQueryBuildSql: %v", err)
+	}
+	if !strings.Contains(sqlStr, `SELECT "order" , count(1) FROM "Group" WHERE "order" = ?`) {
+		t.Fatalf("unexpected sqlite table query: %s", sqlStr)
+	}
+}
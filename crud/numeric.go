@@ -0,0 +1,157 @@
+package crud
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/protosql"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// numericFieldPdb get pdb of string field with NUMERIC db type
+func numericFieldPdb(fd protoreflect.FieldDescriptor) (*protodb.PDBField, bool) {
+	if fd.IsList() || fd.IsMap() || fd.Kind() != protoreflect.StringKind {
+		return nil, false
+	}
+	pdb, _ := pdbutil.GetPDB(fd)
+	if !pdb.IsNumeric() {
+		return nil, false
+	}
+	return pdb, true
+}
+
+// NormalizeNumericString validate decimal string and format it canonically:
+// no exponent, no leading zeros, scale digits after the point when scale > 0,
+// otherwise no trailing zeros. precision > 0 limits the integer digits to precision-scale.
+func NormalizeNumericString(s string, precision int32, scale int32) (string, error) {
+	str := strings.TrimSpace(s)
+	if len(str) == 0 {
+		return "", fmt.Errorf("empty numeric value")
+	}
+
+	neg := false
+	switch str[0] {
+	case '-':
+		neg = true
+		str = str[1:]
+	case '+':
+		str = str[1:]
+	}
+
+	exp := 0
+	if i := strings.IndexAny(str, "eE"); i >= 0 {
+		e, err := strconv.Atoi(str[i+1:])
+		if err != nil {
+			return "", fmt.Errorf("invalid numeric value %q", s)
+		}
+		exp = e
+		str = str[:i]
+	}
+
+	intPart, fracPart, _ := strings.Cut(str, ".")
+	if len(intPart) == 0 && len(fracPart) == 0 {
+		return "", fmt.Errorf("invalid numeric value %q", s)
+	}
+	if !isDigits(intPart) || !isDigits(fracPart) {
+		return "", fmt.Errorf("invalid numeric value %q", s)
+	}
+	if exp > 1000 || exp < -1000 {
+		return "", fmt.Errorf("numeric exponent out of range %q", s)
+	}
+
+	// shift decimal point by exponent
+	digits := intPart + fracPart
+	point := len(intPart) + exp
+	if point < 0 {
+		digits = strings.Repeat("0", -point) + digits
+		point = 0
+	}
+	if point > len(digits) {
+		digits += strings.Repeat("0", point-len(digits))
+	}
+	intPart, fracPart = digits[:point], digits[point:]
+
+	if scale > 0 || precision > 0 {
+		intPart, fracPart = roundNumericFrac(intPart, fracPart, int(scale))
+	} else {
+		fracPart = strings.TrimRight(fracPart, "0")
+	}
+
+	intPart = strings.TrimLeft(intPart, "0")
+	if precision > 0 && len(intPart) > int(precision-scale) {
+		return "", fmt.Errorf("numeric value %q out of range for precision %d scale %d", s, precision, scale)
+	}
+	if len(intPart) == 0 {
+		intPart = "0"
+	}
+
+	result := intPart
+	if len(fracPart) > 0 {
+		result += "." + fracPart
+	}
+	if neg && strings.Trim(result, "0.") != "" {
+		result = "-" + result
+	}
+	return result, nil
+}
+
+// roundNumericFrac round fraction to scale digits, half away from zero like numeric in db
+func roundNumericFrac(intPart string, fracPart string, scale int) (string, string) {
+	if len(fracPart) <= scale {
+		return intPart, fracPart + strings.Repeat("0", scale-len(fracPart))
+	}
+
+	roundUp := fracPart[scale] >= '5'
+	digits := []byte(intPart + fracPart[:scale])
+	if roundUp {
+		i := len(digits) - 1
+		for ; i >= 0; i-- {
+			if digits[i] == '9' {
+				digits[i] = '0'
+				continue
+			}
+			digits[i]++
+			break
+		}
+		if i < 0 {
+			digits = append([]byte{'1'}, digits...)
+		}
+	}
+	point := len(digits) - scale
+	return string(digits[:point]), string(digits[point:])
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// encodeNumericSQLArg normalize numeric string, empty string is NULL
+func encodeNumericSQLArg(pdb *protodb.PDBField, goValue any) (any, error) {
+	s, ok := goValue.(string)
+	if !ok {
+		return goValue, nil
+	}
+	if len(strings.TrimSpace(s)) == 0 {
+		return nil, nil
+	}
+	return NormalizeNumericString(s, pdb.NumericPrecision, pdb.NumericScale)
+}
+
+// numericPlaceholder bind placeholder as numeric so comparisons are not textual
+func numericPlaceholder(dialect sqldb.TDBDialect, pdb *protodb.PDBField, placeholder protosql.SQLPlaceholder, paraNo int) string {
+	return dialect.Dialect().NumericCast(buildPlaceholder(placeholder, paraNo), pdb.NumericPrecision, pdb.NumericScale)
+}
+
+// numericColumn numeric column expression for comparing, sqlite stores numeric as text
+func numericColumn(dialect sqldb.TDBDialect, fieldName string) string {
+	return dialect.Dialect().NumericColumn(fieldName)
+}
@@ -0,0 +1,174 @@
+package crud
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/protosql"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func buildNumericMsgDesc(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+
+	amountOpts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(amountOpts, protodb.E_Pdb, &protodb.PDBField{DbType: protodb.FieldDbType_NUMERIC, NumericPrecision: 10, NumericScale: 2})
+	rateOpts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(rateOpts, protodb.E_Pdb, &protodb.PDBField{DbType: protodb.FieldDbType_NUMERIC})
+
+	fdp := &descriptorpb.FileDescriptorProto{
+		Syntax:  strPtr("proto3"),
+		Name:    strPtr("numeric_test.proto"),
+		Package: strPtr("test"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: strPtr("Payment"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: strPtr("id"), Number: int32Ptr(1), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum()},
+					{Name: strPtr("amount"), Number: int32Ptr(2), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Options: amountOpts},
+					{Name: strPtr("rate"), Number: int32Ptr(3), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Options: rateOpts},
+				},
+			},
+		},
+	}
+
+	fd, err := protodesc.NewFile(fdp, nil)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile: %v", err)
+	}
+	return fd.Messages().ByName("Payment")
+}
+
+func TestNormalizeNumericString(t *testing.T) {
+	cases := []struct {
+		in        string
+		precision int32
+		scale     int32
+		want      string
+	}{
+		{"12.5", 10, 2, "12.50"},
+		{"+0012.345", 10, 2, "12.35"},
+		{"-12.345", 10, 2, "-12.35"},
+		{"99.995", 10, 2, "100.00"},
+		{"-0.001", 10, 2, "0.00"},
+		{"1.5e2", 0, 0, "150"},
+		{"1234.5000", 0, 0, "1234.5"},
+		{".25", 0, 0, "0.25"},
+		{"2.5E-3", 0, 0, "0.0025"},
+		{"7", 5, 0, "7"},
+		{"0.5", 5, 0, "1"},
+		{"123456789012345678901234567890.123456789", 0, 0, "123456789012345678901234567890.123456789"},
+	}
+	for _, c := range cases {
+		got, err := NormalizeNumericString(c.in, c.precision, c.scale)
+		if err != nil {
+			t.Fatalf("NormalizeNumericString(%q): %v", c.in, err)
+		}
+		if got != c.want {
+			t.Fatalf("NormalizeNumericString(%q, %d, %d) = %q, want %q", c.in, c.precision, c.scale, got, c.want)
+		}
+	}
+
+	for _, bad := range []string{"", "abc", "1.2.3", "1e", "--1", "0x10", "."} {
+		if _, err := NormalizeNumericString(bad, 0, 0); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+	if _, err := NormalizeNumericString("123456789.1", 10, 2); err == nil {
+		t.Fatal("expected out of range error")
+	}
+}
+
+func TestEncodeSQLArg_Numeric(t *testing.T) {
+	msgDesc := buildNumericMsgDesc(t)
+	amount := msgDesc.Fields().ByName("amount")
+
+	v, err := EncodeSQLArg(amount, sqldb.SQLite, "3.1")
+	if err != nil || v != "3.10" {
+		t.Fatalf("unexpected numeric arg: %#v err:%v", v, err)
+	}
+	v, err = EncodeSQLArg(amount, sqldb.Postgres, "")
+	if err != nil || v != nil {
+		t.Fatalf("empty numeric should be NULL, got %#v err:%v", v, err)
+	}
+	if _, err := EncodeSQLArg(amount, sqldb.Postgres, "1,5"); err == nil {
+		t.Fatal("expected invalid numeric error")
+	}
+}
+
+func TestDbScan2ProtoMsg_Numeric(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	columns := []string{"id", "amount", "rate"}
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows(columns).AddRow(int64(1), []byte("12.5"), "0.0500"))
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatalf("db.Query: %v", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		t.Fatal("expected one row")
+	}
+
+	msgDesc := buildNumericMsgDesc(t)
+	msg := dynamicpb.NewMessage(msgDesc)
+	msgFieldsMap := pdbutil.BuildMsgFieldsMap(columns, msgDesc.Fields(), true)
+	if err := DbScan2ProtoMsg(rows, msg, columns, msgFieldsMap); err != nil {
+		t.Fatalf("DbScan2ProtoMsg: %v", err)
+	}
+	if got := msg.Get(msgDesc.Fields().ByName("amount")).String(); got != "12.50" {
+		t.Fatalf("unexpected amount: %q", got)
+	}
+	if got := msg.Get(msgDesc.Fields().ByName("rate")).String(); got != "0.05" {
+		t.Fatalf("unexpected rate: %q", got)
+	}
+}
+
+func TestBuildWhere2Condition_Numeric(t *testing.T) {
+	msgDesc := buildNumericMsgDesc(t)
+	amount := msgDesc.Fields().ByName("amount")
+
+	cond, args, _, err := buildWhere2Condition(sqldb.Postgres, protosql.SQL_DOLLAR, 3, amount, protodb.WhereOperator_WOP_GTE, "1e3")
+	if err != nil {
+		t.Fatalf("buildWhere2Condition: %v", err)
+	}
+	if cond != "amount >= $3::numeric" || args[0] != "1000" {
+		t.Fatalf("unexpected pg cond: %s %#v", cond, args)
+	}
+
+	cond, _, _, err = buildWhere2Condition(sqldb.Mysql, protosql.SQL_QUESTION, 1, amount, protodb.WhereOperator_WOP_LT, "5")
+	if err != nil || cond != "amount < CAST(? AS decimal(10,2))" {
+		t.Fatalf("unexpected mysql cond: %s err:%v", cond, err)
+	}
+
+	cond, _, _, err = buildWhere2Condition(sqldb.SQLite, protosql.SQL_QUESTION, 1, amount, protodb.WhereOperator_WOP_GT, "5")
+	if err != nil || cond != "CAST(amount AS NUMERIC) > CAST(? AS NUMERIC)" {
+		t.Fatalf("unexpected sqlite cond: %s err:%v", cond, err)
+	}
+
+	if _, _, _, err := buildWhere2Condition(sqldb.Postgres, protosql.SQL_DOLLAR, 1, amount, protodb.WhereOperator_WOP_GT, "abc"); err == nil {
+		t.Fatal("expected invalid numeric error")
+	}
+
+	db := &sqldb.DBWithDialect{Executor: dummyDB{}, Dialect: sqldb.Postgres}
+	sqlStr, vals, err := TableQueryBuildSql(db, msgDesc, &protodb.TableQueryReq{TableName: "Payment", Where: map[string]string{"amount": "10.0"}}, "", nil)
+	if err != nil {
+		t.Fatalf("TableQueryBuildSql: %v", err)
+	}
+	if !strings.Contains(sqlStr, `"amount" = $1::numeric`) || vals[0] != "10" {
+		t.Fatalf("unexpected where: %s %#v", sqlStr, vals)
+	}
+}
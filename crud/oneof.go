@@ -0,0 +1,152 @@
+package crud
+
+import (
+	"fmt"
+
+	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/protosql"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// oneofDiscriminatorSQLArgs discriminator columns and set member names of message oneofs,
+// touched select oneofs by full name, nil for all oneofs
+func oneofDiscriminatorSQLArgs(msgobj proto.Message, touched map[protoreflect.FullName]bool) (columnNames []string, vals []any) {
+	pm := msgobj.ProtoReflect()
+	oneofs := pm.Descriptor().Oneofs()
+	for i := 0; i < oneofs.Len(); i++ {
+		oneofDesc := oneofs.Get(i)
+		columnName, ok := pdbutil.GetOneofDiscriminator(oneofDesc)
+		if !ok {
+			continue
+		}
+		if touched != nil && !touched[oneofDesc.FullName()] {
+			continue
+		}
+		columnNames = append(columnNames, columnName)
+		if member := pm.WhichOneof(oneofDesc); member != nil {
+			vals = append(vals, string(member.Name()))
+		} else {
+			vals = append(vals, pdbutil.NullValue)
+		}
+	}
+	return columnNames, vals
+}
+
+// expandOneofUpdateFields add all members of oneof into partial update fields when one member
+// or the oneof name is updated, so the other members are cleared. return the touched oneofs
+func expandOneofUpdateFields(msgDesc protoreflect.MessageDescriptor, updateFieldsMap map[string]bool) map[protoreflect.FullName]bool {
+	touched := map[protoreflect.FullName]bool{}
+	oneofs := msgDesc.Oneofs()
+	for i := 0; i < oneofs.Len(); i++ {
+		oneofDesc := oneofs.Get(i)
+		if oneofDesc.IsSynthetic() {
+			continue
+		}
+		members := oneofDesc.Fields()
+		isTouched := updateFieldsMap[string(oneofDesc.Name())]
+		for j := 0; j < members.Len() && !isTouched; j++ {
+			isTouched = isPartialUpdateField(updateFieldsMap, members.Get(j))
+		}
+		if !isTouched {
+			continue
+		}
+		touched[oneofDesc.FullName()] = true
+		for j := 0; j < members.Len(); j++ {
+			updateFieldsMap[string(members.Get(j).Name())] = true
+		}
+	}
+	return touched
+}
+
+// applyOneofDiscriminator keep only the member recorded in discriminator column as set
+func applyOneofDiscriminator(msg proto.Message, oneofDesc protoreflect.OneofDescriptor, v any) error {
+	pm := msg.ProtoReflect()
+	var active protoreflect.FieldDescriptor
+	switch x := unwrapScanVal(v).(type) {
+	case nil:
+	case string:
+		active = oneofMemberByName(oneofDesc, x)
+	case []byte:
+		active = oneofMemberByName(oneofDesc, string(x))
+	default:
+		return fmt.Errorf("oneof %s discriminator expects string, got %T", oneofDesc.Name(), x)
+	}
+
+	current := pm.WhichOneof(oneofDesc)
+	if current != nil && (active == nil || current.Number() != active.Number()) {
+		pm.Clear(current)
+		current = nil
+	}
+	if active != nil && current == nil {
+		// the member is set with zero value
+		if active.Kind() == protoreflect.MessageKind || active.Kind() == protoreflect.GroupKind {
+			pm.Set(active, protoreflect.ValueOfMessage(pm.NewField(active).Message()))
+		} else {
+			pm.Set(active, active.Default())
+		}
+	}
+	return nil
+}
+
+func oneofMemberByName(oneofDesc protoreflect.OneofDescriptor, name string) protoreflect.FieldDescriptor {
+	if len(name) == 0 {
+		return nil
+	}
+	return oneofDesc.Fields().ByName(protoreflect.Name(name))
+}
+
+// oneofCaseCondition where condition of oneof active member, empty memberName means none is set.
+// use discriminator column if enabled, otherwise check member columns is null
+func oneofCaseCondition(msgDesc protoreflect.MessageDescriptor, dialect sqldb.TDBDialect, placeholder protosql.SQLPlaceholder, paraNo int,
+	oneofName string, memberName string) (cond string, args []any, argInc int, err error) {
+	oneofDesc, member, err := resolveOneofCase(msgDesc, oneofName, memberName)
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	if discriminator, ok := pdbutil.GetOneofDiscriminator(oneofDesc); ok {
+		discriminator = dialect.QuoteIdentifier(discriminator)
+		if member == nil {
+			return discriminator + protosql.SQL_IS_NULL, nil, 0, nil
+		}
+		cond = discriminator + protosql.SQL_EQUEAL + buildPlaceholder(placeholder, paraNo)
+		if placeholder != protosql.SQL_QUESTION {
+			argInc = 1
+		}
+		return cond, []any{string(member.Name())}, argInc, nil
+	}
+
+	if member != nil {
+		return dialect.QuoteIdentifier(pdbutil.GetColumnName(member)) + protosql.SQL_IS_NOT_NULL, nil, 0, nil
+	}
+	members := oneofDesc.Fields()
+	cond = protosql.SQL_LEFT_PARENTHESES
+	for i := 0; i < members.Len(); i++ {
+		if i > 0 {
+			cond += protosql.SQL_AND
+		}
+		cond += dialect.QuoteIdentifier(pdbutil.GetColumnName(members.Get(i))) + protosql.SQL_IS_NULL
+	}
+	return cond + protosql.SQL_RIGHT_PARENTHESES, nil, 0, nil
+}
+
+// resolveOneofCase check oneof and member name of OneofCase filter
+func resolveOneofCase(msgDesc protoreflect.MessageDescriptor, oneofName string, memberName string) (protoreflect.OneofDescriptor, protoreflect.FieldDescriptor, error) {
+	oneofDesc, ok := pdbutil.FindRealOneof(msgDesc, oneofName)
+	if !ok {
+		return nil, nil, fmt.Errorf("oneof %s not found in message %s", oneofName, msgDesc.FullName())
+	}
+	if len(memberName) == 0 {
+		return oneofDesc, nil, nil
+	}
+	members := oneofDesc.Fields()
+	for i := 0; i < members.Len(); i++ {
+		member := members.Get(i)
+		if memberName == string(member.Name()) || memberName == member.TextName() || memberName == pdbutil.GetColumnName(member) {
+			return oneofDesc, member, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("field %s is not a member of oneof %s", memberName, oneofDesc.FullName())
+}
@@ -0,0 +1,206 @@
+package crud
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func buildOneofMsgDesc(t *testing.T, discriminator bool) protoreflect.MessageDescriptor {
+	t.Helper()
+
+	idOpts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(idOpts, protodb.E_Pdb, &protodb.PDBField{Primary: true})
+	oneofOpts := &descriptorpb.OneofOptions{}
+	if discriminator {
+		proto.SetExtension(oneofOpts, protodb.E_Pdbo, &protodb.PDBOneof{Discriminator: true})
+	}
+
+	member := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:       strPtr(name),
+			Number:     int32Ptr(number),
+			Label:      descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:       typ.Enum(),
+			OneofIndex: int32Ptr(0),
+		}
+	}
+	card := member("card", 3, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE)
+	card.TypeName = strPtr(".test.Card")
+
+	fdp := &descriptorpb.FileDescriptorProto{
+		Syntax:  strPtr("proto3"),
+		Name:    strPtr("oneof_test.proto"),
+		Package: strPtr("test"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: strPtr("Card"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: strPtr("number"), Number: int32Ptr(1), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()},
+				},
+			},
+			{
+				Name: strPtr("Payment"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: strPtr("id"), Number: int32Ptr(1), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum(), Options: idOpts},
+					member("cash", 2, descriptorpb.FieldDescriptorProto_TYPE_INT64),
+					card,
+					member("voucher", 4, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+				},
+				OneofDecl: []*descriptorpb.OneofDescriptorProto{{Name: strPtr("method"), Options: oneofOpts}},
+			},
+		},
+	}
+
+	fd, err := protodesc.NewFile(fdp, nil)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile: %v", err)
+	}
+	return fd.Messages().ByName("Payment")
+}
+
+func TestDbBuildSqlInsert_OneofDiscriminator(t *testing.T) {
+	msgDesc := buildOneofMsgDesc(t, true)
+	msg := dynamicpb.NewMessage(msgDesc)
+	msg.Set(msgDesc.Fields().ByName("id"), protoreflect.ValueOfInt64(1))
+	msg.Set(msgDesc.Fields().ByName("voucher"), protoreflect.ValueOfString("V1"))
+
+	sqlStr, vals, err := dbBuildSqlInsert(msg, 0, "", "", msgDesc, msgDesc.Fields(), sqldb.Postgres, false)
+	if err != nil {
+		t.Fatalf("dbBuildSqlInsert: %v", err)
+	}
+	if !strings.Contains(sqlStr, `( "id" , "cash" , "card" , "voucher" , "method_case" )`) {
+		t.Fatalf("unexpected insert columns: %s", sqlStr)
+	}
+	if len(vals) != 5 || vals[1] != pdbutil.NullValue || vals[2] != pdbutil.NullValue || vals[3] != "V1" || vals[4] != "voucher" {
+		t.Fatalf("unexpected insert vals: %#v", vals)
+	}
+
+	// no member set
+	msg.Clear(msgDesc.Fields().ByName("voucher"))
+	_, vals, err = dbBuildSqlInsert(msg, 0, "", "", msgDesc, msgDesc.Fields(), sqldb.Postgres, false)
+	if err != nil {
+		t.Fatalf("dbBuildSqlInsert: %v", err)
+	}
+	if vals[3] != pdbutil.NullValue || vals[4] != pdbutil.NullValue {
+		t.Fatalf("unset oneof should be NULL: %#v", vals)
+	}
+}
+
+func TestDbBuildSqlUpdatePartial_OneofClearsOtherMembers(t *testing.T) {
+	msgDesc := buildOneofMsgDesc(t, true)
+	msg := dynamicpb.NewMessage(msgDesc)
+	msg.Set(msgDesc.Fields().ByName("id"), protoreflect.ValueOfInt64(1))
+	msg.Set(msgDesc.Fields().ByName("cash"), protoreflect.ValueOfInt64(0))
+
+	sqlStr, vals, err := dbBuildSqlUpdatePartial(msg, []string{"cash"}, "", "Payment", msgDesc, msgDesc.Fields(), sqldb.Postgres, false)
+	if err != nil {
+		t.Fatalf("dbBuildSqlUpdatePartial: %v", err)
+	}
+	for _, want := range []string{`"cash" = $1`, `"card" = $2`, `"voucher" = $3`, `"method_case" = $4`, `WHERE "id" = $5`} {
+		if !strings.Contains(sqlStr, want) {
+			t.Fatalf("expected %q in: %s", want, sqlStr)
+		}
+	}
+	if vals[0] != int64(0) || vals[1] != pdbutil.NullValue || vals[2] != pdbutil.NullValue || vals[3] != "cash" {
+		t.Fatalf("unexpected partial update vals: %#v", vals)
+	}
+
+	// oneof name updates all members
+	sqlStr, _, err = dbBuildSqlUpdatePartial(msg, []string{"method"}, "", "Payment", msgDesc, msgDesc.Fields(), sqldb.Postgres, false)
+	if err != nil {
+		t.Fatalf("dbBuildSqlUpdatePartial: %v", err)
+	}
+	if !strings.Contains(sqlStr, `"voucher" = $3`) || !strings.Contains(sqlStr, `"method_case" = $4`) {
+		t.Fatalf("unexpected partial update by oneof name: %s", sqlStr)
+	}
+}
+
+func TestDbScan2ProtoMsg_OneofDiscriminator(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	columns := []string{"id", "cash", "card", "voucher", "method_case"}
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(int64(1), int64(0), nil, nil, "cash").
+		AddRow(int64(2), nil, nil, nil, "card"))
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatalf("db.Query: %v", err)
+	}
+	defer rows.Close()
+
+	msgDesc := buildOneofMsgDesc(t, true)
+	msgFieldsMap := pdbutil.BuildMsgFieldsMap(nil, msgDesc.Fields(), true)
+	scanner, err := NewDbRowScanner(rows, dynamicpb.NewMessage(msgDesc), columns, msgFieldsMap)
+	if err != nil {
+		t.Fatalf("NewDbRowScanner: %v", err)
+	}
+	oneofDesc := msgDesc.Oneofs().ByName("method")
+
+	if !rows.Next() {
+		t.Fatal("expected first row")
+	}
+	msg := dynamicpb.NewMessage(msgDesc)
+	if err := scanner.Scan(rows, msg); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if member := msg.WhichOneof(oneofDesc); member == nil || member.Name() != "cash" {
+		t.Fatalf("zero value member should be set by discriminator, got %v", member)
+	}
+
+	if !rows.Next() {
+		t.Fatal("expected second row")
+	}
+	msg = dynamicpb.NewMessage(msgDesc)
+	if err := scanner.Scan(rows, msg); err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if member := msg.WhichOneof(oneofDesc); member == nil || member.Name() != "card" {
+		t.Fatalf("NULL message member should be set by discriminator, got %v", member)
+	}
+}
+
+func TestTableQueryBuildSql_OneofCase(t *testing.T) {
+	db := &sqldb.DBWithDialect{Executor: dummyDB{}, Dialect: sqldb.Postgres}
+
+	msgDesc := buildOneofMsgDesc(t, true)
+	sqlStr, vals, err := TableQueryBuildSql(db, msgDesc, &protodb.TableQueryReq{
+		TableName: "Payment",
+		Where:     map[string]string{"id": "1"},
+		OneofCase: map[string]string{"method": "card"},
+	}, "", nil)
+	if err != nil {
+		t.Fatalf("TableQueryBuildSql: %v", err)
+	}
+	if !strings.Contains(sqlStr, `"id" = $1 AND "method_case" = $2`) || len(vals) != 2 || vals[1] != "card" {
+		t.Fatalf("unexpected oneof case filter: %s %#v", sqlStr, vals)
+	}
+
+	msgDesc = buildOneofMsgDesc(t, false)
+	sqlStr, _, err = TableQueryBuildSql(db, msgDesc, &protodb.TableQueryReq{TableName: "Payment", OneofCase: map[string]string{"method": ""}}, "", nil)
+	if err != nil {
+		t.Fatalf("TableQueryBuildSql: %v", err)
+	}
+	if !strings.Contains(strings.Join(strings.Fields(sqlStr), " "), `WHERE ( "cash" IS NULL AND "card" IS NULL AND "voucher" IS NULL )`) {
+		t.Fatalf("unexpected no case filter: %s", sqlStr)
+	}
+
+	_, _, err = TableQueryBuildSql(db, msgDesc, &protodb.TableQueryReq{TableName: "Payment", OneofCase: map[string]string{"method": "id"}}, "", nil)
+	if err == nil {
+		t.Fatal("field outside oneof should be rejected")
+	}
+}
@@ -21,7 +21,7 @@ func DbUpdatePartial(db sqldb.DB, msg proto.Message, updateFields []string, dbsc
 	msgPm := msg.ProtoReflect()
 	msgDesc := msgPm.Descriptor()
 	msgFieldDescs := msgDesc.Fields()
-	tableName := string(msgDesc.Name())
+	tableName := pdbutil.GetTableName(msgDesc)
 
 	return dbUpdatePartial(db, msg, updateFields, dbschema, tableName, msgDesc, msgFieldDescs)
 
@@ -65,7 +65,7 @@ func DbUpdatePartialReturnNew(db sqldb.DB, msg proto.Message, updateFields []str
 	msgPm := msg.ProtoReflect()
 	msgDesc := msgPm.Descriptor()
 	msgFieldDescs := msgDesc.Fields()
-	tableName := string(msgDesc.Name())
+	tableName := pdbutil.GetTableName(msgDesc)
 
 	return dbUpdatePartialReturnNew(db, msg, updateFields, dbschema, tableName, msgDesc, msgFieldDescs)
 }
@@ -76,12 +76,12 @@ func dbUpdatePartialReturnNew(db sqldb.DB, msg proto.Message, updateFields []str
 	msgFieldDescs protoreflect.FieldDescriptors) (returnMsg proto.Message, err error) {
 
 	dbdialect := sqldb.GetExecutorDialect(db)
-	if dbdialect == sqldb.Mysql {
+	if !sqldb.GetExecutorCapabilities(db).UpdateReturning {
 		_, err := dbUpdatePartial(db, msg, updateFields, dbschema, tableName, msgDesc, msgFieldDescs)
 		if err != nil {
 			return nil, err
 		}
-		return mysqlSelectReturnedMsg(db, msg, dbschema, tableName, msgDesc, msgFieldDescs)
+		return selectReturnedMsg(db, msg, dbschema, tableName, msgDesc, msgFieldDescs, dbdialect)
 	}
 
 	sqlStr, sqlVals, err := dbBuildSqlUpdatePartial(msg, updateFields, dbschema, tableName, msgDesc, msgFieldDescs, dbdialect, true)
@@ -103,7 +103,7 @@ func dbUpdatePartialReturnNew(db sqldb.DB, msg proto.Message, updateFields []str
 
 	msgFieldsMap := pdbutil.BuildMsgFieldsMap(nil, msgDesc.Fields(), true)
 
-	err = DbScan2ProtoMsg(rows, returnMsg, nil, msgFieldsMap)
+	err = dbScan2ProtoMsg(msgStoreOf(db), rows, returnMsg, nil, msgFieldsMap)
 
 	return returnMsg, err
 }
@@ -114,7 +114,7 @@ func DbUpdatePartialReturnOldAndNew(db sqldb.DB, msg proto.Message, updateFields
 	msgPm := msg.ProtoReflect()
 	msgDesc := msgPm.Descriptor()
 	msgFieldDescs := msgDesc.Fields()
-	tableName := string(msgDesc.Name())
+	tableName := pdbutil.GetTableName(msgDesc)
 
 	return dbUpdatePartialReturnOldAndNew(db, msg, updateFields, dbschema, tableName, msgDesc, msgFieldDescs)
 }
@@ -126,8 +126,10 @@ func dbUpdatePartialReturnOldAndNew(db sqldb.DB, msg proto.Message, updateFields
 
 	dbdialect := sqldb.GetExecutorDialect(db)
 
-	//if db is sqlite/mysql, use selectone + update + selectone fallback
-	if dbdialect == sqldb.SQLite || dbdialect == sqldb.Mysql {
+	oldNewReturning := sqldb.GetExecutorCapabilities(db).OldNewReturning
+
+	//if db can not return old rows(sqlite/mysql), use selectone + update + selectone fallback
+	if oldNewReturning == sqldb.OldNewSelect {
 		oldMsg, err = dbSelectOne(db, msg, nil, nil, dbschema, tableName, msgDesc, msgFieldDescs, dbdialect, true)
 		if err != nil {
 			return nil, nil, err
@@ -137,14 +139,7 @@ func dbUpdatePartialReturnOldAndNew(db sqldb.DB, msg proto.Message, updateFields
 	}
 
 	fnbuildsql := dbBuildSqlUpdatePartialOldAndNew
-	if dbdialect == sqldb.Postgres {
-		pgversion, _ := GetPgVersion(db)
-		if pgversion.Major >= 18 {
-
-			fnbuildsql = dbBuildSqlUpdatePartialOldAndNewNative
-		}
-	}
-	if dbdialect == sqldb.Oracle {
+	if oldNewReturning == sqldb.OldNewNative {
 		fnbuildsql = dbBuildSqlUpdatePartialOldAndNewNative
 	}
 
@@ -168,7 +163,7 @@ func dbUpdatePartialReturnOldAndNew(db sqldb.DB, msg proto.Message, updateFields
 
 	msgFieldsMap := pdbutil.BuildMsgFieldsMap(nil, msgDesc.Fields(), true)
 
-	err = DbScan2ProtoMsgx2(rows, oldMsg, newMsg, nil, msgFieldsMap)
+	err = dbScan2ProtoMsgx2(msgStoreOf(db), rows, oldMsg, newMsg, nil, msgFieldsMap)
 
 	return oldMsg, newMsg, err
 }
@@ -182,7 +177,7 @@ func dbBuildSqlUpdatePartial(msgobj proto.Message, updateFields []string, dbsche
 	sb := strings.Builder{}
 	sb.WriteString(protosql.SQL_UPDATE)
 
-	dbtableName := sqldb.BuildDbTableName(tableName, dbschema, dbdialect)
+	dbtableName := sqldb.BuildQuotedDbTableName(tableName, dbschema, dbdialect)
 	sb.WriteString(dbtableName)
 
 	sb.WriteString(protosql.SQL_SET)
@@ -202,13 +197,15 @@ func dbBuildSqlUpdatePartial(msgobj proto.Message, updateFields []string, dbsche
 	for _, fieldName := range updateFields {
 		updateFieldsMap[fieldName] = true
 	}
+	// one member of oneof is updated, the other members are cleared
+	touchedOneofs := expandOneofUpdateFields(msgDesc, updateFieldsMap)
 
 	for fi := 0; fi < msgFieldDescs.Len(); fi++ {
 		field := msgFieldDescs.Get(fi)
 
-		fieldName := string(field.Name())
+		fieldName := pdbutil.GetColumnName(field)
 
-		if _, ok := updateFieldsMap[fieldName]; !ok {
+		if !isPartialUpdateField(updateFieldsMap, field) && !pdbutil.IsFlattenField(field) {
 			continue
 		}
 
@@ -223,6 +220,21 @@ func dbBuildSqlUpdatePartial(msgobj proto.Message, updateFields []string, dbsche
 			continue
 		}
 
+		if pdbutil.IsFlattenField(field) {
+			// whole sub message or single columns by dotted path
+			include := updateFieldsMap
+			if isPartialUpdateField(updateFieldsMap, field) {
+				include = nil
+			}
+			flatColumnNames, flatVals, err := flatFieldSQLArgs(msgobj, field, dbdialect, true, include)
+			if err != nil {
+				return "", nil, fmt.Errorf("msg:%s field:%s %w", msgDesc.Name(), fieldName, err)
+			}
+			valFieldNames = append(valFieldNames, flatColumnNames...)
+			sqlVals = append(sqlVals, flatVals...)
+			continue
+		}
+
 		valFieldNames = append(valFieldNames, fieldName)
 
 		val, err := getSQLFieldValue(msgobj, field)
@@ -231,7 +243,7 @@ func dbBuildSqlUpdatePartial(msgobj proto.Message, updateFields []string, dbsche
 			return "", nil, err
 		}
 
-		isValZero := pdbutil.IsZeroValue(val)
+		isValZero := isSQLValZero(field, val)
 		_, hasDefaultValue := fieldPdb.HasDefaultValue()
 		hasSetDefaultValue := false
 		if !fieldPdb.IsNotNull() && (fieldPdb.IsReference() || fieldPdb.IsZeroAsNull()) && isValZero {
@@ -253,10 +265,15 @@ func dbBuildSqlUpdatePartial(msgobj proto.Message, updateFields []string, dbsche
 
 	}
 
+	discriminatorNames, discriminatorVals := oneofDiscriminatorSQLArgs(msgobj, touchedOneofs)
+	valFieldNames = append(valFieldNames, discriminatorNames...)
+	sqlVals = append(sqlVals, discriminatorVals...)
+
 	if len(valFieldNames) == 0 {
 		return "", nil, fmt.Errorf("no field need update")
 	}
 
+	mapColumns := mapArgColumns(dbdialect, msgFieldDescs)
 	for _, fieldName := range valFieldNames {
 		if firstPlaceholder {
 			firstPlaceholder = false
@@ -264,14 +281,10 @@ func dbBuildSqlUpdatePartial(msgobj proto.Message, updateFields []string, dbsche
 			sb.WriteString(protosql.SQL_COMMA)
 		}
 
-		sb.WriteString(fieldName)
+		sb.WriteString(dbdialect.QuoteIdentifier(fieldName))
 		sb.WriteString(protosql.SQL_EQUEAL)
-
-		if placeholder == protosql.SQL_QUESTION {
-			sb.WriteString(string(protosql.SQL_QUESTION))
-		} else {
-			sb.WriteString(string(protosql.SQL_DOLLAR))
-			sb.WriteString(strconv.Itoa(sqlParaNo))
+		sb.WriteString(columnArgPlaceholder(dbdialect, mapColumns, fieldName, placeholder, sqlParaNo))
+		if placeholder != protosql.SQL_QUESTION {
 			sqlParaNo++
 		}
 	}
@@ -287,7 +300,7 @@ func dbBuildSqlUpdatePartial(msgobj proto.Message, updateFields []string, dbsche
 			sb.WriteString(protosql.SQL_AND)
 		}
 
-		sb.WriteString(fieldName)
+		sb.WriteString(dbdialect.QuoteIdentifier(fieldName))
 		sb.WriteString(protosql.SQL_EQUEAL)
 
 		if placeholder == protosql.SQL_QUESTION {
@@ -305,7 +318,7 @@ func dbBuildSqlUpdatePartial(msgobj proto.Message, updateFields []string, dbsche
 		sqlVals = append(sqlVals, val)
 	}
 
-	if returnUpdated && mysqlSupportsReturning(dbdialect) {
+	if returnUpdated {
 		sb.WriteString(" RETURNING * ")
 	}
 
@@ -323,7 +336,7 @@ func dbBuildSqlUpdatePartialOldAndNew(msgobj proto.Message, updateFields []strin
 	msgFieldDescs protoreflect.FieldDescriptors,
 	dbdialect sqldb.TDBDialect) (sqlStr string, sqlVals []interface{}, err error) {
 
-	dbtableName := sqldb.BuildDbTableName(tableName, dbschema, dbdialect)
+	dbtableName := sqldb.BuildQuotedDbTableName(tableName, dbschema, dbdialect)
 
 	placeholder := dbdialect.Placeholder()
 	primaryKeyFieldNames := pdbutil.GetPrimaryKeyFieldDescs(msgDesc, msgFieldDescs, false)
@@ -347,7 +360,7 @@ func dbBuildSqlUpdatePartialOldAndNew(msgobj proto.Message, updateFields []strin
 			sb.WriteString(protosql.SQL_AND)
 		}
 
-		sb.WriteString(fieldName)
+		sb.WriteString(dbdialect.QuoteIdentifier(fieldName))
 		sb.WriteString(protosql.SQL_EQUEAL)
 
 		if placeholder == protosql.SQL_QUESTION {
@@ -377,12 +390,14 @@ func dbBuildSqlUpdatePartialOldAndNew(msgobj proto.Message, updateFields []strin
 	for _, fieldName := range updateFields {
 		updateFieldsMap[fieldName] = true
 	}
+	// one member of oneof is updated, the other members are cleared
+	touchedOneofs := expandOneofUpdateFields(msgDesc, updateFieldsMap)
 
 	for fi := 0; fi < msgFieldDescs.Len(); fi++ {
 		field := msgFieldDescs.Get(fi)
-		fieldName := string(field.Name())
+		fieldName := pdbutil.GetColumnName(field)
 
-		if _, ok := updateFieldsMap[fieldName]; !ok {
+		if !isPartialUpdateField(updateFieldsMap, field) && !pdbutil.IsFlattenField(field) {
 			continue
 		}
 
@@ -395,6 +410,21 @@ func dbBuildSqlUpdatePartialOldAndNew(msgobj proto.Message, updateFields []strin
 			continue
 		}
 
+		if pdbutil.IsFlattenField(field) {
+			// whole sub message or single columns by dotted path
+			include := updateFieldsMap
+			if isPartialUpdateField(updateFieldsMap, field) {
+				include = nil
+			}
+			flatColumnNames, flatVals, err := flatFieldSQLArgs(msgobj, field, dbdialect, true, include)
+			if err != nil {
+				return "", nil, fmt.Errorf("msg:%s field:%s %w", msgDesc.Name(), fieldName, err)
+			}
+			valFieldNames = append(valFieldNames, flatColumnNames...)
+			sqlVals = append(sqlVals, flatVals...)
+			continue
+		}
+
 		valFieldNames = append(valFieldNames, fieldName)
 
 		val, err := getSQLFieldValue(msgobj, field)
@@ -403,7 +433,7 @@ func dbBuildSqlUpdatePartialOldAndNew(msgobj proto.Message, updateFields []strin
 			return "", nil, err
 		}
 
-		isValZero := pdbutil.IsZeroValue(val)
+		isValZero := isSQLValZero(field, val)
 		_, hasDefaultValue := fieldPdb.HasDefaultValue()
 		if !fieldPdb.IsNotNull() && (fieldPdb.IsReference() || fieldPdb.IsZeroAsNull()) && isValZero {
 			val = pdbutil.NullValue
@@ -417,11 +447,16 @@ func dbBuildSqlUpdatePartialOldAndNew(msgobj proto.Message, updateFields []strin
 		sqlVals = append(sqlVals, val)
 	}
 
+	discriminatorNames, discriminatorVals := oneofDiscriminatorSQLArgs(msgobj, touchedOneofs)
+	valFieldNames = append(valFieldNames, discriminatorNames...)
+	sqlVals = append(sqlVals, discriminatorVals...)
+
 	if len(valFieldNames) == 0 {
 		return "", nil, fmt.Errorf("no field need update")
 	}
 
 	firstPlaceholder = true
+	mapColumns := mapArgColumns(dbdialect, msgFieldDescs)
 	for _, fieldName := range valFieldNames {
 		if firstPlaceholder {
 			firstPlaceholder = false
@@ -429,14 +464,10 @@ func dbBuildSqlUpdatePartialOldAndNew(msgobj proto.Message, updateFields []strin
 			sb.WriteString(protosql.SQL_COMMA)
 		}
 
-		sb.WriteString(fieldName)
+		sb.WriteString(dbdialect.QuoteIdentifier(fieldName))
 		sb.WriteString(protosql.SQL_EQUEAL)
-
-		if placeholder == protosql.SQL_QUESTION {
-			sb.WriteString(string(protosql.SQL_QUESTION))
-		} else {
-			sb.WriteString(string(protosql.SQL_DOLLAR))
-			sb.WriteString(strconv.Itoa(sqlParaNo))
+		sb.WriteString(columnArgPlaceholder(dbdialect, mapColumns, fieldName, placeholder, sqlParaNo))
+		if placeholder != protosql.SQL_QUESTION {
 			sqlParaNo++
 		}
 	}
@@ -454,10 +485,10 @@ func dbBuildSqlUpdatePartialOldAndNew(msgobj proto.Message, updateFields []strin
 		}
 
 		sb.WriteString(" new.")
-		sb.WriteString(fieldName)
+		sb.WriteString(dbdialect.QuoteIdentifier(fieldName))
 		sb.WriteString(protosql.SQL_EQUEAL)
 		sb.WriteString("old.")
-		sb.WriteString(fieldName)
+		sb.WriteString(dbdialect.QuoteIdentifier(fieldName))
 	}
 
 	sb.WriteString(" RETURNING old.*,new.* ;")
@@ -471,7 +502,7 @@ func dbBuildSqlUpdatePartialOldAndNewNative(msgobj proto.Message, updateFields [
 	msgFieldDescs protoreflect.FieldDescriptors,
 	dbdialect sqldb.TDBDialect) (sqlStr string, sqlVals []interface{}, err error) {
 	// Build SQL like: UPDATE <table> new SET ... WHERE new.pk=? RETURNING OLD.*,NEW.*;
-	dbtableName := sqldb.BuildDbTableName(tableName, dbschema, dbdialect)
+	dbtableName := sqldb.BuildQuotedDbTableName(tableName, dbschema, dbdialect)
 
 	placeholder := dbdialect.Placeholder()
 	primaryKeyFieldNames := pdbutil.GetPrimaryKeyFieldDescs(msgDesc, msgFieldDescs, false)
@@ -490,13 +521,15 @@ func dbBuildSqlUpdatePartialOldAndNewNative(msgobj proto.Message, updateFields [
 	for _, fieldName := range updateFields {
 		updateFieldsMap[fieldName] = true
 	}
+	// one member of oneof is updated, the other members are cleared
+	touchedOneofs := expandOneofUpdateFields(msgDesc, updateFieldsMap)
 
 	// Collect updatable fields and append their values first
 	for fi := 0; fi < msgFieldDescs.Len(); fi++ {
 		field := msgFieldDescs.Get(fi)
-		fieldName := string(field.Name())
+		fieldName := pdbutil.GetColumnName(field)
 
-		if _, ok := updateFieldsMap[fieldName]; !ok {
+		if !isPartialUpdateField(updateFieldsMap, field) && !pdbutil.IsFlattenField(field) {
 			continue
 		}
 		if _, ok := primaryKeyFieldNames[fieldName]; ok {
@@ -508,6 +541,21 @@ func dbBuildSqlUpdatePartialOldAndNewNative(msgobj proto.Message, updateFields [
 			continue
 		}
 
+		if pdbutil.IsFlattenField(field) {
+			// whole sub message or single columns by dotted path
+			include := updateFieldsMap
+			if isPartialUpdateField(updateFieldsMap, field) {
+				include = nil
+			}
+			flatColumnNames, flatVals, err := flatFieldSQLArgs(msgobj, field, dbdialect, true, include)
+			if err != nil {
+				return "", nil, fmt.Errorf("msg:%s field:%s %w", msgDesc.Name(), fieldName, err)
+			}
+			valFieldNames = append(valFieldNames, flatColumnNames...)
+			sqlVals = append(sqlVals, flatVals...)
+			continue
+		}
+
 		valFieldNames = append(valFieldNames, fieldName)
 
 		val, err := getSQLFieldValue(msgobj, field)
@@ -515,7 +563,7 @@ func dbBuildSqlUpdatePartialOldAndNewNative(msgobj proto.Message, updateFields [
 			return "", nil, fmt.Errorf("get field err: %s.%s %w", msgDesc.Name(), fieldName, err)
 		}
 
-		isValZero := pdbutil.IsZeroValue(val)
+		isValZero := isSQLValZero(field, val)
 		_, hasDefaultValue := fieldPdb.HasDefaultValue()
 		hasSetDefaultValue := false
 		if !fieldPdb.IsNotNull() && (fieldPdb.IsReference() || fieldPdb.IsZeroAsNull()) && isValZero {
@@ -535,6 +583,10 @@ func dbBuildSqlUpdatePartialOldAndNewNative(msgobj proto.Message, updateFields [
 		sqlVals = append(sqlVals, val)
 	}
 
+	discriminatorNames, discriminatorVals := oneofDiscriminatorSQLArgs(msgobj, touchedOneofs)
+	valFieldNames = append(valFieldNames, discriminatorNames...)
+	sqlVals = append(sqlVals, discriminatorVals...)
+
 	if len(valFieldNames) == 0 {
 		return "", nil, fmt.Errorf("no field need update")
 	}
@@ -542,6 +594,7 @@ func dbBuildSqlUpdatePartialOldAndNewNative(msgobj proto.Message, updateFields [
 	// Write SET clause
 	firstPlaceholder := true
 	sqlParaNo := 1
+	mapColumns := mapArgColumns(dbdialect, msgFieldDescs)
 	for _, fieldName := range valFieldNames {
 		if firstPlaceholder {
 			firstPlaceholder = false
@@ -549,13 +602,10 @@ func dbBuildSqlUpdatePartialOldAndNewNative(msgobj proto.Message, updateFields [
 			sb.WriteString(protosql.SQL_COMMA)
 		}
 
-		sb.WriteString(fieldName)
+		sb.WriteString(dbdialect.QuoteIdentifier(fieldName))
 		sb.WriteString(protosql.SQL_EQUEAL)
-		if placeholder == protosql.SQL_QUESTION {
-			sb.WriteString(string(protosql.SQL_QUESTION))
-		} else {
-			sb.WriteString(string(protosql.SQL_DOLLAR))
-			sb.WriteString(strconv.Itoa(sqlParaNo))
+		sb.WriteString(columnArgPlaceholder(dbdialect, mapColumns, fieldName, placeholder, sqlParaNo))
+		if placeholder != protosql.SQL_QUESTION {
 			sqlParaNo++
 		}
 	}
@@ -569,7 +619,7 @@ func dbBuildSqlUpdatePartialOldAndNewNative(msgobj proto.Message, updateFields [
 		} else {
 			sb.WriteString(protosql.SQL_AND)
 		}
-		sb.WriteString(fieldName)
+		sb.WriteString(dbdialect.QuoteIdentifier(fieldName))
 		sb.WriteString(protosql.SQL_EQUEAL)
 		if placeholder == protosql.SQL_QUESTION {
 			sb.WriteString(string(protosql.SQL_QUESTION))
@@ -590,3 +640,8 @@ func dbBuildSqlUpdatePartialOldAndNewNative(msgobj proto.Message, updateFields [
 
 	return sb.String(), sqlVals, nil
 }
+
+// isPartialUpdateField is the field in update fields, by field name or column name
+func isPartialUpdateField(updateFieldsMap map[string]bool, field protoreflect.FieldDescriptor) bool {
+	return updateFieldsMap[string(field.Name())] || updateFieldsMap[pdbutil.GetColumnName(field)]
+}
@@ -0,0 +1,187 @@
+package crud
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func buildPresenceMsgDesc(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+
+	idOpts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(idOpts, protodb.E_Pdb, &protodb.PDBField{Primary: true})
+	defOpts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(defOpts, protodb.E_Pdb, &protodb.PDBField{DefaultValue: "7"})
+
+	optional := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, oneofIndex int32, opts *descriptorpb.FieldOptions) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:           strPtr(name),
+			Number:         int32Ptr(number),
+			Label:          descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:           typ.Enum(),
+			OneofIndex:     int32Ptr(oneofIndex),
+			Proto3Optional: proto.Bool(true),
+			Options:        opts,
+		}
+	}
+
+	fdp := &descriptorpb.FileDescriptorProto{
+		Syntax:  strPtr("proto3"),
+		Name:    strPtr("presence_test.proto"),
+		Package: strPtr("test"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: strPtr("OptMsg"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: strPtr("id"), Number: int32Ptr(1), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum(), Options: idOpts},
+					optional("age", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32, 0, nil),
+					optional("nick", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING, 1, nil),
+					optional("level", 4, descriptorpb.FieldDescriptorProto_TYPE_INT32, 2, defOpts),
+					{Name: strPtr("plain"), Number: int32Ptr(5), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum()},
+				},
+				OneofDecl: []*descriptorpb.OneofDescriptorProto{
+					{Name: strPtr("_age")},
+					{Name: strPtr("_nick")},
+					{Name: strPtr("_level")},
+				},
+			},
+		},
+	}
+
+	fd, err := protodesc.NewFile(fdp, nil)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile: %v", err)
+	}
+	return fd.Messages().ByName("OptMsg")
+}
+
+func TestGetSQLFieldValue_OptionalPresence(t *testing.T) {
+	msgDesc := buildPresenceMsgDesc(t)
+	fields := msgDesc.Fields()
+	msg := dynamicpb.NewMessage(msgDesc)
+
+	v, err := getSQLFieldValue(msg, fields.ByName("age"))
+	if err != nil || v != nil {
+		t.Fatalf("unset optional should be nil, got %#v err:%v", v, err)
+	}
+
+	msg.Set(fields.ByName("age"), protoreflect.ValueOfInt32(0))
+	v, err = getSQLFieldValue(msg, fields.ByName("age"))
+	if err != nil || v != int32(0) {
+		t.Fatalf("set zero optional should be 0, got %#v err:%v", v, err)
+	}
+	if isSQLValZero(fields.ByName("age"), v) {
+		t.Fatal("set zero optional should not be treated as zero")
+	}
+	if !isSQLValZero(fields.ByName("plain"), int32(0)) {
+		t.Fatal("zero of field without presence should be treated as zero")
+	}
+}
+
+func TestDbBuildSqlInsert_OptionalPresence(t *testing.T) {
+	msgDesc := buildPresenceMsgDesc(t)
+	fields := msgDesc.Fields()
+	msg := dynamicpb.NewMessage(msgDesc)
+	msg.Set(fields.ByName("id"), protoreflect.ValueOfInt64(1))
+	msg.Set(fields.ByName("nick"), protoreflect.ValueOfString(""))
+
+	_, vals, err := dbBuildSqlInsert(msg, 0, "", "", msgDesc, fields, sqldb.Postgres, false)
+	if err != nil {
+		t.Fatalf("dbBuildSqlInsert: %v", err)
+	}
+	if len(vals) != 5 {
+		t.Fatalf("expected 5 vals, got %d", len(vals))
+	}
+	if vals[1] != nil {
+		t.Fatalf("unset optional age should be NULL, got %#v", vals[1])
+	}
+	if vals[2] != "" {
+		t.Fatalf("set empty optional nick should be kept, got %#v", vals[2])
+	}
+	if vals[3] != "7" {
+		t.Fatalf("unset optional with default should use default, got %#v", vals[3])
+	}
+	if vals[4] != int32(0) {
+		t.Fatalf("plain zero field should be 0, got %#v", vals[4])
+	}
+}
+
+func TestDbScan2ProtoMsg_NullLeavesOptionalUnset(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	columns := []string{"id", "age", "nick"}
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows(columns).AddRow(int64(1), nil, ""))
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatalf("db.Query: %v", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		t.Fatal("expected one row")
+	}
+
+	msgDesc := buildPresenceMsgDesc(t)
+	fields := msgDesc.Fields()
+	msg := dynamicpb.NewMessage(msgDesc)
+	// stale value from a previous row must be cleared by NULL
+	msg.Set(fields.ByName("age"), protoreflect.ValueOfInt32(3))
+
+	msgFieldsMap := pdbutil.BuildMsgFieldsMap(columns, fields, true)
+	if err := DbScan2ProtoMsg(rows, msg, columns, msgFieldsMap); err != nil {
+		t.Fatalf("DbScan2ProtoMsg: %v", err)
+	}
+	if msg.Has(fields.ByName("age")) {
+		t.Fatal("NULL column should leave optional age unset")
+	}
+	if !msg.Has(fields.ByName("nick")) {
+		t.Fatal("empty string should set optional nick")
+	}
+}
+
+func TestTableQueryBuildSql_IsNull(t *testing.T) {
+	msgDesc := buildPresenceMsgDesc(t)
+	db := &sqldb.DBWithDialect{Executor: dummyDB{}, Dialect: sqldb.Postgres}
+
+	req := &protodb.TableQueryReq{
+		TableName: "OptMsg",
+		Where:     map[string]string{"id": "1"},
+		Where2:    map[string]string{"age": ""},
+		Where2Operator: map[string]protodb.WhereOperator{
+			"age": protodb.WhereOperator_WOP_IS_NULL,
+		},
+	}
+	sqlStr, vals, err := TableQueryBuildSql(db, msgDesc, req, "", nil)
+	if err != nil {
+		t.Fatalf("TableQueryBuildSql: %v", err)
+	}
+	if !strings.Contains(sqlStr, `"age" IS NULL`) {
+		t.Fatalf("unexpected sql: %s", sqlStr)
+	}
+	if len(vals) != 1 {
+		t.Fatalf("IS NULL should not add args, got %#v", vals)
+	}
+
+	req.Where2Operator["age"] = protodb.WhereOperator_WOP_IS_NOT_NULL
+	sqlStr, _, err = TableQueryBuildSql(db, msgDesc, req, "", nil)
+	if err != nil {
+		t.Fatalf("TableQueryBuildSql: %v", err)
+	}
+	if !strings.Contains(sqlStr, `"age" IS NOT NULL`) {
+		t.Fatalf("unexpected sql: %s", sqlStr)
+	}
+}
@@ -0,0 +1,209 @@
+package crud
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ColumnBinder scan dest of a column and the setter which assigns the scanned value to the typed field of msg
+type ColumnBinder struct {
+	Dest any
+	Set  func(msg proto.Message) error
+}
+
+// RowBinder binder of a message field, generated by protoc-gen-protodb.
+// every call returns a new dest, ok is false for fields left to the reflective scanner(list, map, message, oneof...)
+type RowBinder func(field protoreflect.FieldNumber) (binder ColumnBinder, ok bool)
+
+type rowBinderEntry struct {
+	msgType reflect.Type
+	binder  RowBinder
+}
+
+var (
+	rowBindersMu sync.RWMutex
+	rowBinders   = make(map[protoreflect.FullName]rowBinderEntry)
+)
+
+// RegisterRowBinder register the generated binder of the go type of msg, NewDbRowScanner binds
+// columns of the message straight to its fields instead of protoreflect. should call in init() function
+func RegisterRowBinder(msg proto.Message, binder RowBinder) {
+	rowBindersMu.Lock()
+	defer rowBindersMu.Unlock()
+	rowBinders[msg.ProtoReflect().Descriptor().FullName()] = rowBinderEntry{msgType: reflect.TypeOf(msg), binder: binder}
+}
+
+// getRowBinder binder of msg, nil if none is registered or msg is another type of the same message(dynamicpb)
+func getRowBinder(msg proto.Message) RowBinder {
+	rowBindersMu.RLock()
+	entry, ok := rowBinders[msg.ProtoReflect().Descriptor().FullName()]
+	rowBindersMu.RUnlock()
+	if !ok || entry.msgType != reflect.TypeOf(msg) {
+		return nil
+	}
+	return entry.binder
+}
+
+// NULL leaves the field unchanged, like the reflective scanner
+
+// BindBool binder of bool field
+func BindBool(field func(msg proto.Message) *bool) ColumnBinder {
+	dest := new(sql.NullBool)
+	return ColumnBinder{Dest: dest, Set: func(msg proto.Message) error {
+		if dest.Valid {
+			*field(msg) = dest.Bool
+		}
+		return nil
+	}}
+}
+
+// BindOptionalBool binder of optional bool field, NULL clears the field
+func BindOptionalBool(field func(msg proto.Message) **bool) ColumnBinder {
+	dest := new(sql.NullBool)
+	return ColumnBinder{Dest: dest, Set: func(msg proto.Message) error {
+		if !dest.Valid {
+			*field(msg) = nil
+			return nil
+		}
+		v := dest.Bool
+		*field(msg) = &v
+		return nil
+	}}
+}
+
+// BindInt binder of int32, int64 and enum(stored as int) field
+func BindInt[T ~int32 | ~int64](field func(msg proto.Message) *T) ColumnBinder {
+	dest := new(sql.NullInt64)
+	return ColumnBinder{Dest: dest, Set: func(msg proto.Message) error {
+		if !dest.Valid {
+			return nil
+		}
+		v, err := intOf[T](dest.Int64)
+		if err != nil {
+			return err
+		}
+		*field(msg) = v
+		return nil
+	}}
+}
+
+// BindOptionalInt binder of optional int32, int64 and enum field, NULL clears the field
+func BindOptionalInt[T ~int32 | ~int64](field func(msg proto.Message) **T) ColumnBinder {
+	dest := new(sql.NullInt64)
+	return ColumnBinder{Dest: dest, Set: func(msg proto.Message) error {
+		if !dest.Valid {
+			*field(msg) = nil
+			return nil
+		}
+		v, err := intOf[T](dest.Int64)
+		if err != nil {
+			return err
+		}
+		*field(msg) = &v
+		return nil
+	}}
+}
+
+func intOf[T ~int32 | ~int64](i int64) (T, error) {
+	v := T(i)
+	if int64(v) != i {
+		return 0, fmt.Errorf("%T field value out of range: %d", v, i)
+	}
+	return v, nil
+}
+
+// BindUint binder of uint32 and uint64 field
+func BindUint[T ~uint32 | ~uint64](field func(msg proto.Message) *T) ColumnBinder {
+	dest := new(nullUint64)
+	return ColumnBinder{Dest: dest, Set: func(msg proto.Message) error {
+		if !dest.Valid {
+			return nil
+		}
+		v, err := uintOf[T](dest.Uint64)
+		if err != nil {
+			return err
+		}
+		*field(msg) = v
+		return nil
+	}}
+}
+
+// BindOptionalUint binder of optional uint32 and uint64 field, NULL clears the field
+func BindOptionalUint[T ~uint32 | ~uint64](field func(msg proto.Message) **T) ColumnBinder {
+	dest := new(nullUint64)
+	return ColumnBinder{Dest: dest, Set: func(msg proto.Message) error {
+		if !dest.Valid {
+			*field(msg) = nil
+			return nil
+		}
+		v, err := uintOf[T](dest.Uint64)
+		if err != nil {
+			return err
+		}
+		*field(msg) = &v
+		return nil
+	}}
+}
+
+func uintOf[T ~uint32 | ~uint64](u uint64) (T, error) {
+	v := T(u)
+	if uint64(v) != u {
+		return 0, fmt.Errorf("%T field value out of range: %d", v, u)
+	}
+	return v, nil
+}
+
+// BindFloat binder of float and double field
+func BindFloat[T ~float32 | ~float64](field func(msg proto.Message) *T) ColumnBinder {
+	dest := new(sql.NullFloat64)
+	return ColumnBinder{Dest: dest, Set: func(msg proto.Message) error {
+		if dest.Valid {
+			*field(msg) = T(dest.Float64)
+		}
+		return nil
+	}}
+}
+
+// BindOptionalFloat binder of optional float and double field, NULL clears the field
+func BindOptionalFloat[T ~float32 | ~float64](field func(msg proto.Message) **T) ColumnBinder {
+	dest := new(sql.NullFloat64)
+	return ColumnBinder{Dest: dest, Set: func(msg proto.Message) error {
+		if !dest.Valid {
+			*field(msg) = nil
+			return nil
+		}
+		v := T(dest.Float64)
+		*field(msg) = &v
+		return nil
+	}}
+}
+
+// BindString binder of string field, numeric(DbType NUMERIC) fields are left to the reflective scanner
+func BindString(field func(msg proto.Message) *string) ColumnBinder {
+	dest := new(sql.NullString)
+	return ColumnBinder{Dest: dest, Set: func(msg proto.Message) error {
+		if dest.Valid {
+			*field(msg) = dest.String
+		}
+		return nil
+	}}
+}
+
+// BindOptionalString binder of optional string field, NULL clears the field
+func BindOptionalString(field func(msg proto.Message) **string) ColumnBinder {
+	dest := new(sql.NullString)
+	return ColumnBinder{Dest: dest, Set: func(msg proto.Message) error {
+		if !dest.Valid {
+			*field(msg) = nil
+			return nil
+		}
+		v := dest.String
+		*field(msg) = &v
+		return nil
+	}}
+}
@@ -0,0 +1,115 @@
+package crud
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ygrpc/protodb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// scanPDBField scan one row of sqlmock into msg
+func scanPDBField(t *testing.T, columns []string, row []any, msg proto.Message) (*DbRowScanner, error) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	values := make([]driver.Value, len(row))
+	for i, v := range row {
+		values[i] = v
+	}
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows(columns).AddRow(values...))
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatalf("db.Query: %v", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		t.Fatal("expected one row")
+	}
+	scanner, err := NewDbRowScanner(rows, msg, nil, nil)
+	if err != nil {
+		t.Fatalf("NewDbRowScanner: %v", err)
+	}
+	return scanner, scanner.Scan(rows, msg)
+}
+
+func TestDbRowScanner_RowBinder(t *testing.T) {
+	columns := []string{"Primary", "Reference", "SerialType", "DbType", "Comment", "NumericScale"}
+	row := []any{true, "t_user(id)", int64(8), int64(3), `["a"]`, nil}
+
+	reflected := &protodb.PDBField{NumericScale: 2}
+	if _, err := scanPDBField(t, columns, row, reflected); err != nil {
+		t.Fatalf("reflective scan: %v", err)
+	}
+
+	registerPDBFieldBinder(t)
+	generated := &protodb.PDBField{NumericScale: 2}
+	scanner, err := scanPDBField(t, columns, row, generated)
+	if err != nil {
+		t.Fatalf("generated scan: %v", err)
+	}
+	// Comment(list) and NumericScale(not in binder) are scanned by protoreflect
+	if scanner.setters == nil || scanner.setters[0] == nil || scanner.setters[4] != nil || scanner.setters[5] != nil {
+		t.Fatalf("unexpected bound columns: %v", scanner.setters)
+	}
+	if !proto.Equal(reflected, generated) {
+		t.Fatalf("generated scan %v, reflective scan %v", generated, reflected)
+	}
+}
+
+func TestDbRowScanner_RowBinderOutOfRange(t *testing.T) {
+	registerPDBFieldBinder(t)
+	_, err := scanPDBField(t, []string{"SerialType"}, []any{int64(1) << 40}, &protodb.PDBField{})
+	if err == nil || !strings.Contains(err.Error(), "column SerialType") || !strings.Contains(err.Error(), "out of range") {
+		t.Fatalf("expected out of range error, got %v", err)
+	}
+}
+
+func TestDbRowScanner_RowBinderSkipsDynamicMsg(t *testing.T) {
+	registerPDBFieldBinder(t)
+	msg := dynamicpb.NewMessage((&protodb.PDBField{}).ProtoReflect().Descriptor())
+	scanner, err := scanPDBField(t, []string{"Primary"}, []any{true}, msg)
+	if err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if scanner.setters != nil {
+		t.Fatal("binder of generated type is used for dynamicpb message")
+	}
+	if !msg.Get(msg.Descriptor().Fields().ByName("Primary")).Bool() {
+		t.Fatal("Primary is not scanned")
+	}
+}
+
+func TestBindOptional(t *testing.T) {
+	var field *string
+	binder := BindOptionalString(func(msg proto.Message) **string { return &field })
+	if err := binder.Dest.(sql.Scanner).Scan("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := binder.Set(nil); err != nil || field == nil || *field != "a" {
+		t.Fatalf("unexpected field %v, err %v", field, err)
+	}
+	if err := binder.Dest.(sql.Scanner).Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := binder.Set(nil); err != nil || field != nil {
+		t.Fatalf("NULL should clear the field, got %v", field)
+	}
+
+	var u uint32
+	uintBinder := BindUint(func(msg proto.Message) *uint32 { return &u })
+	if err := uintBinder.Dest.(sql.Scanner).Scan(int64(1) << 33); err != nil {
+		t.Fatal(err)
+	}
+	if err := uintBinder.Set(nil); err == nil {
+		t.Fatal("expected out of range error")
+	}
+}
@@ -35,7 +35,7 @@ func DbSelectOne(db sqldb.DB, msg proto.Message, keyColumns []string, resultColu
 	msgPm := msg.ProtoReflect()
 	msgDesc := msgPm.Descriptor()
 	msgFieldDescs := msgDesc.Fields()
-	tableName := string(msgDesc.Name())
+	tableName := pdbutil.GetTableName(msgDesc)
 	dbdialect := sqldb.GetExecutorDialect(db)
 
 	return dbSelectOne(db, msg, keyColumns, resultColumns, dbschema, tableName, msgDesc, msgFieldDescs, dbdialect, limitPkUk)
@@ -65,10 +65,10 @@ func dbSelectOne(db sqldb.DB, msg proto.Message, keyColumns []string, resultColu
 
 	if len(resultColumns) == 0 || (len(resultColumns) == 1 && resultColumns[0] == "*") {
 		msgFieldsMap := pdbutil.BuildMsgFieldsMap(nil, msgDesc.Fields(), true)
-		err = DbScan2ProtoMsg(rows, returnMsg, nil, msgFieldsMap)
+		err = dbScan2ProtoMsg(msgStoreOf(db), rows, returnMsg, nil, msgFieldsMap)
 	} else {
 		msgFieldsMap := pdbutil.BuildMsgFieldsMap(resultColumns, msgDesc.Fields(), true)
-		err = DbScan2ProtoMsg(rows, returnMsg, resultColumns, msgFieldsMap)
+		err = dbScan2ProtoMsg(msgStoreOf(db), rows, returnMsg, resultColumns, msgFieldsMap)
 	}
 
 	return returnMsg, err
@@ -90,7 +90,7 @@ func dbBuildSqlSelectOne(msg proto.Message, keyColumns []string, resultColumns [
 
 	sb.WriteString(protosql.SQL_FROM)
 
-	dbtableName := sqldb.BuildDbTableName(tableName, dbschema, dbdialect)
+	dbtableName := sqldb.BuildQuotedDbTableName(tableName, dbschema, dbdialect)
 	sb.WriteString(dbtableName)
 
 	sb.WriteString(protosql.SQL_WHERE)
@@ -106,6 +106,16 @@ func dbBuildSqlSelectOne(msg proto.Message, keyColumns []string, resultColumns [
 			return "", nil, fmt.Errorf("no primary key field for table %s", tableName)
 		}
 	} else {
+		// key columns can be field names or column names
+		columnNames := make([]string, len(keyColumns))
+		for i, fieldName := range keyColumns {
+			columnNames[i] = fieldName
+			if fieldDesc, ok := pdbutil.FindFieldByColumnName(msgDesc, fieldName); ok {
+				columnNames[i] = pdbutil.GetColumnName(fieldDesc)
+			}
+		}
+		keyColumns = columnNames
+
 		if limitPkUk {
 			primaryOrUniqueKeyFieldNames := pdbutil.GetPrimaryKeyOrUniqueFieldDescs(msgDesc, msgFieldDescs, false)
 			for _, fieldName := range keyColumns {
@@ -137,7 +147,7 @@ func dbBuildSqlSelectOne(msg proto.Message, keyColumns []string, resultColumns [
 			sb.WriteString(protosql.SQL_AND)
 		}
 
-		sb.WriteString(fieldName)
+		sb.WriteString(dbdialect.QuoteIdentifier(fieldName))
 		sb.WriteString(protosql.SQL_EQUEAL)
 
 		if placeholder == protosql.SQL_QUESTION {
@@ -148,7 +158,7 @@ func dbBuildSqlSelectOne(msg proto.Message, keyColumns []string, resultColumns [
 			sqlParaNo++
 		}
 
-		fieldDesc := msgDesc.Fields().ByName(protoreflect.Name(fieldName))
+		fieldDesc, _ := pdbutil.FindFieldByColumnName(msgDesc, fieldName)
 		var val any
 		if fieldDesc == nil {
 			val, err = pdbutil.GetField(msg, fieldName)
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/pdbutil"
 	"github.com/ygrpc/protodb/protosql"
 	"github.com/ygrpc/protodb/sqldb"
 	"google.golang.org/protobuf/proto"
@@ -26,7 +27,7 @@ func TableQueryBuildSql(db sqldb.DB, msgDesc protoreflect.MessageDescriptor, tab
 
 	dbdialect := sqldb.GetExecutorDialect(db)
 	placeholder := dbdialect.Placeholder()
-	dbtableName := sqldb.BuildDbTableName(tableQueryReq.TableName, tableQueryReq.SchemeName, dbdialect)
+	dbtableName := sqldb.BuildQuotedDbTableName(tableQueryTableName(msgDesc, tableQueryReq.TableName), tableQueryReq.SchemeName, dbdialect)
 
 	sb := strings.Builder{}
 	sb.Grow(tableQueryBuildSQLCap(tableQueryReq, permissionSqlStr, len(permissionSqlVals), dbtableName, placeholder))
@@ -36,7 +37,16 @@ func TableQueryBuildSql(db sqldb.DB, msgDesc protoreflect.MessageDescriptor, tab
 	if len(tableQueryReq.ResultColumnNames) == 0 {
 		sb.WriteString(protosql.SQL_ASTERISK)
 	} else {
-		sb.WriteString(strings.Join(tableQueryReq.ResultColumnNames, protosql.SQL_COMMA))
+		for i, resultColumn := range tableQueryReq.ResultColumnNames {
+			if i > 0 {
+				sb.WriteString(protosql.SQL_COMMA)
+			}
+			if columnName, ok := tableQueryResultColumn(msgDesc, resultColumn); ok {
+				sb.WriteString(dbdialect.QuoteIdentifier(columnName))
+			} else {
+				sb.WriteString(resultColumn)
+			}
+		}
 	}
 
 	sb.WriteString(protosql.SQL_FROM)
@@ -72,10 +82,26 @@ func TableQueryBuildSql(db sqldb.DB, msgDesc protoreflect.MessageDescriptor, tab
 			}
 			firstPlaceholder = false
 
-			sb.WriteString(fieldName)
+			columnName, fieldDesc, err := getTableQueryColumn(msgDesc, fieldName, "where field")
+			if err != nil {
+				return "", nil, err
+			}
+
+			columnName = dbdialect.QuoteIdentifier(columnName)
+			numericPdb, isNumeric := numericFieldPdb(fieldDesc)
+			if isNumeric {
+				sb.WriteString(numericColumn(dbdialect, columnName))
+			} else {
+				sb.WriteString(columnName)
+			}
 			sb.WriteString(protosql.SQL_EQUEAL)
 
-			if placeholder == protosql.SQL_QUESTION {
+			if isNumeric {
+				sb.WriteString(numericPlaceholder(dbdialect, numericPdb, placeholder, sqlParaNo))
+				if placeholder != protosql.SQL_QUESTION {
+					sqlParaNo++
+				}
+			} else if placeholder == protosql.SQL_QUESTION {
 				sb.WriteString(string(protosql.SQL_QUESTION))
 			} else {
 				sb.WriteString(string(protosql.SQL_DOLLAR))
@@ -83,7 +109,11 @@ func TableQueryBuildSql(db sqldb.DB, msgDesc protoreflect.MessageDescriptor, tab
 				sqlParaNo++
 			}
 
-			sqlVals = append(sqlVals, fieldValue)
+			whereArg, err := tableQueryWhereArg(dbdialect, fieldDesc, fieldName, fieldValue)
+			if err != nil {
+				return "", nil, err
+			}
+			sqlVals = append(sqlVals, whereArg)
 		}
 	}
 
@@ -110,12 +140,15 @@ func TableQueryBuildSql(db sqldb.DB, msgDesc protoreflect.MessageDescriptor, tab
 				sb.WriteString(protosql.SQL_AND)
 			}
 
-			fieldDesc, err := getTableQueryFieldDesc(msgDesc, fieldname, "where2 field")
+			columnName, fieldDesc, err := getTableQueryColumn(msgDesc, fieldname, "where2 field")
 			if err != nil {
 				return "", nil, err
 			}
+			if !jsonConditionSupported(db, dbdialect, fieldDesc, fieldWhereOperator) {
+				return "", nil, fmt.Errorf("where2 field %s operator %v needs json functions, not available on the %v server", fieldname, fieldWhereOperator, dbdialect)
+			}
 
-			condStr, condArgs, argInc, err := buildWhere2ConditionForColumn(dbdialect, placeholder, sqlParaNo, fieldname, fieldDesc, fieldWhereOperator, fieldValue)
+			condStr, condArgs, argInc, err := buildWhere2ConditionForColumn(dbdialect, placeholder, sqlParaNo, dbdialect.QuoteIdentifier(columnName), fieldDesc, fieldWhereOperator, fieldValue)
 			if err != nil {
 				return "", nil, err
 			}
@@ -125,6 +158,40 @@ func TableQueryBuildSql(db sqldb.DB, msgDesc protoreflect.MessageDescriptor, tab
 		}
 	}
 
+	//handle oneof case
+	for oneofName, memberName := range tableQueryReq.OneofCase {
+		if firstPlaceholder {
+			sb.WriteString(protosql.SQL_WHERE)
+			firstPlaceholder = false
+		} else {
+			sb.WriteString(protosql.SQL_AND)
+		}
+
+		condStr, condArgs, argInc, err := oneofCaseCondition(msgDesc, dbdialect, placeholder, sqlParaNo, oneofName, memberName)
+		if err != nil {
+			return "", nil, err
+		}
+		sb.WriteString(condStr)
+		sqlVals = append(sqlVals, condArgs...)
+		sqlParaNo += argInc
+	}
+
+	for i, orderBy := range tableQueryReq.OrderBy {
+		if i == 0 {
+			sb.WriteString(protosql.SQL_ORDER_BY)
+		} else {
+			sb.WriteString(protosql.SQL_COMMA)
+		}
+		columnName, desc, err := getTableQueryOrderColumn(msgDesc, orderBy)
+		if err != nil {
+			return "", nil, err
+		}
+		sb.WriteString(dbdialect.QuoteIdentifier(columnName))
+		if desc {
+			sb.WriteString(protosql.SQL_DESC)
+		}
+	}
+
 	// Add LIMIT and OFFSET if specified
 	if tableQueryReq.Limit > 0 {
 		sb.WriteString(protosql.SQL_LIMIT)
@@ -140,6 +207,33 @@ func TableQueryBuildSql(db sqldb.DB, msgDesc protoreflect.MessageDescriptor, tab
 	return sqlStr, sqlVals, nil
 }
 
+// tableQueryWhereArg keeps where value as string, except well-known message fields which need native sql args,
+// numeric fields which are validated, and enum fields which accept name or number
+func tableQueryWhereArg(dialect sqldb.TDBDialect, fieldDesc protoreflect.FieldDescriptor, fieldName string, fieldValue string) (any, error) {
+	if isScalarEnumField(fieldDesc) {
+		arg, err := enumWhereArg(fieldDesc, fieldValue)
+		if err != nil {
+			return nil, fmt.Errorf("parse where field %s value err: %w", fieldName, err)
+		}
+		return arg, nil
+	}
+	if _, ok := numericFieldPdb(fieldDesc); ok {
+		arg, err := NormalizeNumericString(fieldValue, 0, 0)
+		if err != nil {
+			return nil, fmt.Errorf("parse where field %s value err: %w", fieldName, err)
+		}
+		return arg, nil
+	}
+	if !isWellKnownField(fieldDesc) {
+		return fieldValue, nil
+	}
+	arg, err := parseWellKnownScalarString(fieldDesc, dialect, fieldValue)
+	if err != nil {
+		return nil, fmt.Errorf("parse where field %s value err: %w", fieldName, err)
+	}
+	return arg, nil
+}
+
 func tableQueryBuildSQLCap(tableQueryReq *protodb.TableQueryReq, permissionSqlStr string, permissionSqlValCount int, dbtableName string, placeholder protosql.SQLPlaceholder) int {
 	capacity := len(protosql.SQL_SELECT)
 	if len(tableQueryReq.ResultColumnNames) == 0 {
@@ -149,7 +243,8 @@ func tableQueryBuildSQLCap(tableQueryReq *protodb.TableQueryReq, permissionSqlSt
 			if i > 0 {
 				capacity += len(protosql.SQL_COMMA)
 			}
-			capacity += len(columnName)
+			// quoted known columns
+			capacity += len(columnName) + 2
 		}
 	}
 
@@ -169,7 +264,7 @@ func tableQueryBuildSQLCap(tableQueryReq *protodb.TableQueryReq, permissionSqlSt
 				capacity += len(protosql.SQL_AND)
 			}
 			firstPlaceholder = false
-			capacity += len(fieldName) + len(protosql.SQL_EQUEAL) + sqlPlaceholderCap(placeholder, sqlParaNo)
+			capacity += len(fieldName) + 2 + len(protosql.SQL_EQUEAL) + sqlPlaceholderCap(placeholder, sqlParaNo)
 			if placeholder != protosql.SQL_QUESTION {
 				sqlParaNo++
 			}
@@ -193,6 +288,14 @@ func tableQueryBuildSQLCap(tableQueryReq *protodb.TableQueryReq, permissionSqlSt
 		}
 	}
 
+	for oneofName := range tableQueryReq.OneofCase {
+		capacity += len(protosql.SQL_WHERE) + tableQueryWhere2ConditionCap(oneofName, placeholder, sqlParaNo)
+	}
+
+	for _, orderBy := range tableQueryReq.OrderBy {
+		capacity += len(protosql.SQL_ORDER_BY) + len(orderBy) + 2 + len(protosql.SQL_DESC)
+	}
+
 	if tableQueryReq.Limit > 0 {
 		capacity += len(protosql.SQL_LIMIT) + decimalDigitCount64(int64(tableQueryReq.Limit))
 	}
@@ -221,7 +324,7 @@ func validateTableQueryIdentifiers(msgDesc protoreflect.MessageDescriptor, table
 	}
 
 	expectedTableName := string(msgDesc.Name())
-	if !strings.EqualFold(tableQueryReq.TableName, expectedTableName) {
+	if !strings.EqualFold(tableQueryReq.TableName, expectedTableName) && !strings.EqualFold(tableQueryReq.TableName, pdbutil.GetTableName(msgDesc)) {
 		return fmt.Errorf("table name %s does not match message %s", tableQueryReq.TableName, expectedTableName)
 	}
 	if err := validateTableQueryIdentifierSegment("table", tableQueryReq.TableName); err != nil {
@@ -233,19 +336,31 @@ func validateTableQueryIdentifiers(msgDesc protoreflect.MessageDescriptor, table
 	}
 
 	for fieldName := range tableQueryReq.Where {
-		if _, err := getTableQueryFieldDesc(msgDesc, fieldName, "where field"); err != nil {
+		if _, _, err := getTableQueryColumn(msgDesc, fieldName, "where field"); err != nil {
 			return err
 		}
 	}
 
 	for fieldName := range tableQueryReq.Where2 {
-		if _, err := getTableQueryFieldDesc(msgDesc, fieldName, "where2 field"); err != nil {
+		if _, _, err := getTableQueryColumn(msgDesc, fieldName, "where2 field"); err != nil {
 			return err
 		}
 	}
 
 	for fieldName := range tableQueryReq.Where2Operator {
-		if _, err := getTableQueryFieldDesc(msgDesc, fieldName, "where2 operator field"); err != nil {
+		if _, _, err := getTableQueryColumn(msgDesc, fieldName, "where2 operator field"); err != nil {
+			return err
+		}
+	}
+
+	for oneofName, memberName := range tableQueryReq.OneofCase {
+		if _, _, err := resolveOneofCase(msgDesc, oneofName, memberName); err != nil {
+			return err
+		}
+	}
+
+	for _, orderBy := range tableQueryReq.OrderBy {
+		if _, _, err := getTableQueryOrderColumn(msgDesc, orderBy); err != nil {
 			return err
 		}
 	}
@@ -253,6 +368,13 @@ func validateTableQueryIdentifiers(msgDesc protoreflect.MessageDescriptor, table
 	return nil
 }
 
+// getTableQueryOrderColumn db column of order by field, '-' prefix for descending
+func getTableQueryOrderColumn(msgDesc protoreflect.MessageDescriptor, orderBy string) (columnName string, desc bool, err error) {
+	fieldName, desc := strings.CutPrefix(orderBy, "-")
+	columnName, _, err = getTableQueryColumn(msgDesc, fieldName, "order by field")
+	return columnName, desc, err
+}
+
 // validateTableQueryResultColumns allows projection expressions such as trim(col) or col::integer.
 // This intentionally does not require a proto-field match because SQL result expressions may be aliased
 // by callers; it only applies the expression-level injection guard used for SELECT list inputs.
@@ -268,6 +390,10 @@ func validateTableQueryResultColumns(msgDesc protoreflect.MessageDescriptor, res
 		if strings.TrimSpace(fieldName) == "*" {
 			return fmt.Errorf("result column * must be the only result column")
 		}
+		if _, ok := tableQueryResultColumn(msgDesc, fieldName); ok {
+			// known column is quoted, reserved words like order are allowed
+			continue
+		}
 		if err := checkSQLColumnsIsNoInjectionInWhere(fieldName); err != nil {
 			return fmt.Errorf("check result column %s err: %w", fieldName, err)
 		}
@@ -275,15 +401,59 @@ func validateTableQueryResultColumns(msgDesc protoreflect.MessageDescriptor, res
 	return nil
 }
 
+// tableQueryResultColumn db column of result column name when it is exactly a column of message,
+// other result columns are expressions and written as is
+func tableQueryResultColumn(msgDesc protoreflect.MessageDescriptor, name string) (string, bool) {
+	if column, ok := pdbutil.FindFlatColumn(msgDesc, name); ok {
+		return column.Name, true
+	}
+	fieldDesc, ok := pdbutil.FindFieldByColumnName(msgDesc, name)
+	if !ok || pdbutil.IsFlattenField(fieldDesc) {
+		return "", false
+	}
+	return pdbutil.GetColumnName(fieldDesc), true
+}
+
+// tableQueryTableName db table name of query, the request can use message name or table name,
+// keep the request name when it is the table name
+func tableQueryTableName(msgDesc protoreflect.MessageDescriptor, reqTableName string) string {
+	tableName := pdbutil.GetTableName(msgDesc)
+	if strings.EqualFold(reqTableName, tableName) {
+		return reqTableName
+	}
+	return tableName
+}
+
+// getTableQueryColumn resolves condition fields to the db column, columns of flattened sub message
+// accept dotted field path(address.city) or column name(address_city)
+func getTableQueryColumn(msgDesc protoreflect.MessageDescriptor, fieldName string, label string) (columnName string, fieldDesc protoreflect.FieldDescriptor, err error) {
+	if column, ok := pdbutil.FindFlatColumn(msgDesc, fieldName); ok {
+		return column.Name, column.Field(), nil
+	}
+	fieldDesc, err = getTableQueryFieldDesc(msgDesc, fieldName, label)
+	if err != nil {
+		return "", nil, err
+	}
+	if pdbutil.IsFlattenField(fieldDesc) {
+		return "", nil, fmt.Errorf("%s %s is flattened, use sub field path like %s.xxx", label, fieldName, fieldName)
+	}
+	columnName = pdbutil.GetColumnName(fieldDesc)
+	if strings.EqualFold(fieldName, columnName) {
+		// keep the request name
+		columnName = fieldName
+	}
+	return columnName, fieldDesc, nil
+}
+
 // getTableQueryFieldDesc resolves condition fields against the descriptor before they enter WHERE SQL.
-// It accepts DB-style lowercase names as aliases for exported proto field names.
+// It accepts column names and DB-style lowercase names as aliases for exported proto field names.
 func getTableQueryFieldDesc(msgDesc protoreflect.MessageDescriptor, fieldName string, label string) (protoreflect.FieldDescriptor, error) {
 	if err := validateTableQueryIdentifierSegment(label, fieldName); err != nil {
 		return nil, err
 	}
 	fieldDesc := msgDesc.Fields().ByName(protoreflect.Name(fieldName))
 	if fieldDesc == nil {
-		fieldDesc = getTableQueryFieldDescFold(msgDesc, fieldName)
+		fieldDesc, _ = pdbutil.FindFieldByColumnName(msgDesc, fieldName)
 	}
 	if fieldDesc == nil {
 		return nil, fmt.Errorf("%s %s not found in message %s", label, fieldName, msgDesc.FullName())
@@ -291,18 +461,6 @@ func getTableQueryFieldDesc(msgDesc protoreflect.MessageDescriptor, fieldName st
 	return fieldDesc, nil
 }
 
-// getTableQueryFieldDescFold is the lowercase DB-name compatibility path for proto fields.
-func getTableQueryFieldDescFold(msgDesc protoreflect.MessageDescriptor, fieldName string) protoreflect.FieldDescriptor {
-	fields := msgDesc.Fields()
-	for i := 0; i < fields.Len(); i++ {
-		fieldDesc := fields.Get(i)
-		if strings.EqualFold(fieldName, string(fieldDesc.Name())) || strings.EqualFold(fieldName, fieldDesc.TextName()) {
-			return fieldDesc
-		}
-	}
-	return nil
-}
-
 // validateTableQueryIdentifierSegment rejects qualified names here because schema/table/where fields are
 // assembled by the builder; allowing dots would let callers cross table/schema boundaries.
 func validateTableQueryIdentifierSegment(label string, name string) error {
@@ -326,52 +484,44 @@ func buildWhere2Condition(dialect sqldb.TDBDialect, placeholder protosql.SQLPlac
 // buildWhere2ConditionForColumn uses fieldDesc for type semantics while keeping fieldName for emitted SQL.
 // This lets lowercase DB column names pass validation without rewriting them back to exported proto names.
 func buildWhere2ConditionForColumn(dialect sqldb.TDBDialect, placeholder protosql.SQLPlaceholder, paraNo int, fieldName string, fieldDesc protoreflect.FieldDescriptor, op protodb.WhereOperator, valueStr string) (cond string, args []any, argInc int, err error) {
+	switch op {
+	case protodb.WhereOperator_WOP_IS_NULL, protodb.WhereOperator_WOP_IS_NOT_NULL:
+		return fieldName + WhereOperator2Str(op), nil, 0, nil
+	}
 	if fieldDesc.IsMap() {
-		switch dialect {
-		case sqldb.Postgres:
-			switch op {
-			case protodb.WhereOperator_WOP_HAS_KEY:
-				cond = fieldName + " ? " + buildPlaceholder(placeholder, paraNo)
-				return cond, []any{valueStr}, 1, nil
-			case protodb.WhereOperator_WOP_CONTAINS:
-				cond = fieldName + " @> " + buildPlaceholder(placeholder, paraNo) + "::jsonb"
-				return cond, []any{valueStr}, 1, nil
-			default:
-				return "", nil, 0, fmt.Errorf("unsupported operator %v for map field %s", op, fieldName)
-			}
-		case sqldb.Mysql:
-			switch op {
-			case protodb.WhereOperator_WOP_HAS_KEY:
-				// NOTE: assumes map keys can be addressed as $.<key>.
-				cond = "JSON_CONTAINS_PATH(" + fieldName + ", 'one', CONCAT('$.' , " + buildPlaceholder(placeholder, paraNo) + "))"
-				return cond, []any{valueStr}, 1, nil
-			case protodb.WhereOperator_WOP_CONTAINS:
-				cond = "JSON_CONTAINS(" + fieldName + ", CAST(" + buildPlaceholder(placeholder, paraNo) + " AS JSON))"
-				return cond, []any{valueStr}, 1, nil
-			default:
-				return "", nil, 0, fmt.Errorf("unsupported operator %v for map field %s", op, fieldName)
-			}
-		case sqldb.SQLite:
-			switch op {
-			case protodb.WhereOperator_WOP_HAS_KEY:
-				cond = "EXISTS (SELECT 1 FROM json_each(" + fieldName + ") WHERE key = " + buildPlaceholder(placeholder, paraNo) + ")"
-				return cond, []any{valueStr}, 1, nil
-			case protodb.WhereOperator_WOP_CONTAINS:
-				// valueStr must be a JSON object string
-				cond = "NOT EXISTS (SELECT 1 FROM json_each(" + buildPlaceholder(placeholder, paraNo) + ") b WHERE NOT EXISTS (SELECT 1 FROM json_each(" + fieldName + ") a WHERE a.key = b.key AND a.value = b.value))"
-				return cond, []any{valueStr}, 1, nil
-			default:
-				return "", nil, 0, fmt.Errorf("unsupported operator %v for sqlite map field %s", op, fieldName)
-			}
-		default:
-			return "", nil, 0, fmt.Errorf("unsupported dialect %v", dialect)
+		cond, ok := dialect.Dialect().MapCondition(fieldName, strings.TrimPrefix(op.String(), "WOP_"), buildPlaceholder(placeholder, paraNo))
+		if !ok {
+			return "", nil, 0, fmt.Errorf("unsupported operator %v for %v map field %s", op, dialect, fieldName)
 		}
+		return cond, []any{valueStr}, 1, nil
 	}
 	if !fieldDesc.IsList() {
 		// keep backward compatibility: treat value as string for scalar ops
 		switch op {
 		case protodb.WhereOperator_WOP_GT, protodb.WhereOperator_WOP_LT, protodb.WhereOperator_WOP_GTE, protodb.WhereOperator_WOP_LTE, protodb.WhereOperator_WOP_LIKE, protodb.WhereOperator_WOP_EQ:
+			if numericPdb, ok := numericFieldPdb(fieldDesc); ok && op != protodb.WhereOperator_WOP_LIKE {
+				arg, err := NormalizeNumericString(valueStr, 0, 0)
+				if err != nil {
+					return "", nil, 0, fmt.Errorf("parse where2 field %s value err: %w", fieldName, err)
+				}
+				cond = numericColumn(dialect, fieldName) + WhereOperator2Str(op) + numericPlaceholder(dialect, numericPdb, placeholder, paraNo)
+				return cond, []any{arg}, 1, nil
+			}
 			cond = fieldName + WhereOperator2Str(op) + buildPlaceholder(placeholder, paraNo)
+			if isScalarEnumField(fieldDesc) && op != protodb.WhereOperator_WOP_LIKE {
+				arg, err := enumWhereArg(fieldDesc, valueStr)
+				if err != nil {
+					return "", nil, 0, fmt.Errorf("parse where2 field %s value err: %w", fieldName, err)
+				}
+				return cond, []any{arg}, 1, nil
+			}
+			if isWellKnownField(fieldDesc) && op != protodb.WhereOperator_WOP_LIKE {
+				arg, err := parseWellKnownScalarString(fieldDesc, dialect, valueStr)
+				if err != nil {
+					return "", nil, 0, fmt.Errorf("parse where2 field %s value err: %w", fieldName, err)
+				}
+				return cond, []any{arg}, 1, nil
+			}
 			return cond, []any{valueStr}, 1, nil
 		default:
 			return "", nil, 0, fmt.Errorf("unsupported operator %v for non-list field %s", op, fieldName)
@@ -379,116 +529,92 @@ func buildWhere2ConditionForColumn(dialect sqldb.TDBDialect, placeholder protosq
 	}
 
 	// list operators
-	switch dialect {
-	case sqldb.Postgres:
-		if fieldDesc.Kind() == protoreflect.MessageKind {
-			switch op {
-			case protodb.WhereOperator_WOP_CONTAINS, protodb.WhereOperator_WOP_CONTAINS_ALL:
-				cond = fieldName + " @> " + buildPlaceholder(placeholder, paraNo) + "::jsonb"
-				return cond, []any{valueStr}, 1, nil
-			case protodb.WhereOperator_WOP_LEN_GT, protodb.WhereOperator_WOP_LEN_GTE, protodb.WhereOperator_WOP_LEN_LT, protodb.WhereOperator_WOP_LEN_LTE:
-				cmp := lenOpToSql(op)
-				cond = "jsonb_array_length(" + fieldName + ")" + cmp + buildPlaceholder(placeholder, paraNo)
-				n, err := strconv.ParseInt(strings.TrimSpace(valueStr), 10, 64)
-				if err != nil {
-					return "", nil, 0, err
-				}
-				return cond, []any{n}, 1, nil
-			default:
-				return "", nil, 0, fmt.Errorf("unsupported list operator %v for repeated message field %s", op, fieldName)
-			}
+	cond, listArg, ok := dialect.Dialect().ListCondition(fieldName, fieldDesc.Kind(), strings.TrimPrefix(op.String(), "WOP_"), buildPlaceholder(placeholder, paraNo))
+	if !ok {
+		return "", nil, 0, fmt.Errorf("unsupported list operator %v for %v field %s", op, dialect, fieldName)
+	}
+	switch listArg {
+	case sqldb.ListArgScalar:
+		scalar, err := parseScalarString(fieldDesc.Kind(), valueStr)
+		if err != nil {
+			return "", nil, 0, err
+		}
+		return cond, []any{scalar}, 1, nil
+	case sqldb.ListArgArray:
+		arr, err := parseScalarJSONArray(fieldDesc.Kind(), valueStr)
+		if err != nil {
+			return "", nil, 0, err
+		}
+		return cond, []any{arr}, 1, nil
+	case sqldb.ListArgLength:
+		n, err := strconv.ParseInt(strings.TrimSpace(valueStr), 10, 64)
+		if err != nil {
+			return "", nil, 0, err
 		}
+		return cond, []any{n}, 1, nil
+	default:
+		return cond, []any{valueStr}, 1, nil
+	}
+}
 
-		elemType := protodb.GetProtoDBType(fieldDesc.Kind(), sqldb.Postgres)
-		switch op {
-		case protodb.WhereOperator_WOP_CONTAINS:
-			scalar, err := parseScalarString(fieldDesc.Kind(), valueStr)
-			if err != nil {
-				return "", nil, 0, err
-			}
-			cond = fieldName + " @> ARRAY[" + buildPlaceholder(placeholder, paraNo) + "]::" + elemType + "[]"
-			return cond, []any{scalar}, 1, nil
-		case protodb.WhereOperator_WOP_OVERLAP, protodb.WhereOperator_WOP_CONTAINS_ALL:
-			arr, err := parseScalarJSONArray(fieldDesc.Kind(), valueStr)
-			if err != nil {
-				return "", nil, 0, err
-			}
-			opStr := " && "
-			if op == protodb.WhereOperator_WOP_CONTAINS_ALL {
-				opStr = " @> "
-			}
-			cond = fieldName + opStr + buildPlaceholder(placeholder, paraNo)
-			return cond, []any{arr}, 1, nil
-		case protodb.WhereOperator_WOP_LEN_GT, protodb.WhereOperator_WOP_LEN_GTE, protodb.WhereOperator_WOP_LEN_LT, protodb.WhereOperator_WOP_LEN_LTE:
-			cmp := lenOpToSql(op)
-			cond = "cardinality(" + fieldName + ")" + cmp + buildPlaceholder(placeholder, paraNo)
-			n, err := strconv.ParseInt(strings.TrimSpace(valueStr), 10, 64)
-			if err != nil {
-				return "", nil, 0, err
-			}
-			return cond, []any{n}, 1, nil
-		default:
-			return "", nil, 0, fmt.Errorf("unsupported list operator %v for field %s", op, fieldName)
+// jsonConditionSupported list/map conditions on json text columns need json functions of the server(sqlite json1)
+func jsonConditionSupported(db sqldb.DB, dialect sqldb.TDBDialect, fieldDesc protoreflect.FieldDescriptor, op protodb.WhereOperator) bool {
+	switch op {
+	case protodb.WhereOperator_WOP_IS_NULL, protodb.WhereOperator_WOP_IS_NOT_NULL:
+		return true
+	}
+	d := dialect.Dialect()
+	switch {
+	case fieldDesc.IsMap():
+		if d.NativeMaps() {
+			return true
 		}
-	case sqldb.SQLite:
-		switch op {
-		case protodb.WhereOperator_WOP_CONTAINS:
-			scalar, err := parseScalarString(fieldDesc.Kind(), valueStr)
-			if err != nil {
-				return "", nil, 0, err
-			}
-			cond = "EXISTS (SELECT 1 FROM json_each(" + fieldName + ") WHERE value = " + buildPlaceholder(placeholder, paraNo) + ")"
-			return cond, []any{scalar}, 1, nil
-		case protodb.WhereOperator_WOP_OVERLAP:
-			cond = "EXISTS (SELECT 1 FROM json_each(" + fieldName + ") a JOIN json_each(" + buildPlaceholder(placeholder, paraNo) + ") b ON a.value = b.value)"
-			return cond, []any{valueStr}, 1, nil
-		case protodb.WhereOperator_WOP_CONTAINS_ALL:
-			cond = "NOT EXISTS (SELECT 1 FROM json_each(" + buildPlaceholder(placeholder, paraNo) + ") b WHERE NOT EXISTS (SELECT 1 FROM json_each(" + fieldName + ") a WHERE a.value = b.value))"
-			return cond, []any{valueStr}, 1, nil
-		case protodb.WhereOperator_WOP_LEN_GT, protodb.WhereOperator_WOP_LEN_GTE, protodb.WhereOperator_WOP_LEN_LT, protodb.WhereOperator_WOP_LEN_LTE:
-			cmp := lenOpToSql(op)
-			cond = "json_array_length(" + fieldName + ")" + cmp + buildPlaceholder(placeholder, paraNo)
-			n, err := strconv.ParseInt(strings.TrimSpace(valueStr), 10, 64)
-			if err != nil {
-				return "", nil, 0, err
-			}
-			return cond, []any{n}, 1, nil
-		default:
-			return "", nil, 0, fmt.Errorf("unsupported list operator %v for sqlite field %s", op, fieldName)
+	case fieldDesc.IsList():
+		if d.NativeArrays() && fieldDesc.Kind() != protoreflect.MessageKind {
+			return true
+		}
+	default:
+		return true
+	}
+	return sqldb.GetExecutorCapabilities(db).JSON
+}
+
+// WhereOperators where2 operators supported by the column of fieldDesc in db, in enum order
+func WhereOperators(db sqldb.DB, fieldDesc protoreflect.FieldDescriptor) []protodb.WhereOperator {
+	dialect := sqldb.GetExecutorDialect(db)
+	values := protodb.WhereOperator_WOP_UNKNOWN.Descriptor().Values()
+	ops := make([]protodb.WhereOperator, 0, values.Len())
+	for i := 0; i < values.Len(); i++ {
+		op := protodb.WhereOperator(values.Get(i).Number())
+		if whereOperatorSupported(dialect, fieldDesc, op) && jsonConditionSupported(db, dialect, fieldDesc, op) {
+			ops = append(ops, op)
 		}
-	case sqldb.Mysql:
+	}
+	return ops
+}
+
+// whereOperatorSupported can buildWhere2ConditionForColumn build op for fieldDesc in dialect
+func whereOperatorSupported(dialect sqldb.TDBDialect, fieldDesc protoreflect.FieldDescriptor, op protodb.WhereOperator) bool {
+	switch op {
+	case protodb.WhereOperator_WOP_UNKNOWN:
+		return false
+	case protodb.WhereOperator_WOP_IS_NULL, protodb.WhereOperator_WOP_IS_NOT_NULL:
+		return true
+	}
+	opName := strings.TrimPrefix(op.String(), "WOP_")
+	if fieldDesc.IsMap() {
+		_, ok := dialect.Dialect().MapCondition("c", opName, "?")
+		return ok
+	}
+	if !fieldDesc.IsList() {
 		switch op {
-		case protodb.WhereOperator_WOP_CONTAINS:
-			if fieldDesc.Kind() == protoreflect.MessageKind {
-				cond = "JSON_CONTAINS(" + fieldName + ", JSON_ARRAY(CAST(" + buildPlaceholder(placeholder, paraNo) + " AS JSON)))"
-				return cond, []any{valueStr}, 1, nil
-			}
-			scalar, err := parseScalarString(fieldDesc.Kind(), valueStr)
-			if err != nil {
-				return "", nil, 0, err
-			}
-			cond = "JSON_CONTAINS(" + fieldName + ", JSON_ARRAY(" + buildPlaceholder(placeholder, paraNo) + "))"
-			return cond, []any{scalar}, 1, nil
-		case protodb.WhereOperator_WOP_OVERLAP:
-			cond = "JSON_OVERLAPS(" + fieldName + ", CAST(" + buildPlaceholder(placeholder, paraNo) + " AS JSON))"
-			return cond, []any{valueStr}, 1, nil
-		case protodb.WhereOperator_WOP_CONTAINS_ALL:
-			cond = "JSON_CONTAINS(" + fieldName + ", CAST(" + buildPlaceholder(placeholder, paraNo) + " AS JSON))"
-			return cond, []any{valueStr}, 1, nil
-		case protodb.WhereOperator_WOP_LEN_GT, protodb.WhereOperator_WOP_LEN_GTE, protodb.WhereOperator_WOP_LEN_LT, protodb.WhereOperator_WOP_LEN_LTE:
-			cmp := lenOpToSql(op)
-			cond = "JSON_LENGTH(" + fieldName + ")" + cmp + buildPlaceholder(placeholder, paraNo)
-			n, err := strconv.ParseInt(strings.TrimSpace(valueStr), 10, 64)
-			if err != nil {
-				return "", nil, 0, err
-			}
-			return cond, []any{n}, 1, nil
-		default:
-			return "", nil, 0, fmt.Errorf("unsupported list operator %v for mysql field %s", op, fieldName)
+		case protodb.WhereOperator_WOP_GT, protodb.WhereOperator_WOP_LT, protodb.WhereOperator_WOP_GTE, protodb.WhereOperator_WOP_LTE, protodb.WhereOperator_WOP_LIKE, protodb.WhereOperator_WOP_EQ:
+			return true
 		}
-	default:
-		return "", nil, 0, fmt.Errorf("unsupported dialect %v", dialect)
+		return false
 	}
+	_, _, ok := dialect.Dialect().ListCondition("c", fieldDesc.Kind(), opName, "?")
+	return ok
 }
 
 func buildPlaceholder(placeholder protosql.SQLPlaceholder, paraNo int) string {
@@ -620,6 +746,10 @@ func WhereOperator2Str(fieldop protodb.WhereOperator) string {
 		return protosql.SQL_LIKE
 	case protodb.WhereOperator_WOP_EQ:
 		return protosql.SQL_EQUEAL
+	case protodb.WhereOperator_WOP_IS_NULL:
+		return protosql.SQL_IS_NULL
+	case protodb.WhereOperator_WOP_IS_NOT_NULL:
+		return protosql.SQL_IS_NOT_NULL
 	default:
 		return " unsupported operator: " + fmt.Sprint(fieldop)
 	}
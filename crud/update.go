@@ -21,7 +21,7 @@ func DbUpdate(db sqldb.DB, msg proto.Message, msgLastFieldNo int32, dbschema str
 	msgPm := msg.ProtoReflect()
 	msgDesc := msgPm.Descriptor()
 	msgFieldDescs := msgDesc.Fields()
-	tableName := string(msgDesc.Name())
+	tableName := pdbutil.GetTableName(msgDesc)
 
 	return dbUpdate(db, msg, msgLastFieldNo, dbschema, tableName, msgDesc, msgFieldDescs)
 }
@@ -61,7 +61,7 @@ func DbUpdateReturnNew(db sqldb.DB, msg proto.Message, msgLastFieldNo int32, dbs
 	msgPm := msg.ProtoReflect()
 	msgDesc := msgPm.Descriptor()
 	msgFieldDescs := msgDesc.Fields()
-	tableName := string(msgDesc.Name())
+	tableName := pdbutil.GetTableName(msgDesc)
 
 	return dbUpdateReturnNew(db, msg, msgLastFieldNo, dbschema, tableName, msgDesc, msgFieldDescs)
 }
@@ -72,7 +72,7 @@ func DbUpdateReturnOldAndNew(db sqldb.DB, msg proto.Message, msgLastFieldNo int3
 	msgPm := msg.ProtoReflect()
 	msgDesc := msgPm.Descriptor()
 	msgFieldDescs := msgDesc.Fields()
-	tableName := string(msgDesc.Name())
+	tableName := pdbutil.GetTableName(msgDesc)
 
 	return dbUpdateReturnOldAndNew(db, msg, msgLastFieldNo, dbschema, tableName, msgDesc, msgFieldDescs)
 }
@@ -83,12 +83,12 @@ func dbUpdateReturnNew(db sqldb.DB, msg proto.Message, msgLastFieldNo int32, dbs
 	msgFieldDescs protoreflect.FieldDescriptors,
 ) (newMsg proto.Message, err error) {
 	dbdialect := sqldb.GetExecutorDialect(db)
-	if dbdialect == sqldb.Mysql {
+	if !sqldb.GetExecutorCapabilities(db).UpdateReturning {
 		_, err := dbUpdate(db, msg, msgLastFieldNo, dbschema, tableName, msgDesc, msgFieldDescs)
 		if err != nil {
 			return nil, err
 		}
-		return mysqlSelectReturnedMsg(db, msg, dbschema, tableName, msgDesc, msgFieldDescs)
+		return selectReturnedMsg(db, msg, dbschema, tableName, msgDesc, msgFieldDescs, dbdialect)
 	}
 
 	sqlStr, sqlVals, err := dbBuildSqlUpdate(msg, msgLastFieldNo, dbschema, tableName, msgDesc, msgFieldDescs, dbdialect, true)
@@ -110,7 +110,7 @@ func dbUpdateReturnNew(db sqldb.DB, msg proto.Message, msgLastFieldNo int32, dbs
 
 	msgFieldsMap := pdbutil.BuildMsgFieldsMap(nil, msgDesc.Fields(), true)
 
-	err = DbScan2ProtoMsg(rows, newMsg, nil, msgFieldsMap)
+	err = dbScan2ProtoMsg(msgStoreOf(db), rows, newMsg, nil, msgFieldsMap)
 
 	return newMsg, err
 }
@@ -121,7 +121,7 @@ func dbBuildSqlUpdate(msgobj proto.Message, msgLastFieldNo int32, dbschema strin
 	msgFieldDescs protoreflect.FieldDescriptors,
 	dbdialect sqldb.TDBDialect, returnUpdated bool,
 ) (sqlStr string, sqlVals []interface{}, err error) {
-	dbtableName := sqldb.BuildDbTableName(tableName, dbschema, dbdialect)
+	dbtableName := sqldb.BuildQuotedDbTableName(tableName, dbschema, dbdialect)
 	valFieldNames := make([]string, 0, msgFieldDescs.Len())
 
 	placeholder := dbdialect.Placeholder()
@@ -134,7 +134,7 @@ func dbBuildSqlUpdate(msgobj proto.Message, msgLastFieldNo int32, dbschema strin
 	for fi := 0; fi < msgFieldDescs.Len(); fi++ {
 		field := msgFieldDescs.Get(fi)
 
-		fieldName := string(field.Name())
+		fieldName := pdbutil.GetColumnName(field)
 
 		if _, ok := primaryKeyFieldNames[fieldName]; ok {
 			// primary key field, skip
@@ -153,6 +153,16 @@ func dbBuildSqlUpdate(msgobj proto.Message, msgLastFieldNo int32, dbschema strin
 			}
 		}
 
+		if pdbutil.IsFlattenField(field) {
+			flatColumnNames, flatVals, err := flatFieldSQLArgs(msgobj, field, dbdialect, true, nil)
+			if err != nil {
+				return "", nil, fmt.Errorf("msg:%s field:%s %w", msgDesc.Name(), fieldName, err)
+			}
+			valFieldNames = append(valFieldNames, flatColumnNames...)
+			sqlVals = append(sqlVals, flatVals...)
+			continue
+		}
+
 		valFieldNames = append(valFieldNames, fieldName)
 
 		val, err := getSQLFieldValue(msgobj, field)
@@ -161,7 +171,7 @@ func dbBuildSqlUpdate(msgobj proto.Message, msgLastFieldNo int32, dbschema strin
 			return "", nil, err
 		}
 
-		isValZero := pdbutil.IsZeroValue(val)
+		isValZero := isSQLValZero(field, val)
 		_, hasDefaultValue := fieldPdb.HasDefaultValue()
 		hasSetDefaultValue := false
 		if !fieldPdb.IsNotNull() && (fieldPdb.IsReference() || fieldPdb.IsZeroAsNull()) && isValZero {
@@ -181,7 +191,11 @@ func dbBuildSqlUpdate(msgobj proto.Message, msgLastFieldNo int32, dbschema strin
 		sqlVals = append(sqlVals, val)
 	}
 
-	returningUpdated := returnUpdated && mysqlSupportsReturning(dbdialect)
+	discriminatorNames, discriminatorVals := oneofDiscriminatorSQLArgs(msgobj, nil)
+	valFieldNames = append(valFieldNames, discriminatorNames...)
+	sqlVals = append(sqlVals, discriminatorVals...)
+
+	returningUpdated := returnUpdated
 
 	sb := strings.Builder{}
 	sb.Grow(dbBuildSqlUpdateCap(dbtableName, valFieldNames, primaryKeyFieldNames, placeholder, returningUpdated))
@@ -192,6 +206,7 @@ func dbBuildSqlUpdate(msgobj proto.Message, msgLastFieldNo int32, dbschema strin
 	firstPlaceholder := true
 	sqlParaNo := 1
 
+	mapColumns := mapArgColumns(dbdialect, msgFieldDescs)
 	for _, fieldName := range valFieldNames {
 		if firstPlaceholder {
 			firstPlaceholder = false
@@ -199,14 +214,10 @@ func dbBuildSqlUpdate(msgobj proto.Message, msgLastFieldNo int32, dbschema strin
 			sb.WriteString(protosql.SQL_COMMA)
 		}
 
-		sb.WriteString(fieldName)
+		sb.WriteString(dbdialect.QuoteIdentifier(fieldName))
 		sb.WriteString(protosql.SQL_EQUEAL)
-
-		if placeholder == protosql.SQL_QUESTION {
-			sb.WriteString(string(protosql.SQL_QUESTION))
-		} else {
-			sb.WriteString(string(protosql.SQL_DOLLAR))
-			sb.WriteString(strconv.Itoa(sqlParaNo))
+		sb.WriteString(columnArgPlaceholder(dbdialect, mapColumns, fieldName, placeholder, sqlParaNo))
+		if placeholder != protosql.SQL_QUESTION {
 			sqlParaNo++
 		}
 	}
@@ -222,7 +233,7 @@ func dbBuildSqlUpdate(msgobj proto.Message, msgLastFieldNo int32, dbschema strin
 			sb.WriteString(protosql.SQL_AND)
 		}
 
-		sb.WriteString(fieldName)
+		sb.WriteString(dbdialect.QuoteIdentifier(fieldName))
 		sb.WriteString(protosql.SQL_EQUEAL)
 
 		if placeholder == protosql.SQL_QUESTION {
@@ -290,8 +301,10 @@ func dbUpdateReturnOldAndNew(db sqldb.DB, msg proto.Message, msgLastFieldNo int3
 ) (oldMsg proto.Message, newMsg proto.Message, err error) {
 	dbdialect := sqldb.GetExecutorDialect(db)
 
-	//if db is sqlite/mysql, use selectone + update + selectone fallback
-	if dbdialect == sqldb.SQLite || dbdialect == sqldb.Mysql {
+	oldNewReturning := sqldb.GetExecutorCapabilities(db).OldNewReturning
+
+	//if db can not return old rows(sqlite/mysql), use selectone + update + selectone fallback
+	if oldNewReturning == sqldb.OldNewSelect {
 		oldMsg, err = dbSelectOne(db, msg, nil, nil, dbschema, tableName, msgDesc, msgFieldDescs, dbdialect, true)
 		if err != nil {
 			return nil, nil, err
@@ -301,14 +314,7 @@ func dbUpdateReturnOldAndNew(db sqldb.DB, msg proto.Message, msgLastFieldNo int3
 	}
 
 	fnbuildsql := dbBuildSqlUpdateOldAndNew
-	if dbdialect == sqldb.Postgres {
-		pgversion, _ := GetPgVersion(db)
-		if pgversion.Major >= 18 {
-
-			fnbuildsql = dbBuildSqlUpdateOldAndNewNative
-		}
-	}
-	if dbdialect == sqldb.Oracle {
+	if oldNewReturning == sqldb.OldNewNative {
 		fnbuildsql = dbBuildSqlUpdateOldAndNewNative
 	}
 
@@ -332,7 +338,7 @@ func dbUpdateReturnOldAndNew(db sqldb.DB, msg proto.Message, msgLastFieldNo int3
 
 	msgFieldsMap := pdbutil.BuildMsgFieldsMap(nil, msgDesc.Fields(), true)
 
-	err = DbScan2ProtoMsgx2(rows, oldMsg, newMsg, nil, msgFieldsMap)
+	err = dbScan2ProtoMsgx2(msgStoreOf(db), rows, oldMsg, newMsg, nil, msgFieldsMap)
 
 	return oldMsg, newMsg, nil
 }
@@ -347,7 +353,7 @@ func dbBuildSqlUpdateOldAndNew(msgobj proto.Message, msgLastFieldNo int32, dbsch
 	// with old as (select * from ttt where id=1)
 	// update ttt new set username='1234567' from old where new.id=old.id RETURNING old.*,new.*;
 
-	dbtableName := sqldb.BuildDbTableName(tableName, dbschema, dbdialect)
+	dbtableName := sqldb.BuildQuotedDbTableName(tableName, dbschema, dbdialect)
 
 	placeholder := dbdialect.Placeholder()
 	primaryKeyFieldNames := pdbutil.GetPrimaryKeyFieldDescs(msgDesc, msgFieldDescs, false)
@@ -371,7 +377,7 @@ func dbBuildSqlUpdateOldAndNew(msgobj proto.Message, msgLastFieldNo int32, dbsch
 			sb.WriteString(protosql.SQL_AND)
 		}
 
-		sb.WriteString(fieldName)
+		sb.WriteString(dbdialect.QuoteIdentifier(fieldName))
 		sb.WriteString(protosql.SQL_EQUEAL)
 
 		if placeholder == protosql.SQL_QUESTION {
@@ -402,7 +408,7 @@ func dbBuildSqlUpdateOldAndNew(msgobj proto.Message, msgLastFieldNo int32, dbsch
 	for fi := 0; fi < msgFieldDescs.Len(); fi++ {
 		field := msgFieldDescs.Get(fi)
 
-		fieldName := string(field.Name())
+		fieldName := pdbutil.GetColumnName(field)
 
 		if _, ok := primaryKeyFieldNames[fieldName]; ok {
 			// primary key field, skip
@@ -421,6 +427,16 @@ func dbBuildSqlUpdateOldAndNew(msgobj proto.Message, msgLastFieldNo int32, dbsch
 			}
 		}
 
+		if pdbutil.IsFlattenField(field) {
+			flatColumnNames, flatVals, err := flatFieldSQLArgs(msgobj, field, dbdialect, true, nil)
+			if err != nil {
+				return "", nil, fmt.Errorf("msg:%s field:%s %w", msgDesc.Name(), fieldName, err)
+			}
+			valFieldNames = append(valFieldNames, flatColumnNames...)
+			sqlVals = append(sqlVals, flatVals...)
+			continue
+		}
+
 		valFieldNames = append(valFieldNames, fieldName)
 
 		val, err := getSQLFieldValue(msgobj, field)
@@ -429,7 +445,7 @@ func dbBuildSqlUpdateOldAndNew(msgobj proto.Message, msgLastFieldNo int32, dbsch
 			return "", nil, err
 		}
 
-		isValZero := pdbutil.IsZeroValue(val)
+		isValZero := isSQLValZero(field, val)
 		_, hasDefaultValue := fieldPdb.HasDefaultValue()
 		if !fieldPdb.IsNotNull() && (fieldPdb.IsReference() || fieldPdb.IsZeroAsNull()) && isValZero {
 			val = pdbutil.NullValue
@@ -444,12 +460,17 @@ func dbBuildSqlUpdateOldAndNew(msgobj proto.Message, msgLastFieldNo int32, dbsch
 
 	}
 
+	discriminatorNames, discriminatorVals := oneofDiscriminatorSQLArgs(msgobj, nil)
+	valFieldNames = append(valFieldNames, discriminatorNames...)
+	sqlVals = append(sqlVals, discriminatorVals...)
+
 	if len(valFieldNames) == 0 {
 		return "", nil, fmt.Errorf("no field need update")
 	}
 
 	firstPlaceholder = true
 
+	mapColumns := mapArgColumns(dbdialect, msgFieldDescs)
 	for _, fieldName := range valFieldNames {
 		if firstPlaceholder {
 			firstPlaceholder = false
@@ -457,14 +478,10 @@ func dbBuildSqlUpdateOldAndNew(msgobj proto.Message, msgLastFieldNo int32, dbsch
 			sb.WriteString(protosql.SQL_COMMA)
 		}
 
-		sb.WriteString(fieldName)
+		sb.WriteString(dbdialect.QuoteIdentifier(fieldName))
 		sb.WriteString(protosql.SQL_EQUEAL)
-
-		if placeholder == protosql.SQL_QUESTION {
-			sb.WriteString(string(protosql.SQL_QUESTION))
-		} else {
-			sb.WriteString(string(protosql.SQL_DOLLAR))
-			sb.WriteString(strconv.Itoa(sqlParaNo))
+		sb.WriteString(columnArgPlaceholder(dbdialect, mapColumns, fieldName, placeholder, sqlParaNo))
+		if placeholder != protosql.SQL_QUESTION {
 			sqlParaNo++
 		}
 	}
@@ -481,10 +498,10 @@ func dbBuildSqlUpdateOldAndNew(msgobj proto.Message, msgLastFieldNo int32, dbsch
 		}
 
 		sb.WriteString(" new.")
-		sb.WriteString(fieldName)
+		sb.WriteString(dbdialect.QuoteIdentifier(fieldName))
 		// sb.WriteString(protosql.SQL_EQUEAL)
 		sb.WriteString("=old.")
-		sb.WriteString(fieldName)
+		sb.WriteString(dbdialect.QuoteIdentifier(fieldName))
 
 	}
 
@@ -502,7 +519,7 @@ func dbBuildSqlUpdateOldAndNewNative(msgobj proto.Message, msgLastFieldNo int32,
 	dbdialect sqldb.TDBDialect,
 ) (sqlStr string, sqlVals []interface{}, err error) {
 	// Build SQL like: UPDATE <table> SET ... WHERE pk=? RETURNING OLD.*,NEW.*;
-	dbtableName := sqldb.BuildDbTableName(tableName, dbschema, dbdialect)
+	dbtableName := sqldb.BuildQuotedDbTableName(tableName, dbschema, dbdialect)
 
 	placeholder := dbdialect.Placeholder()
 	primaryKeyFieldNames := pdbutil.GetPrimaryKeyFieldDescs(msgDesc, msgFieldDescs, false)
@@ -521,7 +538,7 @@ func dbBuildSqlUpdateOldAndNewNative(msgobj proto.Message, msgLastFieldNo int32,
 	// Collect updatable fields and values
 	for fi := 0; fi < msgFieldDescs.Len(); fi++ {
 		field := msgFieldDescs.Get(fi)
-		fieldName := string(field.Name())
+		fieldName := pdbutil.GetColumnName(field)
 
 		if _, ok := primaryKeyFieldNames[fieldName]; ok {
 			continue
@@ -536,6 +553,16 @@ func dbBuildSqlUpdateOldAndNewNative(msgobj proto.Message, msgLastFieldNo int32,
 			continue
 		}
 
+		if pdbutil.IsFlattenField(field) {
+			flatColumnNames, flatVals, err := flatFieldSQLArgs(msgobj, field, dbdialect, true, nil)
+			if err != nil {
+				return "", nil, fmt.Errorf("msg:%s field:%s %w", msgDesc.Name(), fieldName, err)
+			}
+			valFieldNames = append(valFieldNames, flatColumnNames...)
+			sqlVals = append(sqlVals, flatVals...)
+			continue
+		}
+
 		valFieldNames = append(valFieldNames, fieldName)
 
 		val, err := getSQLFieldValue(msgobj, field)
@@ -543,7 +570,7 @@ func dbBuildSqlUpdateOldAndNewNative(msgobj proto.Message, msgLastFieldNo int32,
 			return "", nil, fmt.Errorf("get field err: %s.%s %w", msgDesc.Name(), fieldName, err)
 		}
 
-		isValZero := pdbutil.IsZeroValue(val)
+		isValZero := isSQLValZero(field, val)
 		_, hasDefaultValue := fieldPdb.HasDefaultValue()
 		hasSetDefaultValue := false
 		if !fieldPdb.IsNotNull() && (fieldPdb.IsReference() || fieldPdb.IsZeroAsNull()) && isValZero {
@@ -562,6 +589,10 @@ func dbBuildSqlUpdateOldAndNewNative(msgobj proto.Message, msgLastFieldNo int32,
 		sqlVals = append(sqlVals, val)
 	}
 
+	discriminatorNames, discriminatorVals := oneofDiscriminatorSQLArgs(msgobj, nil)
+	valFieldNames = append(valFieldNames, discriminatorNames...)
+	sqlVals = append(sqlVals, discriminatorVals...)
+
 	if len(valFieldNames) == 0 {
 		return "", nil, fmt.Errorf("no field need update")
 	}
@@ -569,19 +600,17 @@ func dbBuildSqlUpdateOldAndNewNative(msgobj proto.Message, msgLastFieldNo int32,
 	// Write SET clause placeholders
 	firstPlaceholder := true
 	sqlParaNo := 1
+	mapColumns := mapArgColumns(dbdialect, msgFieldDescs)
 	for _, fieldName := range valFieldNames {
 		if firstPlaceholder {
 			firstPlaceholder = false
 		} else {
 			sb.WriteString(protosql.SQL_COMMA)
 		}
-		sb.WriteString(fieldName)
+		sb.WriteString(dbdialect.QuoteIdentifier(fieldName))
 		sb.WriteString(protosql.SQL_EQUEAL)
-		if placeholder == protosql.SQL_QUESTION {
-			sb.WriteString(string(protosql.SQL_QUESTION))
-		} else {
-			sb.WriteString(string(protosql.SQL_DOLLAR))
-			sb.WriteString(strconv.Itoa(sqlParaNo))
+		sb.WriteString(columnArgPlaceholder(dbdialect, mapColumns, fieldName, placeholder, sqlParaNo))
+		if placeholder != protosql.SQL_QUESTION {
 			sqlParaNo++
 		}
 	}
@@ -595,7 +624,7 @@ func dbBuildSqlUpdateOldAndNewNative(msgobj proto.Message, msgLastFieldNo int32,
 		} else {
 			sb.WriteString(protosql.SQL_AND)
 		}
-		sb.WriteString(fieldName)
+		sb.WriteString(dbdialect.QuoteIdentifier(fieldName))
 		sb.WriteString(protosql.SQL_EQUEAL)
 		if placeholder == protosql.SQL_QUESTION {
 			sb.WriteString(string(protosql.SQL_QUESTION))
@@ -0,0 +1,372 @@
+package crud
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var dbTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// isWellKnownField non-repeated field of well-known message type which has native db mapping
+func isWellKnownField(fd protoreflect.FieldDescriptor) bool {
+	if fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() {
+		return false
+	}
+	return protodb.IsWellKnownMsg(fd.Message().FullName())
+}
+
+// encodeWellKnownSQLArg encode well-known message to native sql arg, nil message is NULL
+func encodeWellKnownSQLArg(fieldDesc protoreflect.FieldDescriptor, dialect sqldb.TDBDialect, goValue any) (any, error) {
+	pm, ok := goValue.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("message field expects proto.Message, got %T", goValue)
+	}
+	m := pm.ProtoReflect()
+	if !m.IsValid() {
+		return nil, nil
+	}
+
+	msgName := m.Descriptor().FullName()
+	if _, ok := protodb.WellKnownWrapperKind(msgName); ok {
+		return m.Get(m.Descriptor().Fields().ByName("value")).Interface(), nil
+	}
+
+	switch msgName {
+	case protodb.WktTimestamp:
+		return dialect.Dialect().EncodeTime(wktTime(m)), nil
+	case protodb.WktDuration:
+		seconds, nanos := wktSecondsNanos(m)
+		return encodeDurationSQLArg(dialect, seconds, nanos)
+	case protodb.WktFieldMask:
+		paths := m.Get(m.Descriptor().Fields().ByName("paths")).List()
+		strs := make([]string, 0, paths.Len())
+		for i := 0; i < paths.Len(); i++ {
+			strs = append(strs, paths.Get(i).String())
+		}
+		return strings.Join(strs, ","), nil
+	default:
+		// Struct, Value, ListValue
+		b, err := protojson.Marshal(pm)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	}
+}
+
+// encodeDurationSQLArg postgres use interval, others use bigint nanoseconds
+func encodeDurationSQLArg(dialect sqldb.TDBDialect, seconds int64, nanos int64) (any, error) {
+	if dialect.Dialect().DurationAsInterval() {
+		sign := ""
+		if seconds < 0 || nanos < 0 {
+			sign = "-"
+			seconds, nanos = -seconds, -nanos
+		}
+		return fmt.Sprintf("%s%d.%09d seconds", sign, seconds, nanos), nil
+	}
+	if seconds > math.MaxInt64/int64(time.Second) || seconds < math.MinInt64/int64(time.Second) {
+		return nil, fmt.Errorf("duration %ds overflows int64 nanoseconds", seconds)
+	}
+	return seconds*int64(time.Second) + nanos, nil
+}
+
+func wktSecondsNanos(m protoreflect.Message) (seconds int64, nanos int64) {
+	fields := m.Descriptor().Fields()
+	return m.Get(fields.ByName("seconds")).Int(), m.Get(fields.ByName("nanos")).Int()
+}
+
+func wktTime(m protoreflect.Message) time.Time {
+	seconds, nanos := wktSecondsNanos(m)
+	return time.Unix(seconds, nanos).UTC()
+}
+
+func setWktSecondsNanos(m protoreflect.Message, seconds int64, nanos int64) {
+	fields := m.Descriptor().Fields()
+	m.Set(fields.ByName("seconds"), protoreflect.ValueOfInt64(seconds))
+	m.Set(fields.ByName("nanos"), protoreflect.ValueOfInt32(int32(nanos)))
+}
+
+// allocWellKnownScanDest scan dest for well-known message field
+func allocWellKnownScanDest(fd protoreflect.FieldDescriptor) any {
+	msgDesc := fd.Message()
+	if _, ok := protodb.WellKnownWrapperKind(msgDesc.FullName()); ok {
+		return allocScanDest(msgDesc.Fields().ByName("value"))
+	}
+	switch msgDesc.FullName() {
+	case protodb.WktTimestamp, protodb.WktDuration:
+		// time.Time, string, []byte or int64 depends on driver and dialect
+		return new(any)
+	default:
+		return new(sql.NullString)
+	}
+}
+
+// setProtoMsgWellKnownField set well-known message field from db value, NULL clears the field
+func setProtoMsgWellKnownField(msg proto.Message, fd protoreflect.FieldDescriptor, val any) error {
+	pm := msg.ProtoReflect()
+	if val == nil {
+		pm.Clear(fd)
+		return nil
+	}
+
+	fm := pm.NewField(fd).Message()
+	msgName := fd.Message().FullName()
+
+	if kind, ok := protodb.WellKnownWrapperKind(msgName); ok {
+		if b, isBytes := val.([]byte); isBytes && kind == protoreflect.BytesKind {
+			val = append([]byte(nil), b...)
+		}
+		v, err := scalarElemToProtoreflectValue(kind, val)
+		if err != nil {
+			return noFallback(fmt.Errorf("field %s: %w", fd.FullName(), err))
+		}
+		fm.Set(fm.Descriptor().Fields().ByName("value"), v)
+		pm.Set(fd, protoreflect.ValueOfMessage(fm))
+		return nil
+	}
+
+	switch msgName {
+	case protodb.WktTimestamp:
+		t, err := toTime(val)
+		if err != nil {
+			return noFallback(fmt.Errorf("field %s: %w", fd.FullName(), err))
+		}
+		setWktSecondsNanos(fm, t.Unix(), int64(t.Nanosecond()))
+	case protodb.WktDuration:
+		seconds, nanos, err := toDurationSecondsNanos(val)
+		if err != nil {
+			return noFallback(fmt.Errorf("field %s: %w", fd.FullName(), err))
+		}
+		setWktSecondsNanos(fm, seconds, nanos)
+	case protodb.WktFieldMask:
+		s, err := toText(val)
+		if err != nil {
+			return noFallback(fmt.Errorf("field %s: %w", fd.FullName(), err))
+		}
+		paths := fm.Mutable(fm.Descriptor().Fields().ByName("paths")).List()
+		for _, path := range strings.Split(s, ",") {
+			path = strings.TrimSpace(path)
+			if len(path) > 0 {
+				paths.Append(protoreflect.ValueOfString(path))
+			}
+		}
+	default:
+		// Struct, Value, ListValue
+		s, err := toText(val)
+		if err != nil {
+			return noFallback(fmt.Errorf("field %s: %w", fd.FullName(), err))
+		}
+		unmarshalOpts := protojson.UnmarshalOptions{DiscardUnknown: true}
+		if err := unmarshalOpts.Unmarshal([]byte(s), fm.Interface()); err != nil {
+			return noFallback(fmt.Errorf("field %s: %w", fd.FullName(), err))
+		}
+	}
+
+	pm.Set(fd, protoreflect.ValueOfMessage(fm))
+	return nil
+}
+
+// parseWellKnownScalarString parse where condition value of well-known message field to sql arg
+func parseWellKnownScalarString(fd protoreflect.FieldDescriptor, dialect sqldb.TDBDialect, s string) (any, error) {
+	msgName := fd.Message().FullName()
+	if kind, ok := protodb.WellKnownWrapperKind(msgName); ok {
+		return parseScalarString(kind, s)
+	}
+	switch msgName {
+	case protodb.WktTimestamp:
+		t, err := parseDbTime(s)
+		if err != nil {
+			return nil, err
+		}
+		return dialect.Dialect().EncodeTime(t), nil
+	case protodb.WktDuration:
+		seconds, nanos, err := toDurationSecondsNanos(s)
+		if err != nil {
+			return nil, err
+		}
+		return encodeDurationSQLArg(dialect, seconds, nanos)
+	default:
+		return s, nil
+	}
+}
+
+func toText(v any) (string, error) {
+	switch x := v.(type) {
+	case string:
+		return x, nil
+	case []byte:
+		return string(x), nil
+	default:
+		return "", fmt.Errorf("expects string/bytes, got %T", v)
+	}
+}
+
+func toTime(v any) (time.Time, error) {
+	switch x := v.(type) {
+	case time.Time:
+		return x, nil
+	case string:
+		return parseDbTime(x)
+	case []byte:
+		return parseDbTime(string(x))
+	case int64:
+		// unix seconds
+		return time.Unix(x, 0).UTC(), nil
+	default:
+		return time.Time{}, fmt.Errorf("cannot convert %T to time", v)
+	}
+}
+
+// parseDbTime parse time text returned by db drivers, time without zone is utc
+func parseDbTime(s string) (time.Time, error) {
+	ss := strings.TrimSpace(s)
+	for _, layout := range dbTimeLayouts {
+		t, err := time.Parse(layout, ss)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse time %q", s)
+}
+
+func toDurationSecondsNanos(v any) (seconds int64, nanos int64, err error) {
+	switch x := v.(type) {
+	case int64:
+		d := time.Duration(x)
+		return int64(d / time.Second), int64(d % time.Second), nil
+	case time.Duration:
+		return int64(x / time.Second), int64(x % time.Second), nil
+	case []byte:
+		return toDurationSecondsNanos(string(x))
+	case string:
+		ss := strings.TrimSpace(x)
+		if i, err := strconv.ParseInt(ss, 10, 64); err == nil {
+			return toDurationSecondsNanos(i)
+		}
+		if seconds, nanos, ok := parsePgInterval(ss); ok {
+			return seconds, nanos, nil
+		}
+		// go/protojson duration like 1.5s
+		d, err := time.ParseDuration(ss)
+		if err != nil {
+			return 0, 0, fmt.Errorf("cannot parse duration %q", x)
+		}
+		return int64(d / time.Second), int64(d % time.Second), nil
+	default:
+		return 0, 0, fmt.Errorf("cannot convert %T to duration", v)
+	}
+}
+
+// parsePgInterval parse postgres interval text like: 1 year 2 mons 3 days -04:05:06.789
+// month is 30 days and year is 12 months like postgres justify_interval
+func parsePgInterval(s string) (seconds int64, nanos int64, ok bool) {
+	tokens := strings.Fields(s)
+	if len(tokens) == 0 {
+		return 0, 0, false
+	}
+
+	var totalNanos, totalSeconds int64
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if strings.Contains(token, ":") {
+			sec, nsec, ok := parsePgIntervalTime(token)
+			if !ok {
+				return 0, 0, false
+			}
+			totalSeconds += sec
+			totalNanos += nsec
+			continue
+		}
+
+		n, err := strconv.ParseInt(strings.TrimPrefix(token, "+"), 10, 64)
+		if err != nil || i+1 >= len(tokens) {
+			return 0, 0, false
+		}
+		i++
+		unit := strings.TrimSuffix(tokens[i], "s")
+		switch unit {
+		case "year":
+			totalSeconds += n * 12 * 30 * 86400
+		case "mon":
+			totalSeconds += n * 30 * 86400
+		case "day":
+			totalSeconds += n * 86400
+		case "hour":
+			totalSeconds += n * 3600
+		case "min":
+			totalSeconds += n * 60
+		case "sec":
+			totalSeconds += n
+		default:
+			return 0, 0, false
+		}
+	}
+
+	// seconds and nanos must have the same sign
+	totalSeconds += totalNanos / int64(time.Second)
+	totalNanos %= int64(time.Second)
+	if totalSeconds > 0 && totalNanos < 0 {
+		totalSeconds--
+		totalNanos += int64(time.Second)
+	} else if totalSeconds < 0 && totalNanos > 0 {
+		totalSeconds++
+		totalNanos -= int64(time.Second)
+	}
+	return totalSeconds, totalNanos, true
+}
+
+// parsePgIntervalTime parse [-+]HH:MM:SS[.ffffff]
+func parsePgIntervalTime(s string) (seconds int64, nanos int64, ok bool) {
+	sign := int64(1)
+	if strings.HasPrefix(s, "-") {
+		sign = -1
+		s = s[1:]
+	} else {
+		s = strings.TrimPrefix(s, "+")
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, 0, false
+	}
+	hours, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	minutes, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	secStr, fracStr, _ := strings.Cut(parts[2], ".")
+	secs, err := strconv.ParseInt(secStr, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	if len(fracStr) > 0 {
+		if len(fracStr) > 9 {
+			fracStr = fracStr[:9]
+		}
+		fracStr += strings.Repeat("0", 9-len(fracStr))
+		nanos, err = strconv.ParseInt(fracStr, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+	}
+	return sign * (hours*3600 + minutes*60 + secs), sign * nanos, true
+}
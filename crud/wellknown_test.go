@@ -0,0 +1,295 @@
+package crud
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func buildWellKnownMsgDesc(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+
+	msgField := func(name string, number int32, typeName string) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     strPtr(name),
+			Number:   int32Ptr(number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+			TypeName: strPtr(typeName),
+		}
+	}
+
+	fdp := &descriptorpb.FileDescriptorProto{
+		Syntax:  strPtr("proto3"),
+		Name:    strPtr("wellknown_test.proto"),
+		Package: strPtr("test"),
+		Dependency: []string{
+			"google/protobuf/timestamp.proto",
+			"google/protobuf/duration.proto",
+			"google/protobuf/wrappers.proto",
+			"google/protobuf/struct.proto",
+			"google/protobuf/field_mask.proto",
+		},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: strPtr("WktMsg"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: strPtr("id"), Number: int32Ptr(1), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum()},
+					msgField("created", 2, ".google.protobuf.Timestamp"),
+					msgField("ttl", 3, ".google.protobuf.Duration"),
+					msgField("score", 4, ".google.protobuf.Int64Value"),
+					msgField("nick", 5, ".google.protobuf.StringValue"),
+					msgField("extra", 6, ".google.protobuf.Struct"),
+					msgField("mask", 7, ".google.protobuf.FieldMask"),
+				},
+			},
+		},
+	}
+
+	// make sure well-known files are registered
+	_ = timestamppb.Now()
+	_ = durationpb.New(0)
+	_ = wrapperspb.Int64(0)
+	_ = &structpb.Struct{}
+	_ = &fieldmaskpb.FieldMask{}
+
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile: %v", err)
+	}
+	return fd.Messages().ByName("WktMsg")
+}
+
+func TestGetWellKnownDBType(t *testing.T) {
+	msgDesc := buildWellKnownMsgDesc(t)
+	fields := msgDesc.Fields()
+
+	cases := []struct {
+		field   string
+		dialect sqldb.TDBDialect
+		want    string
+	}{
+		{"created", sqldb.Postgres, "timestamptz"},
+		{"created", sqldb.Mysql, "datetime(6)"},
+		{"ttl", sqldb.Postgres, "interval"},
+		{"ttl", sqldb.Mysql, "bigint"},
+		{"score", sqldb.Postgres, "bigint"},
+		{"nick", sqldb.SQLite, "text"},
+		{"extra", sqldb.Postgres, "jsonb"},
+		{"extra", sqldb.Mysql, "json"},
+		{"mask", sqldb.Mysql, "text"},
+	}
+	for _, c := range cases {
+		fd := fields.ByName(protoreflect.Name(c.field))
+		if got := protodb.GetProtoDBTypeOfField(fd, c.dialect); got != c.want {
+			t.Fatalf("%s %s: got %s want %s", c.field, c.dialect, got, c.want)
+		}
+	}
+}
+
+func TestEncodeSQLArg_WellKnownTypes(t *testing.T) {
+	msgDesc := buildWellKnownMsgDesc(t)
+	fields := msgDesc.Fields()
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 123456000, time.UTC)
+
+	v, err := EncodeSQLArg(fields.ByName("created"), sqldb.Postgres, timestamppb.New(ts))
+	if err != nil {
+		t.Fatalf("EncodeSQLArg timestamp: %v", err)
+	}
+	if got, ok := v.(time.Time); !ok || !got.Equal(ts) {
+		t.Fatalf("unexpected timestamp arg: %#v", v)
+	}
+
+	v, err = EncodeSQLArg(fields.ByName("created"), sqldb.SQLite, timestamppb.New(ts))
+	if err != nil {
+		t.Fatalf("EncodeSQLArg sqlite timestamp: %v", err)
+	}
+	if v != "2024-05-06T07:08:09.123456000Z" {
+		t.Fatalf("unexpected sqlite timestamp arg: %#v", v)
+	}
+
+	v, err = EncodeSQLArg(fields.ByName("ttl"), sqldb.Postgres, durationpb.New(-1500*time.Millisecond))
+	if err != nil {
+		t.Fatalf("EncodeSQLArg duration: %v", err)
+	}
+	if v != "-1.500000000 seconds" {
+		t.Fatalf("unexpected pg duration arg: %#v", v)
+	}
+
+	v, err = EncodeSQLArg(fields.ByName("ttl"), sqldb.Mysql, durationpb.New(1500*time.Millisecond))
+	if err != nil {
+		t.Fatalf("EncodeSQLArg duration: %v", err)
+	}
+	if v != int64(1500*time.Millisecond) {
+		t.Fatalf("unexpected mysql duration arg: %#v", v)
+	}
+
+	v, err = EncodeSQLArg(fields.ByName("score"), sqldb.Postgres, wrapperspb.Int64(42))
+	if err != nil || v != int64(42) {
+		t.Fatalf("unexpected wrapper arg: %#v err:%v", v, err)
+	}
+
+	var nilScore *wrapperspb.Int64Value
+	v, err = EncodeSQLArg(fields.ByName("score"), sqldb.Postgres, nilScore)
+	if err != nil || v != nil {
+		t.Fatalf("nil wrapper should be NULL, got %#v err:%v", v, err)
+	}
+
+	v, err = EncodeSQLArg(fields.ByName("mask"), sqldb.Postgres, &fieldmaskpb.FieldMask{Paths: []string{"a", "b.c"}})
+	if err != nil || v != "a,b.c" {
+		t.Fatalf("unexpected field mask arg: %#v err:%v", v, err)
+	}
+
+	extra, _ := structpb.NewStruct(map[string]any{"k": "v"})
+	v, err = EncodeSQLArg(fields.ByName("extra"), sqldb.Postgres, extra)
+	if err != nil || !strings.Contains(v.(string), `"k"`) {
+		t.Fatalf("unexpected struct arg: %#v err:%v", v, err)
+	}
+}
+
+func TestGetSQLFieldValue_UnsetWellKnownIsNull(t *testing.T) {
+	msgDesc := buildWellKnownMsgDesc(t)
+	msg := dynamicpb.NewMessage(msgDesc)
+
+	v, err := getSQLFieldValue(msg, msgDesc.Fields().ByName("score"))
+	if err != nil {
+		t.Fatalf("getSQLFieldValue: %v", err)
+	}
+	if v != nil {
+		t.Fatalf("expected nil for unset wrapper, got %#v", v)
+	}
+}
+
+func TestDbScan2ProtoMsg_WellKnownTypes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	columns := []string{"id", "created", "ttl", "score", "nick", "extra", "mask"}
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(int64(1), "2024-05-06 07:08:09.123456+00", "1 day 02:00:00.5", int64(42), nil, `{"k":"v"}`, "a,b.c"))
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatalf("db.Query: %v", err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		t.Fatal("expected one row")
+	}
+
+	msgDesc := buildWellKnownMsgDesc(t)
+	msg := dynamicpb.NewMessage(msgDesc)
+	msgFieldsMap := pdbutil.BuildMsgFieldsMap(columns, msgDesc.Fields(), true)
+	if err := DbScan2ProtoMsg(rows, msg, columns, msgFieldsMap); err != nil {
+		t.Fatalf("DbScan2ProtoMsg: %v", err)
+	}
+
+	fields := msgDesc.Fields()
+	created := &timestamppb.Timestamp{}
+	proto.Merge(created, msg.Get(fields.ByName("created")).Message().Interface())
+	if !created.AsTime().Equal(time.Date(2024, 5, 6, 7, 8, 9, 123456000, time.UTC)) {
+		t.Fatalf("unexpected created: %v", created.AsTime())
+	}
+
+	ttl := &durationpb.Duration{}
+	proto.Merge(ttl, msg.Get(fields.ByName("ttl")).Message().Interface())
+	if ttl.AsDuration() != 26*time.Hour+500*time.Millisecond {
+		t.Fatalf("unexpected ttl: %v", ttl.AsDuration())
+	}
+
+	score := &wrapperspb.Int64Value{}
+	proto.Merge(score, msg.Get(fields.ByName("score")).Message().Interface())
+	if score.GetValue() != 42 {
+		t.Fatalf("unexpected score: %v", score)
+	}
+
+	if msg.Has(fields.ByName("nick")) {
+		t.Fatal("NULL wrapper should stay unset")
+	}
+
+	mask := &fieldmaskpb.FieldMask{}
+	proto.Merge(mask, msg.Get(fields.ByName("mask")).Message().Interface())
+	if strings.Join(mask.GetPaths(), "|") != "a|b.c" {
+		t.Fatalf("unexpected mask: %v", mask.GetPaths())
+	}
+
+	extra := &structpb.Struct{}
+	proto.Merge(extra, msg.Get(fields.ByName("extra")).Message().Interface())
+	if extra.GetFields()["k"].GetStringValue() != "v" {
+		t.Fatalf("unexpected extra: %v", extra)
+	}
+}
+
+func TestParsePgInterval(t *testing.T) {
+	cases := map[string]time.Duration{
+		"00:00:01.5":                  1500 * time.Millisecond,
+		"1 mon 2 day 03:04:05.000006": (32*24+3)*time.Hour + 4*time.Minute + 5*time.Second + 6*time.Microsecond,
+		"-00:00:02":                   -2 * time.Second,
+		"3 days":                      72 * time.Hour,
+		"1 day -01:00:00":             23 * time.Hour,
+	}
+	for s, want := range cases {
+		seconds, nanos, ok := parsePgInterval(s)
+		if !ok {
+			t.Fatalf("parsePgInterval(%q) failed", s)
+		}
+		if got := time.Duration(seconds)*time.Second + time.Duration(nanos); got != want {
+			t.Fatalf("parsePgInterval(%q) = %v, want %v", s, got, want)
+		}
+	}
+	if _, _, ok := parsePgInterval("abc"); ok {
+		t.Fatal("expected parse failure")
+	}
+}
+
+func TestTableQueryBuildSql_WellKnownWhere(t *testing.T) {
+	msgDesc := buildWellKnownMsgDesc(t)
+	db := &sqldb.DBWithDialect{Executor: dummyDB{}, Dialect: sqldb.Postgres}
+
+	req := &protodb.TableQueryReq{
+		TableName: "WktMsg",
+		Where:     map[string]string{"score": "7"},
+		Where2:    map[string]string{"created": "2024-05-06T07:08:09Z"},
+		Where2Operator: map[string]protodb.WhereOperator{
+			"created": protodb.WhereOperator_WOP_GT,
+		},
+	}
+
+	sqlStr, vals, err := TableQueryBuildSql(db, msgDesc, req, "", nil)
+	if err != nil {
+		t.Fatalf("TableQueryBuildSql: %v", err)
+	}
+	if !strings.Contains(sqlStr, `"score" = $1`) || !strings.Contains(sqlStr, `"created" > $2`) {
+		t.Fatalf("unexpected sql: %s", sqlStr)
+	}
+	if vals[0] != int64(7) {
+		t.Fatalf("unexpected where arg: %#v", vals[0])
+	}
+	if ts, ok := vals[1].(time.Time); !ok || !ts.Equal(time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)) {
+		t.Fatalf("unexpected where2 arg: %#v", vals[1])
+	}
+
+	req.Where2 = map[string]string{"created": "not a time"}
+	if _, _, err := TableQueryBuildSql(db, msgDesc, req, "", nil); err == nil {
+		t.Fatal("expected error for invalid timestamp")
+	}
+}
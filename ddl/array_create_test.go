@@ -118,10 +118,10 @@ func TestDbCreateSQL_RepeatedDefaults_Postgres(t *testing.T) {
 		t.Fatalf("expected 1 sql, got %d", len(item.SqlStr))
 	}
 	sqlStr := item.SqlStr[0]
-	if !strings.Contains(sqlStr, "nums bigint[]") || !strings.Contains(sqlStr, "DEFAULT '{}'::bigint[]") {
+	if !strings.Contains(sqlStr, `"nums" bigint[]`) || !strings.Contains(sqlStr, "DEFAULT '{}'::bigint[]") {
 		t.Fatalf("missing nums default: %s", sqlStr)
 	}
-	if !strings.Contains(sqlStr, "subs jsonb") || !strings.Contains(sqlStr, "DEFAULT '[]'::jsonb") {
+	if !strings.Contains(sqlStr, `"subs" jsonb`) || !strings.Contains(sqlStr, "DEFAULT '[]'::jsonb") {
 		t.Fatalf("missing subs default: %s", sqlStr)
 	}
 }
@@ -0,0 +1,34 @@
+package ddl
+
+import (
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// TTableColumn db column of a table message
+type TTableColumn struct {
+	Name string
+	// proto field name, dotted path for flattened sub message columns like address.city
+	FieldPath string
+	Field     protoreflect.FieldDescriptor
+	Pdb       *protodb.PDBField
+}
+
+// TableColumns db columns of msgDesc in create table order, NotDB fields are skipped
+// and flattened sub message fields expand to their columns
+func TableColumns(msgDesc protoreflect.MessageDescriptor) []TTableColumn {
+	var columns []TTableColumn
+	for _, column := range msgTableColumns(msgDesc.Fields()) {
+		if column.pdb.NotDB {
+			continue
+		}
+		columns = append(columns, TTableColumn{Name: column.name, FieldPath: column.path, Field: column.fieldDesc, Pdb: column.pdb})
+	}
+	return columns
+}
+
+// ColumnSqlType sql type of column in dialect, the same as create table uses
+func ColumnSqlType(column TTableColumn, dialect sqldb.TDBDialect) string {
+	return getSqlTypeStr(column.Field, column.Pdb, dialect)
+}
@@ -4,10 +4,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/ygrpc/protodb"
-	"github.com/ygrpc/protodb/msgstore"
 	"github.com/ygrpc/protodb/pdbutil"
 	"github.com/ygrpc/protodb/protosql"
 	"github.com/ygrpc/protodb/sqldb"
@@ -16,11 +16,7 @@ import (
 )
 
 const (
-	postgresTableExistsSQL = "SELECT EXISTS (SELECT table_name FROM information_schema.tables WHERE table_schema = $1 AND table_name = $2)"
-	postgresColumnsSQL     = "SELECT column_name FROM information_schema.columns WHERE table_schema = $1 AND table_name = $2"
-	sqliteTableExistsSQL   = "SELECT EXISTS (SELECT name FROM sqlite_master WHERE type='table' AND name=?)"
-	sqliteIndexInfoSQL     = "select name from pragma_index_info(?)"
-	sqliteTableInfoSQL     = "SELECT name FROM pragma_table_info(?)"
+	sqliteIndexInfoSQL = "select name from pragma_index_info(?)"
 )
 
 type TDbTableInitSql struct {
@@ -88,10 +84,15 @@ func normalizeUserDefaultValue(defaultValue string, dialect sqldb.TDBDialect, sq
 // withComment if add comment for sql
 // builtInitSqlMap is a map to store all init sql, user should use a global map for speed up(no need generate init sql again)
 func DbCreateSQL(db *sql.DB, msg proto.Message, dbschema string, checkRefference bool, withComment bool, builtInitSqlMap map[string]*TDbTableInitSql) (sqlInitSql *TDbTableInitSql, err error) {
+	return DefaultDdl.DbCreateSQL(db, msg, dbschema, checkRefference, withComment, builtInitSqlMap)
+}
+
+// DbCreateSQL see DbCreateSQL, referenced tables are found in this.Msgs
+func (this *TDdl) DbCreateSQL(db *sql.DB, msg proto.Message, dbschema string, checkRefference bool, withComment bool, builtInitSqlMap map[string]*TDbTableInitSql) (sqlInitSql *TDbTableInitSql, err error) {
 	msgPm := msg.ProtoReflect()
 	msgDesc := msgPm.Descriptor()
 	msgFieldDescs := msgDesc.Fields()
-	tableName := string(msgDesc.Name())
+	tableName := pdbutil.GetTableName(msgDesc)
 
 	if builtInitSqlMap == nil {
 		return nil, errors.New("builtInitSqlMap cannot be nil")
@@ -107,7 +108,7 @@ func DbCreateSQL(db *sql.DB, msg proto.Message, dbschema string, checkRefference
 	// put nil to mark this table is in process
 	builtInitSqlMap[tableName] = nil
 
-	initSqlItem, err := dbCreateSQL(db, msg, dbschema, tableName, msgDesc, msgFieldDescs, checkRefference, withComment, builtInitSqlMap)
+	initSqlItem, err := this.dbCreateSQL(db, sqldb.GetDBDialect(db), msg, dbschema, tableName, msgDesc, msgFieldDescs, checkRefference, withComment, builtInitSqlMap)
 	builtInitSqlMap[tableName] = initSqlItem
 	if err != nil {
 		delete(builtInitSqlMap, tableName)
@@ -115,7 +116,8 @@ func DbCreateSQL(db *sql.DB, msg proto.Message, dbschema string, checkRefference
 	return initSqlItem, err
 }
 
-func dbCreateSQL(db *sql.DB, msg proto.Message, dbschema string, tableName string,
+// dbCreateSQL create sql of msg in dbdialect, db is only used to build the referenced tables if checkRefference
+func (this *TDdl) dbCreateSQL(db *sql.DB, dbdialect sqldb.TDBDialect, msg proto.Message, dbschema string, tableName string,
 	msgDesc protoreflect.MessageDescriptor, msgFieldDescs protoreflect.FieldDescriptors, checkRefference bool, withComment bool,
 	builtInitSqlMap map[string]*TDbTableInitSql) (sqlInitSql *TDbTableInitSql, err error) {
 	pdbm, found := pdbutil.GetPDBM(msgDesc)
@@ -125,19 +127,15 @@ func dbCreateSQL(db *sql.DB, msg proto.Message, dbschema string, tableName strin
 		}
 	}
 
-	fieldPdbMap := map[string]*protodb.PDBField{}
 	primarykeys := []protoreflect.FieldDescriptor{}
 
-	dbdialect := sqldb.GetDBDialect(db)
-
 	//uniquename->proto field
 	uniquekeysMap := map[string][]protoreflect.FieldDescriptor{}
 	for i := 0; i < msgFieldDescs.Len(); i++ {
 		fieldDesc := msgFieldDescs.Get(i)
-		fieldName := string(fieldDesc.Name())
+		fieldName := pdbutil.GetColumnName(fieldDesc)
 
 		pdb, _ := pdbutil.GetPDB(fieldDesc)
-		fieldPdbMap[fieldName] = pdb
 
 		if pdb.Primary {
 			primarykeys = append(primarykeys, fieldDesc)
@@ -180,17 +178,24 @@ func dbCreateSQL(db *sql.DB, msg proto.Message, dbschema string, tableName strin
 		}
 	}
 
+	if dbdialect == sqldb.Postgres {
+		sqlStr += createPostgresEnumTypesSql(msgFieldDescs)
+	}
+	if dbdialect == sqldb.DuckDB {
+		sqlStr += createDuckDBSequencesSql(tableName, dbschema, msgFieldDescs)
+	}
+
 	sqlStr += protosql.SQL_CREATETABLE + protosql.SQL_IFNOTEXISTS
 
-	dbtableName := sqldb.BuildDbTableName(tableName, dbschema, dbdialect)
+	dbtableName := sqldb.BuildQuotedDbTableName(tableName, dbschema, dbdialect)
 	sqlStr += dbtableName
 	sqlStr += protosql.SQL_LEFT_PARENTHESES
 	sqlStr += "\n"
 
-	for i := 0; i < msgFieldDescs.Len(); i++ {
-		fieldDesc := msgFieldDescs.Get(i)
-		fieldname := string(fieldDesc.Name())
-		fieldPdb := fieldPdbMap[fieldname]
+	for _, column := range msgTableColumns(msgFieldDescs) {
+		fieldDesc := column.fieldDesc
+		fieldname := column.name
+		fieldPdb := column.pdb
 		fieldMsg := fieldDesc
 
 		if fieldPdb.NotDB {
@@ -205,7 +210,7 @@ func dbCreateSQL(db *sql.DB, msg proto.Message, dbschema string, tableName strin
 			}
 		}
 
-		sqlStr += fieldname + " "
+		sqlStr += dbdialect.QuoteIdentifier(fieldname) + " "
 		sqlTypeStr := getSqlTypeStr(fieldMsg, fieldPdb, dbdialect)
 		sqlStr += sqlTypeStr
 
@@ -217,6 +222,7 @@ func dbCreateSQL(db *sql.DB, msg proto.Message, dbschema string, tableName strin
 			} else if fieldDesc.IsList() {
 				sqlStr += protosql.NOT_NULL
 			} else {
+				// optional (explicit presence) fields stay nullable, unset is stored as NULL
 				if fieldPdb.NotNull {
 					sqlStr += protosql.NOT_NULL
 				} else {
@@ -228,7 +234,7 @@ func dbCreateSQL(db *sql.DB, msg proto.Message, dbschema string, tableName strin
 		if len(fieldPdb.Reference) > 0 {
 			sqlStr += protosql.REFERENCES + fieldPdb.Reference
 			if checkRefference {
-				err := addRefferenceDepSqlForCreate(initSqlItem, fieldPdb.Reference, db, withComment, builtInitSqlMap)
+				err := this.addRefferenceDepSqlForCreate(initSqlItem, fieldPdb.Reference, db, withComment, builtInitSqlMap)
 				if err != nil {
 					return nil, fmt.Errorf("%s add reference %s for field %s fail:%s", tableName, fieldPdb.Reference, fieldname, err.Error())
 				}
@@ -274,6 +280,8 @@ func dbCreateSQL(db *sql.DB, msg proto.Message, dbschema string, tableName strin
 			sqlStr += protosql.DEFAULT +
 				TryAddQuote2DefaultValue(fieldDesc.Kind(), fieldPdb.DefaultValue)
 
+		} else if dbdialect == sqldb.DuckDB && fieldPdb.IsAutoIncrement() {
+			sqlStr += protosql.DEFAULT + "nextval('" + duckdbSequenceName(tableName, dbschema, fieldname) + "')"
 		}
 
 		for _, s := range fieldPdb.SQLAppend {
@@ -282,6 +290,10 @@ func dbCreateSQL(db *sql.DB, msg proto.Message, dbschema string, tableName strin
 
 		sqlStr += protosql.SQL_COMMA + "\n"
 
+		if discriminator, ok := oneofDiscriminatorAfter(msgDesc, fieldDesc); ok {
+			sqlStr += dbdialect.QuoteIdentifier(discriminator) + " " + oneofDiscriminatorSqlType(dbdialect) + protosql.NULL + protosql.SQL_COMMA + "\n"
+		}
+
 		for _, s := range fieldPdb.SQLAppendsEnd {
 			sqlStr += s + "\n"
 		}
@@ -306,8 +318,8 @@ func dbCreateSQL(db *sql.DB, msg proto.Message, dbschema string, tableName strin
 	// primary keys
 	sqlStr += protosql.SQL_COMMA + protosql.SQL_PRIMARYKEY + protosql.SQL_LEFT_PARENTHESES
 	for _, protofield := range primarykeys {
-		fieldname := string(protofield.Name())
-		sqlStr += fieldname + ","
+		fieldname := pdbutil.GetColumnName(protofield)
+		sqlStr += dbdialect.QuoteIdentifier(fieldname) + ","
 	}
 	sqlStr = sqlStr[:len(sqlStr)-1] + protosql.SQL_RIGHT_PARENTHESES
 
@@ -347,7 +359,7 @@ func createOneUniqueKeySql(tableName string, uniqueName string, uniquekeysFields
 	if dialect != sqldb.Mysql {
 		sb.WriteString(protosql.SQL_IF_NOT_EXISTS)
 	}
-	sb.WriteString(uniqueName)
+	sb.WriteString(dialect.QuoteIdentifier(uniqueName))
 	//on
 	sb.WriteString(protosql.SQL_ON)
 	sb.WriteString(tableName)
@@ -360,7 +372,7 @@ func createOneUniqueKeySql(tableName string, uniqueName string, uniquekeysFields
 		} else {
 			sb.WriteString(protosql.SQL_COMMA)
 		}
-		sb.WriteString(string(field.Name()))
+		sb.WriteString(dialect.QuoteIdentifier(pdbutil.GetColumnName(field)))
 	}
 	sb.WriteString(protosql.SQL_RIGHT_PARENTHESES)
 	sb.WriteString(protosql.SQL_SEMICOLON)
@@ -369,10 +381,17 @@ func createOneUniqueKeySql(tableName string, uniqueName string, uniquekeysFields
 }
 
 func createUniqueKeySql(tableName string, uniquekeysMap map[string][]protoreflect.FieldDescriptor, dialect sqldb.TDBDialect) string {
+	// sorted by name, the generated sql is stable
+	uniqueNames := make([]string, 0, len(uniquekeysMap))
+	for uniqueName := range uniquekeysMap {
+		uniqueNames = append(uniqueNames, uniqueName)
+	}
+	sort.Strings(uniqueNames)
+
 	sb := strings.Builder{}
 	// unique keys
-	for uniqueName, uniqueFields := range uniquekeysMap {
-		sb.WriteString(createOneUniqueKeySql(tableName, uniqueName, uniqueFields, dialect))
+	for _, uniqueName := range uniqueNames {
+		sb.WriteString(createOneUniqueKeySql(tableName, uniqueName, uniquekeysMap[uniqueName], dialect))
 	}
 
 	return sb.String()
@@ -387,7 +406,7 @@ func GetRefTableName(reference string) (string, error) {
 	return tablename, nil
 }
 
-func addRefferenceDepSqlForCreate(item *TDbTableInitSql, reference string, db *sql.DB, withComment bool,
+func (this *TDdl) addRefferenceDepSqlForCreate(item *TDbTableInitSql, reference string, db *sql.DB, withComment bool,
 	builtInitSqlMap map[string]*TDbTableInitSql) error {
 	refTableName, err := GetRefTableName(reference)
 	if err != nil {
@@ -404,12 +423,12 @@ func addRefferenceDepSqlForCreate(item *TDbTableInitSql, reference string, db *s
 		return nil
 	}
 
-	depMsg, found := msgstore.GetMsg(refTableName, false)
+	depMsg, found := this.Msgs.GetMsg(refTableName, false)
 	if !found {
 		return fmt.Errorf("reference table msg %s not found for %s", refTableName, item.TableName)
 	}
 
-	depSqlItem, err := DbCreateSQL(db, depMsg, item.DbSchema, true, withComment, builtInitSqlMap)
+	depSqlItem, err := this.DbCreateSQL(db, depMsg, item.DbSchema, true, withComment, builtInitSqlMap)
 	if err != nil {
 		return err
 	}
@@ -430,16 +449,12 @@ func getSqlTypeStr(fieldMsg protoreflect.FieldDescriptor, fieldPdb *protodb.PDBF
 				return pdbdbtype
 			}
 		}
-		switch dialect {
-		case sqldb.Postgres:
-			return "jsonb"
-		case sqldb.Mysql:
-			return "json"
-		case sqldb.SQLite:
-			return "text"
-		default:
-			return "text"
-		}
+		return columnTypeOrText(dialect, sqldb.ColumnType{
+			Kind:         fieldMsg.Kind(),
+			Map:          true,
+			MapKeyKind:   fieldMsg.MapKey().Kind(),
+			MapValueKind: fieldMsg.MapValue().Kind(),
+		})
 	}
 	if fieldMsg.IsList() {
 		if len(fieldPdb.DbTypeStr) > 0 || fieldPdb.DbType != protodb.FieldDbType_AutoMatch {
@@ -449,18 +464,11 @@ func getSqlTypeStr(fieldMsg protoreflect.FieldDescriptor, fieldPdb *protodb.PDBF
 				return pdbdbtype
 			}
 		}
-		switch dialect {
-		case sqldb.Postgres:
-			if fieldMsg.Kind() == protoreflect.MessageKind {
-				return "jsonb"
-			}
-			return protodb.GetProtoDBType(fieldMsg.Kind(), sqldb.Postgres) + "[]"
-		case sqldb.Mysql:
-			return "json"
-		case sqldb.SQLite:
-			return "text"
-		default:
-			return protodb.GetProtoDBType(fieldMsg.Kind(), dialect)
+		return columnTypeOrText(dialect, sqldb.ColumnType{Kind: fieldMsg.Kind(), Repeated: true})
+	}
+	if len(fieldPdb.DbTypeStr) == 0 && fieldPdb.DbType == protodb.FieldDbType_AutoMatch {
+		if enumType, ok := enumSqlTypeStr(fieldMsg, dialect); ok {
+			return enumType
 		}
 	}
 	//get db type from pdb
@@ -473,6 +481,14 @@ func getSqlTypeStr(fieldMsg protoreflect.FieldDescriptor, fieldPdb *protodb.PDBF
 	}
 }
 
+// columnTypeOrText column type of dialect, text if the dialect has no mapping
+func columnTypeOrText(dialect sqldb.TDBDialect, t sqldb.ColumnType) string {
+	if dbType := dialect.Dialect().ColumnType(t); len(dbType) > 0 {
+		return dbType
+	}
+	return "text"
+}
+
 // DbMigrateTable migrate a table to the definition of proto message
 // msg can be a proto message
 // dbschema can be empty,use for pgsql schema
@@ -480,6 +496,11 @@ func getSqlTypeStr(fieldMsg protoreflect.FieldDescriptor, fieldPdb *protodb.PDBF
 // withComment if add comment for sql
 // builtInitSqlMap is a map to store all init sql, user should use a global map for speed up(no need generate init sql again)
 func DbMigrateTable(db *sql.DB, msg proto.Message, dbschema string, checkRefference bool, withComment bool, builtInitSqlMap map[string]*TDbTableInitSql) (migrateItem *TDbTableInitSql, err error) {
+	return DefaultDdl.DbMigrateTable(db, msg, dbschema, checkRefference, withComment, builtInitSqlMap)
+}
+
+// DbMigrateTable see DbMigrateTable, referenced tables are found in this.Msgs
+func (this *TDdl) DbMigrateTable(db *sql.DB, msg proto.Message, dbschema string, checkRefference bool, withComment bool, builtInitSqlMap map[string]*TDbTableInitSql) (migrateItem *TDbTableInitSql, err error) {
 	if builtInitSqlMap == nil {
 		return nil, errors.New("builtInitSqlMap cannot be nil")
 	}
@@ -487,7 +508,7 @@ func DbMigrateTable(db *sql.DB, msg proto.Message, dbschema string, checkReffere
 	msgPm := msg.ProtoReflect()
 	msgDesc := msgPm.Descriptor()
 	msgFieldDescs := msgDesc.Fields()
-	tableName := string(msgDesc.Name())
+	tableName := pdbutil.GetTableName(msgDesc)
 
 	if sqlInitSql, ok := builtInitSqlMap[tableName]; ok {
 		if sqlInitSql == nil {
@@ -512,13 +533,17 @@ func DbMigrateTable(db *sql.DB, msg proto.Message, dbschema string, checkReffere
 
 	switch dbdialect {
 	case sqldb.Postgres:
-		migrateItem, err = dbMigrateTablePostgres(migrateItem, db, msg, dbschema, tableName, msgDesc, msgFieldDescs, checkRefference, withComment, builtInitSqlMap)
+		migrateItem, err = this.dbMigrateTablePostgres(migrateItem, db, msg, dbschema, tableName, msgDesc, msgFieldDescs, checkRefference, withComment, builtInitSqlMap)
 	case sqldb.Mysql:
-		migrateItem, err = dbMigrateTableMysql(migrateItem, db, msg, dbschema, tableName, msgDesc, msgFieldDescs, checkRefference, withComment, builtInitSqlMap)
+		migrateItem, err = this.dbMigrateTableMysql(migrateItem, db, msg, dbschema, tableName, msgDesc, msgFieldDescs, checkRefference, withComment, builtInitSqlMap)
 	case sqldb.SQLite:
-		migrateItem, err = dbMigrateTableSQLite(migrateItem, db, msg, dbschema, tableName, msgDesc, msgFieldDescs, checkRefference, withComment, builtInitSqlMap)
+		migrateItem, err = this.dbMigrateTableSQLite(migrateItem, db, msg, dbschema, tableName, msgDesc, msgFieldDescs, checkRefference, withComment, builtInitSqlMap)
 	default:
-		err = fmt.Errorf("not support database dialect %s", dbdialect.String())
+		if _, ok := sqldb.GetDialect(dbdialect); !ok {
+			err = fmt.Errorf("not support database dialect %s", dbdialect.String())
+			break
+		}
+		migrateItem, err = this.dbMigrateTableDialect(migrateItem, db, dbdialect, msg, dbschema, tableName, msgDesc, msgFieldDescs, checkRefference, withComment, builtInitSqlMap)
 	}
 
 	builtInitSqlMap[tableName] = migrateItem
@@ -531,15 +556,7 @@ func DbMigrateTable(db *sql.DB, msg proto.Message, dbschema string, checkReffere
 
 // IsPostgresqlTableExists check if table exists. if dbschema is empty, use public
 func IsPostgresqlTableExists(db *sql.DB, dbschema, tableName string) (bool, error) {
-	var exists bool
-	if len(dbschema) == 0 {
-		dbschema = "public"
-	}
-	err := db.QueryRow(postgresTableExistsSQL, strings.ToLower(dbschema), strings.ToLower(tableName)).Scan(&exists)
-	if err != nil {
-		return false, fmt.Errorf("error checking table existence: %w", err)
-	}
-	return exists, nil
+	return isDialectTableExists(db, sqldb.Postgres, dbschema, tableName)
 
 }
 
@@ -557,8 +574,15 @@ func getPostgresqlIndex(db *sql.DB, schemaName string, idxName string) (indexCol
 		return indexColumns, err
 	}
 
-	//to lower
-	indexdef = strings.ToLower(indexdef)
+	for _, columnName := range parsePostgresqlIndexColumns(indexdef) {
+		indexColumns[strings.ToLower(columnName)] = struct{}{}
+	}
+
+	return indexColumns, nil
+}
+
+// parsePostgresqlIndexColumns get columns in order from pg_indexes.indexdef
+func parsePostgresqlIndexColumns(indexdef string) (columns []string) {
 	//cut by (
 	_, after, found := strings.Cut(indexdef, "(")
 	if !found {
@@ -571,15 +595,14 @@ func getPostgresqlIndex(db *sql.DB, schemaName string, idxName string) (indexCol
 	}
 	//split by ,
 	for _, column := range strings.Split(before, ",") {
-		column = strings.TrimSpace(column)
-		columnName := strings.ToLower(column)
-		indexColumns[columnName] = struct{}{}
+		column = strings.Trim(strings.TrimSpace(column), `"`)
+		columns = append(columns, column)
 	}
 
-	return indexColumns, nil
+	return columns
 }
 
-func dbMigrateTablePostgres(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.Message, dbschema string, tableName string,
+func (this *TDdl) dbMigrateTablePostgres(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.Message, dbschema string, tableName string,
 	msgDesc protoreflect.MessageDescriptor, msgFieldDescs protoreflect.FieldDescriptors,
 	checkRefference bool, withComment bool, builtInitSqlMap map[string]*TDbTableInitSql) (return_migrateItem *TDbTableInitSql, err error) {
 	if len(dbschema) == 0 {
@@ -596,7 +619,7 @@ func dbMigrateTablePostgres(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.
 
 	if !exists {
 		// Table doesn't exist, create it
-		createSQLItem, err := dbCreateSQL(db, msg, dbschema, tableName, msgDesc, msgFieldDescs, true, withComment, builtInitSqlMap)
+		createSQLItem, err := this.dbCreateSQL(db, sqldb.Postgres, msg, dbschema, tableName, msgDesc, msgFieldDescs, true, withComment, builtInitSqlMap)
 
 		if err != nil {
 			return nil, err
@@ -606,41 +629,34 @@ func dbMigrateTablePostgres(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.
 	}
 
 	// Get existing columns
-	rows, err := db.Query(postgresColumnsSQL, strings.ToLower(dbschema), strings.ToLower(tableName))
+	existingColumns, err := getDialectTableColumns(db, sqldb.Postgres, dbschema, tableName)
 	if err != nil {
-		return nil, fmt.Errorf("error getting table columns: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
 
-	existingColumns := make(map[string]bool)
-	for rows.Next() {
-		var columnName string
-		if err := rows.Scan(&columnName); err != nil {
-			return nil, fmt.Errorf("error scanning table columns: %w", err)
-		}
-		// lowercase
-		existingColumns[strings.ToLower(columnName)] = true
-	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating table columns: %w", err)
+	// native enum types must exist before columns use them
+	enumTypeSqls, err := migratePostgresEnumTypesSql(db, msgFieldDescs)
+	if err != nil {
+		return nil, err
 	}
+	migrateItem.SqlStr = append(migrateItem.SqlStr, enumTypeSqls...)
 
 	// Generate ALTER TABLE statements
 	var alterStatements []string
 
 	// Get database dialect
 	dbdialect := sqldb.Postgres
-	dbtableName := sqldb.BuildDbTableName(tableName, dbschema, dbdialect)
+	dbtableName := sqldb.BuildQuotedDbTableName(tableName, dbschema, dbdialect)
 
 	//uniquename->proto field
 	uniquekeysMap := map[string][]protoreflect.FieldDescriptor{}
 
 	// Process each field in the proto message
-	for i := 0; i < msgFieldDescs.Len(); i++ {
-		fieldDesc := msgFieldDescs.Get(i)
-		fieldName := string(fieldDesc.Name())
+	for _, column := range msgTableColumns(msgFieldDescs) {
+		fieldDesc := column.fieldDesc
+		fieldName := column.name
 		fieldNameLowercase := strings.ToLower(fieldName)
-		pdb, _ := pdbutil.GetPDB(fieldDesc)
+		pdb := column.pdb
 
 		// Skip fields marked as NotDB
 		if pdb.NotDB {
@@ -665,7 +681,7 @@ func dbMigrateTablePostgres(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.
 		if _, exists := existingColumns[fieldNameLowercase]; !exists {
 			// Column doesn't exist, add it
 			alterStmt := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s",
-				dbtableName, fieldName, sqlType)
+				dbtableName, dbdialect.QuoteIdentifier(fieldName), sqlType)
 
 			// Add constraints
 			if fieldDesc.IsMap() {
@@ -715,12 +731,12 @@ func dbMigrateTablePostgres(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.
 				continue
 			}
 			if !isSqlInitItemExist(migrateItem, depMsgName, builtInitSqlMap) {
-				depMsg, found := msgstore.GetMsg(depMsgName, false)
+				depMsg, found := this.Msgs.GetMsg(depMsgName, false)
 				if !found {
 					return nil, fmt.Errorf("reference table msg %s not found for %s", pdb.Reference, depMsgName)
 				}
 
-				depMigrateItem, err := DbMigrateTable(db, depMsg, dbschema, checkRefference, withComment, builtInitSqlMap)
+				depMigrateItem, err := this.DbMigrateTable(db, depMsg, dbschema, checkRefference, withComment, builtInitSqlMap)
 				if err != nil {
 					return nil, fmt.Errorf("%s migrate reference %s for field %s fail:%s", migrateItem.TableName, pdb.Reference, fieldName, err.Error())
 				}
@@ -730,6 +746,8 @@ func dbMigrateTablePostgres(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.
 		}
 	}
 
+	alterStatements = append(alterStatements, migrateOneofDiscriminatorSql(dbtableName, msgDesc, existingColumns, dbdialect)...)
+
 	if len(alterStatements) > 0 {
 		migrateItem.SqlStr = append(migrateItem.SqlStr, alterStatements...)
 	}
@@ -742,7 +760,7 @@ func dbMigrateTablePostgres(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.
 	migrateUniqueKeySql := ""
 	for uniqueKeyName, uniqueKeyFields := range uniquekeysMap {
 		//check if the index exist first,if not create it
-		indexColumns, err := getPostgresqlIndex(db, dbschema, uniqueKeyName)
+		indexColumns, err := getPostgresqlIndex(db, dbdialect.FoldIdentifier(dbschema), dbdialect.FoldIdentifier(uniqueKeyName))
 		if err != nil {
 			return nil, err
 		}
@@ -753,9 +771,9 @@ func dbMigrateTablePostgres(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.
 		} else {
 			if !uniqueKeyColumnsEqual(indexColumns, uniqueKeyFields) {
 				//not equal,drop it and create it
-				dropUniqueKeySql := fmt.Sprintf("drop index if exists %s ;\n", uniqueKeyName)
+				dropUniqueKeySql := fmt.Sprintf("drop index if exists %s ;\n", dbdialect.QuoteIdentifier(uniqueKeyName))
 				if len(dbschema) > 0 {
-					dropUniqueKeySql = fmt.Sprintf("drop index if exists %s.%s ;\n", dbschema, uniqueKeyName)
+					dropUniqueKeySql = fmt.Sprintf("drop index if exists %s.%s ;\n", dbdialect.QuoteIdentifier(dbschema), dbdialect.QuoteIdentifier(uniqueKeyName))
 				}
 				migrateUniqueKeySql += dropUniqueKeySql
 				createUniqueKeySql := createOneUniqueKeySql(dbtableName, uniqueKeyName, uniqueKeyFields, dbdialect)
@@ -799,10 +817,11 @@ func isSqlInitItemExist(item *TDbTableInitSql, tableName string, existTableMap m
 	return false
 }
 
-func dbMigrateTableMysql(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.Message, dbschema string, tableName string,
+func (this *TDdl) dbMigrateTableMysql(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.Message, dbschema string, tableName string,
 	msgDesc protoreflect.MessageDescriptor, msgFieldDescs protoreflect.FieldDescriptors, checkRefference bool, withComment bool,
 	builtInitSqlMap map[string]*TDbTableInitSql) (return_migrateItem *TDbTableInitSql, err error) {
 	dbtableName := sqldb.BuildDbTableName(tableName, dbschema, sqldb.Mysql)
+	quotedTableName := sqldb.BuildQuotedDbTableName(tableName, dbschema, sqldb.Mysql)
 
 	exists, err := IsMysqlTableExists(db, dbtableName)
 	if err != nil {
@@ -811,40 +830,27 @@ func dbMigrateTableMysql(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.Mes
 
 	migrateItem.TableExists = exists
 	if !exists {
-		createSQLItem, err := dbCreateSQL(db, msg, dbschema, tableName, msgDesc, msgFieldDescs, true, withComment, builtInitSqlMap)
+		createSQLItem, err := this.dbCreateSQL(db, sqldb.Mysql, msg, dbschema, tableName, msgDesc, msgFieldDescs, true, withComment, builtInitSqlMap)
 		if err != nil {
 			return nil, err
 		}
 		return createSQLItem, nil
 	}
 
-	rows, err := db.Query("SELECT column_name FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ?", dbtableName)
+	existingColumns, err := getDialectTableColumns(db, sqldb.Mysql, "", dbtableName)
 	if err != nil {
-		return nil, fmt.Errorf("error getting table columns: %w", err)
-	}
-	defer rows.Close()
-
-	existingColumns := make(map[string]bool)
-	for rows.Next() {
-		var columnName string
-		if err := rows.Scan(&columnName); err != nil {
-			return nil, fmt.Errorf("error scanning table columns: %w", err)
-		}
-		existingColumns[strings.ToLower(columnName)] = true
-	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating table columns: %w", err)
+		return nil, err
 	}
 
 	var alterStatements []string
 	uniquekeysMap := map[string][]protoreflect.FieldDescriptor{}
 	dbdialect := sqldb.Mysql
 
-	for i := 0; i < msgFieldDescs.Len(); i++ {
-		fieldDesc := msgFieldDescs.Get(i)
-		fieldName := string(fieldDesc.Name())
+	for _, column := range msgTableColumns(msgFieldDescs) {
+		fieldDesc := column.fieldDesc
+		fieldName := column.name
 		fieldNameLowercase := strings.ToLower(fieldName)
-		pdb, _ := pdbutil.GetPDB(fieldDesc)
+		pdb := column.pdb
 
 		if pdb.NotDB {
 			continue
@@ -868,7 +874,7 @@ func dbMigrateTableMysql(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.Mes
 
 		sqlType := getSqlTypeStr(fieldDesc, pdb, dbdialect)
 		if !existingColumns[fieldNameLowercase] {
-			alterStmt := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", dbtableName, fieldName, sqlType)
+			alterStmt := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", quotedTableName, dbdialect.QuoteIdentifier(fieldName), sqlType)
 
 			if fieldDesc.IsMap() {
 				alterStmt += " NOT NULL "
@@ -901,6 +907,14 @@ func dbMigrateTableMysql(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.Mes
 
 			alterStmt += ";"
 			alterStatements = append(alterStatements, alterStmt)
+		} else if isNativeEnumField(fieldDesc) && strings.HasPrefix(sqlType, "enum(") {
+			alterStmt, err := migrateMysqlEnumColumnSql(db, dbtableName, fieldName, fieldDesc, pdb, sqlType)
+			if err != nil {
+				return nil, err
+			}
+			if len(alterStmt) > 0 {
+				alterStatements = append(alterStatements, alterStmt)
+			}
 		}
 
 		if len(pdb.Reference) > 0 && checkRefference {
@@ -912,11 +926,11 @@ func dbMigrateTableMysql(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.Mes
 				continue
 			}
 			if !isSqlInitItemExist(migrateItem, depMsgName, builtInitSqlMap) {
-				depMsg, found := msgstore.GetMsg(depMsgName, false)
+				depMsg, found := this.Msgs.GetMsg(depMsgName, false)
 				if !found {
 					return nil, fmt.Errorf("reference table msg %s not found for %s", pdb.Reference, depMsgName)
 				}
-				depMigrateItem, err := DbMigrateTable(db, depMsg, dbschema, checkRefference, withComment, builtInitSqlMap)
+				depMigrateItem, err := this.DbMigrateTable(db, depMsg, dbschema, checkRefference, withComment, builtInitSqlMap)
 				if err != nil {
 					return nil, fmt.Errorf("%s migrate reference %s for field %s fail:%s", migrateItem.TableName, pdb.Reference, fieldName, err.Error())
 				}
@@ -926,6 +940,8 @@ func dbMigrateTableMysql(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.Mes
 		}
 	}
 
+	alterStatements = append(alterStatements, migrateOneofDiscriminatorSql(quotedTableName, msgDesc, existingColumns, dbdialect)...)
+
 	if len(alterStatements) > 0 {
 		migrateItem.SqlStr = append(migrateItem.SqlStr, alterStatements...)
 	}
@@ -937,10 +953,10 @@ func dbMigrateTableMysql(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.Mes
 			return nil, err
 		}
 		if len(indexColumns) == 0 {
-			migrateUniqueKeySql += createOneUniqueKeySql(dbtableName, uniqueKeyName, uniqueKeyFields, dbdialect)
+			migrateUniqueKeySql += createOneUniqueKeySql(quotedTableName, uniqueKeyName, uniqueKeyFields, dbdialect)
 		} else if !uniqueKeyColumnsEqual(indexColumns, uniqueKeyFields) {
-			migrateUniqueKeySql += fmt.Sprintf("drop index %s on %s;\n", uniqueKeyName, dbtableName)
-			migrateUniqueKeySql += createOneUniqueKeySql(dbtableName, uniqueKeyName, uniqueKeyFields, dbdialect)
+			migrateUniqueKeySql += fmt.Sprintf("drop index %s on %s;\n", dbdialect.QuoteIdentifier(uniqueKeyName), quotedTableName)
+			migrateUniqueKeySql += createOneUniqueKeySql(quotedTableName, uniqueKeyName, uniqueKeyFields, dbdialect)
 		}
 	}
 	if len(migrateUniqueKeySql) > 0 {
@@ -959,12 +975,7 @@ func dbMigrateTableMysql(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.Mes
 
 // IsMysqlTableExists checks if a table exists in the current database.
 func IsMysqlTableExists(db *sql.DB, tableName string) (bool, error) {
-	var exists bool
-	err := db.QueryRow("SELECT EXISTS (SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?)", tableName).Scan(&exists)
-	if err != nil {
-		return false, fmt.Errorf("error checking table existence: %w", err)
-	}
-	return exists, nil
+	return isDialectTableExists(db, sqldb.Mysql, "", tableName)
 }
 
 func getMysqlIndex(db *sql.DB, tableName string, idxName string) (indexColumns map[string]struct{}, err error) {
@@ -990,12 +1001,7 @@ func getMysqlIndex(db *sql.DB, tableName string, idxName string) (indexColumns m
 
 // IsSQLiteTableExists check if table exists.
 func IsSQLiteTableExists(db *sql.DB, tableName string) (bool, error) {
-	var exists bool
-	err := db.QueryRow(sqliteTableExistsSQL, tableName).Scan(&exists)
-	if err != nil {
-		return false, fmt.Errorf("error checking table existence: %w", err)
-	}
-	return exists, nil
+	return isDialectTableExists(db, sqldb.SQLite, "", tableName)
 }
 
 func getSqliteIndex(db *sql.DB, idxName string) (indexColumns map[string]struct{}, err error) {
@@ -1024,10 +1030,11 @@ func getSqliteIndex(db *sql.DB, idxName string) (indexColumns map[string]struct{
 	return indexColumns, nil
 }
 
-func dbMigrateTableSQLite(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.Message, dbschema string, tableName string,
+func (this *TDdl) dbMigrateTableSQLite(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.Message, dbschema string, tableName string,
 	msgDesc protoreflect.MessageDescriptor, msgFieldDescs protoreflect.FieldDescriptors, checkRefference bool, withComment bool,
 	builtInitSqlMap map[string]*TDbTableInitSql) (return_migrateItem *TDbTableInitSql, err error) {
 	dbtableName := dbschema + tableName
+	quotedTableName := sqldb.SQLite.QuoteIdentifier(dbtableName)
 
 	// Check if table exists
 	exists, err := IsSQLiteTableExists(db, dbtableName)
@@ -1039,7 +1046,7 @@ func dbMigrateTableSQLite(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.Me
 
 	if !exists {
 		// Table doesn't exist, create it
-		createSQLItem, err := dbCreateSQL(db, msg, dbschema, tableName, msgDesc, msgFieldDescs, true, withComment, builtInitSqlMap)
+		createSQLItem, err := this.dbCreateSQL(db, sqldb.SQLite, msg, dbschema, tableName, msgDesc, msgFieldDescs, true, withComment, builtInitSqlMap)
 
 		if err != nil {
 			return nil, err
@@ -1049,23 +1056,9 @@ func dbMigrateTableSQLite(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.Me
 	}
 
 	//get all columns of table
-	rows, err := db.Query(sqliteTableInfoSQL, dbtableName)
+	existingColumns, err := getDialectTableColumns(db, sqldb.SQLite, "", dbtableName)
 	if err != nil {
-		return nil, fmt.Errorf("error getting table columns: %w", err)
-	}
-	defer rows.Close()
-
-	var existingColumns = make(map[string]bool)
-	for rows.Next() {
-		var columnName string
-		if err := rows.Scan(&columnName); err != nil {
-			return nil, fmt.Errorf("error scanning table columns: %w", err)
-		}
-		//lowercase
-		existingColumns[strings.ToLower(columnName)] = true
-	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating table columns: %w", err)
+		return nil, err
 	}
 
 	// Generate ALTER TABLE statements
@@ -1077,11 +1070,11 @@ func dbMigrateTableSQLite(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.Me
 	dbdialect := sqldb.SQLite
 
 	// Process each field in the proto message
-	for i := 0; i < msgFieldDescs.Len(); i++ {
-		fieldDesc := msgFieldDescs.Get(i)
-		fieldName := string(fieldDesc.Name())
+	for _, column := range msgTableColumns(msgFieldDescs) {
+		fieldDesc := column.fieldDesc
+		fieldName := column.name
 		fieldNameLowercase := strings.ToLower(fieldName)
-		pdb, _ := pdbutil.GetPDB(fieldDesc)
+		pdb := column.pdb
 
 		// Skip fields marked as NotDB
 		if pdb.NotDB {
@@ -1111,7 +1104,7 @@ func dbMigrateTableSQLite(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.Me
 		if _, exists := existingColumns[fieldNameLowercase]; !exists {
 			// Column doesn't exist, add it
 			alterStmt := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s",
-				dbtableName, fieldName, sqlType)
+				quotedTableName, dbdialect.QuoteIdentifier(fieldName), sqlType)
 
 			// Add constraints
 			if fieldDesc.IsMap() {
@@ -1155,12 +1148,12 @@ func dbMigrateTableSQLite(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.Me
 				//self reference
 				continue
 			}
-			depMsg, found := msgstore.GetMsg(depMsgName, false)
+			depMsg, found := this.Msgs.GetMsg(depMsgName, false)
 			if !found {
 				return nil, fmt.Errorf("reference table msg %s not found for %s", pdb.Reference, depMsgName)
 			}
 
-			depMigrateItem, err := DbMigrateTable(db, depMsg, dbschema, checkRefference, withComment, builtInitSqlMap)
+			depMigrateItem, err := this.DbMigrateTable(db, depMsg, dbschema, checkRefference, withComment, builtInitSqlMap)
 			if err != nil {
 				return nil, fmt.Errorf("%s migrate reference %s for field %s fail:%s", migrateItem.TableName, pdb.Reference, fieldName, err.Error())
 			}
@@ -1169,6 +1162,8 @@ func dbMigrateTableSQLite(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.Me
 		}
 	}
 
+	alterStatements = append(alterStatements, migrateOneofDiscriminatorSql(quotedTableName, msgDesc, existingColumns, dbdialect)...)
+
 	if len(alterStatements) > 0 {
 		migrateItem.SqlStr = append(migrateItem.SqlStr, alterStatements...)
 	}
@@ -1192,14 +1187,14 @@ func dbMigrateTableSQLite(migrateItem *TDbTableInitSql, db *sql.DB, msg proto.Me
 		}
 		if len(indexColumns) == 0 {
 			//not exist,create it
-			createUniqueKeySql := createOneUniqueKeySql(dbtableName, uniqueKeyName, uniqueKeyFields, dbdialect)
+			createUniqueKeySql := createOneUniqueKeySql(quotedTableName, uniqueKeyName, uniqueKeyFields, dbdialect)
 			migrateUniqueKeySql += createUniqueKeySql
 		} else {
 			if !uniqueKeyColumnsEqual(indexColumns, uniqueKeyFields) {
 				//not equal,drop it and create it
-				dropUniqueKeySql := fmt.Sprintf("drop index if exists %s;\n", uniqueKeyName)
+				dropUniqueKeySql := fmt.Sprintf("drop index if exists %s;\n", dbdialect.QuoteIdentifier(uniqueKeyName))
 				migrateUniqueKeySql += dropUniqueKeySql
-				createUniqueKeySql := createOneUniqueKeySql(dbtableName, uniqueKeyName, uniqueKeyFields, dbdialect)
+				createUniqueKeySql := createOneUniqueKeySql(quotedTableName, uniqueKeyName, uniqueKeyFields, dbdialect)
 				migrateUniqueKeySql += createUniqueKeySql
 			}
 		}
@@ -1220,7 +1215,7 @@ func uniqueKeyColumnsEqual(columns map[string]struct{}, fields []protoreflect.Fi
 		return false
 	}
 	for _, field := range fields {
-		fileldName := string(field.Name())
+		fileldName := pdbutil.GetColumnName(field)
 		fileldNameLower := strings.ToLower(fileldName)
 		_, ok := columns[fileldNameLower]
 		if !ok {
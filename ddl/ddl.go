@@ -0,0 +1,14 @@
+package ddl
+
+import "github.com/ygrpc/protodb/msgstore"
+
+// TDdl ddl of table messages, referenced tables are found in the table messages of Msgs.
+// a service with its own service.Registry uses TDdl{Msgs: registry.Msgs}
+type TDdl struct {
+	Msgs *msgstore.TMsgStore
+	// NoComment GenerateSchemaSql/GenerateSchema/GenerateRegisteredSchema without the comments of messages and fields
+	NoComment bool
+}
+
+// DefaultDdl ddl of msgstore.GlobalMsgStore, used by the package functions
+var DefaultDdl = &TDdl{Msgs: msgstore.GlobalMsgStore}
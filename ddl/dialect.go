@@ -0,0 +1,169 @@
+package ddl
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// isDialectTableExists check if table exists by the catalog query of dialect
+func isDialectTableExists(db *sql.DB, dialect sqldb.TDBDialect, dbschema string, tableName string) (bool, error) {
+	query, args := dialect.Dialect().TableExistsQuery(dbschema, tableName)
+	var exists bool
+	err := db.QueryRow(query, args...).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("error checking table existence: %w", err)
+	}
+	return exists, nil
+}
+
+// getDialectTableColumns lowercase column names of table by the catalog query of dialect
+func getDialectTableColumns(db *sql.DB, dialect sqldb.TDBDialect, dbschema string, tableName string) (map[string]bool, error) {
+	query, args := dialect.Dialect().ColumnsQuery(dbschema, tableName)
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting table columns: %w", err)
+	}
+	defer rows.Close()
+
+	existingColumns := make(map[string]bool)
+	for rows.Next() {
+		var columnName string
+		if err := rows.Scan(&columnName); err != nil {
+			return nil, fmt.Errorf("error scanning table columns: %w", err)
+		}
+		existingColumns[strings.ToLower(columnName)] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating table columns: %w", err)
+	}
+	return existingColumns, nil
+}
+
+// dbMigrateTableDialect migrate table of a dialect registered outside protodb,
+// only missing columns are added, unique keys are created with the table
+func (this *TDdl) dbMigrateTableDialect(migrateItem *TDbTableInitSql, db *sql.DB, dbdialect sqldb.TDBDialect, msg proto.Message, dbschema string, tableName string,
+	msgDesc protoreflect.MessageDescriptor, msgFieldDescs protoreflect.FieldDescriptors, checkRefference bool, withComment bool,
+	builtInitSqlMap map[string]*TDbTableInitSql) (return_migrateItem *TDbTableInitSql, err error) {
+	exists, err := isDialectTableExists(db, dbdialect, dbschema, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	migrateItem.TableExists = exists
+	if !exists {
+		createSQLItem, err := this.dbCreateSQL(db, dbdialect, msg, dbschema, tableName, msgDesc, msgFieldDescs, true, withComment, builtInitSqlMap)
+		if err != nil {
+			return nil, err
+		}
+		return createSQLItem, nil
+	}
+
+	existingColumns, err := getDialectTableColumns(db, dbdialect, dbschema, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	quotedTableName := sqldb.BuildQuotedDbTableName(tableName, dbschema, dbdialect)
+	var alterStatements []string
+	for _, column := range msgTableColumns(msgFieldDescs) {
+		fieldDesc := column.fieldDesc
+		fieldName := column.name
+		pdb := column.pdb
+
+		if pdb.NotDB {
+			continue
+		}
+
+		if !existingColumns[strings.ToLower(fieldName)] {
+			sqlType := getSqlTypeStr(fieldDesc, pdb, dbdialect)
+			alterStmt := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s",
+				quotedTableName, dbdialect.QuoteIdentifier(fieldName), sqlType)
+
+			if fieldDesc.IsMap() {
+				alterStmt += " NOT NULL "
+				if len(pdb.DefaultValue) > 0 {
+					alterStmt += " DEFAULT " + normalizeUserDefaultValue(pdb.DefaultValue, dbdialect, sqlType)
+				} else {
+					alterStmt += " DEFAULT '{}'"
+				}
+			} else if fieldDesc.IsList() {
+				alterStmt += " NOT NULL "
+				if len(pdb.DefaultValue) > 0 {
+					alterStmt += " DEFAULT " + normalizeUserDefaultValue(pdb.DefaultValue, dbdialect, sqlType)
+				} else {
+					alterStmt += " DEFAULT '[]'"
+				}
+			} else {
+				if pdb.NotNull {
+					alterStmt += " NOT NULL "
+				}
+				if len(pdb.DefaultValue) > 0 {
+					alterStmt += " DEFAULT " + TryAddQuote2DefaultValue(fieldDesc.Kind(), pdb.DefaultValue)
+				}
+			}
+
+			if len(pdb.Reference) > 0 {
+				alterStmt += " REFERENCES " + pdb.Reference
+			}
+
+			alterStmt += ";"
+			alterStatements = append(alterStatements, alterStmt)
+		}
+
+		//check dependency
+		if len(pdb.Reference) > 0 && checkRefference {
+			depMsgName, err := GetRefTableName(pdb.Reference)
+			if err != nil {
+				return nil, fmt.Errorf("get reference table name %s err: %s", migrateItem.TableName, err)
+			}
+			if depMsgName == migrateItem.TableName {
+				//self reference
+				continue
+			}
+			depMsg, found := this.Msgs.GetMsg(depMsgName, false)
+			if !found {
+				return nil, fmt.Errorf("reference table msg %s not found for %s", pdb.Reference, depMsgName)
+			}
+
+			depMigrateItem, err := this.DbMigrateTable(db, depMsg, dbschema, checkRefference, withComment, builtInitSqlMap)
+			if err != nil {
+				return nil, fmt.Errorf("%s migrate reference %s for field %s fail:%s", migrateItem.TableName, pdb.Reference, fieldName, err.Error())
+			}
+			migrateItem.DepTableSqlItemMap[depMsgName] = depMigrateItem
+			migrateItem.DepTableNames = append(migrateItem.DepTableNames, depMsgName)
+		}
+	}
+
+	alterStatements = append(alterStatements, migrateOneofDiscriminatorSql(quotedTableName, msgDesc, existingColumns, dbdialect)...)
+	migrateItem.SqlStr = append(migrateItem.SqlStr, alterStatements...)
+
+	pdbm, found := pdbutil.GetPDBM(msgDesc)
+	if found && len(pdbm.SQLMigrate) > 0 {
+		migrateItem.SqlStr = append(migrateItem.SqlStr, pdbm.SQLMigrate...)
+	}
+
+	return migrateItem, nil
+}
+
+// duckdbSequenceName sequence of the serial column, duckdb has no auto increment column
+func duckdbSequenceName(tableName string, dbschema string, columnName string) string {
+	return sqldb.DuckDB.Dialect().TableName("seq_"+tableName+"_"+columnName, dbschema)
+}
+
+// createDuckDBSequencesSql create sequences of the serial columns, ignore if exists
+func createDuckDBSequencesSql(tableName string, dbschema string, msgFieldDescs protoreflect.FieldDescriptors) string {
+	sqlStr := ""
+	for _, column := range msgTableColumns(msgFieldDescs) {
+		if column.pdb.NotDB || !column.pdb.IsAutoIncrement() {
+			continue
+		}
+		sqlStr += "CREATE SEQUENCE IF NOT EXISTS " + sqldb.DuckDB.Dialect().QuotedTableName("seq_"+tableName+"_"+column.name, dbschema) + ";\n"
+	}
+	return sqlStr
+}
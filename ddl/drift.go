@@ -0,0 +1,389 @@
+package ddl
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/proto"
+)
+
+// TDriftSeverity severity of a drift item
+type TDriftSeverity int
+
+const (
+	// DriftInfo harmless difference, like an extra nullable column
+	DriftInfo TDriftSeverity = iota
+	// DriftWarning reads and writes still work, but the db is not what migrate would create
+	DriftWarning
+	// DriftCritical reads or writes of the table will fail, like a missing column
+	DriftCritical
+)
+
+func (s TDriftSeverity) String() string {
+	switch s {
+	case DriftInfo:
+		return "info"
+	case DriftWarning:
+		return "warning"
+	case DriftCritical:
+		return "critical"
+	default:
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+}
+
+func (s TDriftSeverity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// drift kinds of TDriftItem
+const (
+	DriftMissingTable       = "missing_table"
+	DriftMissingColumn      = "missing_column"
+	DriftExtraColumn        = "extra_column"
+	DriftTypeMismatch       = "type_mismatch"
+	DriftNullability        = "nullability"
+	DriftPrimaryKey         = "primary_key"
+	DriftMissingUniqueIndex = "missing_unique_index"
+	DriftMissingForeignKey  = "missing_foreign_key"
+)
+
+// TDriftItem one difference between the db and the proto definition
+type TDriftItem struct {
+	Table string `json:"table"`
+	// empty for table level items
+	Column   string         `json:"column,omitempty"`
+	Kind     string         `json:"kind"`
+	Severity TDriftSeverity `json:"severity"`
+	// expected definition from proto
+	Expected string `json:"expected,omitempty"`
+	// actual definition in db
+	Actual string `json:"actual,omitempty"`
+}
+
+func (this *TDriftItem) String() string {
+	name := this.Table
+	if len(this.Column) > 0 {
+		name += "." + this.Column
+	}
+	s := fmt.Sprintf("%s %s %s", this.Severity, name, this.Kind)
+	if len(this.Expected) > 0 || len(this.Actual) > 0 {
+		s += fmt.Sprintf(" (expected %q, actual %q)", this.Expected, this.Actual)
+	}
+	return s
+}
+
+// TDriftReport result of CheckDrift, items are in table then column order
+type TDriftReport struct {
+	Items []*TDriftItem `json:"items"`
+}
+
+// MaxSeverity highest severity of the items, DriftInfo if no item
+func (this *TDriftReport) MaxSeverity() TDriftSeverity {
+	maxSeverity := DriftInfo
+	for _, item := range this.Items {
+		if item.Severity > maxSeverity {
+			maxSeverity = item.Severity
+		}
+	}
+	return maxSeverity
+}
+
+// HasCritical any critical item
+func (this *TDriftReport) HasCritical() bool {
+	return this.MaxSeverity() == DriftCritical
+}
+
+// Err error listing the items of minSeverity and above, nil if no such item
+func (this *TDriftReport) Err(minSeverity TDriftSeverity) error {
+	var lines []string
+	for _, item := range this.Items {
+		if item.Severity >= minSeverity {
+			lines = append(lines, item.String())
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return fmt.Errorf("schema drift:\n%s", strings.Join(lines, "\n"))
+}
+
+// CheckDrift compare the tables of msgs in db with their proto definitions, see CheckDriftInSchema
+func CheckDrift(db *sql.DB, msgs ...proto.Message) (*TDriftReport, error) {
+	return CheckDriftInSchema(db, "", msgs...)
+}
+
+// CheckDriftInSchema compare the tables of msgs in dbschema(pg schema, table name prefix for mysql/sqlite)
+// with the columns, types, nullability, primary key, unique indexes and references migrate would create.
+// postgres, mysql and sqlite are supported. the db is only read
+func CheckDriftInSchema(db *sql.DB, dbschema string, msgs ...proto.Message) (*TDriftReport, error) {
+	dialect := sqldb.GetDBDialect(db)
+	report := &TDriftReport{}
+	for _, msg := range msgs {
+		msgDesc := msg.ProtoReflect().Descriptor()
+		tableName := pdbutil.GetTableName(msgDesc)
+		expected := expectedDriftColumns(dialect, msg)
+
+		exists, err := isDialectTableExists(db, dialect, dbschema, tableName)
+		if err != nil {
+			return nil, fmt.Errorf("table %s: %w", tableName, err)
+		}
+		if !exists {
+			report.Items = append(report.Items, &TDriftItem{Table: tableName, Kind: DriftMissingTable, Severity: DriftCritical})
+			continue
+		}
+		tables, err := dbIntrospectTables(db, dialect, dbschema, []string{tableName})
+		if err != nil {
+			return nil, err
+		}
+		report.Items = append(report.Items, compareDriftTable(dialect, tableName, expected, tables[0])...)
+	}
+	return report, nil
+}
+
+// DriftHealthHandler health endpoint of CheckDriftInSchema, the report is served as json.
+// status is 503 if the check fails or the report has critical items, 200 otherwise
+func DriftHealthHandler(db *sql.DB, dbschema string, msgs ...proto.Message) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report, err := CheckDriftInSchema(db, dbschema, msgs...)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if report.HasCritical() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
+
+// driftColumn column as migrate would create it
+type driftColumn struct {
+	name      string
+	sqlType   string
+	notNull   bool
+	primary   bool
+	unique    bool
+	uniqueKey string
+	reference string
+}
+
+// expectedDriftColumns columns of the table of msg in create order, oneof discriminators included
+func expectedDriftColumns(dialect sqldb.TDBDialect, msg proto.Message) []driftColumn {
+	msgDesc := msg.ProtoReflect().Descriptor()
+	var columns []driftColumn
+	for _, column := range msgTableColumns(msgDesc.Fields()) {
+		pdb := column.pdb
+		if pdb.NotDB {
+			continue
+		}
+		fieldDesc := column.fieldDesc
+		expected := driftColumn{
+			name:      column.name,
+			sqlType:   getSqlTypeStr(fieldDesc, pdb, dialect),
+			primary:   pdb.IsPrimary(),
+			notNull:   !pdb.IsPrimary() && (fieldDesc.IsMap() || fieldDesc.IsList() || pdb.NotNull),
+			unique:    pdb.Unique,
+			reference: pdb.Reference,
+		}
+		if pdb.Unique {
+			expected.uniqueKey = pdb.UniqueName
+			if len(expected.uniqueKey) == 0 {
+				expected.uniqueKey = "\x00" + column.name
+			}
+		}
+		columns = append(columns, expected)
+		if discriminator, ok := oneofDiscriminatorAfter(msgDesc, fieldDesc); ok {
+			columns = append(columns, driftColumn{name: discriminator, sqlType: oneofDiscriminatorSqlType(dialect)})
+		}
+	}
+	return columns
+}
+
+func compareDriftTable(dialect sqldb.TDBDialect, tableName string, expected []driftColumn, table *TDbTableSchema) []*TDriftItem {
+	var items []*TDriftItem
+	add := func(column string, kind string, severity TDriftSeverity, expectedDef string, actualDef string) {
+		items = append(items, &TDriftItem{Table: tableName, Column: column, Kind: kind, Severity: severity, Expected: expectedDef, Actual: actualDef})
+	}
+
+	expectedNames := make(map[string]bool, len(expected))
+	var expectedPrimary []string
+	expectedUnique := map[string][]string{}
+	for _, column := range expected {
+		expectedNames[strings.ToLower(column.name)] = true
+		if column.primary {
+			expectedPrimary = append(expectedPrimary, strings.ToLower(column.name))
+		}
+		if column.unique {
+			expectedUnique[column.uniqueKey] = append(expectedUnique[column.uniqueKey], strings.ToLower(column.name))
+		}
+
+		actual := table.column(column.name)
+		if actual == nil {
+			add(column.name, DriftMissingColumn, DriftCritical, column.sqlType, "")
+			continue
+		}
+
+		expectedType, actualType := normalizeDriftType(dialect, column.sqlType), normalizeDriftType(dialect, actual.DbType)
+		if expectedType != actualType {
+			severity := DriftCritical
+			if driftTypeFamily(expectedType) == driftTypeFamily(actualType) {
+				severity = DriftWarning
+			}
+			add(column.name, DriftTypeMismatch, severity, expectedType, actualType)
+		}
+
+		if !column.primary && !actual.Primary && column.notNull != actual.NotNull {
+			if actual.NotNull {
+				// unset values are written as NULL
+				add(column.name, DriftNullability, DriftCritical, "NULL", "NOT NULL")
+			} else {
+				add(column.name, DriftNullability, DriftWarning, "NOT NULL", "NULL")
+			}
+		}
+
+		if len(column.reference) > 0 && normalizeDriftReference(column.reference) != normalizeDriftReference(actual.Reference) {
+			add(column.name, DriftMissingForeignKey, DriftWarning, column.reference, actual.Reference)
+		}
+	}
+
+	var actualPrimary []string
+	actualUnique := map[string]bool{}
+	actualUniqueGroups := map[string][]string{}
+	for _, actual := range table.Columns {
+		name := strings.ToLower(actual.Name)
+		if actual.Primary {
+			actualPrimary = append(actualPrimary, name)
+		}
+		if actual.Unique {
+			key := actual.UniqueName
+			if len(key) == 0 {
+				key = "\x00" + name
+			}
+			actualUniqueGroups[key] = append(actualUniqueGroups[key], name)
+		}
+		if expectedNames[name] {
+			continue
+		}
+		if actual.NotNull && len(actual.DefaultValue) == 0 && actual.SerialType == 0 && !actual.Primary {
+			// inserts without the column fail
+			add(actual.Name, DriftExtraColumn, DriftCritical, "", actual.DbType+" NOT NULL")
+		} else {
+			add(actual.Name, DriftExtraColumn, DriftInfo, "", actual.DbType)
+		}
+	}
+
+	if driftColumnList(expectedPrimary) != driftColumnList(actualPrimary) {
+		add("", DriftPrimaryKey, DriftWarning, driftColumnList(expectedPrimary), driftColumnList(actualPrimary))
+	}
+
+	for _, columns := range actualUniqueGroups {
+		actualUnique[driftColumnList(columns)] = true
+	}
+	uniqueKeys := make([]string, 0, len(expectedUnique))
+	for key := range expectedUnique {
+		uniqueKeys = append(uniqueKeys, key)
+	}
+	sort.Strings(uniqueKeys)
+	for _, key := range uniqueKeys {
+		columns := driftColumnList(expectedUnique[key])
+		if !actualUnique[columns] {
+			add("", DriftMissingUniqueIndex, DriftWarning, columns, "")
+		}
+	}
+
+	return items
+}
+
+// driftColumnList sorted column names like (a,b)
+func driftColumnList(columns []string) string {
+	sorted := append([]string{}, columns...)
+	sort.Strings(sorted)
+	return "(" + strings.Join(sorted, ",") + ")"
+}
+
+func normalizeDriftReference(reference string) string {
+	return strings.ToLower(strings.Join(strings.Fields(reference), ""))
+}
+
+var driftTypeAliases = map[string]string{
+	"bigserial":                   "bigint",
+	"serial":                      "integer",
+	"smallserial":                 "smallint",
+	"int8":                        "bigint",
+	"int4":                        "integer",
+	"int2":                        "smallint",
+	"bool":                        "boolean",
+	"float8":                      "double precision",
+	"float4":                      "real",
+	"decimal":                     "numeric",
+	"character varying":           "varchar",
+	"character":                   "char",
+	"timestamp with time zone":    "timestamptz",
+	"timestamp without time zone": "timestamp",
+}
+
+// normalizeDriftType lowercase type without serial/auto increment, aliases of the dialect are resolved
+func normalizeDriftType(dialect sqldb.TDBDialect, dbType string) string {
+	dbType = strings.ToLower(strings.Join(strings.Fields(dbType), " "))
+	dbType = strings.NewReplacer(" (", "(", "( ", "(", " )", ")", ", ", ",", " ,", ",", `"`, "").Replace(dbType)
+	for _, suffix := range []string{" auto_increment", " autoincrement", " primary key"} {
+		dbType = strings.TrimSuffix(dbType, suffix)
+	}
+
+	array := strings.HasSuffix(dbType, "[]")
+	dbType = strings.TrimSuffix(dbType, "[]")
+	base, args, _ := strings.Cut(dbType, "(")
+	if alias, ok := driftTypeAliases[base]; ok {
+		base = alias
+	}
+	if dialect == sqldb.Mysql && base == "integer" {
+		base = "int"
+	}
+	if dialect == sqldb.Postgres {
+		// enum type may be schema qualified
+		if i := strings.LastIndex(base, "."); i >= 0 {
+			base = base[i+1:]
+		}
+	}
+
+	dbType = base
+	if len(args) > 0 {
+		dbType += "(" + args
+	}
+	if array {
+		dbType += "[]"
+	}
+	return dbType
+}
+
+var driftTypeFamilies = map[string]string{
+	"smallint": "int", "integer": "int", "int": "int", "bigint": "int", "mediumint": "int", "tinyint": "int",
+	"real": "float", "double precision": "float", "double": "float", "float": "float",
+	"numeric": "numeric",
+	"text":    "string", "varchar": "string", "char": "string", "tinytext": "string", "mediumtext": "string", "longtext": "string",
+	"json": "json", "jsonb": "json",
+	"timestamp": "time", "timestamptz": "time", "datetime": "time",
+	"bytea": "bytes", "blob": "bytes", "tinyblob": "bytes", "mediumblob": "bytes", "longblob": "bytes", "varbinary": "bytes",
+}
+
+// driftTypeFamily family of compatible types(values of the same family scan into the same proto kind)
+func driftTypeFamily(dbType string) string {
+	array := strings.HasSuffix(dbType, "[]")
+	base, _, _ := strings.Cut(strings.TrimSuffix(dbType, "[]"), "(")
+	family, ok := driftTypeFamilies[base]
+	if !ok {
+		family = base
+	}
+	if array {
+		family += "[]"
+	}
+	return family
+}
@@ -0,0 +1,126 @@
+package ddl
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	_ "modernc.org/sqlite"
+)
+
+func TestCheckDrift_SQLite(t *testing.T) {
+	fd := schemaTestFile(t, "ddl_drift_test.proto", map[string][]string{
+		"t_dr_parent": {},
+		"t_dr_child":  {"parent_id:t_dr_parent(id)", "code"},
+		"t_dr_absent": {},
+	}, nil)
+	parent := dynamicpb.NewMessage(fd.Messages().ByName("tdrparent"))
+	child := dynamicpb.NewMessage(fd.Messages().ByName("tdrchild"))
+	absent := dynamicpb.NewMessage(fd.Messages().ByName("tdrabsent"))
+
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "drift.db"))
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	items, err := GenerateSchemaSql(sqldb.SQLite, "", child)
+	if err != nil {
+		t.Fatalf("GenerateSchemaSql: %v", err)
+	}
+	if err := ExecSql(db, items); err != nil {
+		t.Fatalf("ExecSql: %v", err)
+	}
+
+	report, err := CheckDrift(db, parent, child)
+	if err != nil {
+		t.Fatalf("CheckDrift: %v", err)
+	}
+	if len(report.Items) != 0 || report.Err(DriftInfo) != nil {
+		t.Fatalf("expected no drift after create, got %v", report.Err(DriftInfo))
+	}
+
+	for _, stmt := range []string{
+		`DROP TABLE t_dr_child`,
+		`CREATE TABLE t_dr_child (id integer PRIMARY KEY, parent_id text NOT NULL, legacy integer NOT NULL, note text)`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	report, err = CheckDrift(db, child, absent)
+	if err != nil {
+		t.Fatalf("CheckDrift: %v", err)
+	}
+	got := map[string]TDriftSeverity{}
+	for _, item := range report.Items {
+		got[item.Kind+" "+item.Column] = item.Severity
+	}
+	want := map[string]TDriftSeverity{
+		"type_mismatch parent_id":       DriftCritical,
+		"nullability parent_id":         DriftCritical,
+		"missing_foreign_key parent_id": DriftWarning,
+		"missing_column code":           DriftCritical,
+		"extra_column legacy":           DriftCritical,
+		"extra_column note":             DriftInfo,
+		"missing_unique_index ":         DriftWarning,
+		"missing_table ":                DriftCritical,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected drift items:\n%v", report.Err(DriftInfo))
+	}
+	for key, severity := range want {
+		if s, ok := got[key]; !ok || s != severity {
+			t.Fatalf("%s = %v(%v), want %v:\n%v", key, s, ok, severity, report.Err(DriftInfo))
+		}
+	}
+	if !report.HasCritical() || strings.Contains(report.Err(DriftCritical).Error(), "extra_column note") {
+		t.Fatalf("critical error should skip info items:\n%v", report.Err(DriftCritical))
+	}
+
+	rec := httptest.NewRecorder()
+	DriftHealthHandler(db, "", child).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	var body struct {
+		Items []struct {
+			Kind     string `json:"kind"`
+			Severity string `json:"severity"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || rec.Code != http.StatusServiceUnavailable || len(body.Items) == 0 || body.Items[0].Severity != "critical" {
+		t.Fatalf("health = %d %v: %s", rec.Code, err, rec.Body.String())
+	}
+	rec = httptest.NewRecorder()
+	DriftHealthHandler(db, "", parent).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("health of parent = %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestNormalizeDriftType(t *testing.T) {
+	cases := []struct {
+		dialect  sqldb.TDBDialect
+		a, b     string
+		same     bool
+		sameKind bool
+	}{
+		{sqldb.Postgres, "bigserial", "bigint", true, true},
+		{sqldb.Postgres, "NUMERIC(20, 4)", "numeric(20,4)", true, true},
+		{sqldb.Postgres, "varchar(64)", "varchar(128)", false, true},
+		{sqldb.Postgres, "int8[]", "bigint[]", true, true},
+		{sqldb.Postgres, "text", "bigint", false, false},
+		{sqldb.Mysql, "bigint AUTO_INCREMENT", "bigint", true, true},
+		{sqldb.Mysql, "integer", "int", true, true},
+		{sqldb.Mysql, "text", "longtext", false, true},
+	}
+	for _, c := range cases {
+		a, b := normalizeDriftType(c.dialect, c.a), normalizeDriftType(c.dialect, c.b)
+		if (a == b) != c.same || (driftTypeFamily(a) == driftTypeFamily(b)) != c.sameKind {
+			t.Errorf("%s %q(%q) vs %q(%q): same=%v family=%v", c.dialect, c.a, a, c.b, b, a == b, driftTypeFamily(a) == driftTypeFamily(b))
+		}
+	}
+}
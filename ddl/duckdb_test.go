@@ -0,0 +1,52 @@
+package ddl
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/sqldb"
+)
+
+func TestGetSqlTypeStr_DuckDB(t *testing.T) {
+	_, _, idField, numsField, subsField := buildDDLArrayDescriptors(t)
+
+	if got := getSqlTypeStr(idField, &protodb.PDBField{}, sqldb.DuckDB); got != "BIGINT" {
+		t.Fatalf("id type = %q", got)
+	}
+	if got := getSqlTypeStr(numsField, &protodb.PDBField{}, sqldb.DuckDB); got != "BIGINT[]" {
+		t.Fatalf("uint64 list type = %q", got)
+	}
+	if got := getSqlTypeStr(subsField, &protodb.PDBField{}, sqldb.DuckDB); got != "JSON" {
+		t.Fatalf("message list type = %q", got)
+	}
+	where2 := (&protodb.TableQueryReq{}).ProtoReflect().Descriptor().Fields().ByName("Where2")
+	if got := getSqlTypeStr(where2, &protodb.PDBField{}, sqldb.DuckDB); got != "MAP(VARCHAR, VARCHAR)" {
+		t.Fatalf("map type = %q", got)
+	}
+}
+
+func TestIsDialectTableExists_DuckDB(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	query, _ := sqldb.DuckDB.Dialect().TableExistsQuery("", "User")
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
+		WithArgs("main", "User").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	exists, err := isDialectTableExists(db, sqldb.DuckDB, "", "User")
+	if err != nil {
+		t.Fatalf("isDialectTableExists: %v", err)
+	}
+	if !exists {
+		t.Fatal("expected table to exist")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
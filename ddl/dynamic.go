@@ -0,0 +1,37 @@
+package ddl
+
+import (
+	"database/sql"
+
+	"github.com/ygrpc/protodb/msgstore"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// DbMigrateFileDescriptorSet register the table messages of fds(see msgstore.RegisterFileDescriptorSet),
+// then create or migrate their tables. return the registered messages
+func DbMigrateFileDescriptorSet(db *sql.DB, fds *descriptorpb.FileDescriptorSet, dbschema string) ([]protoreflect.MessageDescriptor, error) {
+	msgDescs, err := msgstore.RegisterFileDescriptorSet(fds)
+	if err != nil {
+		return nil, err
+	}
+	if err := DbMigrateMsgDescriptors(db, msgDescs, dbschema); err != nil {
+		return nil, err
+	}
+	return msgDescs, nil
+}
+
+// DbMigrateMsgDescriptors create or migrate the tables of msgDescs, messages are built by dynamicpb
+func DbMigrateMsgDescriptors(db *sql.DB, msgDescs []protoreflect.MessageDescriptor, dbschema string) error {
+	builtInitSqlMap := make(map[string]*TDbTableInitSql)
+	items := make([]*TDbTableInitSql, 0, len(msgDescs))
+	for _, msgDesc := range msgDescs {
+		item, err := DbMigrateTable(db, dynamicpb.NewMessage(msgDesc), dbschema, true, false, builtInitSqlMap)
+		if err != nil {
+			return err
+		}
+		items = append(items, item)
+	}
+	return ExecSql(db, items)
+}
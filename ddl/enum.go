@@ -0,0 +1,165 @@
+package ddl
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	postgresEnumLabelsSQL = "SELECT e.enumlabel FROM pg_enum e WHERE e.enumtypid = to_regtype($1) ORDER BY e.enumsortorder"
+	mysqlColumnTypeSQL    = "SELECT column_type FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? AND column_name = ?"
+)
+
+// EnumDbTypeName name of the postgres native enum type for proto enum, like myapp_user_status
+func EnumDbTypeName(enumDesc protoreflect.EnumDescriptor) string {
+	return strings.ToLower(strings.ReplaceAll(string(enumDesc.FullName()), ".", "_"))
+}
+
+func enumValueNames(enumDesc protoreflect.EnumDescriptor) []string {
+	values := enumDesc.Values()
+	names := make([]string, 0, values.Len())
+	for i := 0; i < values.Len(); i++ {
+		names = append(names, string(values.Get(i).Name()))
+	}
+	return names
+}
+
+func quoteEnumLabels(names []string) string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, "'"+strings.ReplaceAll(name, "'", "''")+"'")
+	}
+	return strings.Join(quoted, ",")
+}
+
+// isNativeEnumField enum field stored in native db enum type
+func isNativeEnumField(fieldDesc protoreflect.FieldDescriptor) bool {
+	return pdbutil.IsEnumStoredAsName(fieldDesc) && pdbutil.GetEnumStorage(fieldDesc) == protodb.EnumStorage_EnumAsNative
+}
+
+// usePostgresEnumType the field column type is a native enum type created by protodb
+func usePostgresEnumType(fieldDesc protoreflect.FieldDescriptor, pdb *protodb.PDBField) bool {
+	return len(pdb.DbTypeStr) == 0 && pdb.DbType == protodb.FieldDbType_AutoMatch && isNativeEnumField(fieldDesc)
+}
+
+// enumSqlTypeStr db type of enum field stored by name, ok is false for integer storage
+func enumSqlTypeStr(fieldDesc protoreflect.FieldDescriptor, dialect sqldb.TDBDialect) (string, bool) {
+	if !pdbutil.IsEnumStoredAsName(fieldDesc) {
+		return "", false
+	}
+	if !isNativeEnumField(fieldDesc) {
+		return "text", true
+	}
+	return columnTypeOrText(dialect, sqldb.ColumnType{
+		Kind:       protoreflect.EnumKind,
+		EnumName:   EnumDbTypeName(fieldDesc.Enum()),
+		EnumValues: enumValueNames(fieldDesc.Enum()),
+	}), true
+}
+
+// createPostgresEnumTypeSql create enum type, ignore if exists
+func createPostgresEnumTypeSql(enumDesc protoreflect.EnumDescriptor) string {
+	return fmt.Sprintf("DO $$ BEGIN CREATE TYPE %s AS ENUM (%s); EXCEPTION WHEN duplicate_object THEN NULL; END $$;\n",
+		EnumDbTypeName(enumDesc), quoteEnumLabels(enumValueNames(enumDesc)))
+}
+
+// createPostgresEnumTypesSql create sql of native enum types used by the message fields
+func createPostgresEnumTypesSql(msgFieldDescs protoreflect.FieldDescriptors) string {
+	sqlStr := ""
+	created := map[protoreflect.FullName]struct{}{}
+	for _, column := range msgTableColumns(msgFieldDescs) {
+		fieldDesc, pdb := column.fieldDesc, column.pdb
+		if pdb.NotDB || !usePostgresEnumType(fieldDesc, pdb) {
+			continue
+		}
+		enumDesc := fieldDesc.Enum()
+		if _, ok := created[enumDesc.FullName()]; ok {
+			continue
+		}
+		created[enumDesc.FullName()] = struct{}{}
+		sqlStr += createPostgresEnumTypeSql(enumDesc)
+	}
+	return sqlStr
+}
+
+// migratePostgresEnumTypesSql create missing native enum types and add new enum values
+func migratePostgresEnumTypesSql(db *sql.DB, msgFieldDescs protoreflect.FieldDescriptors) ([]string, error) {
+	var sqls []string
+	migrated := map[protoreflect.FullName]struct{}{}
+	for _, column := range msgTableColumns(msgFieldDescs) {
+		fieldDesc, pdb := column.fieldDesc, column.pdb
+		if pdb.NotDB || !usePostgresEnumType(fieldDesc, pdb) {
+			continue
+		}
+		enumDesc := fieldDesc.Enum()
+		if _, ok := migrated[enumDesc.FullName()]; ok {
+			continue
+		}
+		migrated[enumDesc.FullName()] = struct{}{}
+
+		typeName := EnumDbTypeName(enumDesc)
+		labels, err := getPostgresEnumLabels(db, typeName)
+		if err != nil {
+			return nil, err
+		}
+		if len(labels) == 0 {
+			sqls = append(sqls, createPostgresEnumTypeSql(enumDesc))
+			continue
+		}
+		for _, name := range enumValueNames(enumDesc) {
+			if _, ok := labels[name]; !ok {
+				// ADD VALUE can not run in a transaction block before postgres 12
+				sqls = append(sqls, fmt.Sprintf("ALTER TYPE %s ADD VALUE IF NOT EXISTS %s;", typeName, quoteEnumLabels([]string{name})))
+			}
+		}
+	}
+	return sqls, nil
+}
+
+func getPostgresEnumLabels(db *sql.DB, typeName string) (map[string]struct{}, error) {
+	rows, err := db.Query(postgresEnumLabelsSQL, typeName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting enum type %s labels: %w", typeName, err)
+	}
+	defer rows.Close()
+
+	labels := map[string]struct{}{}
+	for rows.Next() {
+		var label string
+		if err := rows.Scan(&label); err != nil {
+			return nil, fmt.Errorf("error scanning enum type %s labels: %w", typeName, err)
+		}
+		labels[label] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating enum type %s labels: %w", typeName, err)
+	}
+	return labels, nil
+}
+
+// migrateMysqlEnumColumnSql modify existing native enum column when enum values changed
+func migrateMysqlEnumColumnSql(db *sql.DB, dbtableName string, columnName string, fieldDesc protoreflect.FieldDescriptor, pdb *protodb.PDBField, sqlType string) (string, error) {
+	var columnType string
+	err := db.QueryRow(mysqlColumnTypeSQL, dbtableName, columnName).Scan(&columnType)
+	if err != nil {
+		return "", fmt.Errorf("error getting column type %s.%s: %w", dbtableName, columnName, err)
+	}
+	if strings.ReplaceAll(columnType, " ", "") == strings.ReplaceAll(sqlType, " ", "") {
+		return "", nil
+	}
+
+	alterStmt := fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s %s", sqldb.Mysql.QuoteIdentifier(dbtableName), sqldb.Mysql.QuoteIdentifier(columnName), sqlType)
+	if pdb.NotNull {
+		alterStmt += " NOT NULL "
+	}
+	if len(pdb.DefaultValue) > 0 {
+		alterStmt += " DEFAULT " + TryAddQuote2DefaultValue(fieldDesc.Kind(), pdb.DefaultValue)
+	}
+	return alterStmt + ";", nil
+}
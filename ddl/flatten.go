@@ -0,0 +1,52 @@
+package ddl
+
+import (
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/pdbutil"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// tableColumn db column of message field
+type tableColumn struct {
+	name string
+	// field name, dotted path for flattened sub message columns
+	path      string
+	fieldDesc protoreflect.FieldDescriptor
+	pdb       *protodb.PDBField
+}
+
+// msgTableColumns db columns of message fields in order, flattened sub message field expands to its columns.
+// Primary/Unique of sub message fields are ignored, they belong to the sub message not the table
+func msgTableColumns(msgFieldDescs protoreflect.FieldDescriptors) []tableColumn {
+	columns := make([]tableColumn, 0, msgFieldDescs.Len())
+	for i := 0; i < msgFieldDescs.Len(); i++ {
+		fieldDesc := msgFieldDescs.Get(i)
+		pdb, _ := pdbutil.GetPDB(fieldDesc)
+		if !pdbutil.IsFlattenField(fieldDesc) {
+			columns = append(columns, tableColumn{name: pdbutil.GetColumnName(fieldDesc), path: string(fieldDesc.Name()), fieldDesc: fieldDesc, pdb: oneofMemberPdb(fieldDesc, pdb)})
+			continue
+		}
+		for _, flatColumn := range pdbutil.GetFlatColumns(fieldDesc) {
+			columnPdb, _ := pdbutil.GetPDB(flatColumn.Field())
+			if columnPdb.Primary || columnPdb.Unique || len(columnPdb.UniqueName) > 0 {
+				columnPdb = proto.Clone(columnPdb).(*protodb.PDBField)
+				columnPdb.Primary = false
+				columnPdb.Unique = false
+				columnPdb.UniqueName = ""
+			}
+			columns = append(columns, tableColumn{name: flatColumn.Name, path: flatColumn.DottedPath(), fieldDesc: flatColumn.Field(), pdb: oneofMemberPdb(flatColumn.Field(), columnPdb)})
+		}
+	}
+	return columns
+}
+
+// oneofMemberPdb oneof member column is NULL when another member is set, so it can not be NOT NULL
+func oneofMemberPdb(fieldDesc protoreflect.FieldDescriptor, pdb *protodb.PDBField) *protodb.PDBField {
+	if !pdb.NotNull || pdbutil.GetRealOneof(fieldDesc) == nil {
+		return pdb
+	}
+	pdb = proto.Clone(pdb).(*protodb.PDBField)
+	pdb.NotNull = false
+	return pdb
+}
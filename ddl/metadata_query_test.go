@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ygrpc/protodb/sqldb"
 )
 
 func TestIsPostgresqlTableExistsUsesQueryArgs(t *testing.T) {
@@ -15,7 +16,8 @@ func TestIsPostgresqlTableExistsUsesQueryArgs(t *testing.T) {
 	}
 	defer db.Close()
 
-	mock.ExpectQuery(regexp.QuoteMeta(postgresTableExistsSQL)).
+	query, _ := sqldb.Postgres.Dialect().TableExistsQuery("", "user")
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
 		WithArgs("public", "user'; drop table x;--").
 		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
 
@@ -39,7 +41,8 @@ func TestIsSQLiteTableExistsUsesQueryArgs(t *testing.T) {
 	defer db.Close()
 
 	tableName := "user'; drop table x;--"
-	mock.ExpectQuery(regexp.QuoteMeta(sqliteTableExistsSQL)).
+	query, _ := sqldb.SQLite.Dialect().TableExistsQuery("", tableName)
+	mock.ExpectQuery(regexp.QuoteMeta(query)).
 		WithArgs(tableName).
 		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 
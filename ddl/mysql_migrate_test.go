@@ -97,7 +97,7 @@ func TestCreateOneUniqueKeySql_MysqlOmitsIfNotExists(t *testing.T) {
 	if strings.Contains(sqlStr, "IF NOT EXISTS") {
 		t.Fatalf("mysql unique index sql should not contain IF NOT EXISTS: %s", sqlStr)
 	}
-	if compactSQL(sqlStr) != "CREATE UNIQUE INDEX uk_TestMsg_email ON TestMsg ( email ) ;" {
+	if compactSQL(sqlStr) != "CREATE UNIQUE INDEX `uk_TestMsg_email` ON TestMsg ( `email` ) ;" {
 		t.Fatalf("unexpected mysql unique index sql: %s", sqlStr)
 	}
 }
@@ -127,7 +127,7 @@ func TestDbMigrateTableMysql_AddsMissingColumnsAndUniqueIndex(t *testing.T) {
 		DepTableNames:      make([]string, 0),
 		DepTableSqlItemMap: make(map[string]*TDbTableInitSql),
 	}
-	got, err := dbMigrateTableMysql(migrateItem, db, msg, "", "TestMsg", msgDesc, msgDesc.Fields(), false, false, map[string]*TDbTableInitSql{})
+	got, err := DefaultDdl.dbMigrateTableMysql(migrateItem, db, msg, "", "TestMsg", msgDesc, msgDesc.Fields(), false, false, map[string]*TDbTableInitSql{})
 	if err != nil {
 		t.Fatalf("dbMigrateTableMysql: %v", err)
 	}
@@ -136,19 +136,57 @@ func TestDbMigrateTableMysql_AddsMissingColumnsAndUniqueIndex(t *testing.T) {
 	}
 
 	all := strings.Join(got.SqlStr, "\n")
-	if !strings.Contains(compactSQL(all), "ALTER TABLE TestMsg ADD COLUMN email text NOT NULL ;") {
+	if !strings.Contains(compactSQL(all), "ALTER TABLE `TestMsg` ADD COLUMN `email` text NOT NULL ;") {
 		t.Fatalf("missing email alter sql: %s", all)
 	}
-	if !strings.Contains(compactSQL(all), "ALTER TABLE TestMsg ADD COLUMN tags json NOT NULL DEFAULT (CAST('[]' AS JSON));") {
+	if !strings.Contains(compactSQL(all), "ALTER TABLE `TestMsg` ADD COLUMN `tags` json NOT NULL DEFAULT (CAST('[]' AS JSON));") {
 		t.Fatalf("missing tags alter sql: %s", all)
 	}
-	if !strings.Contains(compactSQL(all), "ALTER TABLE TestMsg ADD COLUMN subs json NOT NULL DEFAULT (CAST('[]' AS JSON));") {
+	if !strings.Contains(compactSQL(all), "ALTER TABLE `TestMsg` ADD COLUMN `subs` json NOT NULL DEFAULT (CAST('[]' AS JSON));") {
 		t.Fatalf("missing subs alter sql: %s", all)
 	}
-	if !strings.Contains(compactSQL(all), "CREATE UNIQUE INDEX uk_TestMsg_email ON TestMsg ( email ) ;") {
+	if !strings.Contains(compactSQL(all), "CREATE UNIQUE INDEX `uk_TestMsg_email` ON `TestMsg` ( `email` ) ;") {
 		t.Fatalf("missing unique index sql: %s", all)
 	}
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Fatalf("expectations: %v", err)
 	}
 }
+
+func TestDbMigrateTableMysql_RecreatesChangedUniqueIndexQuoted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	msg, msgDesc := buildDDLMySQLDescriptors(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?)")).
+		WithArgs("TestMsg").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT column_name FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ?")).
+		WithArgs("TestMsg").
+		WillReturnRows(sqlmock.NewRows([]string{"column_name"}).AddRow("id").AddRow("email").AddRow("tags").AddRow("subs"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT column_name FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = ? AND index_name = ? ORDER BY seq_in_index")).
+		WithArgs("TestMsg", "uk_TestMsg_email").
+		WillReturnRows(sqlmock.NewRows([]string{"column_name"}).AddRow("id"))
+
+	migrateItem := &TDbTableInitSql{
+		TableName:          "TestMsg",
+		SqlStr:             make([]string, 0),
+		DepTableNames:      make([]string, 0),
+		DepTableSqlItemMap: make(map[string]*TDbTableInitSql),
+	}
+	got, err := DefaultDdl.dbMigrateTableMysql(migrateItem, db, msg, "", "TestMsg", msgDesc, msgDesc.Fields(), false, false, map[string]*TDbTableInitSql{})
+	if err != nil {
+		t.Fatalf("dbMigrateTableMysql: %v", err)
+	}
+	all := compactSQL(strings.Join(got.SqlStr, "\n"))
+	if !strings.Contains(all, "drop index `uk_TestMsg_email` on `TestMsg`; CREATE UNIQUE INDEX `uk_TestMsg_email` ON `TestMsg` ( `email` ) ;") {
+		t.Fatalf("missing quoted drop and create of unique index: %s", all)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
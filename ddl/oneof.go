@@ -0,0 +1,43 @@
+package ddl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// oneofDiscriminatorSqlType column type of oneof discriminator, it stores the member field name
+func oneofDiscriminatorSqlType(dialect sqldb.TDBDialect) string {
+	return columnTypeOrText(dialect, sqldb.ColumnType{Kind: protoreflect.StringKind, MaxLength: 255})
+}
+
+// oneofDiscriminatorAfter discriminator column to create after the field,
+// it follows the last member of a oneof of the table message
+func oneofDiscriminatorAfter(msgDesc protoreflect.MessageDescriptor, fieldDesc protoreflect.FieldDescriptor) (columnName string, ok bool) {
+	oneofDesc := pdbutil.GetRealOneof(fieldDesc)
+	if oneofDesc == nil || oneofDesc.Parent().FullName() != msgDesc.FullName() {
+		return "", false
+	}
+	members := oneofDesc.Fields()
+	if members.Get(members.Len()-1).Number() != fieldDesc.Number() {
+		return "", false
+	}
+	return pdbutil.GetOneofDiscriminator(oneofDesc)
+}
+
+// migrateOneofDiscriminatorSql add missing discriminator columns
+func migrateOneofDiscriminatorSql(dbtableName string, msgDesc protoreflect.MessageDescriptor, existingColumns map[string]bool, dialect sqldb.TDBDialect) (alterStatements []string) {
+	oneofs := msgDesc.Oneofs()
+	for i := 0; i < oneofs.Len(); i++ {
+		columnName, ok := pdbutil.GetOneofDiscriminator(oneofs.Get(i))
+		if !ok || existingColumns[strings.ToLower(columnName)] {
+			continue
+		}
+		alterStatements = append(alterStatements, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s NULL;",
+			dbtableName, dialect.QuoteIdentifier(columnName), oneofDiscriminatorSqlType(dialect)))
+	}
+	return alterStatements
+}
@@ -0,0 +1,900 @@
+package ddl
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	postgresTablesSQL         = "SELECT table_name FROM information_schema.tables WHERE table_schema = $1 AND table_type = 'BASE TABLE' ORDER BY table_name"
+	postgresColumnsDetailSQL  = "SELECT column_name, data_type, udt_name, is_nullable, column_default, character_maximum_length, numeric_precision, numeric_scale FROM information_schema.columns WHERE table_schema = $1 AND table_name = $2 ORDER BY ordinal_position"
+	postgresIndexesSQL        = "SELECT indexname, indexdef FROM pg_indexes WHERE schemaname = $1 AND tablename = $2 ORDER BY indexname"
+	postgresKeyConstraintsSQL = "SELECT tc.constraint_name, tc.constraint_type, kcu.column_name, COALESCE(ccu.table_name, ''), COALESCE(ccu.column_name, '') " +
+		"FROM information_schema.table_constraints tc " +
+		"JOIN information_schema.key_column_usage kcu ON tc.constraint_schema = kcu.constraint_schema AND tc.constraint_name = kcu.constraint_name AND tc.table_name = kcu.table_name " +
+		"LEFT JOIN information_schema.constraint_column_usage ccu ON tc.constraint_type = 'FOREIGN KEY' AND tc.constraint_schema = ccu.constraint_schema AND tc.constraint_name = ccu.constraint_name " +
+		"WHERE tc.table_schema = $1 AND tc.table_name = $2 AND tc.constraint_type IN ('PRIMARY KEY', 'FOREIGN KEY') " +
+		"ORDER BY tc.constraint_name, kcu.ordinal_position"
+
+	mysqlTablesSQL        = "SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE' ORDER BY table_name"
+	mysqlColumnsDetailSQL = "SELECT column_name, column_type, is_nullable, column_default, extra FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? ORDER BY ordinal_position"
+	mysqlIndexesSQL       = "SELECT index_name, non_unique, column_name FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = ? ORDER BY index_name, seq_in_index"
+	mysqlForeignKeysSQL   = "SELECT column_name, referenced_table_name, referenced_column_name FROM information_schema.key_column_usage WHERE table_schema = DATABASE() AND table_name = ? AND referenced_table_name IS NOT NULL ORDER BY constraint_name, ordinal_position"
+
+	sqliteTablesSQL          = "SELECT name FROM sqlite_master WHERE type='table' AND name NOT LIKE 'sqlite_%' ORDER BY name"
+	sqliteTableInfoDetailSQL = "SELECT name, type, \"notnull\", dflt_value, pk FROM pragma_table_info(?) ORDER BY cid"
+	sqliteIndexListSQL       = "SELECT name, \"unique\", origin FROM pragma_index_list(?) ORDER BY name"
+	sqliteForeignKeysSQL     = "SELECT \"from\", \"table\", \"to\" FROM pragma_foreign_key_list(?) ORDER BY id, seq"
+)
+
+// TDbColumnSchema column definition read from db catalog
+type TDbColumnSchema struct {
+	Name string
+	// normalized db type, like: integer, varchar(64), text[]
+	DbType string
+	// postgres array column
+	IsArray bool
+	NotNull bool
+	// default value in sql syntax, empty if no default
+	DefaultValue string
+	// serial type 0:not serial type 2:smallint 4:integer 8:bigint
+	SerialType int32
+	Primary    bool
+	Unique     bool
+	// unique index name, empty if it is the protodb default name
+	UniqueName string
+	// reference like: other_table(other_field)
+	Reference string
+}
+
+// TDbTableSchema table definition read from db catalog
+type TDbTableSchema struct {
+	DbSchema  string
+	TableName string
+	Columns   []*TDbColumnSchema
+}
+
+// TReverseProtoOptions options for generate proto from db
+type TReverseProtoOptions struct {
+	// proto package name
+	Package string
+	// option go_package
+	GoPackage string
+	// import path of protodb.proto, default: protodb.proto
+	ProtodbImport string
+}
+
+var errNoReverseTable = errors.New("no table found")
+
+var protoIdentRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// DbReverseProto introspect tables of db and generate .proto file content with protodb options
+// dbschema is pg schema(default public) or table name prefix for mysql/sqlite like DbCreateSQL
+// tableNames empty means all tables in dbschema
+func DbReverseProto(db *sql.DB, dbschema string, tableNames []string, opts TReverseProtoOptions) (string, error) {
+	dialect := sqldb.GetDBDialect(db)
+	tables, err := dbIntrospectTables(db, dialect, dbschema, tableNames)
+	if err != nil {
+		return "", err
+	}
+	return GenerateProtoFromTables(tables, dialect, opts)
+}
+
+// DbIntrospectTables read table definitions from db catalog
+// tableNames empty means all tables in dbschema
+func DbIntrospectTables(db *sql.DB, dbschema string, tableNames []string) ([]*TDbTableSchema, error) {
+	return dbIntrospectTables(db, sqldb.GetDBDialect(db), dbschema, tableNames)
+}
+
+func dbIntrospectTables(db *sql.DB, dialect sqldb.TDBDialect, dbschema string, tableNames []string) ([]*TDbTableSchema, error) {
+	if dialect == sqldb.Postgres && len(dbschema) == 0 {
+		dbschema = "public"
+	}
+
+	if len(tableNames) == 0 {
+		var err error
+		tableNames, err = listDbTables(db, dialect, dbschema)
+		if err != nil {
+			return nil, err
+		}
+		if len(tableNames) == 0 {
+			return nil, errNoReverseTable
+		}
+	}
+
+	tables := make([]*TDbTableSchema, 0, len(tableNames))
+	for _, tableName := range tableNames {
+		var table *TDbTableSchema
+		var err error
+		switch dialect {
+		case sqldb.Postgres:
+			table, err = introspectTablePostgres(db, dbschema, tableName)
+		case sqldb.Mysql:
+			table, err = introspectTableMysql(db, dbschema, tableName)
+		case sqldb.SQLite:
+			table, err = introspectTableSQLite(db, dbschema, tableName)
+		default:
+			return nil, fmt.Errorf("reverse proto not support db dialect: %s", dialect)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("introspect table %s err: %w", tableName, err)
+		}
+		tables = append(tables, table)
+	}
+
+	return tables, nil
+}
+
+// listDbTables list table names in dbschema, mysql/sqlite table name prefix is trimmed
+func listDbTables(db *sql.DB, dialect sqldb.TDBDialect, dbschema string) ([]string, error) {
+	var rows *sql.Rows
+	var err error
+	switch dialect {
+	case sqldb.Postgres:
+		rows, err = db.Query(postgresTablesSQL, dbschema)
+	case sqldb.Mysql:
+		rows, err = db.Query(mysqlTablesSQL)
+	case sqldb.SQLite:
+		rows, err = db.Query(sqliteTablesSQL)
+	default:
+		return nil, fmt.Errorf("reverse proto not support db dialect: %s", dialect)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error listing tables: %w", err)
+	}
+	defer rows.Close()
+
+	tableNames := []string{}
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, fmt.Errorf("error scanning tables: %w", err)
+		}
+		if dialect != sqldb.Postgres && len(dbschema) > 0 {
+			if !strings.HasPrefix(tableName, dbschema) {
+				continue
+			}
+			tableName = strings.TrimPrefix(tableName, dbschema)
+		}
+		tableNames = append(tableNames, tableName)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tables: %w", err)
+	}
+
+	return tableNames, nil
+}
+
+func introspectTablePostgres(db *sql.DB, dbschema string, tableName string) (*TDbTableSchema, error) {
+	table := &TDbTableSchema{DbSchema: dbschema, TableName: tableName}
+
+	rows, err := db.Query(postgresColumnsDetailSQL, dbschema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting table columns: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var columnName, dataType, udtName, isNullable string
+		var columnDefault sql.NullString
+		var charMaxLen, numPrecision, numScale sql.NullInt64
+		if err := rows.Scan(&columnName, &dataType, &udtName, &isNullable, &columnDefault, &charMaxLen, &numPrecision, &numScale); err != nil {
+			return nil, fmt.Errorf("error scanning table columns: %w", err)
+		}
+
+		column := &TDbColumnSchema{
+			Name:    columnName,
+			NotNull: strings.EqualFold(isNullable, "NO"),
+		}
+		column.DbType, column.IsArray = postgresColumnType(dataType, udtName, charMaxLen, numPrecision, numScale)
+
+		if columnDefault.Valid {
+			defaultValue := strings.TrimSpace(columnDefault.String)
+			if strings.HasPrefix(strings.ToLower(defaultValue), "nextval(") {
+				column.SerialType = serialTypeOfDbType(column.DbType)
+			}
+			if column.SerialType == 0 {
+				column.DefaultValue = trimPostgresDefaultCast(defaultValue)
+			}
+		}
+
+		table.Columns = append(table.Columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating table columns: %w", err)
+	}
+	rows.Close()
+
+	if len(table.Columns) == 0 {
+		return nil, fmt.Errorf("table %s.%s not found", dbschema, tableName)
+	}
+
+	//primary key and reference
+	pkIndexName := ""
+	keyRows, err := db.Query(postgresKeyConstraintsSQL, dbschema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting table constraints: %w", err)
+	}
+	defer keyRows.Close()
+
+	for keyRows.Next() {
+		var constraintName, constraintType, columnName, refTable, refColumn string
+		if err := keyRows.Scan(&constraintName, &constraintType, &columnName, &refTable, &refColumn); err != nil {
+			return nil, fmt.Errorf("error scanning table constraints: %w", err)
+		}
+		column := table.column(columnName)
+		if column == nil {
+			continue
+		}
+		switch constraintType {
+		case "PRIMARY KEY":
+			column.Primary = true
+			pkIndexName = constraintName
+		case "FOREIGN KEY":
+			if len(refTable) > 0 && len(refColumn) > 0 {
+				column.Reference = refTable + "(" + refColumn + ")"
+			}
+		}
+	}
+	if err := keyRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating table constraints: %w", err)
+	}
+	keyRows.Close()
+
+	//unique index, protodb create unique key by create unique index
+	idxRows, err := db.Query(postgresIndexesSQL, dbschema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting table indexes: %w", err)
+	}
+	defer idxRows.Close()
+
+	uniqueIndexes := map[string][]string{}
+	for idxRows.Next() {
+		var indexName, indexDef string
+		if err := idxRows.Scan(&indexName, &indexDef); err != nil {
+			return nil, fmt.Errorf("error scanning table indexes: %w", err)
+		}
+		if indexName == pkIndexName {
+			continue
+		}
+		if !strings.HasPrefix(strings.ToUpper(strings.TrimSpace(indexDef)), "CREATE UNIQUE INDEX") {
+			continue
+		}
+		uniqueIndexes[indexName] = parsePostgresqlIndexColumns(indexDef)
+	}
+	if err := idxRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating table indexes: %w", err)
+	}
+
+	table.applyUniqueIndexes(uniqueIndexes, sqldb.Postgres, "")
+
+	return table, nil
+}
+
+func introspectTableMysql(db *sql.DB, dbschema string, tableName string) (*TDbTableSchema, error) {
+	table := &TDbTableSchema{DbSchema: dbschema, TableName: tableName}
+	dbtableName := sqldb.BuildDbTableName(tableName, dbschema, sqldb.Mysql)
+
+	rows, err := db.Query(mysqlColumnsDetailSQL, dbtableName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting table columns: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var columnName, columnType, isNullable, extra string
+		var columnDefault sql.NullString
+		if err := rows.Scan(&columnName, &columnType, &isNullable, &columnDefault, &extra); err != nil {
+			return nil, fmt.Errorf("error scanning table columns: %w", err)
+		}
+
+		column := &TDbColumnSchema{
+			Name:    columnName,
+			DbType:  mysqlColumnType(columnType),
+			NotNull: strings.EqualFold(isNullable, "NO"),
+		}
+		extra = strings.ToLower(extra)
+		if strings.Contains(extra, "auto_increment") {
+			column.SerialType = serialTypeOfDbType(column.DbType)
+		}
+		if columnDefault.Valid && column.SerialType == 0 {
+			column.DefaultValue = mysqlDefaultValue(columnDefault.String, column.DbType, strings.Contains(extra, "default_generated"))
+		}
+
+		table.Columns = append(table.Columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating table columns: %w", err)
+	}
+	rows.Close()
+
+	if len(table.Columns) == 0 {
+		return nil, fmt.Errorf("table %s not found", dbtableName)
+	}
+
+	idxRows, err := db.Query(mysqlIndexesSQL, dbtableName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting table indexes: %w", err)
+	}
+	defer idxRows.Close()
+
+	uniqueIndexes := map[string][]string{}
+	for idxRows.Next() {
+		var indexName, columnName string
+		var nonUnique int
+		if err := idxRows.Scan(&indexName, &nonUnique, &columnName); err != nil {
+			return nil, fmt.Errorf("error scanning table indexes: %w", err)
+		}
+		if indexName == "PRIMARY" {
+			if column := table.column(columnName); column != nil {
+				column.Primary = true
+			}
+			continue
+		}
+		if nonUnique != 0 {
+			continue
+		}
+		uniqueIndexes[indexName] = append(uniqueIndexes[indexName], columnName)
+	}
+	if err := idxRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating table indexes: %w", err)
+	}
+	idxRows.Close()
+
+	table.applyUniqueIndexes(uniqueIndexes, sqldb.Mysql, dbschema)
+
+	fkRows, err := db.Query(mysqlForeignKeysSQL, dbtableName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting table foreign keys: %w", err)
+	}
+	defer fkRows.Close()
+
+	for fkRows.Next() {
+		var columnName, refTable, refColumn string
+		if err := fkRows.Scan(&columnName, &refTable, &refColumn); err != nil {
+			return nil, fmt.Errorf("error scanning table foreign keys: %w", err)
+		}
+		if column := table.column(columnName); column != nil {
+			column.Reference = strings.TrimPrefix(refTable, dbschema) + "(" + refColumn + ")"
+		}
+	}
+	if err := fkRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating table foreign keys: %w", err)
+	}
+
+	return table, nil
+}
+
+func introspectTableSQLite(db *sql.DB, dbschema string, tableName string) (*TDbTableSchema, error) {
+	table := &TDbTableSchema{DbSchema: dbschema, TableName: tableName}
+	dbtableName := dbschema + tableName
+
+	rows, err := db.Query(sqliteTableInfoDetailSQL, dbtableName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting table columns: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var columnName, columnType string
+		var notNull, pk int
+		var defaultValue sql.NullString
+		if err := rows.Scan(&columnName, &columnType, &notNull, &defaultValue, &pk); err != nil {
+			return nil, fmt.Errorf("error scanning table columns: %w", err)
+		}
+
+		column := &TDbColumnSchema{
+			Name:    columnName,
+			DbType:  strings.ToLower(strings.TrimSpace(columnType)),
+			NotNull: notNull != 0,
+			Primary: pk > 0,
+		}
+		if defaultValue.Valid {
+			column.DefaultValue = strings.TrimSpace(defaultValue.String)
+		}
+
+		table.Columns = append(table.Columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating table columns: %w", err)
+	}
+	rows.Close()
+
+	if len(table.Columns) == 0 {
+		return nil, fmt.Errorf("table %s not found", dbtableName)
+	}
+
+	idxRows, err := db.Query(sqliteIndexListSQL, dbtableName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting table indexes: %w", err)
+	}
+	defer idxRows.Close()
+
+	uniqueIndexNames := []string{}
+	for idxRows.Next() {
+		var indexName, origin string
+		var unique int
+		if err := idxRows.Scan(&indexName, &unique, &origin); err != nil {
+			return nil, fmt.Errorf("error scanning table indexes: %w", err)
+		}
+		if unique == 0 || origin == "pk" {
+			continue
+		}
+		uniqueIndexNames = append(uniqueIndexNames, indexName)
+	}
+	if err := idxRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating table indexes: %w", err)
+	}
+	idxRows.Close()
+
+	uniqueIndexes := map[string][]string{}
+	for _, indexName := range uniqueIndexNames {
+		columns, err := getSqliteIndexColumns(db, indexName)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(indexName, "sqlite_autoindex_") {
+			//inline unique constraint has no user name
+			indexName = "uk_" + dbtableName + "_" + strings.Join(columns, "_")
+		}
+		uniqueIndexes[indexName] = columns
+	}
+
+	table.applyUniqueIndexes(uniqueIndexes, sqldb.SQLite, dbschema)
+
+	fkRows, err := db.Query(sqliteForeignKeysSQL, dbtableName)
+	if err != nil {
+		return nil, fmt.Errorf("error getting table foreign keys: %w", err)
+	}
+	defer fkRows.Close()
+
+	for fkRows.Next() {
+		var columnName, refTable string
+		var refColumn sql.NullString
+		if err := fkRows.Scan(&columnName, &refTable, &refColumn); err != nil {
+			return nil, fmt.Errorf("error scanning table foreign keys: %w", err)
+		}
+		column := table.column(columnName)
+		if column == nil || !refColumn.Valid {
+			continue
+		}
+		column.Reference = strings.TrimPrefix(refTable, dbschema) + "(" + refColumn.String + ")"
+	}
+	if err := fkRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating table foreign keys: %w", err)
+	}
+
+	return table, nil
+}
+
+// getSqliteIndexColumns get index columns in index order
+func getSqliteIndexColumns(db *sql.DB, idxName string) ([]string, error) {
+	rows, err := db.Query(sqliteIndexInfoSQL, idxName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := []string{}
+	for rows.Next() {
+		var columnName string
+		if err := rows.Scan(&columnName); err != nil {
+			return nil, err
+		}
+		columns = append(columns, columnName)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+func (t *TDbTableSchema) column(name string) *TDbColumnSchema {
+	for _, column := range t.Columns {
+		if strings.EqualFold(column.Name, name) {
+			return column
+		}
+	}
+	return nil
+}
+
+// applyUniqueIndexes set Unique/UniqueName of columns, a column only keep the first unique index by name
+func (t *TDbTableSchema) applyUniqueIndexes(uniqueIndexes map[string][]string, dialect sqldb.TDBDialect, dbschema string) {
+	indexNames := make([]string, 0, len(uniqueIndexes))
+	for indexName := range uniqueIndexes {
+		indexNames = append(indexNames, indexName)
+	}
+	sort.Strings(indexNames)
+
+	for _, indexName := range indexNames {
+		columnNames := uniqueIndexes[indexName]
+		columns := make([]*TDbColumnSchema, 0, len(columnNames))
+		for _, columnName := range columnNames {
+			column := t.column(columnName)
+			if column == nil || column.Unique {
+				// expression index or column already in other unique index
+				columns = nil
+				break
+			}
+			columns = append(columns, column)
+		}
+		if len(columns) == 0 {
+			continue
+		}
+
+		uniqueName := indexName
+		if dialect != sqldb.Postgres && len(dbschema) > 0 {
+			uniqueName = strings.TrimPrefix(uniqueName, dbschema+"_")
+		}
+		if len(columns) == 1 && indexName == defaultUniqueIndexName(dialect, dbschema, t.TableName, columns[0].Name) {
+			uniqueName = ""
+		}
+
+		for _, column := range columns {
+			column.Unique = true
+			column.UniqueName = uniqueName
+		}
+	}
+}
+
+// defaultUniqueIndexName the unique index name used by DbCreateSQL when UniqueName is empty
+func defaultUniqueIndexName(dialect sqldb.TDBDialect, dbschema string, tableName string, fieldName string) string {
+	if dialect != sqldb.Postgres && len(dbschema) > 0 {
+		return fmt.Sprintf("uk_%s_%s_%s", dbschema, tableName, fieldName)
+	}
+	return fmt.Sprintf("uk_%s_%s", tableName, fieldName)
+}
+
+var postgresArrayElemTypes = map[string]string{
+	"int2":        "smallint",
+	"int4":        "integer",
+	"int8":        "bigint",
+	"float4":      "real",
+	"float8":      "double precision",
+	"bool":        "boolean",
+	"bpchar":      "char",
+	"timestamptz": "timestamptz",
+}
+
+func postgresColumnType(dataType string, udtName string, charMaxLen, numPrecision, numScale sql.NullInt64) (dbType string, isArray bool) {
+	switch dataType {
+	case "character varying":
+		if charMaxLen.Valid {
+			return fmt.Sprintf("varchar(%d)", charMaxLen.Int64), false
+		}
+		return "varchar", false
+	case "character":
+		if charMaxLen.Valid {
+			return fmt.Sprintf("char(%d)", charMaxLen.Int64), false
+		}
+		return "char", false
+	case "numeric":
+		if numPrecision.Valid {
+			return fmt.Sprintf("numeric(%d,%d)", numPrecision.Int64, numScale.Int64), false
+		}
+		return "numeric", false
+	case "timestamp without time zone":
+		return "timestamp", false
+	case "timestamp with time zone":
+		return "timestamptz", false
+	case "time without time zone":
+		return "time", false
+	case "time with time zone":
+		return "timetz", false
+	case "USER-DEFINED":
+		return udtName, false
+	case "ARRAY":
+		elemType := strings.TrimPrefix(udtName, "_")
+		if t, ok := postgresArrayElemTypes[elemType]; ok {
+			elemType = t
+		}
+		return elemType + "[]", true
+	default:
+		return dataType, false
+	}
+}
+
+var mysqlIntDisplayWidthRegexp = regexp.MustCompile(`^(tinyint|smallint|mediumint|int|bigint)\(\d+\)`)
+
+func mysqlColumnType(columnType string) string {
+	columnType = strings.ToLower(strings.TrimSpace(columnType))
+	if columnType == "tinyint(1)" {
+		return "boolean"
+	}
+	//mysql 5.7 int(11) -> int
+	return mysqlIntDisplayWidthRegexp.ReplaceAllString(columnType, "$1")
+}
+
+func mysqlDefaultValue(defaultValue string, dbType string, isExpression bool) string {
+	if isExpression || strings.EqualFold(defaultValue, "CURRENT_TIMESTAMP") {
+		return defaultValue
+	}
+	_, kind := dbType2ProtoType(sqldb.Mysql, dbType)
+	if isNumericKind(kind) {
+		return defaultValue
+	}
+	//mysql column_default is the literal value without quote
+	return "'" + strings.ReplaceAll(defaultValue, "'", "''") + "'"
+}
+
+var postgresLiteralCastRegexp = regexp.MustCompile(`^('(?:[^']|'')*')::[a-zA-Z0-9_ \[\]".]+$`)
+
+// trimPostgresDefaultCast 'abc'::character varying -> 'abc'
+func trimPostgresDefaultCast(defaultValue string) string {
+	if strings.HasPrefix(strings.ToUpper(defaultValue), "NULL::") {
+		return ""
+	}
+	if m := postgresLiteralCastRegexp.FindStringSubmatch(defaultValue); m != nil {
+		return m[1]
+	}
+	return defaultValue
+}
+
+func serialTypeOfDbType(dbType string) int32 {
+	switch strings.TrimSuffix(dbType, " unsigned") {
+	case "smallint":
+		return 2
+	case "integer", "int", "mediumint":
+		return 4
+	case "bigint":
+		return 8
+	}
+	return 0
+}
+
+func isNumericKind(kind protoreflect.Kind) bool {
+	switch kind {
+	case protoreflect.BoolKind,
+		protoreflect.Int32Kind, protoreflect.Int64Kind,
+		protoreflect.Uint32Kind, protoreflect.Uint64Kind,
+		protoreflect.FloatKind, protoreflect.DoubleKind:
+		return true
+	}
+	return false
+}
+
+// dbType2ProtoType map normalized db type to proto scalar type
+func dbType2ProtoType(dialect sqldb.TDBDialect, dbType string) (protoType string, kind protoreflect.Kind) {
+	unsigned := strings.HasSuffix(dbType, " unsigned")
+	baseType, _, _ := strings.Cut(strings.TrimSuffix(dbType, " unsigned"), "(")
+	baseType = strings.TrimSpace(baseType)
+
+	switch baseType {
+	case "boolean", "bool":
+		return "bool", protoreflect.BoolKind
+	case "tinyint", "smallint", "mediumint", "int", "int2", "int4":
+		if unsigned {
+			return "uint32", protoreflect.Uint32Kind
+		}
+		return "int32", protoreflect.Int32Kind
+	case "integer":
+		if dialect == sqldb.SQLite {
+			//sqlite integer is 64-bit
+			return "int64", protoreflect.Int64Kind
+		}
+		if unsigned {
+			return "uint32", protoreflect.Uint32Kind
+		}
+		return "int32", protoreflect.Int32Kind
+	case "bigint", "int8":
+		if unsigned {
+			return "uint64", protoreflect.Uint64Kind
+		}
+		return "int64", protoreflect.Int64Kind
+	case "real":
+		if dialect == sqldb.SQLite {
+			return "double", protoreflect.DoubleKind
+		}
+		return "float", protoreflect.FloatKind
+	case "float", "float4":
+		return "float", protoreflect.FloatKind
+	case "double precision", "double", "float8":
+		return "double", protoreflect.DoubleKind
+	case "bytea", "blob", "tinyblob", "mediumblob", "longblob", "binary", "varbinary":
+		return "bytes", protoreflect.BytesKind
+	default:
+		return "string", protoreflect.StringKind
+	}
+}
+
+// parseNumericDbType parse numeric(p,s)/decimal(p,s), precision is 0 for unconstrained numeric
+func parseNumericDbType(dialect sqldb.TDBDialect, dbType string) (precision int, scale int, ok bool) {
+	if dialect == sqldb.SQLite {
+		//sqlite numeric affinity does not keep exact decimals
+		return 0, 0, false
+	}
+	baseType, args, hasArgs := strings.Cut(dbType, "(")
+	switch strings.TrimSpace(baseType) {
+	case "numeric", "decimal":
+	default:
+		return 0, 0, false
+	}
+	if !hasArgs {
+		return 0, 0, true
+	}
+	args = strings.TrimSuffix(strings.TrimSpace(args), ")")
+	precisionStr, scaleStr, _ := strings.Cut(args, ",")
+	precision, err := strconv.Atoi(strings.TrimSpace(precisionStr))
+	if err != nil {
+		return 0, 0, false
+	}
+	if len(strings.TrimSpace(scaleStr)) > 0 {
+		scale, err = strconv.Atoi(strings.TrimSpace(scaleStr))
+		if err != nil {
+			return 0, 0, false
+		}
+	}
+	return precision, scale, true
+}
+
+// reverseFieldDbType find DbType or DbTypeStr for db type which GetProtoDBType can not infer
+func reverseFieldDbType(dialect sqldb.TDBDialect, column *TDbColumnSchema, kind protoreflect.Kind) (protodb.FieldDbType, string) {
+	autoType := protodb.GetProtoDBType(kind, dialect)
+	if column.IsArray {
+		autoType += "[]"
+	}
+	if column.DbType == autoType {
+		return protodb.FieldDbType_AutoMatch, ""
+	}
+	if column.IsArray {
+		return protodb.FieldDbType_AutoMatch, column.DbType
+	}
+
+	dbTypes := make([]int, 0, len(protodb.FieldDbType_name))
+	for v := range protodb.FieldDbType_name {
+		if v != int32(protodb.FieldDbType_AutoMatch) {
+			dbTypes = append(dbTypes, int(v))
+		}
+	}
+	sort.Ints(dbTypes)
+	for _, v := range dbTypes {
+		pdb := &protodb.PDBField{DbType: protodb.FieldDbType(v)}
+		if pdb.PdbDbTypeStr(nil, dialect) == column.DbType {
+			return pdb.DbType, ""
+		}
+	}
+
+	return protodb.FieldDbType_AutoMatch, column.DbType
+}
+
+// GenerateProtoFromTables generate .proto file content with protodb options from table definitions
+func GenerateProtoFromTables(tables []*TDbTableSchema, dialect sqldb.TDBDialect, opts TReverseProtoOptions) (string, error) {
+	if len(opts.ProtodbImport) == 0 {
+		opts.ProtodbImport = "protodb.proto"
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString("// Code generated by protodb reverse from " + dialect.String() + " database.\n\n")
+	sb.WriteString("syntax = \"proto3\";\n\n")
+	if len(opts.Package) > 0 {
+		sb.WriteString("package " + opts.Package + ";\n\n")
+	}
+	sb.WriteString("import \"" + opts.ProtodbImport + "\";\n\n")
+	if len(opts.GoPackage) > 0 {
+		sb.WriteString("option go_package = \"" + opts.GoPackage + "\";\n\n")
+	}
+
+	for i, table := range tables {
+		if !protoIdentRegexp.MatchString(table.TableName) {
+			return "", fmt.Errorf("table name %s is not a valid proto identifier", table.TableName)
+		}
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString("message " + table.TableName + " {\n")
+
+		for fieldNo, column := range table.Columns {
+			if !protoIdentRegexp.MatchString(column.Name) {
+				return "", fmt.Errorf("column name %s.%s is not a valid proto identifier", table.TableName, column.Name)
+			}
+			sb.WriteString("  ")
+			sb.WriteString(reverseProtoField(dialect, column, fieldNo+1))
+			sb.WriteString("\n")
+		}
+
+		sb.WriteString("}\n")
+	}
+
+	return sb.String(), nil
+}
+
+func reverseProtoField(dialect sqldb.TDBDialect, column *TDbColumnSchema, fieldNo int) string {
+	elemType := column.DbType
+	if column.IsArray {
+		elemType = strings.TrimSuffix(elemType, "[]")
+	}
+	protoType, kind := dbType2ProtoType(dialect, elemType)
+
+	options := []string{}
+	addOption := func(name string, value string) {
+		options = append(options, "(protodb.pdb)."+name+" = "+value)
+	}
+
+	if column.Primary {
+		addOption("Primary", "true")
+	}
+	if column.Unique {
+		addOption("Unique", "true")
+		if len(column.UniqueName) > 0 {
+			addOption("UniqueName", quoteProtoString(column.UniqueName))
+		}
+	}
+	if column.NotNull && !column.Primary && !column.IsArray {
+		addOption("NotNull", "true")
+	}
+	if column.SerialType != 0 {
+		addOption("SerialType", strconv.Itoa(int(column.SerialType)))
+	} else if precision, scale, ok := parseNumericDbType(dialect, column.DbType); ok {
+		addOption("DbType", protodb.FieldDbType_NUMERIC.String())
+		if precision > 0 {
+			addOption("NumericPrecision", strconv.Itoa(precision))
+			addOption("NumericScale", strconv.Itoa(scale))
+		}
+	} else {
+		dbType, dbTypeStr := reverseFieldDbType(dialect, column, kind)
+		if dbType != protodb.FieldDbType_AutoMatch {
+			addOption("DbType", dbType.String())
+		}
+		if len(dbTypeStr) > 0 {
+			addOption("DbTypeStr", quoteProtoString(dbTypeStr))
+		}
+	}
+	if defaultValue := reverseDefaultValue(column, kind); len(defaultValue) > 0 {
+		addOption("DefaultValue", quoteProtoString(defaultValue))
+	}
+	if len(column.Reference) > 0 {
+		addOption("Reference", quoteProtoString(column.Reference))
+	}
+
+	field := ""
+	if column.IsArray {
+		field = "repeated "
+	}
+	field += protoType + " " + column.Name + " = " + strconv.Itoa(fieldNo)
+	if len(options) > 0 {
+		field += " [" + strings.Join(options, ", ") + "]"
+	}
+	return field + ";"
+}
+
+// reverseDefaultValue convert db default to DefaultValue which DbCreateSQL can emit again
+func reverseDefaultValue(column *TDbColumnSchema, kind protoreflect.Kind) string {
+	defaultValue := strings.TrimSpace(column.DefaultValue)
+	if len(defaultValue) == 0 || strings.EqualFold(defaultValue, "NULL") {
+		return ""
+	}
+	if column.IsArray {
+		//DbCreateSQL add empty array default for repeated field
+		if defaultValue == "'{}'" || defaultValue == "'[]'" {
+			return ""
+		}
+		return defaultValue
+	}
+	if isNumericKind(kind) {
+		return defaultValue
+	}
+	if strings.HasPrefix(defaultValue, "'") || strings.HasSuffix(defaultValue, ")") {
+		return defaultValue
+	}
+	if _, err := strconv.ParseFloat(defaultValue, 64); err == nil {
+		return defaultValue
+	}
+	//sql expression like CURRENT_TIMESTAMP, keep it from being quoted as string
+	return "(" + defaultValue + ")"
+}
+
+func quoteProtoString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
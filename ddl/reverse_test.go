@@ -0,0 +1,161 @@
+package ddl
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ygrpc/protodb/sqldb"
+)
+
+func TestReverseProto_Postgres(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(postgresTablesSQL)).
+		WithArgs("public").
+		WillReturnRows(sqlmock.NewRows([]string{"table_name"}).AddRow("users"))
+
+	mock.ExpectQuery(regexp.QuoteMeta(postgresColumnsDetailSQL)).
+		WithArgs("public", "users").
+		WillReturnRows(sqlmock.NewRows([]string{"column_name", "data_type", "udt_name", "is_nullable", "column_default", "character_maximum_length", "numeric_precision", "numeric_scale"}).
+			AddRow("id", "bigint", "int8", "NO", "nextval('users_id_seq'::regclass)", nil, 64, 0).
+			AddRow("email", "character varying", "varchar", "NO", nil, 128, nil, nil).
+			AddRow("name", "text", "text", "YES", "'anonymous'::text", nil, nil, nil).
+			AddRow("settings", "jsonb", "jsonb", "NO", "'{}'::jsonb", nil, nil, nil).
+			AddRow("created_at", "timestamp with time zone", "timestamptz", "YES", "now()", nil, nil, nil).
+			AddRow("tags", "ARRAY", "_text", "NO", "'{}'::text[]", nil, nil, nil).
+			AddRow("org_id", "integer", "int4", "YES", nil, nil, 32, 0).
+			AddRow("tenant", "text", "text", "YES", nil, nil, nil, nil).
+			AddRow("code", "text", "text", "YES", nil, nil, nil, nil))
+
+	mock.ExpectQuery(regexp.QuoteMeta(postgresKeyConstraintsSQL)).
+		WithArgs("public", "users").
+		WillReturnRows(sqlmock.NewRows([]string{"constraint_name", "constraint_type", "column_name", "ref_table", "ref_column"}).
+			AddRow("users_org_id_fkey", "FOREIGN KEY", "org_id", "org", "id").
+			AddRow("users_pkey", "PRIMARY KEY", "id", "", ""))
+
+	mock.ExpectQuery(regexp.QuoteMeta(postgresIndexesSQL)).
+		WithArgs("public", "users").
+		WillReturnRows(sqlmock.NewRows([]string{"indexname", "indexdef"}).
+			AddRow("idx_users_name", "CREATE INDEX idx_users_name ON public.users USING btree (name)").
+			AddRow("uk_tenant_code", "CREATE UNIQUE INDEX uk_tenant_code ON public.users USING btree (tenant, code)").
+			AddRow("uk_users_email", "CREATE UNIQUE INDEX uk_users_email ON public.users USING btree (email)").
+			AddRow("users_pkey", "CREATE UNIQUE INDEX users_pkey ON public.users USING btree (id)"))
+
+	tables, err := dbIntrospectTables(db, sqldb.Postgres, "", nil)
+	if err != nil {
+		t.Fatalf("dbIntrospectTables: %v", err)
+	}
+	protoStr, err := GenerateProtoFromTables(tables, sqldb.Postgres, TReverseProtoOptions{Package: "myapp", GoPackage: "github.com/my/app"})
+	if err != nil {
+		t.Fatalf("GenerateProtoFromTables: %v", err)
+	}
+
+	wants := []string{
+		`package myapp;`,
+		`import "protodb.proto";`,
+		`option go_package = "github.com/my/app";`,
+		`message users {`,
+		`int64 id = 1 [(protodb.pdb).Primary = true, (protodb.pdb).SerialType = 8];`,
+		`string email = 2 [(protodb.pdb).Unique = true, (protodb.pdb).NotNull = true, (protodb.pdb).DbTypeStr = "varchar(128)"];`,
+		`string name = 3 [(protodb.pdb).DefaultValue = "'anonymous'"];`,
+		`string settings = 4 [(protodb.pdb).NotNull = true, (protodb.pdb).DbType = JSONB, (protodb.pdb).DefaultValue = "'{}'"];`,
+		`string created_at = 5 [(protodb.pdb).DbTypeStr = "timestamptz", (protodb.pdb).DefaultValue = "now()"];`,
+		`repeated string tags = 6;`,
+		`int32 org_id = 7 [(protodb.pdb).Reference = "org(id)"];`,
+		`string tenant = 8 [(protodb.pdb).Unique = true, (protodb.pdb).UniqueName = "uk_tenant_code"];`,
+		`string code = 9 [(protodb.pdb).Unique = true, (protodb.pdb).UniqueName = "uk_tenant_code"];`,
+	}
+	for _, want := range wants {
+		if !strings.Contains(protoStr, want) {
+			t.Fatalf("expected %q in:\n%s", want, protoStr)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestReverseProto_SQLite(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(sqliteTableInfoDetailSQL)).
+		WithArgs("orders").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "type", "notnull", "dflt_value", "pk"}).
+			AddRow("id", "INTEGER", 1, nil, 1).
+			AddRow("user_id", "integer", 0, nil, 0).
+			AddRow("amount", "double precision", 1, "0", 0).
+			AddRow("state", "text", 1, "CURRENT_TIMESTAMP", 0).
+			AddRow("serial_no", "varchar(32)", 0, nil, 0))
+
+	mock.ExpectQuery(regexp.QuoteMeta(sqliteIndexListSQL)).
+		WithArgs("orders").
+		WillReturnRows(sqlmock.NewRows([]string{"name", "unique", "origin"}).
+			AddRow("sqlite_autoindex_orders_1", 1, "u"))
+
+	mock.ExpectQuery(regexp.QuoteMeta(sqliteIndexInfoSQL)).
+		WithArgs("sqlite_autoindex_orders_1").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("serial_no"))
+
+	mock.ExpectQuery(regexp.QuoteMeta(sqliteForeignKeysSQL)).
+		WithArgs("orders").
+		WillReturnRows(sqlmock.NewRows([]string{"from", "table", "to"}).AddRow("user_id", "users", "id"))
+
+	tables, err := dbIntrospectTables(db, sqldb.SQLite, "", []string{"orders"})
+	if err != nil {
+		t.Fatalf("dbIntrospectTables: %v", err)
+	}
+	protoStr, err := GenerateProtoFromTables(tables, sqldb.SQLite, TReverseProtoOptions{})
+	if err != nil {
+		t.Fatalf("GenerateProtoFromTables: %v", err)
+	}
+
+	wants := []string{
+		`int64 id = 1 [(protodb.pdb).Primary = true];`,
+		`int64 user_id = 2 [(protodb.pdb).Reference = "users(id)"];`,
+		`double amount = 3 [(protodb.pdb).NotNull = true, (protodb.pdb).DbType = DOUBLE, (protodb.pdb).DefaultValue = "0"];`,
+		`string state = 4 [(protodb.pdb).NotNull = true, (protodb.pdb).DefaultValue = "(CURRENT_TIMESTAMP)"];`,
+		`string serial_no = 5 [(protodb.pdb).Unique = true, (protodb.pdb).DbTypeStr = "varchar(32)"];`,
+	}
+	for _, want := range wants {
+		if !strings.Contains(protoStr, want) {
+			t.Fatalf("expected %q in:\n%s", want, protoStr)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestReverseProto_MysqlColumnTypes(t *testing.T) {
+	cases := map[string]string{
+		"int(11)":          "int",
+		"tinyint(1)":       "boolean",
+		"int(10) unsigned": "int unsigned",
+		"VARCHAR(64)":      "varchar(64)",
+	}
+	for columnType, want := range cases {
+		if got := mysqlColumnType(columnType); got != want {
+			t.Fatalf("mysqlColumnType(%q) = %q, want %q", columnType, got, want)
+		}
+	}
+
+	protoType, _ := dbType2ProtoType(sqldb.Mysql, "int unsigned")
+	if protoType != "uint32" {
+		t.Fatalf("expected uint32, got %s", protoType)
+	}
+	if got := mysqlDefaultValue("it's", "varchar(10)", false); got != "'it''s'" {
+		t.Fatalf("unexpected mysql default: %s", got)
+	}
+}
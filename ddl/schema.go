@@ -0,0 +1,148 @@
+package ddl
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// GenerateSchemaSql create sql of the tables of msgs and the tables they reference, with the comments,
+// referenced tables are found in msgstore.GlobalMsgStore and ordered before the tables referencing them.
+// no database is needed, the sql is of dialect. a reference cycle is an error with its path,
+// like t_a.b_id -> t_b.a_id -> t_a
+func GenerateSchemaSql(dialect sqldb.TDBDialect, dbschema string, msgs ...proto.Message) ([]*TDbTableInitSql, error) {
+	return DefaultDdl.GenerateSchemaSql(dialect, dbschema, msgs...)
+}
+
+// GenerateSchemaSql see GenerateSchemaSql, referenced tables are found in this.Msgs, no comments if this.NoComment
+func (this *TDdl) GenerateSchemaSql(dialect sqldb.TDBDialect, dbschema string, msgs ...proto.Message) ([]*TDbTableInitSql, error) {
+	g := &schemaGenerator{
+		ddl:      this,
+		dialect:  dialect,
+		dbschema: dbschema,
+		built:    make(map[string]*TDbTableInitSql),
+		visiting: make(map[string]int),
+	}
+	for _, msg := range msgs {
+		if err := g.visit(msg); err != nil {
+			return nil, err
+		}
+	}
+	return g.ordered, nil
+}
+
+// GenerateSchema one script of GenerateSchemaSql, tables are separated by a blank line
+func GenerateSchema(dialect sqldb.TDBDialect, dbschema string, msgs ...proto.Message) (string, error) {
+	return DefaultDdl.GenerateSchema(dialect, dbschema, msgs...)
+}
+
+// GenerateSchema see GenerateSchema, referenced tables are found in this.Msgs
+func (this *TDdl) GenerateSchema(dialect sqldb.TDBDialect, dbschema string, msgs ...proto.Message) (string, error) {
+	items, err := this.GenerateSchemaSql(dialect, dbschema, msgs...)
+	if err != nil {
+		return "", err
+	}
+	sb := strings.Builder{}
+	for i, item := range items {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		for _, sqlStr := range item.SqlStr {
+			sb.WriteString(strings.TrimRight(sqlStr, " \r\n\t"))
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String(), nil
+}
+
+// GenerateRegisteredSchema GenerateSchema of every table message(not NotDB) registered in msgstore.GlobalMsgStore
+func GenerateRegisteredSchema(dialect sqldb.TDBDialect, dbschema string) (string, error) {
+	return DefaultDdl.GenerateRegisteredSchema(dialect, dbschema)
+}
+
+// GenerateRegisteredSchema GenerateSchema of every table message(not NotDB) registered in this.Msgs
+func (this *TDdl) GenerateRegisteredSchema(dialect sqldb.TDBDialect, dbschema string) (string, error) {
+	var msgs []proto.Message
+	for _, msgDesc := range this.Msgs.MsgDescriptors() {
+		if pdbm, _ := pdbutil.GetPDBM(msgDesc); pdbm.NotDB {
+			continue
+		}
+		msgs = append(msgs, dynamicpb.NewMessage(msgDesc))
+	}
+	return this.GenerateSchema(dialect, dbschema, msgs...)
+}
+
+// schemaGenerator depth first visit of the reference graph, a table is built after its references
+type schemaGenerator struct {
+	ddl      *TDdl
+	dialect  sqldb.TDBDialect
+	dbschema string
+	built    map[string]*TDbTableInitSql
+	ordered  []*TDbTableInitSql
+	// path table.column of the references being visited, visiting table name -> its index in path
+	path     []string
+	visiting map[string]int
+}
+
+func (this *schemaGenerator) visit(msg proto.Message) error {
+	msgDesc := msg.ProtoReflect().Descriptor()
+	msgFieldDescs := msgDesc.Fields()
+	tableName := pdbutil.GetTableName(msgDesc)
+	if _, ok := this.built[tableName]; ok {
+		return nil
+	}
+	if i, ok := this.visiting[tableName]; ok {
+		cycle := append(append([]string{}, this.path[i:]...), tableName)
+		return fmt.Errorf("reference cycle %s", strings.Join(cycle, " -> "))
+	}
+	this.visiting[tableName] = len(this.path)
+	defer delete(this.visiting, tableName)
+
+	var depTableNames []string
+	for _, column := range msgTableColumns(msgFieldDescs) {
+		if column.pdb.NotDB || len(column.pdb.Reference) == 0 {
+			continue
+		}
+		refTableName, err := GetRefTableName(column.pdb.Reference)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", tableName, column.name, err)
+		}
+		if refTableName == tableName {
+			// self reference
+			continue
+		}
+		refMsg, found := this.ddl.Msgs.GetMsg(refTableName, false)
+		if !found {
+			return fmt.Errorf("reference table msg %s not found for %s.%s", refTableName, tableName, column.name)
+		}
+
+		this.path = append(this.path, tableName+"."+column.name)
+		err = this.visit(refMsg)
+		this.path = this.path[:len(this.path)-1]
+		if err != nil {
+			return err
+		}
+		depTableNames = append(depTableNames, refTableName)
+	}
+
+	item, err := this.ddl.dbCreateSQL(nil, this.dialect, msg, this.dbschema, tableName, msgDesc, msgFieldDescs, false, !this.ddl.NoComment, nil)
+	if err != nil {
+		return fmt.Errorf("table %s: %w", tableName, err)
+	}
+	item.DbSchema = this.dbschema
+	for _, depTableName := range depTableNames {
+		if _, ok := item.DepTableSqlItemMap[depTableName]; ok {
+			continue
+		}
+		item.DepTableNames = append(item.DepTableNames, depTableName)
+		item.DepTableSqlItemMap[depTableName] = this.built[depTableName]
+	}
+
+	this.built[tableName] = item
+	this.ordered = append(this.ordered, item)
+	return nil
+}
@@ -0,0 +1,152 @@
+package ddl
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/msgstore"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// schemaTestFile file of table messages, fields are name:reference, id is the int64 primary key
+func schemaTestFile(t *testing.T, fileName string, tables map[string][]string, appendsEnd map[string][]string) protoreflect.FileDescriptor {
+	t.Helper()
+	fdp := &descriptorpb.FileDescriptorProto{
+		Syntax:     strPtr("proto3"),
+		Name:       strPtr(fileName),
+		Package:    strPtr("schematest"),
+		Dependency: []string{"protodb.proto"},
+	}
+	for _, tableName := range sortedKeys(tables) {
+		msgOpts := &descriptorpb.MessageOptions{}
+		proto.SetExtension(msgOpts, protodb.E_Pdbm, &protodb.PDBMsg{TableName: tableName, Comment: []string{tableName + " table"}, SQLAppendsEnd: appendsEnd[tableName]})
+		idOpts := &descriptorpb.FieldOptions{}
+		proto.SetExtension(idOpts, protodb.E_Pdb, &protodb.PDBField{Primary: true})
+		msg := &descriptorpb.DescriptorProto{
+			Name:    strPtr(strings.ReplaceAll(tableName, "_", "")),
+			Options: msgOpts,
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: strPtr("id"), Number: int32Ptr(1), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), Type: descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum(), Options: idOpts},
+			},
+		}
+		for i, field := range tables[tableName] {
+			name, reference, _ := strings.Cut(field, ":")
+			opts := &descriptorpb.FieldOptions{}
+			proto.SetExtension(opts, protodb.E_Pdb, &protodb.PDBField{Reference: reference, Unique: len(reference) == 0})
+			msg.Field = append(msg.Field, &descriptorpb.FieldDescriptorProto{
+				Name: strPtr(name), Number: int32Ptr(int32(i + 2)), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type: descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum(), Options: opts,
+			})
+		}
+		fdp.MessageType = append(fdp.MessageType, msg)
+	}
+
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile: %v", err)
+	}
+	for i := 0; i < fd.Messages().Len(); i++ {
+		msgstore.RegisterDynamicMsg(fd.Messages().Get(i))
+	}
+	return fd
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestGenerateSchema_ReferenceOrder(t *testing.T) {
+	fd := schemaTestFile(t, "ddl_schema_order_test.proto", map[string][]string{
+		"t_gs_order":    {"customer_id:t_gs_customer(id)", "region_id:t_gs_region(id)", "code"},
+		"t_gs_customer": {"region_id:t_gs_region(id)"},
+		"t_gs_region":   {"parent_id:t_gs_region(id)"},
+	}, map[string][]string{
+		"t_gs_customer": {"CREATE INDEX IF NOT EXISTS idx_gs_customer_region ON t_gs_customer(region_id);"},
+	})
+
+	script, err := GenerateSchema(sqldb.Postgres, "", dynamicpb.NewMessage(fd.Messages().ByName("tgsorder")))
+	if err != nil {
+		t.Fatalf("GenerateSchema: %v", err)
+	}
+	region := strings.Index(script, `CREATE TABLE  IF NOT EXISTS "t_gs_region"`)
+	customer := strings.Index(script, `CREATE TABLE  IF NOT EXISTS "t_gs_customer"`)
+	order := strings.Index(script, `CREATE TABLE  IF NOT EXISTS "t_gs_order"`)
+	if region < 0 || customer < region || order < customer {
+		t.Fatalf("tables not in reference order:\n%s", script)
+	}
+	for _, want := range []string{
+		"-- t_gs_order table\n",
+		"uk_t_gs_order_code",
+		"idx_gs_customer_region",
+		`REFERENCES t_gs_region(id)`,
+	} {
+		if !strings.Contains(script, want) {
+			t.Fatalf("script missing %q:\n%s", want, script)
+		}
+	}
+	if strings.Count(script, `CREATE TABLE  IF NOT EXISTS "t_gs_region"`) != 1 {
+		t.Fatalf("referenced table generated more than once:\n%s", script)
+	}
+	noComment, err := (&TDdl{Msgs: msgstore.GlobalMsgStore, NoComment: true}).GenerateSchema(sqldb.Postgres, "", dynamicpb.NewMessage(fd.Messages().ByName("tgsorder")))
+	if err != nil || strings.Contains(noComment, "-- t_gs_order table") || !strings.Contains(noComment, `"t_gs_order"`) {
+		t.Fatalf("GenerateSchema without comments: %v\n%s", err, noComment)
+	}
+
+	items, err := GenerateSchemaSql(sqldb.SQLite, "app_", dynamicpb.NewMessage(fd.Messages().ByName("tgsorder")))
+	if err != nil {
+		t.Fatalf("GenerateSchemaSql: %v", err)
+	}
+	if len(items) != 3 || items[2].TableName != "t_gs_order" || strings.Join(items[2].DepTableNames, ",") != "t_gs_customer,t_gs_region" ||
+		items[2].DbSchema != "app_" || !strings.Contains(items[0].SqlStr[0], `"app_t_gs_region"`) {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+}
+
+func TestGenerateSchema_Cycle(t *testing.T) {
+	fd := schemaTestFile(t, "ddl_schema_cycle_test.proto", map[string][]string{
+		"t_gs_a": {"b_id:t_gs_b(id)"},
+		"t_gs_b": {"c_id:t_gs_c(id)"},
+		"t_gs_c": {"a_id:t_gs_a(id)"},
+	}, nil)
+
+	_, err := GenerateSchema(sqldb.Postgres, "", dynamicpb.NewMessage(fd.Messages().ByName("tgsa")))
+	if err == nil || !strings.Contains(err.Error(), "t_gs_a.b_id -> t_gs_b.c_id -> t_gs_c.a_id -> t_gs_a") {
+		t.Fatalf("expected cycle path error, got %v", err)
+	}
+}
+
+func TestTDdl_ReferencesOfMsgStore(t *testing.T) {
+	fd := schemaTestFile(t, "ddl_schema_store_test.proto", map[string][]string{
+		"t_gs_store_child":  {"parent_id:t_gs_store_parent(id)"},
+		"t_gs_store_parent": {},
+	}, nil)
+	child := dynamicpb.NewMessage(fd.Messages().ByName("tgsstorechild"))
+
+	empty := &TDdl{Msgs: msgstore.NewMsgStore()}
+	if _, err := empty.GenerateSchemaSql(sqldb.Postgres, "", child); err == nil || !strings.Contains(err.Error(), "reference table msg t_gs_store_parent not found") {
+		t.Fatalf("expected the reference to be resolved by the empty store, got %v", err)
+	}
+
+	store := msgstore.NewMsgStore()
+	store.RegisterDynamicMsg(fd.Messages().ByName("tgsstoreparent"))
+	items, err := (&TDdl{Msgs: store}).GenerateSchemaSql(sqldb.Postgres, "", child)
+	if err != nil {
+		t.Fatalf("GenerateSchemaSql: %v", err)
+	}
+	if len(items) != 2 || items[0].TableName != "t_gs_store_parent" || items[1].TableName != "t_gs_store_child" {
+		t.Fatalf("unexpected tables: %v", items)
+	}
+}
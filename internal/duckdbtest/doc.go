@@ -0,0 +1,5 @@
+// Package duckdbtest runs protodb against the real cgo DuckDB driver.
+// it is a separate module so the root go.mod does not require the driver, run it with
+//
+//	cd internal/duckdbtest && go test ./...
+package duckdbtest
@@ -0,0 +1,196 @@
+//go:build cgo
+
+package duckdbtest
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/duckdb/duckdb-go/v2"
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/crud"
+	"github.com/ygrpc/protodb/ddl"
+	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// openDuckDB in memory duckdb with the table of the dynamic item created by ddl
+func openDuckDB(t *testing.T, msg proto.Message) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("duckdb", "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if dialect := sqldb.GetDBDialect(db); dialect != sqldb.DuckDB {
+		t.Fatalf("GetDBDialect = %v, want DuckDB", dialect)
+	}
+	item, err := ddl.DbCreateSQL(db, msg, "", false, false, map[string]*ddl.TDbTableInitSql{})
+	if err != nil {
+		t.Fatalf("DbCreateSQL: %v", err)
+	}
+	if err := ddl.ExecSql(db, []*ddl.TDbTableInitSql{item}); err != nil {
+		t.Fatalf("ExecSql: %v", err)
+	}
+	return db
+}
+
+// duckdbQuery run a TableQuery and scan all rows
+func duckdbQuery(t *testing.T, db *sql.DB, msgDesc protoreflect.MessageDescriptor, req *protodb.TableQueryReq) []proto.Message {
+	t.Helper()
+	sqlStr, vals, err := crud.TableQueryBuildSql(db, msgDesc, req, "", nil)
+	if err != nil {
+		t.Fatalf("TableQueryBuildSql: %v", err)
+	}
+	rows, err := db.Query(sqlStr, vals...)
+	if err != nil {
+		t.Fatalf("query %s: %v", sqlStr, err)
+	}
+	defer rows.Close()
+	columnNames, err := rows.Columns()
+	if err != nil {
+		t.Fatalf("Columns: %v", err)
+	}
+	msgFieldsMap := pdbutil.BuildMsgFieldsMap(nil, msgDesc.Fields(), true)
+	var result []proto.Message
+	for rows.Next() {
+		msg := dynamicpb.NewMessage(msgDesc)
+		if err := crud.DbScan2ProtoMsg(rows, msg, columnNames, msgFieldsMap); err != nil {
+			t.Fatalf("DbScan2ProtoMsg: %v", err)
+		}
+		result = append(result, msg)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("rows: %v", err)
+	}
+	return result
+}
+
+func TestGetDBDialect(t *testing.T) {
+	db, err := sql.Open("duckdb", "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+	if dialect := sqldb.GetDBDialect(db); dialect != sqldb.DuckDB {
+		t.Fatalf("GetDBDialect = %v, want DuckDB", dialect)
+	}
+	if placeholder := sqldb.GetDBPlaceholder(db); placeholder != sqldb.DuckDB.Dialect().Placeholder() {
+		t.Fatalf("GetDBPlaceholder = %v", placeholder)
+	}
+}
+
+func TestDbMigrate(t *testing.T) {
+	msg, _ := newDynamicItem(t)
+	db := openDuckDB(t, msg)
+
+	item, err := ddl.DbMigrateTable(db, msg, "", false, false, map[string]*ddl.TDbTableInitSql{})
+	if err != nil {
+		t.Fatalf("DbMigrateTable: %v", err)
+	}
+	if !item.TableExists || len(item.SqlStr) != 0 {
+		t.Fatalf("migrate created table = %v %v, want no sql", item.TableExists, item.SqlStr)
+	}
+	if _, err := db.Exec(`INSERT INTO dyn_item (id, name, tags, scores) VALUES (10, 'raw', ['x'], [1, 2])`); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+}
+
+func TestCrud(t *testing.T) {
+	msg, msgDesc := newDynamicItem(t)
+	fields := msgDesc.Fields()
+	db := openDuckDB(t, msg)
+
+	newItem := func(id int64, name string, tags []string, attrs map[string]string, scores ...int32) proto.Message {
+		m := dynamicpb.NewMessage(msgDesc)
+		pm := m.ProtoReflect()
+		pm.Set(fields.ByName("id"), protoreflect.ValueOfInt64(id))
+		pm.Set(fields.ByName("name"), protoreflect.ValueOfString(name))
+		for _, tag := range tags {
+			pm.Mutable(fields.ByName("tags")).List().Append(protoreflect.ValueOfString(tag))
+		}
+		for k, v := range attrs {
+			pm.Mutable(fields.ByName("attrs")).Map().Set(protoreflect.ValueOfString(k).MapKey(), protoreflect.ValueOfString(v))
+		}
+		for _, score := range scores {
+			pm.Mutable(fields.ByName("scores")).List().Append(protoreflect.ValueOfInt32(score))
+		}
+		pm.Set(fields.ByName("status"), protoreflect.ValueOfEnum(1))
+		profile := pm.Mutable(fields.ByName("profile")).Message()
+		profile.Set(profile.Descriptor().Fields().ByName("city"), protoreflect.ValueOfString("Paris"))
+		pm.Set(fields.ByName("created_at"), protoreflect.ValueOfMessage(timestamppb.New(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)).ProtoReflect()))
+		return m
+	}
+
+	box := newItem(1, "box", []string{"a", "b"}, map[string]string{"color": "red"}, 1, 2, 3)
+	inserted, err := crud.DbInsertReturn(db, box, 0, "")
+	if err != nil {
+		t.Fatalf("DbInsertReturn: %v", err)
+	}
+	if !proto.Equal(inserted, box) {
+		t.Fatalf("insert returning = %v, want %v", inserted, box)
+	}
+	if _, err := crud.DbInsert(db, newItem(2, "bag", []string{"c"}, map[string]string{"size": "xl"}), 0, ""); err != nil {
+		t.Fatalf("DbInsert: %v", err)
+	}
+
+	box.ProtoReflect().Set(fields.ByName("name"), protoreflect.ValueOfString("crate"))
+	box.ProtoReflect().Mutable(fields.ByName("tags")).List().Append(protoreflect.ValueOfString("z"))
+	updated, err := crud.DbUpdateReturnNew(db, box, 0, "")
+	if err != nil {
+		t.Fatalf("DbUpdateReturnNew: %v", err)
+	}
+	if !proto.Equal(updated, box) {
+		t.Fatalf("update returning = %v, want %v", updated, box)
+	}
+
+	key := dynamicpb.NewMessage(msgDesc)
+	key.ProtoReflect().Set(fields.ByName("id"), protoreflect.ValueOfInt64(1))
+	selected, err := crud.DbSelectOne(db, key, nil, nil, "", true)
+	if err != nil {
+		t.Fatalf("DbSelectOne: %v", err)
+	}
+	if !proto.Equal(selected, box) {
+		t.Fatalf("select one = %v, want %v", selected, box)
+	}
+
+	cases := []struct {
+		column string
+		op     protodb.WhereOperator
+		value  string
+		names  []string
+	}{
+		{"tags", protodb.WhereOperator_WOP_CONTAINS, "z", []string{"crate"}},
+		{"tags", protodb.WhereOperator_WOP_OVERLAP, `["c","x"]`, []string{"bag"}},
+		{"tags", protodb.WhereOperator_WOP_CONTAINS_ALL, `["a","b"]`, []string{"crate"}},
+		{"tags", protodb.WhereOperator_WOP_LEN_GT, "1", []string{"crate"}},
+		{"scores", protodb.WhereOperator_WOP_CONTAINS, "2", []string{"crate"}},
+		{"attrs", protodb.WhereOperator_WOP_HAS_KEY, "size", []string{"bag"}},
+	}
+	for _, c := range cases {
+		rows := duckdbQuery(t, db, msgDesc, &protodb.TableQueryReq{
+			TableName:      "dyn_item",
+			Where2:         map[string]string{c.column: c.value},
+			Where2Operator: map[string]protodb.WhereOperator{c.column: c.op},
+		})
+		var names []string
+		for _, row := range rows {
+			names = append(names, row.ProtoReflect().Get(fields.ByName("name")).String())
+		}
+		if len(names) != len(c.names) || (len(names) > 0 && names[0] != c.names[0]) {
+			t.Fatalf("%s %v %s = %v, want %v", c.column, c.op, c.value, names, c.names)
+		}
+	}
+
+	if _, err := crud.DbDelete(db, key, ""); err != nil {
+		t.Fatalf("DbDelete: %v", err)
+	}
+	if rows := duckdbQuery(t, db, msgDesc, &protodb.TableQueryReq{TableName: "dyn_item"}); len(rows) != 1 {
+		t.Fatalf("rows after delete = %d", len(rows))
+	}
+}
@@ -0,0 +1,84 @@
+package duckdbtest
+
+import (
+	"testing"
+
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/msgstore"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// dynamicItemFileSet descriptor set of a table only known at runtime
+func dynamicItemFileSet() *descriptorpb.FileDescriptorSet {
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	typ := func(t descriptorpb.FieldDescriptorProto_Type) *descriptorpb.FieldDescriptorProto_Type {
+		return t.Enum()
+	}
+	pdbm := &descriptorpb.MessageOptions{}
+	proto.SetExtension(pdbm, protodb.E_Pdbm, &protodb.PDBMsg{TableName: "dyn_item"})
+	primary := &descriptorpb.FieldOptions{}
+	proto.SetExtension(primary, protodb.E_Pdb, &protodb.PDBField{Primary: true, SerialType: 8})
+	return &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{{
+		Name:       proto.String("dyn/item.proto"),
+		Package:    proto.String("dyn"),
+		Dependency: []string{"protodb.proto", "google/protobuf/timestamp.proto"},
+		Syntax:     proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name:    proto.String("Item"),
+				Options: pdbm,
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("id"), JsonName: proto.String("id"), Number: proto.Int32(1), Label: optional, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_INT64), Options: primary},
+					{Name: proto.String("name"), JsonName: proto.String("name"), Number: proto.Int32(2), Label: optional, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_STRING)},
+					{Name: proto.String("tags"), JsonName: proto.String("tags"), Number: proto.Int32(3), Label: repeated, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_STRING)},
+					{Name: proto.String("attrs"), JsonName: proto.String("attrs"), Number: proto.Int32(4), Label: repeated, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_MESSAGE), TypeName: proto.String(".dyn.Item.AttrsEntry")},
+					{Name: proto.String("status"), JsonName: proto.String("status"), Number: proto.Int32(5), Label: optional, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_ENUM), TypeName: proto.String(".dyn.Status")},
+					{Name: proto.String("profile"), JsonName: proto.String("profile"), Number: proto.Int32(6), Label: optional, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_MESSAGE), TypeName: proto.String(".dyn.Profile")},
+					{Name: proto.String("created_at"), JsonName: proto.String("createdAt"), Number: proto.Int32(7), Label: optional, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_MESSAGE), TypeName: proto.String(".google.protobuf.Timestamp")},
+					{Name: proto.String("scores"), JsonName: proto.String("scores"), Number: proto.Int32(8), Label: repeated, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_INT32)},
+				},
+				NestedType: []*descriptorpb.DescriptorProto{{
+					Name:    proto.String("AttrsEntry"),
+					Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+					Field: []*descriptorpb.FieldDescriptorProto{
+						{Name: proto.String("key"), JsonName: proto.String("key"), Number: proto.Int32(1), Label: optional, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_STRING)},
+						{Name: proto.String("value"), JsonName: proto.String("value"), Number: proto.Int32(2), Label: optional, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_STRING)},
+					},
+				}},
+			},
+			{
+				Name: proto.String("Profile"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("city"), JsonName: proto.String("city"), Number: proto.Int32(1), Label: optional, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_STRING)},
+				},
+			},
+		},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Status"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("STATUS_UNKNOWN"), Number: proto.Int32(0)},
+				{Name: proto.String("STATUS_OK"), Number: proto.Int32(1)},
+			},
+		}},
+	}}}
+}
+
+// newDynamicItem register the dynamic item table, return a new message of it
+func newDynamicItem(t *testing.T) (proto.Message, protoreflect.MessageDescriptor) {
+	t.Helper()
+	msgDescs, err := msgstore.RegisterFileDescriptorSet(dynamicItemFileSet())
+	if err != nil {
+		t.Fatalf("RegisterFileDescriptorSet: %v", err)
+	}
+	if len(msgDescs) != 1 || msgDescs[0].Name() != "Item" {
+		t.Fatalf("unexpected table messages: %v", msgDescs)
+	}
+	msg, ok := msgstore.GetMsg("dyn_item", true)
+	if !ok {
+		t.Fatal("dynamic table is not registered by table name")
+	}
+	return msg, msgDescs[0]
+}
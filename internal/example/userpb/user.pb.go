@@ -0,0 +1,259 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: internal/example/userpb/user.proto
+
+package userpb
+
+import (
+	_ "github.com/ygrpc/protodb"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type UserStatus int32
+
+const (
+	UserStatus_USER_STATUS_UNKNOWN  UserStatus = 0
+	UserStatus_USER_STATUS_ACTIVE   UserStatus = 1
+	UserStatus_USER_STATUS_DISABLED UserStatus = 2
+)
+
+// Enum value maps for UserStatus.
+var (
+	UserStatus_name = map[int32]string{
+		0: "USER_STATUS_UNKNOWN",
+		1: "USER_STATUS_ACTIVE",
+		2: "USER_STATUS_DISABLED",
+	}
+	UserStatus_value = map[string]int32{
+		"USER_STATUS_UNKNOWN":  0,
+		"USER_STATUS_ACTIVE":   1,
+		"USER_STATUS_DISABLED": 2,
+	}
+)
+
+func (x UserStatus) Enum() *UserStatus {
+	p := new(UserStatus)
+	*p = x
+	return p
+}
+
+func (x UserStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (UserStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_internal_example_userpb_user_proto_enumTypes[0].Descriptor()
+}
+
+func (UserStatus) Type() protoreflect.EnumType {
+	return &file_internal_example_userpb_user_proto_enumTypes[0]
+}
+
+func (x UserStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use UserStatus.Descriptor instead.
+func (UserStatus) EnumDescriptor() ([]byte, []int) {
+	return file_internal_example_userpb_user_proto_rawDescGZIP(), []int{0}
+}
+
+type User struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Email         string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	TenantId      int64                  `protobuf:"varint,4,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Status        UserStatus             `protobuf:"varint,5,opt,name=status,proto3,enum=userpb.UserStatus" json:"status,omitempty"`
+	Tags          []string               `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Nickname      *string                `protobuf:"bytes,8,opt,name=nickname,proto3,oneof" json:"nickname,omitempty"`
+	Password      string                 `protobuf:"bytes,9,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	mi := &file_internal_example_userpb_user_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_example_userpb_user_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_internal_example_userpb_user_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *User) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *User) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetTenantId() int64 {
+	if x != nil {
+		return x.TenantId
+	}
+	return 0
+}
+
+func (x *User) GetStatus() UserStatus {
+	if x != nil {
+		return x.Status
+	}
+	return UserStatus_USER_STATUS_UNKNOWN
+}
+
+func (x *User) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *User) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *User) GetNickname() string {
+	if x != nil && x.Nickname != nil {
+		return *x.Nickname
+	}
+	return ""
+}
+
+func (x *User) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+var File_internal_example_userpb_user_proto protoreflect.FileDescriptor
+
+const file_internal_example_userpb_user_proto_rawDesc = "" +
+	"\n" +
+	"\"internal/example/userpb/user.proto\x12\x06userpb\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\rprotodb.proto\"\x83\x03\n" +
+	"\x04User\x12\x17\n" +
+	"\x02id\x18\x01 \x01(\x03B\a\x82v\x04\x10\x01P\bR\x02id\x12#\n" +
+	"\busername\x18\x02 \x01(\tB\a\x82v\x04\x18\x01 \x01R\busername\x12*\n" +
+	"\x05email\x18\x03 \x01(\tB\x14\x82v\x11\x18\x01\x82\x01\ftenant_emailR\x05email\x121\n" +
+	"\ttenant_id\x18\x04 \x01(\x03B\x14\x82v\x11\x18\x01\x82\x01\ftenant_emailR\btenantId\x12*\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x12.userpb.UserStatusR\x06status\x12\x12\n" +
+	"\x04tags\x18\x06 \x03(\tR\x04tags\x12@\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampB\x05\x82v\x02H\x01R\tcreatedAt\x12\x1f\n" +
+	"\bnickname\x18\b \x01(\tH\x00R\bnickname\x88\x01\x01\x12!\n" +
+	"\bpassword\x18\t \x01(\tB\x05\x82v\x02\b\x01R\bpassword:\v\x82v\bJ\x06t_userB\v\n" +
+	"\t_nickname*W\n" +
+	"\n" +
+	"UserStatus\x12\x17\n" +
+	"\x13USER_STATUS_UNKNOWN\x10\x00\x12\x16\n" +
+	"\x12USER_STATUS_ACTIVE\x10\x01\x12\x18\n" +
+	"\x14USER_STATUS_DISABLED\x10\x02B2Z0github.com/ygrpc/protodb/internal/example/userpbb\x06proto3"
+
+var (
+	file_internal_example_userpb_user_proto_rawDescOnce sync.Once
+	file_internal_example_userpb_user_proto_rawDescData []byte
+)
+
+func file_internal_example_userpb_user_proto_rawDescGZIP() []byte {
+	file_internal_example_userpb_user_proto_rawDescOnce.Do(func() {
+		file_internal_example_userpb_user_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_internal_example_userpb_user_proto_rawDesc), len(file_internal_example_userpb_user_proto_rawDesc)))
+	})
+	return file_internal_example_userpb_user_proto_rawDescData
+}
+
+var file_internal_example_userpb_user_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_internal_example_userpb_user_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_internal_example_userpb_user_proto_goTypes = []any{
+	(UserStatus)(0),               // 0: userpb.UserStatus
+	(*User)(nil),                  // 1: userpb.User
+	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
+}
+var file_internal_example_userpb_user_proto_depIdxs = []int32{
+	0, // 0: userpb.User.status:type_name -> userpb.UserStatus
+	2, // 1: userpb.User.created_at:type_name -> google.protobuf.Timestamp
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_internal_example_userpb_user_proto_init() }
+func file_internal_example_userpb_user_proto_init() {
+	if File_internal_example_userpb_user_proto != nil {
+		return
+	}
+	file_internal_example_userpb_user_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_internal_example_userpb_user_proto_rawDesc), len(file_internal_example_userpb_user_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_internal_example_userpb_user_proto_goTypes,
+		DependencyIndexes: file_internal_example_userpb_user_proto_depIdxs,
+		EnumInfos:         file_internal_example_userpb_user_proto_enumTypes,
+		MessageInfos:      file_internal_example_userpb_user_proto_msgTypes,
+	}.Build()
+	File_internal_example_userpb_user_proto = out.File
+	file_internal_example_userpb_user_proto_goTypes = nil
+	file_internal_example_userpb_user_proto_depIdxs = nil
+}
@@ -0,0 +1,149 @@
+package msgstore
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// NewFilesFromDescriptorSet build the files of fds, dependencies not in fds
+// (protodb.proto, well-known types) are resolved from protoregistry.GlobalFiles.
+// files already linked into the binary are the global ones, so their go types are kept
+func NewFilesFromDescriptorSet(fds *descriptorpb.FileDescriptorSet) (*protoregistry.Files, error) {
+	fdps := make(map[string]*descriptorpb.FileDescriptorProto, len(fds.GetFile()))
+	for _, fdp := range fds.GetFile() {
+		if _, ok := fdps[fdp.GetName()]; ok {
+			return nil, fmt.Errorf("duplicate file %s in descriptor set", fdp.GetName())
+		}
+		fdps[fdp.GetName()] = fdp
+	}
+
+	files := new(protoregistry.Files)
+	resolver := &filesResolver{local: files}
+	visiting := make(map[string]bool)
+	var build func(name string) error
+	build = func(name string) error {
+		if _, err := files.FindFileByPath(name); err == nil {
+			return nil
+		}
+		if visiting[name] {
+			return fmt.Errorf("import cycle of file %s", name)
+		}
+		fdp, ok := fdps[name]
+		if !ok {
+			fd, err := protoregistry.GlobalFiles.FindFileByPath(name)
+			if err != nil {
+				return fmt.Errorf("file %s not found in descriptor set or linked files", name)
+			}
+			return files.RegisterFile(fd)
+		}
+		if fd, err := protoregistry.GlobalFiles.FindFileByPath(name); err == nil {
+			return files.RegisterFile(fd)
+		}
+
+		visiting[name] = true
+		for _, dep := range fdp.GetDependency() {
+			if err := build(dep); err != nil {
+				return err
+			}
+		}
+		visiting[name] = false
+
+		fd, err := protodesc.NewFile(fdp, resolver)
+		if err != nil {
+			return fmt.Errorf("build file %s err: %w", name, err)
+		}
+		return files.RegisterFile(fd)
+	}
+
+	for _, fdp := range fds.GetFile() {
+		if err := build(fdp.GetName()); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// filesResolver resolve from local files, then the global files
+type filesResolver struct {
+	local *protoregistry.Files
+}
+
+func (r *filesResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if fd, err := r.local.FindFileByPath(path); err == nil {
+		return fd, nil
+	}
+	return protoregistry.GlobalFiles.FindFileByPath(path)
+}
+
+func (r *filesResolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if d, err := r.local.FindDescriptorByName(name); err == nil {
+		return d, nil
+	}
+	return protoregistry.GlobalFiles.FindDescriptorByName(name)
+}
+
+// RegisterDynamicMsg register msgDesc to GlobalMsgStore by dynamicpb
+func RegisterDynamicMsg(msgDesc protoreflect.MessageDescriptor) {
+	GlobalMsgStore.RegisterDynamicMsg(msgDesc)
+}
+
+// RegisterFileDescriptorSet register the table messages of fds to GlobalMsgStore by dynamicpb
+func RegisterFileDescriptorSet(fds *descriptorpb.FileDescriptorSet) ([]protoreflect.MessageDescriptor, error) {
+	return GlobalMsgStore.RegisterFileDescriptorSet(fds)
+}
+
+// RegisterFileDescriptorSetBytes register the serialized FileDescriptorSet to GlobalMsgStore
+func RegisterFileDescriptorSetBytes(b []byte) ([]protoreflect.MessageDescriptor, error) {
+	return GlobalMsgStore.RegisterFileDescriptorSetBytes(b)
+}
+
+// RegisterDynamicMsg register msgDesc by dynamicpb, used for tables defined at runtime
+func (this *TMsgStore) RegisterDynamicMsg(msgDesc protoreflect.MessageDescriptor) {
+	this.RegisterMsgType(dynamicpb.NewMessageType(msgDesc))
+}
+
+// RegisterFileDescriptorSet register every table message(see TableMsgDescriptors) of the files in fds
+// which import protodb.proto by dynamicpb,
+// the Crud/TableQuery rpc can serve them without generated go code. return the registered messages
+func (this *TMsgStore) RegisterFileDescriptorSet(fds *descriptorpb.FileDescriptorSet) ([]protoreflect.MessageDescriptor, error) {
+	files, err := NewFilesFromDescriptorSet(fds)
+	if err != nil {
+		return nil, err
+	}
+
+	var msgDescs []protoreflect.MessageDescriptor
+	for _, fdp := range fds.GetFile() {
+		fd, err := files.FindFileByPath(fdp.GetName())
+		if err != nil {
+			return nil, err
+		}
+		if _, err := protoregistry.GlobalFiles.FindFileByPath(fdp.GetName()); err == nil {
+			// linked files register their generated types in init()
+			continue
+		}
+		if !ImportsProtodb(fd) {
+			continue
+		}
+		msgDescs = append(msgDescs, TableMsgDescriptors(fd)...)
+	}
+	for _, msgDesc := range msgDescs {
+		this.RegisterDynamicMsg(msgDesc)
+	}
+	return msgDescs, nil
+}
+
+// RegisterFileDescriptorSetBytes register the serialized FileDescriptorSet,
+// like the output of protoc --include_imports --descriptor_set_out
+func (this *TMsgStore) RegisterFileDescriptorSetBytes(b []byte) ([]protoreflect.MessageDescriptor, error) {
+	fds := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(b, fds); err != nil {
+		return nil, fmt.Errorf("unmarshal FileDescriptorSet err: %w", err)
+	}
+	return this.RegisterFileDescriptorSet(fds)
+}
@@ -0,0 +1,124 @@
+package msgstore
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ygrpc/protodb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func dynamicOrderFileSet() *descriptorpb.FileDescriptorSet {
+	orderOpts := &descriptorpb.MessageOptions{}
+	proto.SetExtension(orderOpts, protodb.E_Pdbm, &protodb.PDBMsg{TableName: "t_dyn_order"})
+	idOpts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(idOpts, protodb.E_Pdb, &protodb.PDBField{Primary: true})
+	skipOpts := &descriptorpb.MessageOptions{}
+	proto.SetExtension(skipOpts, protodb.E_Pdbm, &protodb.PDBMsg{NotDB: true})
+
+	common := &descriptorpb.FileDescriptorProto{
+		Syntax:  proto.String("proto3"),
+		Name:    proto.String("dyn/common.proto"),
+		Package: proto.String("dyn"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Money"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:   proto.String("cents"),
+				Number: proto.Int32(1),
+				Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:   descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum(),
+			}},
+		}},
+	}
+	order := &descriptorpb.FileDescriptorProto{
+		Syntax:     proto.String("proto3"),
+		Name:       proto.String("dyn/order.proto"),
+		Package:    proto.String("dyn"),
+		Dependency: []string{"protodb.proto", "dyn/common.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name:    proto.String("DynOrder"),
+				Options: orderOpts,
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{
+						Name:    proto.String("id"),
+						Number:  proto.Int32(1),
+						Label:   descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+						Type:    descriptorpb.FieldDescriptorProto_TYPE_INT64.Enum(),
+						Options: idOpts,
+					},
+					{
+						Name:     proto.String("amount"),
+						Number:   proto.Int32(2),
+						Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+						Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+						TypeName: proto.String(".dyn.Money"),
+					},
+				},
+			},
+			{Name: proto.String("DynOrderReq"), Options: skipOpts},
+		},
+	}
+	// dependent file first, the loader orders files itself
+	return &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		order,
+		common,
+		protodesc.ToFileDescriptorProto(protodb.File_protodb_proto),
+	}}
+}
+
+func TestRegisterFileDescriptorSet(t *testing.T) {
+	b, err := proto.Marshal(dynamicOrderFileSet())
+	if err != nil {
+		t.Fatalf("proto.Marshal: %v", err)
+	}
+	msgDescs, err := RegisterFileDescriptorSetBytes(b)
+	if err != nil {
+		t.Fatalf("RegisterFileDescriptorSetBytes: %v", err)
+	}
+	if len(msgDescs) != 1 || msgDescs[0].FullName() != "dyn.DynOrder" {
+		t.Fatalf("registered %v, want only dyn.DynOrder", msgDescs)
+	}
+
+	for _, name := range []string{"DynOrder", "t_dyn_order"} {
+		msg, ok := GetMsg(name, true)
+		if !ok {
+			t.Fatalf("GetMsg(%q) not found", name)
+		}
+		if _, ok := msg.(*dynamicpb.Message); !ok {
+			t.Fatalf("GetMsg(%q) = %T, want *dynamicpb.Message", name, msg)
+		}
+	}
+	if _, ok := GetMsg("DynOrderReq", true); ok {
+		t.Fatalf("NotDB message should not be registered")
+	}
+	// protodb.proto is linked, its messages keep the generated types
+	if msg, ok := GetMsg("PDBField", true); ok {
+		if _, ok := msg.(*protodb.PDBField); !ok {
+			t.Fatalf("linked message replaced by %T", msg)
+		}
+	}
+}
+
+func TestRegisterFileDescriptorSetErrors(t *testing.T) {
+	fds := dynamicOrderFileSet()
+	fds.File = fds.File[:1]
+	if _, err := RegisterFileDescriptorSet(fds); err == nil || !strings.Contains(err.Error(), "dyn/common.proto") {
+		t.Fatalf("missing dependency err = %v", err)
+	}
+
+	fds = dynamicOrderFileSet()
+	fds.File = append(fds.File, fds.File[1])
+	if _, err := RegisterFileDescriptorSet(fds); err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Fatalf("duplicate file err = %v", err)
+	}
+
+	fds = dynamicOrderFileSet()
+	fds.File[1].Dependency = []string{"dyn/order.proto"}
+	if _, err := RegisterFileDescriptorSet(fds); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("import cycle err = %v", err)
+	}
+}
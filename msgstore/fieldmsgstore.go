@@ -2,39 +2,44 @@ package msgstore
 
 import (
 	"fmt"
-	"sync"
 
 	"google.golang.org/protobuf/proto"
 )
 
-var (
-	fieldMsgStoreMu sync.RWMutex
-	fieldMsgStore   = make(map[string]TFnGetMsg)
-)
-
-// RegisterFieldMsg register a proto message TFnGetMsg to fieldMsgStore
+// RegisterFieldMsg register a proto message TFnGetMsg to the field msgs of GlobalMsgStore
 // should call in init() function
 func RegisterFieldMsg(msgName string, msgGetFunc TFnGetMsg) {
-	fieldMsgStoreMu.RLock()
-	oldmsgFn, ok := fieldMsgStore[msgName]
-	fieldMsgStoreMu.RUnlock()
+	GlobalMsgStore.RegisterFieldMsg(msgName, msgGetFunc)
+}
+
+// GetFieldMsg get a proto.Message from the field msgs of GlobalMsgStore
+// new if you need a new message, else return global static message
+func GetFieldMsg(msgName string, new bool) (proto.Message, bool) {
+	return GlobalMsgStore.GetFieldMsg(msgName, new)
+}
+
+// RegisterFieldMsg register a proto message TFnGetMsg to fieldMsgStore
+func (this *TMsgStore) RegisterFieldMsg(msgName string, msgGetFunc TFnGetMsg) {
+	this.fieldMsgStoreMu.RLock()
+	oldmsgFn, ok := this.fieldMsgStore[msgName]
+	this.fieldMsgStoreMu.RUnlock()
 	if ok {
 		oldmsg := oldmsgFn(false)
 		newmsg := msgGetFunc(false)
 		fmt.Println("reregister protomsg to fieldMsgStore:", msgName, "old:", oldmsg.ProtoReflect().Descriptor(), "new:", newmsg.ProtoReflect().Descriptor())
 	}
 
-	fieldMsgStoreMu.Lock()
-	fieldMsgStore[(msgName)] = msgGetFunc
-	fieldMsgStoreMu.Unlock()
+	this.fieldMsgStoreMu.Lock()
+	this.fieldMsgStore[(msgName)] = msgGetFunc
+	this.fieldMsgStoreMu.Unlock()
 }
 
 // GetFieldMsg get a proto.Message from fieldMsgStore
 // new if you need a new message, else return global static message
-func GetFieldMsg(msgName string, new bool) (proto.Message, bool) {
-	fieldMsgStoreMu.RLock()
-	msgfn, ok := fieldMsgStore[msgName]
-	fieldMsgStoreMu.RUnlock()
+func (this *TMsgStore) GetFieldMsg(msgName string, new bool) (proto.Message, bool) {
+	this.fieldMsgStoreMu.RLock()
+	msgfn, ok := this.fieldMsgStore[msgName]
+	this.fieldMsgStoreMu.RUnlock()
 	if !ok {
 		return nil, false
 	}
@@ -1,46 +1,166 @@
 package msgstore
 
 import (
-	"fmt"
+	"log"
+	"sort"
 	"sync"
 
+	"github.com/ygrpc/protodb/pdbutil"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 // TFnGetMsg get a proto.Message from msgStore
 // new if need a new message, else return global static message
 type TFnGetMsg = func(new bool) proto.Message
 
-var (
-	msgStoreMu sync.RWMutex
-	msgStore   = make(map[string]TFnGetMsg)
-)
+// TMsgCollision a name registered by two different messages, the later registration wins
+type TMsgCollision struct {
+	// msg name or db table name
+	Name string
+	// Name is a db table name
+	IsTableName bool
+	Old         protoreflect.FullName
+	New         protoreflect.FullName
+}
+
+// TMsgStore messages and field messages of a protodb service,
+// services in one process can use their own store instead of GlobalMsgStore
+type TMsgStore struct {
+	mu       sync.RWMutex
+	msgStore map[string]TFnGetMsg
+	// db table name -> TFnGetMsg, for messages whose table name differs from msg name
+	msgTableStore map[string]TFnGetMsg
+	collisions    []TMsgCollision
+
+	fieldMsgStoreMu sync.RWMutex
+	fieldMsgStore   map[string]TFnGetMsg
+}
 
-// RegisterMsg register a proto message TFnGetMsg to msgStore
+// NewMsgStore create an empty msg store
+func NewMsgStore() *TMsgStore {
+	return &TMsgStore{
+		msgStore:      make(map[string]TFnGetMsg),
+		msgTableStore: make(map[string]TFnGetMsg),
+		fieldMsgStore: make(map[string]TFnGetMsg),
+	}
+}
+
+// GlobalMsgStore the default store used by the package level functions
+var GlobalMsgStore = NewMsgStore()
+
+// RegisterMsg register a proto message TFnGetMsg to GlobalMsgStore
 // should call in init() function
 func RegisterMsg(msgName string, msgGetFunc TFnGetMsg) {
-	msgStoreMu.RLock()
-	oldmsgFn, ok := msgStore[msgName]
-	msgStoreMu.RUnlock()
-	if ok {
-		oldmsg := oldmsgFn(false)
-		newmsg := msgGetFunc(false)
-		fmt.Println("reregister protomsg to msgStore:", msgName, "old:", oldmsg.ProtoReflect().Descriptor(), "new:", newmsg.ProtoReflect().Descriptor())
-	}
+	GlobalMsgStore.RegisterMsg(msgName, msgGetFunc)
+}
 
-	msgStoreMu.Lock()
-	msgStore[(msgName)] = msgGetFunc
-	msgStoreMu.Unlock()
+// Collisions names registered by different messages in GlobalMsgStore
+func Collisions() []TMsgCollision {
+	return GlobalMsgStore.Collisions()
 }
 
-// GetMsg get a proto.Message from msgStore
+// GetMsg get a proto.Message from GlobalMsgStore by msg name or db table name
 // new if need a new message, else return global static message
 func GetMsg(msgName string, new bool) (proto.Message, bool) {
-	msgStoreMu.RLock()
-	msgfn, ok := msgStore[msgName]
-	msgStoreMu.RUnlock()
+	return GlobalMsgStore.GetMsg(msgName, new)
+}
+
+// MsgNames msg names registered in GlobalMsgStore
+func MsgNames() []string {
+	return GlobalMsgStore.MsgNames()
+}
+
+// MsgDescriptors descriptors of the messages registered in GlobalMsgStore
+func MsgDescriptors() []protoreflect.MessageDescriptor {
+	return GlobalMsgStore.MsgDescriptors()
+}
+
+// RegisterMsg register a proto message TFnGetMsg
+// the message can be got by msgName or its db table name(PDBMsg.TableName, file NameStyle)
+// a name already registered by another message is replaced and reported by Collisions
+func (this *TMsgStore) RegisterMsg(msgName string, msgGetFunc TFnGetMsg) {
+	msgDesc := msgGetFunc(false).ProtoReflect().Descriptor()
+	tableName := pdbutil.GetTableName(msgDesc)
+
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	this.checkCollision(this.msgStore, msgName, false, msgDesc.FullName())
+	this.msgStore[msgName] = msgGetFunc
+	if tableName != msgName {
+		this.checkCollision(this.msgTableStore, tableName, true, msgDesc.FullName())
+		this.msgTableStore[tableName] = msgGetFunc
+	}
+}
+
+// checkCollision record name of store registered by another message, caller holds this.mu
+func (this *TMsgStore) checkCollision(store map[string]TFnGetMsg, name string, isTableName bool, newName protoreflect.FullName) {
+	oldFn, ok := store[name]
+	if !ok {
+		return
+	}
+	oldName := oldFn(false).ProtoReflect().Descriptor().FullName()
+	if oldName == newName {
+		return
+	}
+	this.collisions = append(this.collisions, TMsgCollision{Name: name, IsTableName: isTableName, Old: oldName, New: newName})
+	log.Printf("msgstore: %s registered by %s is replaced by %s", name, oldName, newName)
+}
+
+// Collisions names registered by different messages so far, e.g. the same message name in two packages
+func (this *TMsgStore) Collisions() []TMsgCollision {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+	return append([]TMsgCollision(nil), this.collisions...)
+}
+
+// GetMsg get a proto.Message by msg name or db table name
+// new if need a new message, else return global static message
+func (this *TMsgStore) GetMsg(msgName string, new bool) (proto.Message, bool) {
+	this.mu.RLock()
+	msgfn, ok := this.msgStore[msgName]
+	if !ok {
+		msgfn, ok = this.msgTableStore[msgName]
+	}
+	this.mu.RUnlock()
 	if !ok {
 		return nil, false
 	}
 	return msgfn(new), true
 }
+
+// MsgNames registered msg names sorted, db table names of the messages are not included
+func (this *TMsgStore) MsgNames() []string {
+	this.mu.RLock()
+	names := make([]string, 0, len(this.msgStore))
+	for name := range this.msgStore {
+		names = append(names, name)
+	}
+	this.mu.RUnlock()
+	sort.Strings(names)
+	return names
+}
+
+// MsgDescriptors descriptors of the registered messages sorted by full name,
+// a message registered by more than one name is listed once
+func (this *TMsgStore) MsgDescriptors() []protoreflect.MessageDescriptor {
+	this.mu.RLock()
+	fns := make([]TFnGetMsg, 0, len(this.msgStore))
+	for _, fn := range this.msgStore {
+		fns = append(fns, fn)
+	}
+	this.mu.RUnlock()
+
+	seen := make(map[protoreflect.FullName]bool, len(fns))
+	msgDescs := make([]protoreflect.MessageDescriptor, 0, len(fns))
+	for _, fn := range fns {
+		msgDesc := fn(false).ProtoReflect().Descriptor()
+		if seen[msgDesc.FullName()] {
+			continue
+		}
+		seen[msgDesc.FullName()] = true
+		msgDescs = append(msgDescs, msgDesc)
+	}
+	sort.Slice(msgDescs, func(i, j int) bool { return msgDescs[i].FullName() < msgDescs[j].FullName() })
+	return msgDescs
+}
@@ -7,6 +7,9 @@ import (
 
 	"github.com/ygrpc/protodb"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
 )
 
 func testMsgFactory(new bool) proto.Message {
@@ -64,3 +67,28 @@ func TestFieldMsgStoreConcurrentRegisterAndGet(t *testing.T) {
 		}
 	}
 }
+
+func TestGetMsgByTableName(t *testing.T) {
+	msgOpts := &descriptorpb.MessageOptions{}
+	proto.SetExtension(msgOpts, protodb.E_Pdbm, &protodb.PDBMsg{TableName: "t_store_item"})
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Syntax:      proto.String("proto3"),
+		Name:        proto.String("msgstore_test.proto"),
+		Package:     proto.String("test"),
+		MessageType: []*descriptorpb.DescriptorProto{{Name: proto.String("StoreItem"), Options: msgOpts}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile: %v", err)
+	}
+	msgDesc := fd.Messages().ByName("StoreItem")
+	RegisterMsg("StoreItem", func(new bool) proto.Message {
+		return dynamicpb.NewMessage(msgDesc)
+	})
+
+	for _, name := range []string{"StoreItem", "t_store_item"} {
+		msg, ok := GetMsg(name, true)
+		if !ok || msg.ProtoReflect().Descriptor().FullName() != "test.StoreItem" {
+			t.Fatalf("GetMsg(%q) = (%v, %v), want StoreItem", name, msg, ok)
+		}
+	}
+}
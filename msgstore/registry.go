@@ -0,0 +1,113 @@
+package msgstore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/pdbutil"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// RegisterMsgType register msgType to GlobalMsgStore
+func RegisterMsgType(msgType protoreflect.MessageType) {
+	GlobalMsgStore.RegisterMsgType(msgType)
+}
+
+// RegisterFile register the table messages of fd to GlobalMsgStore
+func RegisterFile(fd protoreflect.FileDescriptor) error {
+	return GlobalMsgStore.RegisterFile(fd)
+}
+
+// RegisterAllFromRegistry register the table messages of files to GlobalMsgStore
+func RegisterAllFromRegistry(files *protoregistry.Files, filter func(fd protoreflect.FileDescriptor) bool) error {
+	return GlobalMsgStore.RegisterAllFromRegistry(files, filter)
+}
+
+// RegisterMsgType register the messages of msgType by msg name, the static message is created once
+func (this *TMsgStore) RegisterMsgType(msgType protoreflect.MessageType) {
+	staticMsg := msgType.New().Interface()
+	this.RegisterMsg(string(msgType.Descriptor().Name()), func(new bool) proto.Message {
+		if new {
+			return msgType.New().Interface()
+		}
+		return staticMsg
+	})
+}
+
+// RegisterFile register every top level message of fd except PDBMsg.NotDB ones,
+// the go types are found in protoregistry.GlobalTypes, so the generated package must be linked
+func (this *TMsgStore) RegisterFile(fd protoreflect.FileDescriptor) error {
+	msgDescs := RegistrableMsgDescriptors(fd)
+	msgTypes := make([]protoreflect.MessageType, 0, len(msgDescs))
+	for _, msgDesc := range msgDescs {
+		msgType, err := protoregistry.GlobalTypes.FindMessageByName(msgDesc.FullName())
+		if err != nil {
+			return fmt.Errorf("find go type of %s err: %w", msgDesc.FullName(), err)
+		}
+		msgTypes = append(msgTypes, msgType)
+	}
+	for _, msgType := range msgTypes {
+		this.RegisterMsgType(msgType)
+	}
+	return nil
+}
+
+// RegisterAllFromRegistry RegisterFile every file of files which imports protodb.proto,
+// files is protoregistry.GlobalFiles if nil, filter can be nil to register all of them
+func (this *TMsgStore) RegisterAllFromRegistry(files *protoregistry.Files, filter func(fd protoreflect.FileDescriptor) bool) error {
+	if files == nil {
+		files = protoregistry.GlobalFiles
+	}
+	var errs []error
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		if !ImportsProtodb(fd) || (filter != nil && !filter(fd)) {
+			return true
+		}
+		if err := this.RegisterFile(fd); err != nil {
+			errs = append(errs, fmt.Errorf("register file %s err: %w", fd.Path(), err))
+		}
+		return true
+	})
+	return errors.Join(errs...)
+}
+
+// ImportsProtodb fd imports protodb.proto, which declares the protodb options
+func ImportsProtodb(fd protoreflect.FileDescriptor) bool {
+	imports := fd.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		if imports.Get(i).Path() == protodb.File_protodb_proto.Path() {
+			return true
+		}
+	}
+	return false
+}
+
+// RegistrableMsgDescriptors top level messages of fd except PDBMsg.NotDB ones, used by RegisterFile
+func RegistrableMsgDescriptors(fd protoreflect.FileDescriptor) []protoreflect.MessageDescriptor {
+	var msgDescs []protoreflect.MessageDescriptor
+	msgs := fd.Messages()
+	for i := 0; i < msgs.Len(); i++ {
+		if pdbm, found := pdbutil.GetPDBM(msgs.Get(i)); found && pdbm.NotDB {
+			continue
+		}
+		msgDescs = append(msgDescs, msgs.Get(i))
+	}
+	return msgDescs
+}
+
+// TableMsgDescriptors top level messages with pdbm option(not NotDB) of file
+func TableMsgDescriptors(fd protoreflect.FileDescriptor) []protoreflect.MessageDescriptor {
+	var msgDescs []protoreflect.MessageDescriptor
+	msgs := fd.Messages()
+	for i := 0; i < msgs.Len(); i++ {
+		pdbm, found := pdbutil.GetPDBM(msgs.Get(i))
+		if !found || pdbm.NotDB {
+			continue
+		}
+		msgDescs = append(msgDescs, msgs.Get(i))
+	}
+	return msgDescs
+}
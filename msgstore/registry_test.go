@@ -0,0 +1,109 @@
+package msgstore
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ygrpc/protodb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func newRegistryTestFile(t *testing.T, files *protoregistry.Files, name string, pkg string, deps []string, msgs ...*descriptorpb.DescriptorProto) protoreflect.FileDescriptor {
+	t.Helper()
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Syntax:      proto.String("proto3"),
+		Name:        proto.String(name),
+		Package:     proto.String(pkg),
+		Dependency:  deps,
+		MessageType: msgs,
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile: %v", err)
+	}
+	if err := files.RegisterFile(fd); err != nil {
+		t.Fatalf("RegisterFile: %v", err)
+	}
+	return fd
+}
+
+// registerGlobalTypes stands in for generated go types of fd
+func registerGlobalTypes(t *testing.T, fd protoreflect.FileDescriptor) {
+	t.Helper()
+	msgs := fd.Messages()
+	for i := 0; i < msgs.Len(); i++ {
+		if err := protoregistry.GlobalTypes.RegisterMessage(dynamicpb.NewMessageType(msgs.Get(i))); err != nil {
+			t.Fatalf("RegisterMessage: %v", err)
+		}
+	}
+}
+
+func TestRegisterAllFromRegistry(t *testing.T) {
+	notDBOpts := &descriptorpb.MessageOptions{}
+	proto.SetExtension(notDBOpts, protodb.E_Pdbm, &protodb.PDBMsg{NotDB: true})
+
+	files := new(protoregistry.Files)
+	deps := []string{"protodb.proto"}
+	fdA := newRegistryTestFile(t, files, "rega/item.proto", "rega", deps,
+		&descriptorpb.DescriptorProto{Name: proto.String("RegItem")},
+		&descriptorpb.DescriptorProto{Name: proto.String("RegItemReq"), Options: notDBOpts},
+	)
+	fdB := newRegistryTestFile(t, files, "regb/item.proto", "regb", deps,
+		&descriptorpb.DescriptorProto{Name: proto.String("RegItem")},
+	)
+	fdC := newRegistryTestFile(t, files, "regc/plain.proto", "regc", nil,
+		&descriptorpb.DescriptorProto{Name: proto.String("RegPlain")},
+	)
+	for _, fd := range []protoreflect.FileDescriptor{fdA, fdB, fdC} {
+		registerGlobalTypes(t, fd)
+	}
+
+	onlyA := func(fd protoreflect.FileDescriptor) bool { return fd.Package() == "rega" }
+	if err := RegisterAllFromRegistry(files, onlyA); err != nil {
+		t.Fatalf("RegisterAllFromRegistry: %v", err)
+	}
+	if msg, ok := GetMsg("RegItem", true); !ok || msg.ProtoReflect().Descriptor().FullName() != "rega.RegItem" {
+		t.Fatalf("GetMsg(RegItem) = (%v, %v), want rega.RegItem", msg, ok)
+	}
+	if _, ok := GetMsg("RegItemReq", true); ok {
+		t.Fatalf("NotDB message should not be registered")
+	}
+	for _, c := range Collisions() {
+		if c.Name == "RegItem" {
+			t.Fatalf("unexpected collision %+v", c)
+		}
+	}
+
+	if err := RegisterAllFromRegistry(files, nil); err != nil {
+		t.Fatalf("RegisterAllFromRegistry: %v", err)
+	}
+	if _, ok := GetMsg("RegPlain", true); ok {
+		t.Fatalf("message of file without protodb.proto import should not be registered")
+	}
+	found := false
+	for _, c := range Collisions() {
+		if c.Name == "RegItem" && !c.IsTableName && c.Old != c.New &&
+			strings.HasSuffix(string(c.Old), ".RegItem") && strings.HasSuffix(string(c.New), ".RegItem") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("collision of RegItem not reported: %+v", Collisions())
+	}
+}
+
+func TestRegisterFileWithoutGoType(t *testing.T) {
+	fd := newRegistryTestFile(t, new(protoregistry.Files), "regd/item.proto", "regd", []string{"protodb.proto"},
+		&descriptorpb.DescriptorProto{Name: proto.String("RegNoType")},
+	)
+	if err := RegisterFile(fd); err == nil || !strings.Contains(err.Error(), "regd.RegNoType") {
+		t.Fatalf("RegisterFile err = %v, want missing go type", err)
+	}
+	if _, ok := GetMsg("RegNoType", true); ok {
+		t.Fatalf("message without go type should not be registered")
+	}
+}
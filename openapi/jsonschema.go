@@ -0,0 +1,226 @@
+// Package openapi JSON Schema of protodb messages in protobuf json form and OpenAPI document of the
+// Crud, TableQuery and REST endpoints
+package openapi
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/pdbutil"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// JSONSchemaDialect json schema version of the generated schemas, the same as OpenAPI 3.1
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema json schema of msgDesc in protobuf json form, property names are json names.
+// required from PDBField.NotNull, readOnly from NoUpdate/NoInsert/SerialType, default from DefaultValue,
+// sub messages are in $defs by full name
+func JSONSchema(msgDesc protoreflect.MessageDescriptor) map[string]any {
+	b := newSchemaBuilder("#/$defs/")
+	ref := b.msgRef(msgDesc)
+	return map[string]any{
+		"$schema": JSONSchemaDialect,
+		"$ref":    ref["$ref"],
+		"$defs":   b.defs,
+	}
+}
+
+// schemaBuilder build message schemas into defs, refs are refPrefix + message full name
+type schemaBuilder struct {
+	refPrefix string
+	defs      map[string]any
+}
+
+func newSchemaBuilder(refPrefix string) *schemaBuilder {
+	return &schemaBuilder{refPrefix: refPrefix, defs: make(map[string]any)}
+}
+
+// msgRef $ref of message schema, the schema is built into defs once
+func (this *schemaBuilder) msgRef(msgDesc protoreflect.MessageDescriptor) map[string]any {
+	name := string(msgDesc.FullName())
+	if _, ok := this.defs[name]; !ok {
+		// placeholder for recursive messages
+		this.defs[name] = nil
+		this.defs[name] = this.msgSchema(msgDesc)
+	}
+	return map[string]any{"$ref": this.refPrefix + name}
+}
+
+// msgSchema object schema of message
+func (this *schemaBuilder) msgSchema(msgDesc protoreflect.MessageDescriptor) map[string]any {
+	pdbm, _ := pdbutil.GetPDBM(msgDesc)
+	properties := make(map[string]any)
+	var required []string
+	fields := msgDesc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		pdb, _ := pdbutil.GetPDB(fd)
+		schema := this.fieldSchema(fd)
+		if len(pdb.Comment) > 0 {
+			schema["description"] = strings.Join(pdb.Comment, "\n")
+		}
+		if !pdb.NotDB {
+			if pdb.NoUpdate || pdb.NoInsert || pdb.IsSerial() {
+				schema["readOnly"] = true
+			}
+			if defaultValue, ok := jsonDefaultValue(fd, pdb.DefaultValue); ok {
+				schema["default"] = defaultValue
+			}
+			// the db fills a default and a NoInsert column is not sent, a client can leave them out
+			if pdb.NotNull && !pdb.IsSerial() && !pdb.NoInsert && len(pdb.DefaultValue) == 0 && !fd.IsList() && !fd.IsMap() {
+				required = append(required, fd.JSONName())
+			}
+		}
+		properties[fd.JSONName()] = schema
+	}
+
+	schema := map[string]any{
+		"type":                 "object",
+		"title":                string(msgDesc.Name()),
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	if len(pdbm.Comment) > 0 {
+		schema["description"] = strings.Join(pdbm.Comment, "\n")
+	}
+	return schema
+}
+
+// fieldSchema schema of field value, list and map included
+func (this *schemaBuilder) fieldSchema(fd protoreflect.FieldDescriptor) map[string]any {
+	switch {
+	case fd.IsMap():
+		return map[string]any{"type": "object", "additionalProperties": this.valueSchema(fd.MapValue())}
+	case fd.IsList():
+		return map[string]any{"type": "array", "items": this.valueSchema(fd)}
+	default:
+		return this.valueSchema(fd)
+	}
+}
+
+// valueSchema schema of a single value of field, in protobuf json form
+func (this *schemaBuilder) valueSchema(fd protoreflect.FieldDescriptor) map[string]any {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]any{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]any{"type": "integer", "format": "uint32", "minimum": 0}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		// protobuf json writes 64 bit integers as strings
+		return map[string]any{"type": []string{"string", "integer"}, "format": "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return map[string]any{"type": []string{"string", "integer"}, "format": "uint64"}
+	case protoreflect.FloatKind:
+		return map[string]any{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return map[string]any{"type": "number", "format": "double"}
+	case protoreflect.StringKind:
+		if pdb, _ := pdbutil.GetPDB(fd); pdb.IsNumeric() {
+			return map[string]any{"type": "string", "format": "decimal"}
+		}
+		return map[string]any{"type": "string"}
+	case protoreflect.BytesKind:
+		return map[string]any{"type": "string", "contentEncoding": "base64"}
+	case protoreflect.EnumKind:
+		values := fd.Enum().Values()
+		names := make([]string, 0, values.Len())
+		for i := 0; i < values.Len(); i++ {
+			names = append(names, string(values.Get(i).Name()))
+		}
+		return map[string]any{"type": "string", "enum": names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return this.messageValueSchema(fd.Message())
+	}
+	return map[string]any{}
+}
+
+// messageValueSchema well-known messages in their json form, others by $ref
+func (this *schemaBuilder) messageValueSchema(msgDesc protoreflect.MessageDescriptor) map[string]any {
+	if _, ok := protodb.WellKnownWrapperKind(msgDesc.FullName()); ok {
+		// wrappers are written as the wrapped value
+		return this.valueSchema(msgDesc.Fields().ByName("value"))
+	}
+	switch msgDesc.FullName() {
+	case protodb.WktTimestamp:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case protodb.WktDuration:
+		return map[string]any{"type": "string", "pattern": `^-?[0-9]+(\.[0-9]+)?s$`}
+	case protodb.WktFieldMask:
+		return map[string]any{"type": "string"}
+	case protodb.WktStruct:
+		return map[string]any{"type": "object"}
+	case protodb.WktListValue:
+		return map[string]any{"type": "array"}
+	case protodb.WktValue:
+		return map[string]any{}
+	case "google.protobuf.Empty":
+		return map[string]any{"type": "object"}
+	case "google.protobuf.Any":
+		return map[string]any{"type": "object", "properties": map[string]any{"@type": map[string]any{"type": "string"}}, "required": []string{"@type"}}
+	}
+	return this.msgRef(msgDesc)
+}
+
+// jsonDefaultValue json value of PDBField.DefaultValue, sql expressions like now() are skipped
+func jsonDefaultValue(fd protoreflect.FieldDescriptor, defaultValue string) (any, bool) {
+	defaultValue = strings.TrimSpace(defaultValue)
+	if len(defaultValue) == 0 || fd.IsList() || fd.IsMap() {
+		return nil, false
+	}
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(strings.Trim(defaultValue, "'"))
+		return b, err == nil
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		n, err := strconv.ParseInt(strings.Trim(defaultValue, "'"), 10, 64)
+		return n, err == nil
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		n := strings.Trim(defaultValue, "'")
+		_, err := strconv.ParseInt(n, 10, 64)
+		return n, err == nil
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		f, err := strconv.ParseFloat(strings.Trim(defaultValue, "'"), 64)
+		return f, err == nil
+	case protoreflect.StringKind:
+		if s, ok := unquoteSQLString(defaultValue); ok {
+			return s, true
+		}
+		if strings.ContainsAny(defaultValue, "()'") {
+			return nil, false
+		}
+		return defaultValue, true
+	case protoreflect.EnumKind:
+		name := strings.Trim(defaultValue, "'")
+		if n, err := strconv.ParseInt(name, 10, 32); err == nil {
+			if value := fd.Enum().Values().ByNumber(protoreflect.EnumNumber(n)); value != nil {
+				return string(value.Name()), true
+			}
+			return nil, false
+		}
+		if value := fd.Enum().Values().ByName(protoreflect.Name(name)); value != nil {
+			return name, true
+		}
+	}
+	return nil, false
+}
+
+// unquoteSQLString value of sql string literal like 'it”s'
+func unquoteSQLString(s string) (string, bool) {
+	if len(s) < 2 || s[0] != '\'' || s[len(s)-1] != '\'' {
+		return "", false
+	}
+	inner := s[1 : len(s)-1]
+	if strings.Contains(strings.ReplaceAll(inner, "''", ""), "'") {
+		return "", false
+	}
+	return strings.ReplaceAll(inner, "''", "'"), true
+}
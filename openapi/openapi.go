@@ -0,0 +1,241 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/msgstore"
+	"github.com/ygrpc/protodb/pdbutil"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// OpenAPIVersion version of the generated document
+const OpenAPIVersion = "3.1.0"
+
+// errorSchemaName component schema of rest gateway error
+const errorSchemaName = "protodb.RestError"
+
+// Document OpenAPI document of the ProtoDbSrv rpcs(connect protocol with json) and the rest gateway
+// paths of msgDescs, NotDB messages are skipped. schemas are in components.schemas by message full name
+func Document(msgDescs []protoreflect.MessageDescriptor, title string, version string) map[string]any {
+	b := newSchemaBuilder("#/components/schemas/")
+	b.defs[errorSchemaName] = map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"error": map[string]any{"type": "string"}},
+		"required":   []string{"error"},
+	}
+
+	paths := map[string]any{
+		protodb.ProtoDbSrvCrudProcedure: map[string]any{
+			"post": rpcOperation(b, "Crud", "crud of a table, MsgBytes is the table message",
+				(&protodb.CrudReq{}).ProtoReflect().Descriptor(), (&protodb.CrudResp{}).ProtoReflect().Descriptor(), false),
+		},
+		protodb.ProtoDbSrvTableQueryProcedure: map[string]any{
+			"post": rpcOperation(b, "TableQuery", "query rows of a table, server streaming",
+				(&protodb.TableQueryReq{}).ProtoReflect().Descriptor(), (&protodb.QueryResp{}).ProtoReflect().Descriptor(), true),
+		},
+		protodb.ProtoDbSrvQueryProcedure: map[string]any{
+			"post": rpcOperation(b, "Query", "named query of querystore, server streaming",
+				(&protodb.QueryReq{}).ProtoReflect().Descriptor(), (&protodb.QueryResp{}).ProtoReflect().Descriptor(), true),
+		},
+		protodb.ProtoDbSrvDescribeSchemaProcedure: map[string]any{
+			"post": rpcOperation(b, "DescribeSchema", "tables and queries visible to the caller",
+				(&protodb.DescribeSchemaReq{}).ProtoReflect().Descriptor(), (&protodb.DescribeSchemaResp{}).ProtoReflect().Descriptor(), false),
+		},
+	}
+	for _, msgDesc := range msgDescs {
+		if pdbm, _ := pdbutil.GetPDBM(msgDesc); pdbm.NotDB {
+			continue
+		}
+		addTablePaths(b, paths, msgDesc)
+	}
+
+	return map[string]any{
+		"openapi":           OpenAPIVersion,
+		"jsonSchemaDialect": JSONSchemaDialect,
+		"info":              map[string]any{"title": title, "version": version},
+		"paths":             paths,
+		"components":        map[string]any{"schemas": b.defs},
+	}
+}
+
+// Handler serve Document of the messages registered in msgs(msgstore.GlobalMsgStore if nil) as json,
+// ?schema=<msg or table name> serves JSONSchema of the message instead
+func Handler(msgs *msgstore.TMsgStore, title string, version string) http.Handler {
+	if msgs == nil {
+		msgs = msgstore.GlobalMsgStore
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var doc map[string]any
+		if name := r.URL.Query().Get("schema"); len(name) > 0 {
+			msg, ok := msgs.GetMsg(name, false)
+			if !ok {
+				http.Error(w, "unknown message "+name, http.StatusNotFound)
+				return
+			}
+			doc = JSONSchema(msg.ProtoReflect().Descriptor())
+		} else {
+			// messages can be registered at runtime, the document is built per request
+			doc = Document(msgs.MsgDescriptors(), title, version)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(doc)
+	})
+}
+
+// rpcOperation operation of connect rpc with json codec
+func rpcOperation(b *schemaBuilder, operationId string, summary string, reqDesc protoreflect.MessageDescriptor, respDesc protoreflect.MessageDescriptor, streaming bool) map[string]any {
+	reqContentType, respContentType := "application/json", "application/json"
+	if streaming {
+		// connect streaming, each message is enveloped by 5 bytes(flags, length)
+		reqContentType, respContentType = "application/connect+json", "application/connect+json"
+	}
+	return map[string]any{
+		"operationId": operationId,
+		"summary":     summary,
+		"tags":        []string{"ProtoDbSrv"},
+		"requestBody": map[string]any{
+			"required": true,
+			"content":  map[string]any{reqContentType: map[string]any{"schema": b.msgRef(reqDesc)}},
+		},
+		"responses": map[string]any{
+			"200": map[string]any{
+				"description": "ok",
+				"content":     map[string]any{respContentType: map[string]any{"schema": b.msgRef(respDesc)}},
+			},
+		},
+	}
+}
+
+// addTablePaths rest gateway paths of table message
+func addTablePaths(b *schemaBuilder, paths map[string]any, msgDesc protoreflect.MessageDescriptor) {
+	tableName := pdbutil.GetTableName(msgDesc)
+	msgName := string(msgDesc.Name())
+	row := b.msgRef(msgDesc)
+	tags := []string{tableName}
+
+	rowResponse := func(description string) map[string]any {
+		return map[string]any{
+			"description": description,
+			"content":     map[string]any{"application/json": map[string]any{"schema": row}},
+		}
+	}
+	rowBody := map[string]any{
+		"required": true,
+		"content":  map[string]any{"application/json": map[string]any{"schema": row}},
+	}
+	withErrors := func(responses map[string]any) map[string]any {
+		for status, description := range map[string]string{"400": "bad request", "403": "permission denied", "404": "not found", "500": "server error"} {
+			responses[status] = map[string]any{
+				"description": description,
+				"content":     map[string]any{"application/json": map[string]any{"schema": map[string]any{"$ref": b.refPrefix + errorSchemaName}}},
+			}
+		}
+		return responses
+	}
+
+	queryParams := []any{
+		queryParam("order", "order by fields, '-' prefix for descending, like -created_at,id"),
+		queryParam("limit", "max rows, 0 for no limit"),
+		queryParam("offset", "rows to skip"),
+		queryParam("fields", "result fields, comma separated"),
+	}
+	fields := msgDesc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if pdb, _ := pdbutil.GetPDB(fd); pdb.NotDB {
+			continue
+		}
+		queryParams = append(queryParams, queryParam(string(fd.Name()), "where condition op:value, op is WhereOperator without WOP_, EQ if omitted"))
+	}
+
+	paths["/tables/"+tableName] = map[string]any{
+		"get": map[string]any{
+			"operationId": "query" + msgName,
+			"summary":     "TableQuery " + tableName + ", rows as NDJSON",
+			"tags":        tags,
+			"parameters":  queryParams,
+			"responses": withErrors(map[string]any{
+				"200": map[string]any{
+					"description": "one row per line",
+					"content":     map[string]any{"application/x-ndjson": map[string]any{"schema": row}},
+				},
+			}),
+		},
+		"post": map[string]any{
+			"operationId": "insert" + msgName,
+			"summary":     "Insert " + tableName,
+			"tags":        tags,
+			"requestBody": rowBody,
+			"responses":   withErrors(map[string]any{"201": rowResponse("inserted row")}),
+		},
+	}
+
+	var pkSegments []string
+	var pkParams []any
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if pdb, _ := pdbutil.GetPDB(fd); !pdb.IsPrimary() {
+			continue
+		}
+		pkSegments = append(pkSegments, "{"+string(fd.Name())+"}")
+		pkParams = append(pkParams, map[string]any{
+			"name":     string(fd.Name()),
+			"in":       "path",
+			"required": true,
+			"schema":   b.valueSchema(fd),
+		})
+	}
+	if len(pkSegments) == 0 {
+		return
+	}
+	paths["/tables/"+tableName+"/"+strings.Join(pkSegments, "/")] = map[string]any{
+		"parameters": pkParams,
+		"get": map[string]any{
+			"operationId": "get" + msgName,
+			"summary":     "SelectOne " + tableName + " by primary key",
+			"tags":        tags,
+			"parameters":  []any{queryParam("fields", "result fields, comma separated")},
+			"responses":   withErrors(map[string]any{"200": rowResponse("row")}),
+		},
+		"put": map[string]any{
+			"operationId": "update" + msgName,
+			"summary":     "Update " + tableName,
+			"tags":        tags,
+			"requestBody": rowBody,
+			"responses":   withErrors(map[string]any{"200": rowResponse("updated row")}),
+		},
+		"patch": map[string]any{
+			"operationId": "partialUpdate" + msgName,
+			"summary":     "PartialUpdate the fields in body of " + tableName,
+			"tags":        tags,
+			"requestBody": map[string]any{
+				"required": true,
+				"content": map[string]any{"application/json": map[string]any{"schema": map[string]any{
+					"type":        "object",
+					"description": "fields of " + string(msgDesc.FullName()) + " to update, required fields are not required",
+				}}},
+			},
+			"responses": withErrors(map[string]any{"200": rowResponse("updated row")}),
+		},
+		"delete": map[string]any{
+			"operationId": "delete" + msgName,
+			"summary":     "Delete " + tableName,
+			"tags":        tags,
+			"responses":   withErrors(map[string]any{"200": rowResponse("deleted row")}),
+		},
+	}
+}
+
+// queryParam optional string query parameter
+func queryParam(name string, description string) map[string]any {
+	return map[string]any{
+		"name":        name,
+		"in":          "query",
+		"required":    false,
+		"description": description,
+		"schema":      map[string]any{"type": "string"},
+	}
+}
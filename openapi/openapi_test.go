@@ -0,0 +1,164 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/internal/example/userpb"
+	"github.com/ygrpc/protodb/msgstore"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// roundTrip the generated document as decoded json
+func roundTrip(t *testing.T, doc map[string]any) map[string]any {
+	t.Helper()
+	b, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	return decoded
+}
+
+func lookup(t *testing.T, v any, path ...string) any {
+	t.Helper()
+	for _, key := range path {
+		m, ok := v.(map[string]any)
+		if !ok {
+			t.Fatalf("%v: not an object at %s", path, key)
+		}
+		v = m[key]
+	}
+	return v
+}
+
+func TestJSONSchemaOfTable(t *testing.T) {
+	schema := roundTrip(t, JSONSchema((&userpb.User{}).ProtoReflect().Descriptor()))
+	if schema["$ref"] != "#/$defs/userpb.User" {
+		t.Fatalf("$ref = %v", schema["$ref"])
+	}
+	user := lookup(t, schema, "$defs", "userpb.User")
+
+	if got := lookup(t, user, "required"); !reflect.DeepEqual(got, []any{"username"}) {
+		t.Fatalf("required = %v", got)
+	}
+	if lookup(t, user, "properties", "id", "readOnly") != true || lookup(t, user, "properties", "createdAt", "readOnly") != true {
+		t.Fatalf("serial id and NoUpdate createdAt should be readOnly: %v", lookup(t, user, "properties"))
+	}
+	if got := lookup(t, user, "properties", "status", "enum"); !reflect.DeepEqual(got, []any{"USER_STATUS_UNKNOWN", "USER_STATUS_ACTIVE", "USER_STATUS_DISABLED"}) {
+		t.Fatalf("status enum = %v", got)
+	}
+	if lookup(t, user, "properties", "createdAt", "format") != "date-time" {
+		t.Fatalf("createdAt = %v", lookup(t, user, "properties", "createdAt"))
+	}
+	if lookup(t, user, "properties", "tags", "type") != "array" {
+		t.Fatalf("tags = %v", lookup(t, user, "properties", "tags"))
+	}
+}
+
+func TestJSONSchemaDefaultsAndNested(t *testing.T) {
+	fieldOpts := func(pdb *protodb.PDBField) *descriptorpb.FieldOptions {
+		opts := &descriptorpb.FieldOptions{}
+		proto.SetExtension(opts, protodb.E_Pdb, pdb)
+		return opts
+	}
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type, opts *descriptorpb.FieldOptions) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     typ.Enum(),
+			Options:  opts,
+		}
+	}
+	node := field("parent", 4, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, nil)
+	node.TypeName = proto.String(".js.Node")
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Syntax:     proto.String("proto3"),
+		Name:       proto.String("js/node.proto"),
+		Package:    proto.String("js"),
+		Dependency: []string{"protodb.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Node"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("name", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, fieldOpts(&protodb.PDBField{DefaultValue: "'it''s'", NotNull: true})),
+				field("weight", 2, descriptorpb.FieldDescriptorProto_TYPE_INT32, fieldOpts(&protodb.PDBField{DefaultValue: "10", NoInsert: true})),
+				field("created", 3, descriptorpb.FieldDescriptorProto_TYPE_STRING, fieldOpts(&protodb.PDBField{DefaultValue: "now()"})),
+				node,
+				field("code", 5, descriptorpb.FieldDescriptorProto_TYPE_STRING, fieldOpts(&protodb.PDBField{NotNull: true, NoInsert: true})),
+				field("title", 6, descriptorpb.FieldDescriptorProto_TYPE_STRING, fieldOpts(&protodb.PDBField{NotNull: true})),
+			},
+		}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile: %v", err)
+	}
+
+	schema := roundTrip(t, JSONSchema(fd.Messages().ByName("Node")))
+	props := lookup(t, schema, "$defs", "js.Node", "properties")
+	if lookup(t, props, "name", "default") != "it's" || lookup(t, props, "weight", "default") != float64(10) {
+		t.Fatalf("defaults = %v", props)
+	}
+	if lookup(t, props, "weight", "readOnly") != true {
+		t.Fatalf("NoInsert weight should be readOnly")
+	}
+	if _, ok := lookup(t, props, "created").(map[string]any)["default"]; ok {
+		t.Fatalf("sql expression default should be skipped")
+	}
+	// NotNull name has a default and NotNull code is NoInsert, only title must be sent
+	if got := lookup(t, schema, "$defs", "js.Node", "required"); !reflect.DeepEqual(got, []any{"title"}) {
+		t.Fatalf("required = %v, want [title]", got)
+	}
+	if lookup(t, props, "parent", "$ref") != "#/$defs/js.Node" {
+		t.Fatalf("recursive parent = %v", lookup(t, props, "parent"))
+	}
+}
+
+func TestDocumentAndHandler(t *testing.T) {
+	msgs := msgstore.NewMsgStore()
+	msgs.RegisterMsg("User", func(new bool) proto.Message { return &userpb.User{} })
+
+	doc := roundTrip(t, Document(msgs.MsgDescriptors(), "test", "1.0"))
+	paths := lookup(t, doc, "paths").(map[string]any)
+	for _, path := range []string{protodb.ProtoDbSrvCrudProcedure, protodb.ProtoDbSrvTableQueryProcedure, "/tables/t_user", "/tables/t_user/{id}"} {
+		if _, ok := paths[path]; !ok {
+			t.Errorf("path %s missing", path)
+		}
+	}
+	if _, ok := lookup(t, paths, "/tables/t_user/{id}").(map[string]any)["patch"]; !ok {
+		t.Errorf("patch missing")
+	}
+	if lookup(t, paths, "/tables/t_user", "get", "responses", "200", "content", "application/x-ndjson", "schema", "$ref") != "#/components/schemas/userpb.User" {
+		t.Errorf("table query response = %v", lookup(t, paths, "/tables/t_user", "get", "responses", "200"))
+	}
+	schemas := lookup(t, doc, "components", "schemas").(map[string]any)
+	for _, name := range []string{"userpb.User", "protodb.CrudReq", "protodb.QueryResp", errorSchemaName} {
+		if _, ok := schemas[name]; !ok {
+			t.Errorf("schema %s missing", name)
+		}
+	}
+
+	h := Handler(msgs, "test", "1.0")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json?schema=t_user", nil))
+	var schema map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &schema); err != nil || schema["$ref"] != "#/$defs/userpb.User" {
+		t.Fatalf("schema of t_user = %d %s", w.Code, w.Body)
+	}
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json?schema=nope", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("unknown schema = %d", w.Code)
+	}
+}
@@ -51,6 +51,19 @@ func (x *PDBField) IsZeroAsNull() bool {
 	return x.ZeroAsNull
 }
 
+// is exact decimal NUMERIC db type
+func (x *PDBField) IsNumeric() bool {
+	return x.DbType == FieldDbType_NUMERIC
+}
+
+// numeric type args like (12,2), empty when precision is not set
+func (x *PDBField) NumericTypeArgs() string {
+	if x.NumericPrecision <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("(%d,%d)", x.NumericPrecision, x.NumericScale)
+}
+
 // need in insert
 func (x *PDBField) NeedInInsert() bool {
 	if x.NotDB {
@@ -95,314 +108,69 @@ func (x *PDBField) DefaultValue2SQLArgs() (sqlArgs interface{}) {
 	return x.DefaultValue
 }
 
-// PdbDbTypeStr get db type string from pdb if specified
-func (x *PDBField) PdbDbTypeStrPostgresql(fieldMsg protoreflect.FieldDescriptor) string {
-	switch x.DbType {
-	case FieldDbType_AutoMatch:
-		switch x.SerialType {
-		case 0:
-			return GetProtoDBType(fieldMsg.Kind(), sqldb.Postgres)
-		case 2:
-			return "smallserial"
-		case 4:
-			return "serial"
-		case 8:
-			return "bigserial"
-
-		default:
-			fmt.Println("todo: PdbDbTypeStr unknown serial type:", x.SerialType)
-			return "text"
-		}
-
-	//bool
-	case FieldDbType_BOOL:
-		return "boolean"
-		//int32
-	case FieldDbType_INT32:
-		return "integer"
-		//uint32
-	case FieldDbType_UINT32:
-		return "bigint"
-		//int64
-	case FieldDbType_INT64:
-		return "bigint"
-		//float
-	case FieldDbType_FLOAT:
-		return "real"
-	case FieldDbType_DOUBLE:
-		return "double precision"
-		//text
-	case FieldDbType_TEXT:
-		return "text"
-		//jsonb
-	case FieldDbType_JSONB:
-		return "jsonb"
-		//uuid
-	case FieldDbType_UUID:
-		return "uuid"
-		//timestamp
-	case FieldDbType_TIMESTAMP:
-		return "timestamp"
-		//date
-	case FieldDbType_DATE:
-		return "date"
-		//bytea
-	case FieldDbType_BYTEA:
-		return "bytea"
-		//inet
-	case FieldDbType_INET:
-		return "inet"
-
-	default:
-		//todo
-		fmt.Println("todo: PdbDbTypeStr unknown db type:", x.DbType)
-		return ""
+// ColumnType column type of field asked from sqldb.Dialect
+func (x *PDBField) ColumnType(fieldMsg protoreflect.FieldDescriptor) sqldb.ColumnType {
+	if x.DbType != FieldDbType_AutoMatch {
+		return sqldb.ColumnType{DbType: x.DbType.String(), Precision: x.NumericPrecision, Scale: x.NumericScale}
 	}
+	if x.SerialType != 0 {
+		return sqldb.ColumnType{SerialSize: x.SerialType}
+	}
+	return FieldColumnType(fieldMsg)
 }
 
-func (x *PDBField) PdbDbTypeStrMysql(fieldMsg protoreflect.FieldDescriptor) string {
-	switch x.DbType {
-	case FieldDbType_AutoMatch:
-		switch x.SerialType {
-		case 0:
-			return GetProtoDBType(fieldMsg.Kind(), sqldb.Mysql)
-		case 2:
-			return "smallint AUTO_INCREMENT"
-		case 4:
-			return "int AUTO_INCREMENT"
-		case 8:
-			return "bigint AUTO_INCREMENT"
-		default:
-			fmt.Println("todo: PdbDbTypeStr unknown serial type:", x.SerialType)
-			return "text"
-		}
-
-	//bool
-	case FieldDbType_BOOL:
-		return "boolean"
-	//int32
-	case FieldDbType_INT32:
-		return "int"
-	//uint32
-	case FieldDbType_UINT32:
-		return "int unsigned"
-	//int64
-	case FieldDbType_INT64:
-		return "bigint"
-	//float
-	case FieldDbType_FLOAT:
-		return "float"
-	case FieldDbType_DOUBLE:
-		return "double"
-	//text
-	case FieldDbType_TEXT:
-		return "text"
-	//jsonb
-	case FieldDbType_JSONB:
-		return "json"
-	//uuid
-	case FieldDbType_UUID:
-		return "binary(16)"
-	//timestamp
-	case FieldDbType_TIMESTAMP:
-		return "timestamp"
-	//date
-	case FieldDbType_DATE:
-		return "date"
-	//bytea
-	case FieldDbType_BYTEA:
-		return "blob"
-	//inet
-	case FieldDbType_INET:
-		return "text"
-
-	default:
-		fmt.Println("todo: PdbDbTypeStr unknown db type:", x.DbType)
-		return ""
+// FieldColumnType column type of field by proto type, well-known message types are mapped to native db types
+func FieldColumnType(fieldMsg protoreflect.FieldDescriptor) sqldb.ColumnType {
+	if fieldMsg.Kind() == protoreflect.MessageKind && !fieldMsg.IsList() && !fieldMsg.IsMap() {
+		return wellKnownColumnType(fieldMsg.Message().FullName())
 	}
+	return sqldb.ColumnType{Kind: fieldMsg.Kind()}
+}
+
+// PdbDbTypeStrPostgresql get db type string of postgres, DbTypeStr is not used
+func (x *PDBField) PdbDbTypeStrPostgresql(fieldMsg protoreflect.FieldDescriptor) string {
+	return sqldb.Postgres.Dialect().ColumnType(x.ColumnType(fieldMsg))
+}
+
+// PdbDbTypeStrMysql get db type string of mysql, DbTypeStr is not used
+func (x *PDBField) PdbDbTypeStrMysql(fieldMsg protoreflect.FieldDescriptor) string {
+	return sqldb.Mysql.Dialect().ColumnType(x.ColumnType(fieldMsg))
 }
 
+// PdbDbTypeStrSQLite get db type string of sqlite, DbTypeStr is not used
 func (x *PDBField) PdbDbTypeStrSQLite(fieldMsg protoreflect.FieldDescriptor) string {
-	switch x.DbType {
-	case FieldDbType_AutoMatch:
-		switch x.SerialType {
-		case 0:
-			return GetProtoDBType(fieldMsg.Kind(), sqldb.SQLite)
-		case 2:
-			return "integer"
-		case 4:
-			return "integer"
-		case 8:
-			return "integer"
-
-		default:
-			fmt.Println("todo: PdbDbTypeStr unknown serial type:", x.SerialType)
-			return "text"
-		}
-
-	//bool
-	case FieldDbType_BOOL:
-		return "integer"
-		//int32
-	case FieldDbType_INT32:
-		return "integer"
-		//uint32
-	case FieldDbType_UINT32:
-		return "integer"
-		//int64
-	case FieldDbType_INT64:
-		return "integer"
-		//float
-	case FieldDbType_FLOAT:
-		return "real"
-	case FieldDbType_DOUBLE:
-		return "double precision"
-		//text
-	case FieldDbType_TEXT:
-		return "text"
-		//jsonb
-	case FieldDbType_JSONB:
-		return "jsonb"
-		//uuid
-	case FieldDbType_UUID:
-		return "uuid"
-		//timestamp
-	case FieldDbType_TIMESTAMP:
-		return "timestamp"
-		//date
-	case FieldDbType_DATE:
-		return "date"
-		//bytea
-	case FieldDbType_BYTEA:
-		return "blob"
-		//inet
-	case FieldDbType_INET:
-		return "inet"
-
-	default:
-		//todo
-		fmt.Println("todo: PdbDbTypeStr unknown db type:", x.DbType)
-		return ""
-	}
+	return sqldb.SQLite.Dialect().ColumnType(x.ColumnType(fieldMsg))
 }
 
+// PdbDbTypeStr get db type string from pdb if specified, otherwise from dialect
 func (x *PDBField) PdbDbTypeStr(fieldMsg protoreflect.FieldDescriptor, dialect sqldb.TDBDialect) string {
 	if len(x.DbTypeStr) > 0 {
 		return x.DbTypeStr
 	}
-
-	switch dialect {
-	case sqldb.Postgres:
-		return x.PdbDbTypeStrPostgresql(fieldMsg)
-	case sqldb.Mysql:
-		return x.PdbDbTypeStrMysql(fieldMsg)
-	case sqldb.SQLite:
-		return x.PdbDbTypeStrSQLite(fieldMsg)
-	default:
-		fmt.Println("todo: PdbDbTypeStr unknown db type:", x.DbType)
-		return ""
+	dbType := dialect.Dialect().ColumnType(x.ColumnType(fieldMsg))
+	if len(dbType) == 0 {
+		fmt.Println("todo: PdbDbTypeStr unknown db type:", x.DbType, dialect)
 	}
+	return dbType
 }
-func GetProtoDBType(fieldType protoreflect.Kind, dialect sqldb.TDBDialect) string {
-	switch dialect {
-	case sqldb.Postgres:
-		return GetProtoDBTypePostgresql(fieldType)
-	case sqldb.Mysql:
-		return GetProtoDBTypeMysql(fieldType)
-	case sqldb.SQLite:
-		return GetProtoDBTypeSQLite(fieldType)
-	default:
-		fmt.Println("todo: PdbDbTypeStr unknown db type:", fieldType)
-		return ""
-	}
 
+// GetProtoDBTypeOfField get db type of field, well-known message types are mapped to native db types
+func GetProtoDBTypeOfField(fieldMsg protoreflect.FieldDescriptor, dialect sqldb.TDBDialect) string {
+	return dialect.Dialect().ColumnType(FieldColumnType(fieldMsg))
 }
 
-func GetProtoDBTypeSQLite(fieldType protoreflect.Kind) string {
-	switch fieldType {
-	case protoreflect.BoolKind:
-		return "integer"
-	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
-		return "integer"
-	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
-		return "integer"
-	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
-		return "integer"
-	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
-		return "integer"
-	case protoreflect.FloatKind:
-		return "real"
-	case protoreflect.DoubleKind:
-		return "real"
-	case protoreflect.StringKind:
-		return "text"
-	case protoreflect.BytesKind:
-		return "blob"
-	case protoreflect.EnumKind:
-		return "integer"
-	case protoreflect.MessageKind:
-		return "blob"
-
-	default:
-		return "blob"
-	}
+func GetProtoDBType(fieldType protoreflect.Kind, dialect sqldb.TDBDialect) string {
+	return dialect.Dialect().ColumnType(sqldb.ColumnType{Kind: fieldType})
+}
 
+func GetProtoDBTypeSQLite(fieldType protoreflect.Kind) string {
+	return GetProtoDBType(fieldType, sqldb.SQLite)
 }
 
 func GetProtoDBTypeMysql(fieldType protoreflect.Kind) string {
-	switch fieldType {
-	case protoreflect.BoolKind:
-		return "boolean"
-	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
-		return "int"
-	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
-		return "bigint"
-	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
-		return "int unsigned"
-	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
-		return "bigint unsigned"
-	case protoreflect.FloatKind:
-		return "float"
-	case protoreflect.DoubleKind:
-		return "double"
-	case protoreflect.StringKind:
-		return "text"
-	case protoreflect.BytesKind:
-		return "blob"
-	case protoreflect.EnumKind:
-		return "int"
-	case protoreflect.MessageKind:
-		return "json"
-	default:
-		return "text"
-	}
+	return GetProtoDBType(fieldType, sqldb.Mysql)
 }
+
 func GetProtoDBTypePostgresql(fieldType protoreflect.Kind) string {
-	switch fieldType {
-	case protoreflect.BoolKind:
-		return "boolean"
-	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
-		return "integer"
-	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
-		return "bigint"
-	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
-		return "bigint"
-	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
-		return "bigint"
-	case protoreflect.FloatKind:
-		return "real"
-	case protoreflect.DoubleKind:
-		return "double precision"
-	case protoreflect.StringKind:
-		return "text"
-	case protoreflect.BytesKind:
-		return "bytea"
-	case protoreflect.EnumKind:
-		return "integer"
-	case protoreflect.MessageKind:
-		return "jsonb"
-
-	default:
-		return "text"
-	}
+	return GetProtoDBType(fieldType, sqldb.Postgres)
 }
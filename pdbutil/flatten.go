@@ -0,0 +1,132 @@
+package pdbutil
+
+import (
+	"strings"
+
+	"github.com/puzpuzpuz/xsync/v3"
+	"github.com/ygrpc/protodb"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// FlatColumn db column of a field in flattened sub message
+type FlatColumn struct {
+	// column name, column names of fields in path joined by '_', like address_city
+	Name string
+	// field path from the table message, the last one is the column field
+	Path []protoreflect.FieldDescriptor
+}
+
+// Field the sub message field stored in this column
+func (c *FlatColumn) Field() protoreflect.FieldDescriptor {
+	return c.Path[len(c.Path)-1]
+}
+
+// DottedPath field path joined by '.', like address.city
+func (c *FlatColumn) DottedPath() string {
+	names := make([]string, len(c.Path))
+	for i, fd := range c.Path {
+		names[i] = string(fd.Name())
+	}
+	return strings.Join(names, ".")
+}
+
+var flatColumnsCache = xsync.NewMapOf[descriptorCacheKey, []*FlatColumn]()
+var msgFlatColumnsCache = xsync.NewMapOf[descriptorCacheKey, map[string]*FlatColumn]()
+
+// IsFlattenField is the field a singular sub message stored as prefixed columns
+func IsFlattenField(fieldDescriptor protoreflect.FieldDescriptor) bool {
+	if fieldDescriptor.Kind() != protoreflect.MessageKind || fieldDescriptor.IsList() || fieldDescriptor.IsMap() {
+		return false
+	}
+	if protodb.IsWellKnownMsg(fieldDescriptor.Message().FullName()) {
+		return false
+	}
+	pdb, _ := GetPDB(fieldDescriptor)
+	return pdb.Flatten && !pdb.NotDB
+}
+
+// GetFlatColumns get columns of flattened sub message field, nested flattened fields are expanded too
+func GetFlatColumns(fieldDescriptor protoreflect.FieldDescriptor) []*FlatColumn {
+	if !IsFlattenField(fieldDescriptor) {
+		return nil
+	}
+	cacheKey := makeDescriptorCacheKey(fieldDescriptor)
+	if cached, ok := flatColumnsCache.Load(cacheKey); ok {
+		return cached
+	}
+
+	columns := appendFlatColumns(nil, []protoreflect.FieldDescriptor{fieldDescriptor}, GetColumnName(fieldDescriptor))
+	if cached, loaded := flatColumnsCache.LoadOrStore(cacheKey, columns); loaded {
+		return cached
+	}
+	return columns
+}
+
+func appendFlatColumns(columns []*FlatColumn, path []protoreflect.FieldDescriptor, prefix string) []*FlatColumn {
+	parent := path[len(path)-1]
+	subFields := parent.Message().Fields()
+	for i := 0; i < subFields.Len(); i++ {
+		subField := subFields.Get(i)
+		pdb, _ := GetPDB(subField)
+		if pdb.NotDB {
+			continue
+		}
+		subPath := make([]protoreflect.FieldDescriptor, len(path)+1)
+		copy(subPath, path)
+		subPath[len(path)] = subField
+		name := prefix + "_" + GetColumnName(subField)
+		// guard recursive message types
+		if IsFlattenField(subField) && !pathHasMessage(path, subField.Message().FullName()) {
+			columns = appendFlatColumns(columns, subPath, name)
+			continue
+		}
+		columns = append(columns, &FlatColumn{Name: name, Path: subPath})
+	}
+	return columns
+}
+
+func pathHasMessage(path []protoreflect.FieldDescriptor, msgName protoreflect.FullName) bool {
+	if len(path) > 0 && path[0].ContainingMessage().FullName() == msgName {
+		return true
+	}
+	for _, fd := range path {
+		if fd.Message().FullName() == msgName {
+			return true
+		}
+	}
+	return false
+}
+
+// GetMsgFlatColumns get all flattened columns of message, lowercase column name and dotted path -> column
+func GetMsgFlatColumns(msgDescriptor protoreflect.MessageDescriptor) map[string]*FlatColumn {
+	cacheKey := makeDescriptorCacheKey(msgDescriptor)
+	if cached, ok := msgFlatColumnsCache.Load(cacheKey); ok {
+		return cached
+	}
+
+	var columnsMap map[string]*FlatColumn
+	fields := msgDescriptor.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		for _, column := range GetFlatColumns(fields.Get(i)) {
+			if columnsMap == nil {
+				columnsMap = make(map[string]*FlatColumn)
+			}
+			columnsMap[strings.ToLower(column.Name)] = column
+			columnsMap[strings.ToLower(column.DottedPath())] = column
+		}
+	}
+	if cached, loaded := msgFlatColumnsCache.LoadOrStore(cacheKey, columnsMap); loaded {
+		return cached
+	}
+	return columnsMap
+}
+
+// FindFlatColumn find flattened column by column name(address_city) or dotted path(address.city), case insensitive
+func FindFlatColumn(msgDescriptor protoreflect.MessageDescriptor, name string) (*FlatColumn, bool) {
+	columnsMap := GetMsgFlatColumns(msgDescriptor)
+	if columnsMap == nil {
+		return nil, false
+	}
+	column, ok := columnsMap[strings.ToLower(name)]
+	return column, ok
+}
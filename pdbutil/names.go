@@ -0,0 +1,103 @@
+package pdbutil
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/puzpuzpuz/xsync/v3"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// NameStyleSnake PDBFile.NameStyle for snake style table and column names, like user_name
+const NameStyleSnake = "snake"
+
+var tableNameCache = xsync.NewMapOf[descriptorCacheKey, string]()
+var columnNameCache = xsync.NewMapOf[descriptorCacheKey, string]()
+
+// ToSnakeName convert go style name to snake style, like UserName -> user_name, HTTPServer -> http_server
+func ToSnakeName(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	sb.Grow(len(name) + 4)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && runes[i-1] != '_' {
+				prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+				nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+				if prevLower || (nextLower && unicode.IsUpper(runes[i-1])) {
+					sb.WriteByte('_')
+				}
+			}
+			sb.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// IsSnakeNameStyle is the file NameStyle snake
+func IsSnakeNameStyle(fileDescriptor protoreflect.FileDescriptor) bool {
+	if fileDescriptor == nil {
+		return false
+	}
+	pdbf, _ := GetPDBF(fileDescriptor)
+	return strings.EqualFold(pdbf.NameStyle, NameStyleSnake)
+}
+
+// styledName name of descriptor in the NameStyle of its file
+func styledName(desc protoreflect.Descriptor) string {
+	if IsSnakeNameStyle(desc.ParentFile()) {
+		return ToSnakeName(string(desc.Name()))
+	}
+	return string(desc.Name())
+}
+
+// GetTableName db table name of message, PDBMsg.TableName or message name in file NameStyle
+func GetTableName(msgDescriptor protoreflect.MessageDescriptor) string {
+	cacheKey := makeDescriptorCacheKey(msgDescriptor)
+	if cached, ok := tableNameCache.Load(cacheKey); ok {
+		return cached
+	}
+
+	tableName := styledName(msgDescriptor)
+	if pdbm, _ := GetPDBM(msgDescriptor); len(pdbm.TableName) > 0 {
+		tableName = pdbm.TableName
+	}
+	tableNameCache.Store(cacheKey, tableName)
+	return tableName
+}
+
+// GetColumnName db column name of field, PDBField.ColumnName or field name in file NameStyle
+func GetColumnName(fieldDescriptor protoreflect.FieldDescriptor) string {
+	cacheKey := makeDescriptorCacheKey(fieldDescriptor)
+	if cached, ok := columnNameCache.Load(cacheKey); ok {
+		return cached
+	}
+
+	columnName := styledName(fieldDescriptor)
+	if pdb, _ := GetPDB(fieldDescriptor); len(pdb.ColumnName) > 0 {
+		columnName = pdb.ColumnName
+	}
+	columnNameCache.Store(cacheKey, columnName)
+	return columnName
+}
+
+// FindFieldByColumnName find field of message by column name or field name, case insensitive.
+// flattened columns are not included, use FindFlatColumn for them
+func FindFieldByColumnName(msgDescriptor protoreflect.MessageDescriptor, name string) (protoreflect.FieldDescriptor, bool) {
+	lower := strings.ToLower(name)
+	for _, fieldInfo := range getMsgFieldInfos(msgDescriptor.Fields()) {
+		if fieldInfo.lower == lower {
+			return fieldInfo.field, true
+		}
+	}
+	fields := msgDescriptor.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if strings.EqualFold(string(field.Name()), name) || strings.EqualFold(field.TextName(), name) {
+			return field, true
+		}
+	}
+	return nil, false
+}
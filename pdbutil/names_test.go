@@ -0,0 +1,83 @@
+package pdbutil
+
+import (
+	"testing"
+
+	"github.com/ygrpc/protodb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestToSnakeName(t *testing.T) {
+	tests := map[string]string{
+		"UserName":    "user_name",
+		"user_name":   "user_name",
+		"ID":          "id",
+		"UserID":      "user_id",
+		"HTTPServer":  "http_server",
+		"Address2Zip": "address2_zip",
+		"already_Ok":  "already_ok",
+	}
+	for name, want := range tests {
+		if got := ToSnakeName(name); got != want {
+			t.Errorf("ToSnakeName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestGetTableNameAndColumnName(t *testing.T) {
+	fileOpts := &descriptorpb.FileOptions{}
+	proto.SetExtension(fileOpts, protodb.E_Pdbf, &protodb.PDBFile{NameStyle: NameStyleSnake})
+	msgOpts := &descriptorpb.MessageOptions{}
+	proto.SetExtension(msgOpts, protodb.E_Pdbm, &protodb.PDBMsg{TableName: "t_orders"})
+	columnOpts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(columnOpts, protodb.E_Pdb, &protodb.PDBField{ColumnName: "mail"})
+
+	field := func(name string, number int32, opts *descriptorpb.FieldOptions) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:    proto.String(name),
+			Number:  proto.Int32(number),
+			Label:   descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:    descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			Options: opts,
+		}
+	}
+	fdp := &descriptorpb.FileDescriptorProto{
+		Syntax:  proto.String("proto3"),
+		Name:    proto.String("names_test.proto"),
+		Package: proto.String("test"),
+		Options: fileOpts,
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("UserAccount"), Field: []*descriptorpb.FieldDescriptorProto{field("DisplayName", 1, nil), field("EMail", 2, columnOpts)}},
+			{Name: proto.String("Order"), Options: msgOpts},
+		},
+	}
+	fd, err := protodesc.NewFile(fdp, nil)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile: %v", err)
+	}
+
+	user := fd.Messages().ByName("UserAccount")
+	if got := GetTableName(user); got != "user_account" {
+		t.Fatalf("snake table name = %q", got)
+	}
+	if got := GetTableName(fd.Messages().ByName("Order")); got != "t_orders" {
+		t.Fatalf("TableName override = %q", got)
+	}
+	if got := GetColumnName(user.Fields().ByName("DisplayName")); got != "display_name" {
+		t.Fatalf("snake column name = %q", got)
+	}
+	if got := GetColumnName(user.Fields().ByName("EMail")); got != "mail" {
+		t.Fatalf("ColumnName override = %q", got)
+	}
+
+	for _, name := range []string{"display_name", "DisplayName", "MAIL", "EMail"} {
+		if _, ok := FindFieldByColumnName(user, name); !ok {
+			t.Fatalf("FindFieldByColumnName(%q) not found", name)
+		}
+	}
+	if got := BuildMsgFieldsMap(nil, user.Fields(), true); got["display_name"] == nil || got["mail"] == nil {
+		t.Fatalf("msg fields map should use column names: %v", got)
+	}
+}
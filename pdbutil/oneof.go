@@ -0,0 +1,87 @@
+package pdbutil
+
+import (
+	"strings"
+
+	"github.com/puzpuzpuz/xsync/v3"
+	"github.com/ygrpc/protodb"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var EmptyPDBO = &protodb.PDBOneof{}
+
+type pdboCacheItem struct {
+	pdbo  *protodb.PDBOneof
+	found bool
+}
+
+var pdboCache = xsync.NewMapOf[descriptorCacheKey, pdboCacheItem]()
+
+func GetPDBO(oneofDescriptor protoreflect.OneofDescriptor) (pdbo *protodb.PDBOneof, found bool) {
+	cacheKey := makeDescriptorCacheKey(oneofDescriptor)
+	if cached, ok := pdboCache.Load(cacheKey); ok {
+		return cached.pdbo, cached.found
+	}
+
+	oneofOptions := oneofDescriptor.Options()
+	if oneofOptions == nil {
+		pdboCache.Store(cacheKey, pdboCacheItem{pdbo: EmptyPDBO, found: false})
+		return EmptyPDBO, false
+	}
+
+	pdbo, found = getOptionExtension[*protodb.PDBOneof](oneofOptions, protodb.E_Pdbo)
+	if !found {
+		pdbo = EmptyPDBO
+	}
+	pdboCache.Store(cacheKey, pdboCacheItem{pdbo: pdbo, found: found})
+	return pdbo, found
+}
+
+// GetRealOneof get the oneof of field, nil for normal fields and proto3 optional(synthetic oneof)
+func GetRealOneof(fieldDescriptor protoreflect.FieldDescriptor) protoreflect.OneofDescriptor {
+	oneofDesc := fieldDescriptor.ContainingOneof()
+	if oneofDesc == nil || oneofDesc.IsSynthetic() {
+		return nil
+	}
+	return oneofDesc
+}
+
+// GetOneofDiscriminator get discriminator column name of oneof, ok is false if not enabled.
+// default column name is oneof name in file NameStyle + "_case"
+func GetOneofDiscriminator(oneofDescriptor protoreflect.OneofDescriptor) (columnName string, ok bool) {
+	if oneofDescriptor.IsSynthetic() {
+		return "", false
+	}
+	pdbo, _ := GetPDBO(oneofDescriptor)
+	if !pdbo.Discriminator {
+		return "", false
+	}
+	if len(pdbo.DiscriminatorColumn) > 0 {
+		return pdbo.DiscriminatorColumn, true
+	}
+	return styledName(oneofDescriptor) + "_case", true
+}
+
+// FindOneofByDiscriminator find oneof by discriminator column name, case insensitive
+func FindOneofByDiscriminator(msgDescriptor protoreflect.MessageDescriptor, columnName string) (protoreflect.OneofDescriptor, bool) {
+	oneofs := msgDescriptor.Oneofs()
+	for i := 0; i < oneofs.Len(); i++ {
+		oneofDesc := oneofs.Get(i)
+		if discriminator, ok := GetOneofDiscriminator(oneofDesc); ok && strings.EqualFold(discriminator, columnName) {
+			return oneofDesc, true
+		}
+	}
+	return nil, false
+}
+
+// FindRealOneof find non synthetic oneof by name, case insensitive
+func FindRealOneof(msgDescriptor protoreflect.MessageDescriptor, oneofName string) (protoreflect.OneofDescriptor, bool) {
+	oneofs := msgDescriptor.Oneofs()
+	for i := 0; i < oneofs.Len(); i++ {
+		oneofDesc := oneofs.Get(i)
+		if !oneofDesc.IsSynthetic() && strings.EqualFold(string(oneofDesc.Name()), oneofName) {
+			return oneofDesc, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,50 @@
+package pdbutil
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// getOptionExtension protodb option xt of opts. options of a runtime descriptor set decoded
+// without the protodb extensions(another resolver) keep them in unknown fields, they are decoded here
+func getOptionExtension[T proto.Message](opts proto.Message, xt protoreflect.ExtensionType) (ext T, found bool) {
+	if proto.HasExtension(opts, xt) {
+		ext, found = proto.GetExtension(opts, xt).(T)
+		return ext, found
+	}
+
+	if !opts.ProtoReflect().IsValid() {
+		return ext, false
+	}
+	num := xt.TypeDescriptor().Number()
+	var raw []byte
+	for b := opts.ProtoReflect().GetUnknown(); len(b) > 0; {
+		fieldNum, wireType, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return ext, false
+		}
+		b = b[n:]
+		m := protowire.ConsumeFieldValue(fieldNum, wireType, b)
+		if m < 0 {
+			return ext, false
+		}
+		if fieldNum == num && wireType == protowire.BytesType {
+			v, _ := protowire.ConsumeBytes(b[:m])
+			// repeated occurrences of a message are merged
+			raw = append(raw, v...)
+			found = true
+		}
+		b = b[m:]
+	}
+	if !found {
+		return ext, false
+	}
+
+	msg := xt.New().Message().Interface()
+	if err := proto.Unmarshal(raw, msg); err != nil {
+		return ext, false
+	}
+	ext, found = msg.(T)
+	return ext, found
+}
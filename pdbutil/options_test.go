@@ -0,0 +1,52 @@
+package pdbutil
+
+import (
+	"testing"
+
+	"github.com/ygrpc/protodb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestGetPDBFromUnknownFields(t *testing.T) {
+	fieldOpts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(fieldOpts, protodb.E_Pdb, &protodb.PDBField{Primary: true, UniqueName: "uk_a"})
+	b, err := proto.Marshal(fieldOpts)
+	if err != nil {
+		t.Fatalf("proto.Marshal: %v", err)
+	}
+	// decoded without the protodb extensions, the option is kept as unknown fields
+	unknownOpts := &descriptorpb.FieldOptions{}
+	if err := (proto.UnmarshalOptions{Resolver: new(protoregistry.Types)}).Unmarshal(b, unknownOpts); err != nil {
+		t.Fatalf("proto.Unmarshal: %v", err)
+	}
+	if proto.HasExtension(unknownOpts, protodb.E_Pdb) {
+		t.Fatalf("option should be unknown")
+	}
+
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Syntax:  proto.String("proto3"),
+		Name:    proto.String("options_unknown_test.proto"),
+		Package: proto.String("test"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("UnknownOpts"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:    proto.String("a"),
+				Number:  proto.Int32(1),
+				Label:   descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:    descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				Options: unknownOpts,
+			}},
+		}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile: %v", err)
+	}
+
+	pdb, found := GetPDB(fd.Messages().Get(0).Fields().Get(0))
+	if !found || !pdb.Primary || pdb.UniqueName != "uk_a" {
+		t.Fatalf("GetPDB = (%v, %v), want primary with unique name uk_a", pdb, found)
+	}
+}
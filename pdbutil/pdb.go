@@ -7,7 +7,6 @@ import (
 
 	"github.com/puzpuzpuz/xsync/v3"
 	"github.com/ygrpc/protodb"
-	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
@@ -15,6 +14,7 @@ var NullValue = sql.NullString{String: "", Valid: false}
 
 var EmptyPDB = &protodb.PDBField{}
 var EmptyPDBM = &protodb.PDBMsg{}
+var EmptyPDBF = &protodb.PDBFile{}
 
 type pdbCacheItem struct {
 	pdb   *protodb.PDBField
@@ -26,6 +26,11 @@ type pdbmCacheItem struct {
 	found bool
 }
 
+type pdbfCacheItem struct {
+	pdbf  *protodb.PDBFile
+	found bool
+}
+
 type descriptorCacheKey struct {
 	desc protoreflect.Descriptor
 }
@@ -38,6 +43,8 @@ type msgFieldInfo struct {
 
 var pdbCache = xsync.NewMapOf[descriptorCacheKey, pdbCacheItem]()
 var pdbmCache = xsync.NewMapOf[descriptorCacheKey, pdbmCacheItem]()
+var pdbfCache = xsync.NewMapOf[descriptorCacheKey, pdbfCacheItem]()
+var enumStorageCache = xsync.NewMapOf[descriptorCacheKey, protodb.EnumStorage]()
 var msgFieldInfoCache = xsync.NewMapOf[descriptorCacheKey, []msgFieldInfo]()
 
 func GetPDB(fieldDescriptor protoreflect.FieldDescriptor) (pdb *protodb.PDBField, found bool) {
@@ -52,12 +59,7 @@ func GetPDB(fieldDescriptor protoreflect.FieldDescriptor) (pdb *protodb.PDBField
 		return EmptyPDB, false
 	}
 
-	if !proto.HasExtension(fieldOptions, protodb.E_Pdb) {
-		pdbCache.Store(cacheKey, pdbCacheItem{pdb: EmptyPDB, found: false})
-		return EmptyPDB, false
-	}
-
-	pdb, found = proto.GetExtension(fieldOptions, protodb.E_Pdb).(*protodb.PDBField)
+	pdb, found = getOptionExtension[*protodb.PDBField](fieldOptions, protodb.E_Pdb)
 	if !found {
 		pdb = EmptyPDB
 	}
@@ -77,12 +79,7 @@ func GetPDBM(msgDescriptor protoreflect.MessageDescriptor) (pdbm *protodb.PDBMsg
 		return EmptyPDBM, false
 	}
 
-	if !proto.HasExtension(msgOptions, protodb.E_Pdbm) {
-		pdbmCache.Store(cacheKey, pdbmCacheItem{pdbm: EmptyPDBM, found: false})
-		return EmptyPDBM, false
-	}
-
-	pdbm, found = proto.GetExtension(msgOptions, protodb.E_Pdbm).(*protodb.PDBMsg)
+	pdbm, found = getOptionExtension[*protodb.PDBMsg](msgOptions, protodb.E_Pdbm)
 	if !found {
 		pdbm = EmptyPDBM
 	}
@@ -90,6 +87,61 @@ func GetPDBM(msgDescriptor protoreflect.MessageDescriptor) (pdbm *protodb.PDBMsg
 	return pdbm, found
 }
 
+func GetPDBF(fileDescriptor protoreflect.FileDescriptor) (pdbf *protodb.PDBFile, found bool) {
+	cacheKey := makeDescriptorCacheKey(fileDescriptor)
+	if cached, ok := pdbfCache.Load(cacheKey); ok {
+		return cached.pdbf, cached.found
+	}
+
+	fileOptions := fileDescriptor.Options()
+	if fileOptions == nil {
+		pdbfCache.Store(cacheKey, pdbfCacheItem{pdbf: EmptyPDBF, found: false})
+		return EmptyPDBF, false
+	}
+
+	pdbf, found = getOptionExtension[*protodb.PDBFile](fileOptions, protodb.E_Pdbf)
+	if !found {
+		pdbf = EmptyPDBF
+	}
+	pdbfCache.Store(cacheKey, pdbfCacheItem{pdbf: pdbf, found: found})
+	return pdbf, found
+}
+
+// GetEnumStorage get storage of enum field, field option first then file option, default EnumAsInt
+func GetEnumStorage(fieldDescriptor protoreflect.FieldDescriptor) protodb.EnumStorage {
+	cacheKey := makeDescriptorCacheKey(fieldDescriptor)
+	if cached, ok := enumStorageCache.Load(cacheKey); ok {
+		return cached
+	}
+
+	storage := protodb.EnumStorage_EnumAsInt
+	if fieldDescriptor.Kind() == protoreflect.EnumKind {
+		pdb, _ := GetPDB(fieldDescriptor)
+		if pdb.EnumStorage != protodb.EnumStorage_EnumAuto {
+			storage = pdb.EnumStorage
+		} else if file := fieldDescriptor.ParentFile(); file != nil {
+			pdbf, _ := GetPDBF(file)
+			if pdbf.EnumStorage != protodb.EnumStorage_EnumAuto {
+				storage = pdbf.EnumStorage
+			}
+		}
+	}
+	enumStorageCache.Store(cacheKey, storage)
+	return storage
+}
+
+// IsEnumStoredAsName is the field a single enum stored by value name (EnumAsName/EnumAsNative)
+func IsEnumStoredAsName(fieldDescriptor protoreflect.FieldDescriptor) bool {
+	if fieldDescriptor.Kind() != protoreflect.EnumKind || fieldDescriptor.IsList() || fieldDescriptor.IsMap() {
+		return false
+	}
+	switch GetEnumStorage(fieldDescriptor) {
+	case protodb.EnumStorage_EnumAsName, protodb.EnumStorage_EnumAsNative:
+		return true
+	}
+	return false
+}
+
 func makeDescriptorCacheKey(desc protoreflect.Descriptor) descriptorCacheKey {
 	return descriptorCacheKey{desc: desc}
 }
@@ -107,7 +159,7 @@ func getMsgFieldInfos(msgFieldsDesc protoreflect.FieldDescriptors) []msgFieldInf
 	infos := make([]msgFieldInfo, msgFieldsDesc.Len())
 	for i := 0; i < msgFieldsDesc.Len(); i++ {
 		fieldDesc := msgFieldsDesc.Get(i)
-		name := string(fieldDesc.Name())
+		name := GetColumnName(fieldDesc)
 		infos[i] = msgFieldInfo{
 			field: fieldDesc,
 			name:  name,
@@ -165,6 +217,7 @@ func MaybeNull(val interface{}, field protoreflect.FieldDescriptor, fieldpdb *pr
 }
 
 // BuildMsgFieldsMap build msgFieldsMap, if columnNames is nil, return all msg fields
+// columns of flattened sub message fields map to the sub message field
 func BuildMsgFieldsMap(fieldNames []string, msgFieldsDesc protoreflect.FieldDescriptors, nameLowercase bool) map[string]protoreflect.FieldDescriptor {
 	var columnNamesMap map[string]struct{}
 	if fieldNames != nil {
@@ -198,6 +251,26 @@ func BuildMsgFieldsMap(fieldNames []string, msgFieldsDesc protoreflect.FieldDesc
 		}
 	}
 
+	if msgFieldsDesc.Len() > 0 {
+		// columns of flattened sub message fields
+		msgDesc := msgFieldsDesc.Get(0).ContainingMessage()
+		for lower, column := range GetMsgFlatColumns(msgDesc) {
+			if lower != strings.ToLower(column.Name) {
+				continue
+			}
+			if fieldNames != nil {
+				if _, ok := columnNamesMap[lower]; !ok {
+					continue
+				}
+			}
+			if nameLowercase {
+				msgFieldsMap[lower] = column.Field()
+			} else {
+				msgFieldsMap[column.Name] = column.Field()
+			}
+		}
+	}
+
 	return msgFieldsMap
 }
 
@@ -220,7 +293,7 @@ func GetPrimaryKeyFieldDescs(msgDesc protoreflect.MessageDescriptor, msgFieldDes
 		field := msgFieldDescs.Get(fi)
 		fieldPdb, _ := GetPDB(field)
 		if fieldPdb != nil && fieldPdb.IsPrimary() {
-			result[string(field.Name())] = field
+			result[GetColumnName(field)] = field
 		}
 	}
 
@@ -271,7 +344,7 @@ func GetPrimaryKeyOrUniqueFieldDescs(msgDesc protoreflect.MessageDescriptor, msg
 		field := msgFieldDescs.Get(fi)
 		fieldPdb, _ := GetPDB(field)
 		if fieldPdb != nil && (fieldPdb.IsPrimary() || fieldPdb.IsUnique() || len(fieldPdb.UniqueName) > 0) {
-			fieldName := string(field.Name())
+			fieldName := GetColumnName(field)
 
 			uniqueName := fieldPdb.UniqueName
 			if fieldPdb.IsPrimary() {
@@ -0,0 +1,37 @@
+package pdbutil
+
+import (
+	"github.com/puzpuzpuz/xsync/v3"
+	"github.com/ygrpc/protodb"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var EmptyPDBS = &protodb.PDBService{}
+
+type pdbsCacheItem struct {
+	pdbs  *protodb.PDBService
+	found bool
+}
+
+var pdbsCache = xsync.NewMapOf[descriptorCacheKey, pdbsCacheItem]()
+
+// GetPDBS get pdbs option of service
+func GetPDBS(serviceDescriptor protoreflect.ServiceDescriptor) (pdbs *protodb.PDBService, found bool) {
+	cacheKey := makeDescriptorCacheKey(serviceDescriptor)
+	if cached, ok := pdbsCache.Load(cacheKey); ok {
+		return cached.pdbs, cached.found
+	}
+
+	serviceOptions := serviceDescriptor.Options()
+	if serviceOptions == nil {
+		pdbsCache.Store(cacheKey, pdbsCacheItem{pdbs: EmptyPDBS, found: false})
+		return EmptyPDBS, false
+	}
+
+	pdbs, found = getOptionExtension[*protodb.PDBService](serviceOptions, protodb.E_Pdbs)
+	if !found {
+		pdbs = EmptyPDBS
+	}
+	pdbsCache.Store(cacheKey, pdbsCacheItem{pdbs: pdbs, found: found})
+	return pdbs, found
+}
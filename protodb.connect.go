@@ -38,6 +38,9 @@ const (
 	ProtoDbSrvTableQueryProcedure = "/protodb.ProtoDbSrv/TableQuery"
 	// ProtoDbSrvQueryProcedure is the fully-qualified name of the ProtoDbSrv's Query RPC.
 	ProtoDbSrvQueryProcedure = "/protodb.ProtoDbSrv/Query"
+	// ProtoDbSrvDescribeSchemaProcedure is the fully-qualified name of the ProtoDbSrv's DescribeSchema
+	// RPC.
+	ProtoDbSrvDescribeSchemaProcedure = "/protodb.ProtoDbSrv/DescribeSchema"
 )
 
 // ProtoDbSrvClient is a client for the protodb.ProtoDbSrv service.
@@ -48,6 +51,8 @@ type ProtoDbSrvClient interface {
 	TableQuery(context.Context, *connect.Request[TableQueryReq]) (*connect.ServerStreamForClient[QueryResp], error)
 	// general query
 	Query(context.Context, *connect.Request[QueryReq]) (*connect.ServerStreamForClient[QueryResp], error)
+	// tables, columns and queries visible to the caller
+	DescribeSchema(context.Context, *connect.Request[DescribeSchemaReq]) (*connect.Response[DescribeSchemaResp], error)
 }
 
 // NewProtoDbSrvClient constructs a client for the protodb.ProtoDbSrv service. By default, it uses
@@ -79,14 +84,21 @@ func NewProtoDbSrvClient(httpClient connect.HTTPClient, baseURL string, opts ...
 			connect.WithSchema(protoDbSrvMethods.ByName("Query")),
 			connect.WithClientOptions(opts...),
 		),
+		describeSchema: connect.NewClient[DescribeSchemaReq, DescribeSchemaResp](
+			httpClient,
+			baseURL+ProtoDbSrvDescribeSchemaProcedure,
+			connect.WithSchema(protoDbSrvMethods.ByName("DescribeSchema")),
+			connect.WithClientOptions(opts...),
+		),
 	}
 }
 
 // protoDbSrvClient implements ProtoDbSrvClient.
 type protoDbSrvClient struct {
-	crud       *connect.Client[CrudReq, CrudResp]
-	tableQuery *connect.Client[TableQueryReq, QueryResp]
-	query      *connect.Client[QueryReq, QueryResp]
+	crud           *connect.Client[CrudReq, CrudResp]
+	tableQuery     *connect.Client[TableQueryReq, QueryResp]
+	query          *connect.Client[QueryReq, QueryResp]
+	describeSchema *connect.Client[DescribeSchemaReq, DescribeSchemaResp]
 }
 
 // Crud calls protodb.ProtoDbSrv.Crud.
@@ -104,6 +116,11 @@ func (c *protoDbSrvClient) Query(ctx context.Context, req *connect.Request[Query
 	return c.query.CallServerStream(ctx, req)
 }
 
+// DescribeSchema calls protodb.ProtoDbSrv.DescribeSchema.
+func (c *protoDbSrvClient) DescribeSchema(ctx context.Context, req *connect.Request[DescribeSchemaReq]) (*connect.Response[DescribeSchemaResp], error) {
+	return c.describeSchema.CallUnary(ctx, req)
+}
+
 // ProtoDbSrvHandler is an implementation of the protodb.ProtoDbSrv service.
 type ProtoDbSrvHandler interface {
 	// crud
@@ -112,6 +129,8 @@ type ProtoDbSrvHandler interface {
 	TableQuery(context.Context, *connect.Request[TableQueryReq], *connect.ServerStream[QueryResp]) error
 	// general query
 	Query(context.Context, *connect.Request[QueryReq], *connect.ServerStream[QueryResp]) error
+	// tables, columns and queries visible to the caller
+	DescribeSchema(context.Context, *connect.Request[DescribeSchemaReq]) (*connect.Response[DescribeSchemaResp], error)
 }
 
 // NewProtoDbSrvHandler builds an HTTP handler from the service implementation. It returns the path
@@ -139,6 +158,12 @@ func NewProtoDbSrvHandler(svc ProtoDbSrvHandler, opts ...connect.HandlerOption)
 		connect.WithSchema(protoDbSrvMethods.ByName("Query")),
 		connect.WithHandlerOptions(opts...),
 	)
+	protoDbSrvDescribeSchemaHandler := connect.NewUnaryHandler(
+		ProtoDbSrvDescribeSchemaProcedure,
+		svc.DescribeSchema,
+		connect.WithSchema(protoDbSrvMethods.ByName("DescribeSchema")),
+		connect.WithHandlerOptions(opts...),
+	)
 	return "/protodb.ProtoDbSrv/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case ProtoDbSrvCrudProcedure:
@@ -147,6 +172,8 @@ func NewProtoDbSrvHandler(svc ProtoDbSrvHandler, opts ...connect.HandlerOption)
 			protoDbSrvTableQueryHandler.ServeHTTP(w, r)
 		case ProtoDbSrvQueryProcedure:
 			protoDbSrvQueryHandler.ServeHTTP(w, r)
+		case ProtoDbSrvDescribeSchemaProcedure:
+			protoDbSrvDescribeSchemaHandler.ServeHTTP(w, r)
 		default:
 			http.NotFound(w, r)
 		}
@@ -167,3 +194,7 @@ func (UnimplementedProtoDbSrvHandler) TableQuery(context.Context, *connect.Reque
 func (UnimplementedProtoDbSrvHandler) Query(context.Context, *connect.Request[QueryReq], *connect.ServerStream[QueryResp]) error {
 	return connect.NewError(connect.CodeUnimplemented, errors.New("protodb.ProtoDbSrv.Query is not implemented"))
 }
+
+func (UnimplementedProtoDbSrvHandler) DescribeSchema(context.Context, *connect.Request[DescribeSchemaReq]) (*connect.Response[DescribeSchemaResp], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("protodb.ProtoDbSrv.DescribeSchema is not implemented"))
+}
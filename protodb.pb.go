@@ -22,6 +22,64 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// how enum fields are stored in db
+type EnumStorage int32
+
+const (
+	// field: use file option, file: store as integer
+	EnumStorage_EnumAuto EnumStorage = 0
+	// store enum number as integer
+	EnumStorage_EnumAsInt EnumStorage = 1
+	// store enum value name as text
+	EnumStorage_EnumAsName EnumStorage = 2
+	// store enum value name in native enum type
+	// postgres: CREATE TYPE ... AS ENUM, mysql: ENUM(...), sqlite: text
+	EnumStorage_EnumAsNative EnumStorage = 3
+)
+
+// Enum value maps for EnumStorage.
+var (
+	EnumStorage_name = map[int32]string{
+		0: "EnumAuto",
+		1: "EnumAsInt",
+		2: "EnumAsName",
+		3: "EnumAsNative",
+	}
+	EnumStorage_value = map[string]int32{
+		"EnumAuto":     0,
+		"EnumAsInt":    1,
+		"EnumAsName":   2,
+		"EnumAsNative": 3,
+	}
+)
+
+func (x EnumStorage) Enum() *EnumStorage {
+	p := new(EnumStorage)
+	*p = x
+	return p
+}
+
+func (x EnumStorage) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EnumStorage) Descriptor() protoreflect.EnumDescriptor {
+	return file_protodb_proto_enumTypes[0].Descriptor()
+}
+
+func (EnumStorage) Type() protoreflect.EnumType {
+	return &file_protodb_proto_enumTypes[0]
+}
+
+func (x EnumStorage) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EnumStorage.Descriptor instead.
+func (EnumStorage) EnumDescriptor() ([]byte, []int) {
+	return file_protodb_proto_rawDescGZIP(), []int{0}
+}
+
 type FieldDbType int32
 
 const (
@@ -49,6 +107,9 @@ const (
 	// ipv4 or ipv6 address
 	FieldDbType_INET   FieldDbType = 12
 	FieldDbType_UINT32 FieldDbType = 13
+	// exact decimal, numeric(p,s) in postgres, decimal(p,s) in mysql, text in sqlite
+	// proto field should be string, precision/scale from NumericPrecision/NumericScale
+	FieldDbType_NUMERIC FieldDbType = 14
 )
 
 // Enum value maps for FieldDbType.
@@ -68,6 +129,7 @@ var (
 		11: "BYTEA",
 		12: "INET",
 		13: "UINT32",
+		14: "NUMERIC",
 	}
 	FieldDbType_value = map[string]int32{
 		"AutoMatch": 0,
@@ -84,6 +146,7 @@ var (
 		"BYTEA":     11,
 		"INET":      12,
 		"UINT32":    13,
+		"NUMERIC":   14,
 	}
 )
 
@@ -98,11 +161,11 @@ func (x FieldDbType) String() string {
 }
 
 func (FieldDbType) Descriptor() protoreflect.EnumDescriptor {
-	return file_protodb_proto_enumTypes[0].Descriptor()
+	return file_protodb_proto_enumTypes[1].Descriptor()
 }
 
 func (FieldDbType) Type() protoreflect.EnumType {
-	return &file_protodb_proto_enumTypes[0]
+	return &file_protodb_proto_enumTypes[1]
 }
 
 func (x FieldDbType) Number() protoreflect.EnumNumber {
@@ -111,7 +174,7 @@ func (x FieldDbType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use FieldDbType.Descriptor instead.
 func (FieldDbType) EnumDescriptor() ([]byte, []int) {
-	return file_protodb_proto_rawDescGZIP(), []int{0}
+	return file_protodb_proto_rawDescGZIP(), []int{1}
 }
 
 // crud api code
@@ -157,11 +220,11 @@ func (x CrudReqCode) String() string {
 }
 
 func (CrudReqCode) Descriptor() protoreflect.EnumDescriptor {
-	return file_protodb_proto_enumTypes[1].Descriptor()
+	return file_protodb_proto_enumTypes[2].Descriptor()
 }
 
 func (CrudReqCode) Type() protoreflect.EnumType {
-	return &file_protodb_proto_enumTypes[1]
+	return &file_protodb_proto_enumTypes[2]
 }
 
 func (x CrudReqCode) Number() protoreflect.EnumNumber {
@@ -170,7 +233,7 @@ func (x CrudReqCode) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use CrudReqCode.Descriptor instead.
 func (CrudReqCode) EnumDescriptor() ([]byte, []int) {
-	return file_protodb_proto_rawDescGZIP(), []int{1}
+	return file_protodb_proto_rawDescGZIP(), []int{2}
 }
 
 // crud result type for return
@@ -210,11 +273,11 @@ func (x CrudResultType) String() string {
 }
 
 func (CrudResultType) Descriptor() protoreflect.EnumDescriptor {
-	return file_protodb_proto_enumTypes[2].Descriptor()
+	return file_protodb_proto_enumTypes[3].Descriptor()
 }
 
 func (CrudResultType) Type() protoreflect.EnumType {
-	return &file_protodb_proto_enumTypes[2]
+	return &file_protodb_proto_enumTypes[3]
 }
 
 func (x CrudResultType) Number() protoreflect.EnumNumber {
@@ -223,10 +286,11 @@ func (x CrudResultType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use CrudResultType.Descriptor instead.
 func (CrudResultType) EnumDescriptor() ([]byte, []int) {
-	return file_protodb_proto_rawDescGZIP(), []int{2}
+	return file_protodb_proto_rawDescGZIP(), []int{3}
 }
 
-// where operator
+// where operator of Where2Operator, the Where map only compares with =,
+// a NULL column is queried by WOP_IS_NULL/WOP_IS_NOT_NULL in Where2Operator
 type WhereOperator int32
 
 const (
@@ -257,6 +321,10 @@ const (
 	WhereOperator_WOP_LEN_LTE WhereOperator = 13
 	// map has key
 	WhereOperator_WOP_HAS_KEY WhereOperator = 14
+	// column is null, for optional fields it means unset, where2 value is ignored, Where2Operator only
+	WhereOperator_WOP_IS_NULL WhereOperator = 15
+	// column is not null, where2 value is ignored, Where2Operator only
+	WhereOperator_WOP_IS_NOT_NULL WhereOperator = 16
 )
 
 // Enum value maps for WhereOperator.
@@ -277,6 +345,8 @@ var (
 		12: "WOP_LEN_LT",
 		13: "WOP_LEN_LTE",
 		14: "WOP_HAS_KEY",
+		15: "WOP_IS_NULL",
+		16: "WOP_IS_NOT_NULL",
 	}
 	WhereOperator_value = map[string]int32{
 		"WOP_UNKNOWN":      0,
@@ -294,6 +364,8 @@ var (
 		"WOP_LEN_LT":       12,
 		"WOP_LEN_LTE":      13,
 		"WOP_HAS_KEY":      14,
+		"WOP_IS_NULL":      15,
+		"WOP_IS_NOT_NULL":  16,
 	}
 )
 
@@ -308,11 +380,11 @@ func (x WhereOperator) String() string {
 }
 
 func (WhereOperator) Descriptor() protoreflect.EnumDescriptor {
-	return file_protodb_proto_enumTypes[3].Descriptor()
+	return file_protodb_proto_enumTypes[4].Descriptor()
 }
 
 func (WhereOperator) Type() protoreflect.EnumType {
-	return &file_protodb_proto_enumTypes[3]
+	return &file_protodb_proto_enumTypes[4]
 }
 
 func (x WhereOperator) Number() protoreflect.EnumNumber {
@@ -321,7 +393,7 @@ func (x WhereOperator) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use WhereOperator.Descriptor instead.
 func (WhereOperator) EnumDescriptor() ([]byte, []int) {
-	return file_protodb_proto_rawDescGZIP(), []int{3}
+	return file_protodb_proto_rawDescGZIP(), []int{4}
 }
 
 type PDBFile struct {
@@ -331,7 +403,9 @@ type PDBFile struct {
 	// go (like: UserName) 'snake': snake name style (like: user_name)
 	NameStyle string `protobuf:"bytes,1,opt,name=NameStyle,proto3" json:"NameStyle,omitempty"`
 	// comment for file
-	Comment       []string `protobuf:"bytes,2,rep,name=Comment,proto3" json:"Comment,omitempty"`
+	Comment []string `protobuf:"bytes,2,rep,name=Comment,proto3" json:"Comment,omitempty"`
+	// default storage of enum fields in this file
+	EnumStorage   EnumStorage `protobuf:"varint,3,opt,name=EnumStorage,proto3,enum=protodb.EnumStorage" json:"EnumStorage,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -380,6 +454,13 @@ func (x *PDBFile) GetComment() []string {
 	return nil
 }
 
+func (x *PDBFile) GetEnumStorage() EnumStorage {
+	if x != nil {
+		return x.EnumStorage
+	}
+	return EnumStorage_EnumAuto
+}
+
 type PDBMsg struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// comment for table
@@ -400,7 +481,11 @@ type PDBMsg struct {
 	// do not generate db table for this message
 	NotDB bool `protobuf:"varint,7,opt,name=NotDB,proto3" json:"NotDB,omitempty"`
 	// sql for migrate table
-	SQLMigrate    []string `protobuf:"bytes,8,rep,name=SQLMigrate,proto3" json:"SQLMigrate,omitempty"`
+	SQLMigrate []string `protobuf:"bytes,8,rep,name=SQLMigrate,proto3" json:"SQLMigrate,omitempty"`
+	// db table name, default is message name in file NameStyle
+	TableName string `protobuf:"bytes,9,opt,name=TableName,proto3" json:"TableName,omitempty"`
+	// named queries, param and result message default to this message
+	Queries       []*PDBQuery `protobuf:"bytes,10,rep,name=Queries,proto3" json:"Queries,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -491,6 +576,159 @@ func (x *PDBMsg) GetSQLMigrate() []string {
 	return nil
 }
 
+func (x *PDBMsg) GetTableName() string {
+	if x != nil {
+		return x.TableName
+	}
+	return ""
+}
+
+func (x *PDBMsg) GetQueries() []*PDBQuery {
+	if x != nil {
+		return x.Queries
+	}
+	return nil
+}
+
+// named query declared in proto, registered by querystore.RegisterProtoQueries
+// and called by the Query rpc with QueryReq.QueryName
+type PDBQuery struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// query name
+	Name string `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	// sql with named parameters like :user_id, bound from the fields of the param message.
+	// '::' (postgres cast), quoted strings and comments are kept as is
+	SQL string `protobuf:"bytes,2,opt,name=SQL,proto3" json:"SQL,omitempty"`
+	// sql of a dialect, dialect name(Postgres, Mysql, SQLite, DuckDB...) -> sql, SQL is used for others
+	DialectSQL map[string]string `protobuf:"bytes,3,rep,name=DialectSQL,proto3" json:"DialectSQL,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// full name of the param message, decoded from QueryReq.WhereMsgBytes in QueryReq.MsgFormat
+	ParamMsg string `protobuf:"bytes,4,opt,name=ParamMsg,proto3" json:"ParamMsg,omitempty"`
+	// full name of the result message
+	ResultMsg string `protobuf:"bytes,5,opt,name=ResultMsg,proto3" json:"ResultMsg,omitempty"`
+	// comment for query
+	Comment       []string `protobuf:"bytes,6,rep,name=Comment,proto3" json:"Comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PDBQuery) Reset() {
+	*x = PDBQuery{}
+	mi := &file_protodb_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PDBQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PDBQuery) ProtoMessage() {}
+
+func (x *PDBQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_protodb_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PDBQuery.ProtoReflect.Descriptor instead.
+func (*PDBQuery) Descriptor() ([]byte, []int) {
+	return file_protodb_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PDBQuery) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PDBQuery) GetSQL() string {
+	if x != nil {
+		return x.SQL
+	}
+	return ""
+}
+
+func (x *PDBQuery) GetDialectSQL() map[string]string {
+	if x != nil {
+		return x.DialectSQL
+	}
+	return nil
+}
+
+func (x *PDBQuery) GetParamMsg() string {
+	if x != nil {
+		return x.ParamMsg
+	}
+	return ""
+}
+
+func (x *PDBQuery) GetResultMsg() string {
+	if x != nil {
+		return x.ResultMsg
+	}
+	return ""
+}
+
+func (x *PDBQuery) GetComment() []string {
+	if x != nil {
+		return x.Comment
+	}
+	return nil
+}
+
+// protodb options of a service, used as a group of named queries
+type PDBService struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// named queries, ParamMsg and ResultMsg are required
+	Queries       []*PDBQuery `protobuf:"bytes,1,rep,name=Queries,proto3" json:"Queries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PDBService) Reset() {
+	*x = PDBService{}
+	mi := &file_protodb_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PDBService) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PDBService) ProtoMessage() {}
+
+func (x *PDBService) ProtoReflect() protoreflect.Message {
+	mi := &file_protodb_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PDBService.ProtoReflect.Descriptor instead.
+func (*PDBService) Descriptor() ([]byte, []int) {
+	return file_protodb_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PDBService) GetQueries() []*PDBQuery {
+	if x != nil {
+		return x.Queries
+	}
+	return nil
+}
+
 type PDBField struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// do not generate db field in create table
@@ -529,14 +767,25 @@ type PDBField struct {
 	Comment []string `protobuf:"bytes,15,rep,name=Comment,proto3" json:"Comment,omitempty"`
 	// unique group name
 	// when a unique constrain include multiple column, specify the a group name for it
-	UniqueName    string `protobuf:"bytes,16,opt,name=UniqueName,proto3" json:"UniqueName,omitempty"`
+	UniqueName string `protobuf:"bytes,16,opt,name=UniqueName,proto3" json:"UniqueName,omitempty"`
+	// total digits for NUMERIC db type, 0 means unconstrained (mysql: decimal(65,30))
+	NumericPrecision int32 `protobuf:"varint,17,opt,name=NumericPrecision,proto3" json:"NumericPrecision,omitempty"`
+	// digits after the decimal point for NUMERIC db type
+	NumericScale int32 `protobuf:"varint,18,opt,name=NumericScale,proto3" json:"NumericScale,omitempty"`
+	// storage of enum field, EnumAuto use the file option
+	EnumStorage EnumStorage `protobuf:"varint,19,opt,name=EnumStorage,proto3,enum=protodb.EnumStorage" json:"EnumStorage,omitempty"`
+	// store a singular sub message field as prefixed columns instead of a json column
+	// columns are named by field path joined with '_', like address_city
+	Flatten bool `protobuf:"varint,20,opt,name=Flatten,proto3" json:"Flatten,omitempty"`
+	// db column name, default is field name in file NameStyle
+	ColumnName    string `protobuf:"bytes,21,opt,name=ColumnName,proto3" json:"ColumnName,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *PDBField) Reset() {
 	*x = PDBField{}
-	mi := &file_protodb_proto_msgTypes[2]
+	mi := &file_protodb_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -548,7 +797,7 @@ func (x *PDBField) String() string {
 func (*PDBField) ProtoMessage() {}
 
 func (x *PDBField) ProtoReflect() protoreflect.Message {
-	mi := &file_protodb_proto_msgTypes[2]
+	mi := &file_protodb_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -561,7 +810,7 @@ func (x *PDBField) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PDBField.ProtoReflect.Descriptor instead.
 func (*PDBField) Descriptor() ([]byte, []int) {
-	return file_protodb_proto_rawDescGZIP(), []int{2}
+	return file_protodb_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *PDBField) GetNotDB() bool {
@@ -676,6 +925,95 @@ func (x *PDBField) GetUniqueName() string {
 	return ""
 }
 
+func (x *PDBField) GetNumericPrecision() int32 {
+	if x != nil {
+		return x.NumericPrecision
+	}
+	return 0
+}
+
+func (x *PDBField) GetNumericScale() int32 {
+	if x != nil {
+		return x.NumericScale
+	}
+	return 0
+}
+
+func (x *PDBField) GetEnumStorage() EnumStorage {
+	if x != nil {
+		return x.EnumStorage
+	}
+	return EnumStorage_EnumAuto
+}
+
+func (x *PDBField) GetFlatten() bool {
+	if x != nil {
+		return x.Flatten
+	}
+	return false
+}
+
+func (x *PDBField) GetColumnName() string {
+	if x != nil {
+		return x.ColumnName
+	}
+	return ""
+}
+
+type PDBOneof struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// generate a discriminator column which stores the name of the set member, NULL when none is set
+	Discriminator bool `protobuf:"varint,1,opt,name=Discriminator,proto3" json:"Discriminator,omitempty"`
+	// discriminator column name, default is oneof name + "_case"
+	DiscriminatorColumn string `protobuf:"bytes,2,opt,name=DiscriminatorColumn,proto3" json:"DiscriminatorColumn,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *PDBOneof) Reset() {
+	*x = PDBOneof{}
+	mi := &file_protodb_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PDBOneof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PDBOneof) ProtoMessage() {}
+
+func (x *PDBOneof) ProtoReflect() protoreflect.Message {
+	mi := &file_protodb_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PDBOneof.ProtoReflect.Descriptor instead.
+func (*PDBOneof) Descriptor() ([]byte, []int) {
+	return file_protodb_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PDBOneof) GetDiscriminator() bool {
+	if x != nil {
+		return x.Discriminator
+	}
+	return false
+}
+
+func (x *PDBOneof) GetDiscriminatorColumn() string {
+	if x != nil {
+		return x.DiscriminatorColumn
+	}
+	return ""
+}
+
 // crud request
 type CrudReq struct {
 	state      protoimpl.MessageState `protogen:"open.v1"`
@@ -696,7 +1034,7 @@ type CrudReq struct {
 
 func (x *CrudReq) Reset() {
 	*x = CrudReq{}
-	mi := &file_protodb_proto_msgTypes[3]
+	mi := &file_protodb_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -708,7 +1046,7 @@ func (x *CrudReq) String() string {
 func (*CrudReq) ProtoMessage() {}
 
 func (x *CrudReq) ProtoReflect() protoreflect.Message {
-	mi := &file_protodb_proto_msgTypes[3]
+	mi := &file_protodb_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -721,7 +1059,7 @@ func (x *CrudReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CrudReq.ProtoReflect.Descriptor instead.
 func (*CrudReq) Descriptor() ([]byte, []int) {
-	return file_protodb_proto_rawDescGZIP(), []int{3}
+	return file_protodb_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *CrudReq) GetCode() CrudReqCode {
@@ -813,7 +1151,7 @@ type CrudResp struct {
 
 func (x *CrudResp) Reset() {
 	*x = CrudResp{}
-	mi := &file_protodb_proto_msgTypes[4]
+	mi := &file_protodb_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -825,7 +1163,7 @@ func (x *CrudResp) String() string {
 func (*CrudResp) ProtoMessage() {}
 
 func (x *CrudResp) ProtoReflect() protoreflect.Message {
-	mi := &file_protodb_proto_msgTypes[4]
+	mi := &file_protodb_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -838,7 +1176,7 @@ func (x *CrudResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CrudResp.ProtoReflect.Descriptor instead.
 func (*CrudResp) Descriptor() ([]byte, []int) {
-	return file_protodb_proto_rawDescGZIP(), []int{4}
+	return file_protodb_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *CrudResp) GetRowsAffected() int64 {
@@ -894,14 +1232,18 @@ type TableQueryReq struct {
 	// where2 field operator, fieldname -> op
 	Where2Operator map[string]WhereOperator `protobuf:"bytes,9,rep,name=Where2Operator,proto3" json:"Where2Operator,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value,enum=protodb.WhereOperator"`
 	// where2 field value, fieldname -> value
-	Where2        map[string]string `protobuf:"bytes,10,rep,name=Where2,proto3" json:"Where2,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Where2 map[string]string `protobuf:"bytes,10,rep,name=Where2,proto3" json:"Where2,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// active member of oneof, oneof name -> member field name, empty member means none is set
+	OneofCase map[string]string `protobuf:"bytes,11,rep,name=OneofCase,proto3" json:"OneofCase,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// order by fields in order, field name or column name, '-' prefix for descending like -created_at
+	OrderBy       []string `protobuf:"bytes,12,rep,name=OrderBy,proto3" json:"OrderBy,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *TableQueryReq) Reset() {
 	*x = TableQueryReq{}
-	mi := &file_protodb_proto_msgTypes[5]
+	mi := &file_protodb_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -913,7 +1255,7 @@ func (x *TableQueryReq) String() string {
 func (*TableQueryReq) ProtoMessage() {}
 
 func (x *TableQueryReq) ProtoReflect() protoreflect.Message {
-	mi := &file_protodb_proto_msgTypes[5]
+	mi := &file_protodb_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -926,7 +1268,7 @@ func (x *TableQueryReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TableQueryReq.ProtoReflect.Descriptor instead.
 func (*TableQueryReq) Descriptor() ([]byte, []int) {
-	return file_protodb_proto_rawDescGZIP(), []int{5}
+	return file_protodb_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *TableQueryReq) GetSchemeName() string {
@@ -999,6 +1341,20 @@ func (x *TableQueryReq) GetWhere2() map[string]string {
 	return nil
 }
 
+func (x *TableQueryReq) GetOneofCase() map[string]string {
+	if x != nil {
+		return x.OneofCase
+	}
+	return nil
+}
+
+func (x *TableQueryReq) GetOrderBy() []string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return nil
+}
+
 type QueryResp struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// response batch no, start from 0
@@ -1016,7 +1372,7 @@ type QueryResp struct {
 
 func (x *QueryResp) Reset() {
 	*x = QueryResp{}
-	mi := &file_protodb_proto_msgTypes[6]
+	mi := &file_protodb_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1028,7 +1384,7 @@ func (x *QueryResp) String() string {
 func (*QueryResp) ProtoMessage() {}
 
 func (x *QueryResp) ProtoReflect() protoreflect.Message {
-	mi := &file_protodb_proto_msgTypes[6]
+	mi := &file_protodb_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1041,7 +1397,7 @@ func (x *QueryResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use QueryResp.ProtoReflect.Descriptor instead.
 func (*QueryResp) Descriptor() ([]byte, []int) {
-	return file_protodb_proto_rawDescGZIP(), []int{6}
+	return file_protodb_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *QueryResp) GetResponseNo() int64 {
@@ -1106,7 +1462,7 @@ type QueryReq struct {
 
 func (x *QueryReq) Reset() {
 	*x = QueryReq{}
-	mi := &file_protodb_proto_msgTypes[7]
+	mi := &file_protodb_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1118,7 +1474,7 @@ func (x *QueryReq) String() string {
 func (*QueryReq) ProtoMessage() {}
 
 func (x *QueryReq) ProtoReflect() protoreflect.Message {
-	mi := &file_protodb_proto_msgTypes[7]
+	mi := &file_protodb_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1131,7 +1487,7 @@ func (x *QueryReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use QueryReq.ProtoReflect.Descriptor instead.
 func (*QueryReq) Descriptor() ([]byte, []int) {
-	return file_protodb_proto_rawDescGZIP(), []int{7}
+	return file_protodb_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *QueryReq) GetQueryName() string {
@@ -1204,7 +1560,506 @@ func (x *QueryReq) GetWhere2() map[string]string {
 	return nil
 }
 
-var file_protodb_proto_extTypes = []protoimpl.ExtensionInfo{
+type DescribeSchemaReq struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	SchemeName string                 `protobuf:"bytes,1,opt,name=SchemeName,proto3" json:"SchemeName,omitempty"`
+	// only describe these tables(db table name or msg name), empty for all visible tables
+	TableNames    []string `protobuf:"bytes,2,rep,name=TableNames,proto3" json:"TableNames,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DescribeSchemaReq) Reset() {
+	*x = DescribeSchemaReq{}
+	mi := &file_protodb_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DescribeSchemaReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DescribeSchemaReq) ProtoMessage() {}
+
+func (x *DescribeSchemaReq) ProtoReflect() protoreflect.Message {
+	mi := &file_protodb_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DescribeSchemaReq.ProtoReflect.Descriptor instead.
+func (*DescribeSchemaReq) Descriptor() ([]byte, []int) {
+	return file_protodb_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DescribeSchemaReq) GetSchemeName() string {
+	if x != nil {
+		return x.SchemeName
+	}
+	return ""
+}
+
+func (x *DescribeSchemaReq) GetTableNames() []string {
+	if x != nil {
+		return x.TableNames
+	}
+	return nil
+}
+
+// db column of a table
+type ColumnInfo struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// db column name
+	Name string `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	// proto field name, dotted path for flattened sub message columns like address.city
+	FieldName string `protobuf:"bytes,2,opt,name=FieldName,proto3" json:"FieldName,omitempty"`
+	// proto kind, like string, int64, message, the value kind for map
+	ProtoKind string `protobuf:"bytes,3,opt,name=ProtoKind,proto3" json:"ProtoKind,omitempty"`
+	// full name of message or enum type(map value type), empty for scalar kinds
+	TypeName string `protobuf:"bytes,4,opt,name=TypeName,proto3" json:"TypeName,omitempty"`
+	IsList   bool   `protobuf:"varint,5,opt,name=IsList,proto3" json:"IsList,omitempty"`
+	IsMap    bool   `protobuf:"varint,6,opt,name=IsMap,proto3" json:"IsMap,omitempty"`
+	// sql type in the dialect of the db
+	SQLType string `protobuf:"bytes,7,opt,name=SQLType,proto3" json:"SQLType,omitempty"`
+	Primary bool   `protobuf:"varint,8,opt,name=Primary,proto3" json:"Primary,omitempty"`
+	Unique  bool   `protobuf:"varint,9,opt,name=Unique,proto3" json:"Unique,omitempty"`
+	// unique group name
+	UniqueName string `protobuf:"bytes,10,opt,name=UniqueName,proto3" json:"UniqueName,omitempty"`
+	// reference to other table, other_table(other_field)
+	Reference string `protobuf:"bytes,11,opt,name=Reference,proto3" json:"Reference,omitempty"`
+	NotNull   bool   `protobuf:"varint,12,opt,name=NotNull,proto3" json:"NotNull,omitempty"`
+	NoUpdate  bool   `protobuf:"varint,13,opt,name=NoUpdate,proto3" json:"NoUpdate,omitempty"`
+	NoInsert  bool   `protobuf:"varint,14,opt,name=NoInsert,proto3" json:"NoInsert,omitempty"`
+	// where2 operators supported by the column in the dialect of the db
+	Operators     []WhereOperator `protobuf:"varint,15,rep,packed,name=Operators,proto3,enum=protodb.WhereOperator" json:"Operators,omitempty"`
+	Comment       []string        `protobuf:"bytes,16,rep,name=Comment,proto3" json:"Comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ColumnInfo) Reset() {
+	*x = ColumnInfo{}
+	mi := &file_protodb_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ColumnInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ColumnInfo) ProtoMessage() {}
+
+func (x *ColumnInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_protodb_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ColumnInfo.ProtoReflect.Descriptor instead.
+func (*ColumnInfo) Descriptor() ([]byte, []int) {
+	return file_protodb_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ColumnInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ColumnInfo) GetFieldName() string {
+	if x != nil {
+		return x.FieldName
+	}
+	return ""
+}
+
+func (x *ColumnInfo) GetProtoKind() string {
+	if x != nil {
+		return x.ProtoKind
+	}
+	return ""
+}
+
+func (x *ColumnInfo) GetTypeName() string {
+	if x != nil {
+		return x.TypeName
+	}
+	return ""
+}
+
+func (x *ColumnInfo) GetIsList() bool {
+	if x != nil {
+		return x.IsList
+	}
+	return false
+}
+
+func (x *ColumnInfo) GetIsMap() bool {
+	if x != nil {
+		return x.IsMap
+	}
+	return false
+}
+
+func (x *ColumnInfo) GetSQLType() string {
+	if x != nil {
+		return x.SQLType
+	}
+	return ""
+}
+
+func (x *ColumnInfo) GetPrimary() bool {
+	if x != nil {
+		return x.Primary
+	}
+	return false
+}
+
+func (x *ColumnInfo) GetUnique() bool {
+	if x != nil {
+		return x.Unique
+	}
+	return false
+}
+
+func (x *ColumnInfo) GetUniqueName() string {
+	if x != nil {
+		return x.UniqueName
+	}
+	return ""
+}
+
+func (x *ColumnInfo) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *ColumnInfo) GetNotNull() bool {
+	if x != nil {
+		return x.NotNull
+	}
+	return false
+}
+
+func (x *ColumnInfo) GetNoUpdate() bool {
+	if x != nil {
+		return x.NoUpdate
+	}
+	return false
+}
+
+func (x *ColumnInfo) GetNoInsert() bool {
+	if x != nil {
+		return x.NoInsert
+	}
+	return false
+}
+
+func (x *ColumnInfo) GetOperators() []WhereOperator {
+	if x != nil {
+		return x.Operators
+	}
+	return nil
+}
+
+func (x *ColumnInfo) GetComment() []string {
+	if x != nil {
+		return x.Comment
+	}
+	return nil
+}
+
+// unique constraint of a table
+type UniqueKeyInfo struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// unique group name, empty for single column unique
+	Name          string   `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	Columns       []string `protobuf:"bytes,2,rep,name=Columns,proto3" json:"Columns,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UniqueKeyInfo) Reset() {
+	*x = UniqueKeyInfo{}
+	mi := &file_protodb_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UniqueKeyInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UniqueKeyInfo) ProtoMessage() {}
+
+func (x *UniqueKeyInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_protodb_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UniqueKeyInfo.ProtoReflect.Descriptor instead.
+func (*UniqueKeyInfo) Descriptor() ([]byte, []int) {
+	return file_protodb_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UniqueKeyInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UniqueKeyInfo) GetColumns() []string {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+// db table of a protodb message
+type TableInfo struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// proto message full name
+	MsgName   string        `protobuf:"bytes,1,opt,name=MsgName,proto3" json:"MsgName,omitempty"`
+	TableName string        `protobuf:"bytes,2,opt,name=TableName,proto3" json:"TableName,omitempty"`
+	Columns   []*ColumnInfo `protobuf:"bytes,3,rep,name=Columns,proto3" json:"Columns,omitempty"`
+	// primary key columns
+	PrimaryKey []string         `protobuf:"bytes,4,rep,name=PrimaryKey,proto3" json:"PrimaryKey,omitempty"`
+	UniqueKeys []*UniqueKeyInfo `protobuf:"bytes,5,rep,name=UniqueKeys,proto3" json:"UniqueKeys,omitempty"`
+	// message is not a db table
+	NotDB         bool     `protobuf:"varint,6,opt,name=NotDB,proto3" json:"NotDB,omitempty"`
+	Comment       []string `protobuf:"bytes,7,rep,name=Comment,proto3" json:"Comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TableInfo) Reset() {
+	*x = TableInfo{}
+	mi := &file_protodb_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TableInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TableInfo) ProtoMessage() {}
+
+func (x *TableInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_protodb_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TableInfo.ProtoReflect.Descriptor instead.
+func (*TableInfo) Descriptor() ([]byte, []int) {
+	return file_protodb_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *TableInfo) GetMsgName() string {
+	if x != nil {
+		return x.MsgName
+	}
+	return ""
+}
+
+func (x *TableInfo) GetTableName() string {
+	if x != nil {
+		return x.TableName
+	}
+	return ""
+}
+
+func (x *TableInfo) GetColumns() []*ColumnInfo {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *TableInfo) GetPrimaryKey() []string {
+	if x != nil {
+		return x.PrimaryKey
+	}
+	return nil
+}
+
+func (x *TableInfo) GetUniqueKeys() []*UniqueKeyInfo {
+	if x != nil {
+		return x.UniqueKeys
+	}
+	return nil
+}
+
+func (x *TableInfo) GetNotDB() bool {
+	if x != nil {
+		return x.NotDB
+	}
+	return false
+}
+
+func (x *TableInfo) GetComment() []string {
+	if x != nil {
+		return x.Comment
+	}
+	return nil
+}
+
+// named query of querystore
+type QueryInfo struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	// param message full name, empty when the query is not declared by PDBQuery
+	ParamMsg string `protobuf:"bytes,2,opt,name=ParamMsg,proto3" json:"ParamMsg,omitempty"`
+	// result message full name, empty when the query is not declared by PDBQuery
+	ResultMsg     string   `protobuf:"bytes,3,opt,name=ResultMsg,proto3" json:"ResultMsg,omitempty"`
+	Comment       []string `protobuf:"bytes,4,rep,name=Comment,proto3" json:"Comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryInfo) Reset() {
+	*x = QueryInfo{}
+	mi := &file_protodb_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryInfo) ProtoMessage() {}
+
+func (x *QueryInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_protodb_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryInfo.ProtoReflect.Descriptor instead.
+func (*QueryInfo) Descriptor() ([]byte, []int) {
+	return file_protodb_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *QueryInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *QueryInfo) GetParamMsg() string {
+	if x != nil {
+		return x.ParamMsg
+	}
+	return ""
+}
+
+func (x *QueryInfo) GetResultMsg() string {
+	if x != nil {
+		return x.ResultMsg
+	}
+	return ""
+}
+
+func (x *QueryInfo) GetComment() []string {
+	if x != nil {
+		return x.Comment
+	}
+	return nil
+}
+
+type DescribeSchemaResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tables        []*TableInfo           `protobuf:"bytes,1,rep,name=Tables,proto3" json:"Tables,omitempty"`
+	Queries       []*QueryInfo           `protobuf:"bytes,2,rep,name=Queries,proto3" json:"Queries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DescribeSchemaResp) Reset() {
+	*x = DescribeSchemaResp{}
+	mi := &file_protodb_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DescribeSchemaResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DescribeSchemaResp) ProtoMessage() {}
+
+func (x *DescribeSchemaResp) ProtoReflect() protoreflect.Message {
+	mi := &file_protodb_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DescribeSchemaResp.ProtoReflect.Descriptor instead.
+func (*DescribeSchemaResp) Descriptor() ([]byte, []int) {
+	return file_protodb_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DescribeSchemaResp) GetTables() []*TableInfo {
+	if x != nil {
+		return x.Tables
+	}
+	return nil
+}
+
+func (x *DescribeSchemaResp) GetQueries() []*QueryInfo {
+	if x != nil {
+		return x.Queries
+	}
+	return nil
+}
+
+var file_protodb_proto_extTypes = []protoimpl.ExtensionInfo{
 	{
 		ExtendedType:  (*descriptorpb.FileOptions)(nil),
 		ExtensionType: (*PDBFile)(nil),
@@ -1229,6 +2084,22 @@ var file_protodb_proto_extTypes = []protoimpl.ExtensionInfo{
 		Tag:           "bytes,1888,opt,name=pdb",
 		Filename:      "protodb.proto",
 	},
+	{
+		ExtendedType:  (*descriptorpb.OneofOptions)(nil),
+		ExtensionType: (*PDBOneof)(nil),
+		Field:         1888,
+		Name:          "protodb.pdbo",
+		Tag:           "bytes,1888,opt,name=pdbo",
+		Filename:      "protodb.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.ServiceOptions)(nil),
+		ExtensionType: (*PDBService)(nil),
+		Field:         1888,
+		Name:          "protodb.pdbs",
+		Tag:           "bytes,1888,opt,name=pdbs",
+		Filename:      "protodb.proto",
+	},
 }
 
 // Extension fields to descriptorpb.FileOptions.
@@ -1249,14 +2120,27 @@ var (
 	E_Pdb = &file_protodb_proto_extTypes[2]
 )
 
+// Extension fields to descriptorpb.OneofOptions.
+var (
+	// optional protodb.PDBOneof pdbo = 1888;
+	E_Pdbo = &file_protodb_proto_extTypes[3]
+)
+
+// Extension fields to descriptorpb.ServiceOptions.
+var (
+	// optional protodb.PDBService pdbs = 1888;
+	E_Pdbs = &file_protodb_proto_extTypes[4]
+)
+
 var File_protodb_proto protoreflect.FileDescriptor
 
 const file_protodb_proto_rawDesc = "" +
 	"\n" +
-	"\rprotodb.proto\x12\aprotodb\x1a google/protobuf/descriptor.proto\"A\n" +
+	"\rprotodb.proto\x12\aprotodb\x1a google/protobuf/descriptor.proto\"y\n" +
 	"\aPDBFile\x12\x1c\n" +
 	"\tNameStyle\x18\x01 \x01(\tR\tNameStyle\x12\x18\n" +
-	"\aComment\x18\x02 \x03(\tR\aComment\"\x80\x02\n" +
+	"\aComment\x18\x02 \x03(\tR\aComment\x126\n" +
+	"\vEnumStorage\x18\x03 \x01(\x0e2\x14.protodb.EnumStorageR\vEnumStorage\"\xcb\x02\n" +
 	"\x06PDBMsg\x12\x18\n" +
 	"\aComment\x18\x01 \x03(\tR\aComment\x12\x1e\n" +
 	"\n" +
@@ -1269,7 +2153,25 @@ const file_protodb_proto_rawDesc = "" +
 	"\x05NotDB\x18\a \x01(\bR\x05NotDB\x12\x1e\n" +
 	"\n" +
 	"SQLMigrate\x18\b \x03(\tR\n" +
-	"SQLMigrate\"\xf0\x03\n" +
+	"SQLMigrate\x12\x1c\n" +
+	"\tTableName\x18\t \x01(\tR\tTableName\x12+\n" +
+	"\aQueries\x18\n" +
+	" \x03(\v2\x11.protodb.PDBQueryR\aQueries\"\x86\x02\n" +
+	"\bPDBQuery\x12\x12\n" +
+	"\x04Name\x18\x01 \x01(\tR\x04Name\x12\x10\n" +
+	"\x03SQL\x18\x02 \x01(\tR\x03SQL\x12A\n" +
+	"\n" +
+	"DialectSQL\x18\x03 \x03(\v2!.protodb.PDBQuery.DialectSQLEntryR\n" +
+	"DialectSQL\x12\x1a\n" +
+	"\bParamMsg\x18\x04 \x01(\tR\bParamMsg\x12\x1c\n" +
+	"\tResultMsg\x18\x05 \x01(\tR\tResultMsg\x12\x18\n" +
+	"\aComment\x18\x06 \x03(\tR\aComment\x1a=\n" +
+	"\x0fDialectSQLEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"9\n" +
+	"\n" +
+	"PDBService\x12+\n" +
+	"\aQueries\x18\x01 \x03(\v2\x11.protodb.PDBQueryR\aQueries\"\xb2\x05\n" +
 	"\bPDBField\x12\x14\n" +
 	"\x05NotDB\x18\x01 \x01(\bR\x05NotDB\x12\x18\n" +
 	"\aPrimary\x18\x02 \x01(\bR\aPrimary\x12\x16\n" +
@@ -1293,7 +2195,17 @@ const file_protodb_proto_rawDesc = "" +
 	"\aComment\x18\x0f \x03(\tR\aComment\x12\x1e\n" +
 	"\n" +
 	"UniqueName\x18\x10 \x01(\tR\n" +
-	"UniqueName\"\x9e\x03\n" +
+	"UniqueName\x12*\n" +
+	"\x10NumericPrecision\x18\x11 \x01(\x05R\x10NumericPrecision\x12\"\n" +
+	"\fNumericScale\x18\x12 \x01(\x05R\fNumericScale\x126\n" +
+	"\vEnumStorage\x18\x13 \x01(\x0e2\x14.protodb.EnumStorageR\vEnumStorage\x12\x18\n" +
+	"\aFlatten\x18\x14 \x01(\bR\aFlatten\x12\x1e\n" +
+	"\n" +
+	"ColumnName\x18\x15 \x01(\tR\n" +
+	"ColumnName\"b\n" +
+	"\bPDBOneof\x12$\n" +
+	"\rDiscriminator\x18\x01 \x01(\bR\rDiscriminator\x120\n" +
+	"\x13DiscriminatorColumn\x18\x02 \x01(\tR\x13DiscriminatorColumn\"\x9e\x03\n" +
 	"\aCrudReq\x12(\n" +
 	"\x04Code\x18\x01 \x01(\x0e2\x14.protodb.CrudReqCodeR\x04Code\x127\n" +
 	"\n" +
@@ -1315,7 +2227,7 @@ const file_protodb_proto_rawDesc = "" +
 	"\aErrInfo\x18\x02 \x01(\tR\aErrInfo\x12 \n" +
 	"\vOldMsgBytes\x18\x03 \x01(\fR\vOldMsgBytes\x12 \n" +
 	"\vNewMsgBytes\x18\x04 \x01(\fR\vNewMsgBytes\x12\x1c\n" +
-	"\tMsgFormat\x18\b \x01(\x05R\tMsgFormat\"\x8a\x05\n" +
+	"\tMsgFormat\x18\b \x01(\x05R\tMsgFormat\"\xa7\x06\n" +
 	"\rTableQueryReq\x12\x1e\n" +
 	"\n" +
 	"SchemeName\x18\x01 \x01(\tR\n" +
@@ -1329,7 +2241,9 @@ const file_protodb_proto_rawDesc = "" +
 	"\tMsgFormat\x18\b \x01(\x05R\tMsgFormat\x12R\n" +
 	"\x0eWhere2Operator\x18\t \x03(\v2*.protodb.TableQueryReq.Where2OperatorEntryR\x0eWhere2Operator\x12:\n" +
 	"\x06Where2\x18\n" +
-	" \x03(\v2\".protodb.TableQueryReq.Where2EntryR\x06Where2\x1a8\n" +
+	" \x03(\v2\".protodb.TableQueryReq.Where2EntryR\x06Where2\x12C\n" +
+	"\tOneofCase\x18\v \x03(\v2%.protodb.TableQueryReq.OneofCaseEntryR\tOneofCase\x12\x18\n" +
+	"\aOrderBy\x18\f \x03(\tR\aOrderBy\x1a8\n" +
 	"\n" +
 	"WhereEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
@@ -1339,6 +2253,9 @@ const file_protodb_proto_rawDesc = "" +
 	"\x05value\x18\x02 \x01(\x0e2\x16.protodb.WhereOperatorR\x05value:\x028\x01\x1a9\n" +
 	"\vWhere2Entry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a<\n" +
+	"\x0eOneofCaseEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa1\x01\n" +
 	"\tQueryResp\x12\x1e\n" +
 	"\n" +
@@ -1369,7 +2286,64 @@ const file_protodb_proto_rawDesc = "" +
 	"\x05value\x18\x02 \x01(\x0e2\x16.protodb.WhereOperatorR\x05value:\x028\x01\x1a9\n" +
 	"\vWhere2Entry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01*\xac\x01\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"S\n" +
+	"\x11DescribeSchemaReq\x12\x1e\n" +
+	"\n" +
+	"SchemeName\x18\x01 \x01(\tR\n" +
+	"SchemeName\x12\x1e\n" +
+	"\n" +
+	"TableNames\x18\x02 \x03(\tR\n" +
+	"TableNames\"\xd2\x03\n" +
+	"\n" +
+	"ColumnInfo\x12\x12\n" +
+	"\x04Name\x18\x01 \x01(\tR\x04Name\x12\x1c\n" +
+	"\tFieldName\x18\x02 \x01(\tR\tFieldName\x12\x1c\n" +
+	"\tProtoKind\x18\x03 \x01(\tR\tProtoKind\x12\x1a\n" +
+	"\bTypeName\x18\x04 \x01(\tR\bTypeName\x12\x16\n" +
+	"\x06IsList\x18\x05 \x01(\bR\x06IsList\x12\x14\n" +
+	"\x05IsMap\x18\x06 \x01(\bR\x05IsMap\x12\x18\n" +
+	"\aSQLType\x18\a \x01(\tR\aSQLType\x12\x18\n" +
+	"\aPrimary\x18\b \x01(\bR\aPrimary\x12\x16\n" +
+	"\x06Unique\x18\t \x01(\bR\x06Unique\x12\x1e\n" +
+	"\n" +
+	"UniqueName\x18\n" +
+	" \x01(\tR\n" +
+	"UniqueName\x12\x1c\n" +
+	"\tReference\x18\v \x01(\tR\tReference\x12\x18\n" +
+	"\aNotNull\x18\f \x01(\bR\aNotNull\x12\x1a\n" +
+	"\bNoUpdate\x18\r \x01(\bR\bNoUpdate\x12\x1a\n" +
+	"\bNoInsert\x18\x0e \x01(\bR\bNoInsert\x124\n" +
+	"\tOperators\x18\x0f \x03(\x0e2\x16.protodb.WhereOperatorR\tOperators\x12\x18\n" +
+	"\aComment\x18\x10 \x03(\tR\aComment\"=\n" +
+	"\rUniqueKeyInfo\x12\x12\n" +
+	"\x04Name\x18\x01 \x01(\tR\x04Name\x12\x18\n" +
+	"\aColumns\x18\x02 \x03(\tR\aColumns\"\xfa\x01\n" +
+	"\tTableInfo\x12\x18\n" +
+	"\aMsgName\x18\x01 \x01(\tR\aMsgName\x12\x1c\n" +
+	"\tTableName\x18\x02 \x01(\tR\tTableName\x12-\n" +
+	"\aColumns\x18\x03 \x03(\v2\x13.protodb.ColumnInfoR\aColumns\x12\x1e\n" +
+	"\n" +
+	"PrimaryKey\x18\x04 \x03(\tR\n" +
+	"PrimaryKey\x126\n" +
+	"\n" +
+	"UniqueKeys\x18\x05 \x03(\v2\x16.protodb.UniqueKeyInfoR\n" +
+	"UniqueKeys\x12\x14\n" +
+	"\x05NotDB\x18\x06 \x01(\bR\x05NotDB\x12\x18\n" +
+	"\aComment\x18\a \x03(\tR\aComment\"s\n" +
+	"\tQueryInfo\x12\x12\n" +
+	"\x04Name\x18\x01 \x01(\tR\x04Name\x12\x1a\n" +
+	"\bParamMsg\x18\x02 \x01(\tR\bParamMsg\x12\x1c\n" +
+	"\tResultMsg\x18\x03 \x01(\tR\tResultMsg\x12\x18\n" +
+	"\aComment\x18\x04 \x03(\tR\aComment\"n\n" +
+	"\x12DescribeSchemaResp\x12*\n" +
+	"\x06Tables\x18\x01 \x03(\v2\x12.protodb.TableInfoR\x06Tables\x12,\n" +
+	"\aQueries\x18\x02 \x03(\v2\x12.protodb.QueryInfoR\aQueries*L\n" +
+	"\vEnumStorage\x12\f\n" +
+	"\bEnumAuto\x10\x00\x12\r\n" +
+	"\tEnumAsInt\x10\x01\x12\x0e\n" +
+	"\n" +
+	"EnumAsName\x10\x02\x12\x10\n" +
+	"\fEnumAsNative\x10\x03*\xb9\x01\n" +
 	"\vFieldDbType\x12\r\n" +
 	"\tAutoMatch\x10\x00\x12\b\n" +
 	"\x04BOOL\x10\x01\x12\t\n" +
@@ -1387,7 +2361,8 @@ const file_protodb_proto_rawDesc = "" +
 	"\x05BYTEA\x10\v\x12\b\n" +
 	"\x04INET\x10\f\x12\n" +
 	"\n" +
-	"\x06UINT32\x10\r*^\n" +
+	"\x06UINT32\x10\r\x12\v\n" +
+	"\aNUMERIC\x10\x0e*^\n" +
 	"\vCrudReqCode\x12\n" +
 	"\n" +
 	"\x06INSERT\x10\x00\x12\n" +
@@ -1402,7 +2377,7 @@ const file_protodb_proto_rawDesc = "" +
 	"\tDMLResult\x10\x00\x12\n" +
 	"\n" +
 	"\x06NewMsg\x10\x01\x12\x13\n" +
-	"\x0fOldMsgAndNewMsg\x10\x02*\xf8\x01\n" +
+	"\x0fOldMsgAndNewMsg\x10\x02*\x9e\x02\n" +
 	"\rWhereOperator\x12\x0f\n" +
 	"\vWOP_UNKNOWN\x10\x00\x12\n" +
 	"\n" +
@@ -1424,16 +2399,21 @@ const file_protodb_proto_rawDesc = "" +
 	"\n" +
 	"WOP_LEN_LT\x10\f\x12\x0f\n" +
 	"\vWOP_LEN_LTE\x10\r\x12\x0f\n" +
-	"\vWOP_HAS_KEY\x10\x0e2\xad\x01\n" +
+	"\vWOP_HAS_KEY\x10\x0e\x12\x0f\n" +
+	"\vWOP_IS_NULL\x10\x0f\x12\x13\n" +
+	"\x0fWOP_IS_NOT_NULL\x10\x102\xfa\x01\n" +
 	"\n" +
 	"ProtoDbSrv\x12-\n" +
 	"\x04Crud\x12\x10.protodb.CrudReq\x1a\x11.protodb.CrudResp\"\x00\x12<\n" +
 	"\n" +
 	"TableQuery\x12\x16.protodb.TableQueryReq\x1a\x12.protodb.QueryResp\"\x000\x01\x122\n" +
-	"\x05Query\x12\x11.protodb.QueryReq\x1a\x12.protodb.QueryResp\"\x000\x01:F\n" +
+	"\x05Query\x12\x11.protodb.QueryReq\x1a\x12.protodb.QueryResp\"\x000\x01\x12K\n" +
+	"\x0eDescribeSchema\x12\x1a.protodb.DescribeSchemaReq\x1a\x1b.protodb.DescribeSchemaResp\"\x00:F\n" +
 	"\x04pdbf\x12\x1c.google.protobuf.FileOptions\x18\xe0\x0e \x01(\v2\x10.protodb.PDBFileR\x04pdbf\x88\x01\x01:H\n" +
 	"\x04pdbm\x12\x1f.google.protobuf.MessageOptions\x18\xe0\x0e \x01(\v2\x0f.protodb.PDBMsgR\x04pdbm\x88\x01\x01:F\n" +
-	"\x03pdb\x12\x1d.google.protobuf.FieldOptions\x18\xe0\x0e \x01(\v2\x11.protodb.PDBFieldR\x03pdb\x88\x01\x01B\x1aZ\x18github.com/ygrpc/protodbb\x06proto3"
+	"\x03pdb\x12\x1d.google.protobuf.FieldOptions\x18\xe0\x0e \x01(\v2\x11.protodb.PDBFieldR\x03pdb\x88\x01\x01:H\n" +
+	"\x04pdbo\x12\x1d.google.protobuf.OneofOptions\x18\xe0\x0e \x01(\v2\x11.protodb.PDBOneofR\x04pdbo\x88\x01\x01:L\n" +
+	"\x04pdbs\x12\x1f.google.protobuf.ServiceOptions\x18\xe0\x0e \x01(\v2\x13.protodb.PDBServiceR\x04pdbs\x88\x01\x01B\x1aZ\x18github.com/ygrpc/protodbb\x06proto3"
 
 var (
 	file_protodb_proto_rawDescOnce sync.Once
@@ -1447,60 +2427,91 @@ func file_protodb_proto_rawDescGZIP() []byte {
 	return file_protodb_proto_rawDescData
 }
 
-var file_protodb_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
-var file_protodb_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_protodb_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_protodb_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
 var file_protodb_proto_goTypes = []any{
-	(FieldDbType)(0),                    // 0: protodb.FieldDbType
-	(CrudReqCode)(0),                    // 1: protodb.CrudReqCode
-	(CrudResultType)(0),                 // 2: protodb.CrudResultType
-	(WhereOperator)(0),                  // 3: protodb.WhereOperator
-	(*PDBFile)(nil),                     // 4: protodb.PDBFile
-	(*PDBMsg)(nil),                      // 5: protodb.PDBMsg
-	(*PDBField)(nil),                    // 6: protodb.PDBField
-	(*CrudReq)(nil),                     // 7: protodb.CrudReq
-	(*CrudResp)(nil),                    // 8: protodb.CrudResp
-	(*TableQueryReq)(nil),               // 9: protodb.TableQueryReq
-	(*QueryResp)(nil),                   // 10: protodb.QueryResp
-	(*QueryReq)(nil),                    // 11: protodb.QueryReq
-	nil,                                 // 12: protodb.TableQueryReq.WhereEntry
-	nil,                                 // 13: protodb.TableQueryReq.Where2OperatorEntry
-	nil,                                 // 14: protodb.TableQueryReq.Where2Entry
-	nil,                                 // 15: protodb.QueryReq.WhereEntry
-	nil,                                 // 16: protodb.QueryReq.Where2OperatorEntry
-	nil,                                 // 17: protodb.QueryReq.Where2Entry
-	(*descriptorpb.FileOptions)(nil),    // 18: google.protobuf.FileOptions
-	(*descriptorpb.MessageOptions)(nil), // 19: google.protobuf.MessageOptions
-	(*descriptorpb.FieldOptions)(nil),   // 20: google.protobuf.FieldOptions
+	(EnumStorage)(0),                    // 0: protodb.EnumStorage
+	(FieldDbType)(0),                    // 1: protodb.FieldDbType
+	(CrudReqCode)(0),                    // 2: protodb.CrudReqCode
+	(CrudResultType)(0),                 // 3: protodb.CrudResultType
+	(WhereOperator)(0),                  // 4: protodb.WhereOperator
+	(*PDBFile)(nil),                     // 5: protodb.PDBFile
+	(*PDBMsg)(nil),                      // 6: protodb.PDBMsg
+	(*PDBQuery)(nil),                    // 7: protodb.PDBQuery
+	(*PDBService)(nil),                  // 8: protodb.PDBService
+	(*PDBField)(nil),                    // 9: protodb.PDBField
+	(*PDBOneof)(nil),                    // 10: protodb.PDBOneof
+	(*CrudReq)(nil),                     // 11: protodb.CrudReq
+	(*CrudResp)(nil),                    // 12: protodb.CrudResp
+	(*TableQueryReq)(nil),               // 13: protodb.TableQueryReq
+	(*QueryResp)(nil),                   // 14: protodb.QueryResp
+	(*QueryReq)(nil),                    // 15: protodb.QueryReq
+	(*DescribeSchemaReq)(nil),           // 16: protodb.DescribeSchemaReq
+	(*ColumnInfo)(nil),                  // 17: protodb.ColumnInfo
+	(*UniqueKeyInfo)(nil),               // 18: protodb.UniqueKeyInfo
+	(*TableInfo)(nil),                   // 19: protodb.TableInfo
+	(*QueryInfo)(nil),                   // 20: protodb.QueryInfo
+	(*DescribeSchemaResp)(nil),          // 21: protodb.DescribeSchemaResp
+	nil,                                 // 22: protodb.PDBQuery.DialectSQLEntry
+	nil,                                 // 23: protodb.TableQueryReq.WhereEntry
+	nil,                                 // 24: protodb.TableQueryReq.Where2OperatorEntry
+	nil,                                 // 25: protodb.TableQueryReq.Where2Entry
+	nil,                                 // 26: protodb.TableQueryReq.OneofCaseEntry
+	nil,                                 // 27: protodb.QueryReq.WhereEntry
+	nil,                                 // 28: protodb.QueryReq.Where2OperatorEntry
+	nil,                                 // 29: protodb.QueryReq.Where2Entry
+	(*descriptorpb.FileOptions)(nil),    // 30: google.protobuf.FileOptions
+	(*descriptorpb.MessageOptions)(nil), // 31: google.protobuf.MessageOptions
+	(*descriptorpb.FieldOptions)(nil),   // 32: google.protobuf.FieldOptions
+	(*descriptorpb.OneofOptions)(nil),   // 33: google.protobuf.OneofOptions
+	(*descriptorpb.ServiceOptions)(nil), // 34: google.protobuf.ServiceOptions
 }
 var file_protodb_proto_depIdxs = []int32{
-	0,  // 0: protodb.PDBField.DbType:type_name -> protodb.FieldDbType
-	1,  // 1: protodb.CrudReq.Code:type_name -> protodb.CrudReqCode
-	2,  // 2: protodb.CrudReq.ResultType:type_name -> protodb.CrudResultType
-	12, // 3: protodb.TableQueryReq.Where:type_name -> protodb.TableQueryReq.WhereEntry
-	13, // 4: protodb.TableQueryReq.Where2Operator:type_name -> protodb.TableQueryReq.Where2OperatorEntry
-	14, // 5: protodb.TableQueryReq.Where2:type_name -> protodb.TableQueryReq.Where2Entry
-	15, // 6: protodb.QueryReq.Where:type_name -> protodb.QueryReq.WhereEntry
-	16, // 7: protodb.QueryReq.Where2Operator:type_name -> protodb.QueryReq.Where2OperatorEntry
-	17, // 8: protodb.QueryReq.Where2:type_name -> protodb.QueryReq.Where2Entry
-	3,  // 9: protodb.TableQueryReq.Where2OperatorEntry.value:type_name -> protodb.WhereOperator
-	3,  // 10: protodb.QueryReq.Where2OperatorEntry.value:type_name -> protodb.WhereOperator
-	18, // 11: protodb.pdbf:extendee -> google.protobuf.FileOptions
-	19, // 12: protodb.pdbm:extendee -> google.protobuf.MessageOptions
-	20, // 13: protodb.pdb:extendee -> google.protobuf.FieldOptions
-	4,  // 14: protodb.pdbf:type_name -> protodb.PDBFile
-	5,  // 15: protodb.pdbm:type_name -> protodb.PDBMsg
-	6,  // 16: protodb.pdb:type_name -> protodb.PDBField
-	7,  // 17: protodb.ProtoDbSrv.Crud:input_type -> protodb.CrudReq
-	9,  // 18: protodb.ProtoDbSrv.TableQuery:input_type -> protodb.TableQueryReq
-	11, // 19: protodb.ProtoDbSrv.Query:input_type -> protodb.QueryReq
-	8,  // 20: protodb.ProtoDbSrv.Crud:output_type -> protodb.CrudResp
-	10, // 21: protodb.ProtoDbSrv.TableQuery:output_type -> protodb.QueryResp
-	10, // 22: protodb.ProtoDbSrv.Query:output_type -> protodb.QueryResp
-	20, // [20:23] is the sub-list for method output_type
-	17, // [17:20] is the sub-list for method input_type
-	14, // [14:17] is the sub-list for extension type_name
-	11, // [11:14] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	0,  // 0: protodb.PDBFile.EnumStorage:type_name -> protodb.EnumStorage
+	7,  // 1: protodb.PDBMsg.Queries:type_name -> protodb.PDBQuery
+	22, // 2: protodb.PDBQuery.DialectSQL:type_name -> protodb.PDBQuery.DialectSQLEntry
+	7,  // 3: protodb.PDBService.Queries:type_name -> protodb.PDBQuery
+	1,  // 4: protodb.PDBField.DbType:type_name -> protodb.FieldDbType
+	0,  // 5: protodb.PDBField.EnumStorage:type_name -> protodb.EnumStorage
+	2,  // 6: protodb.CrudReq.Code:type_name -> protodb.CrudReqCode
+	3,  // 7: protodb.CrudReq.ResultType:type_name -> protodb.CrudResultType
+	23, // 8: protodb.TableQueryReq.Where:type_name -> protodb.TableQueryReq.WhereEntry
+	24, // 9: protodb.TableQueryReq.Where2Operator:type_name -> protodb.TableQueryReq.Where2OperatorEntry
+	25, // 10: protodb.TableQueryReq.Where2:type_name -> protodb.TableQueryReq.Where2Entry
+	26, // 11: protodb.TableQueryReq.OneofCase:type_name -> protodb.TableQueryReq.OneofCaseEntry
+	27, // 12: protodb.QueryReq.Where:type_name -> protodb.QueryReq.WhereEntry
+	28, // 13: protodb.QueryReq.Where2Operator:type_name -> protodb.QueryReq.Where2OperatorEntry
+	29, // 14: protodb.QueryReq.Where2:type_name -> protodb.QueryReq.Where2Entry
+	4,  // 15: protodb.ColumnInfo.Operators:type_name -> protodb.WhereOperator
+	17, // 16: protodb.TableInfo.Columns:type_name -> protodb.ColumnInfo
+	18, // 17: protodb.TableInfo.UniqueKeys:type_name -> protodb.UniqueKeyInfo
+	19, // 18: protodb.DescribeSchemaResp.Tables:type_name -> protodb.TableInfo
+	20, // 19: protodb.DescribeSchemaResp.Queries:type_name -> protodb.QueryInfo
+	4,  // 20: protodb.TableQueryReq.Where2OperatorEntry.value:type_name -> protodb.WhereOperator
+	4,  // 21: protodb.QueryReq.Where2OperatorEntry.value:type_name -> protodb.WhereOperator
+	30, // 22: protodb.pdbf:extendee -> google.protobuf.FileOptions
+	31, // 23: protodb.pdbm:extendee -> google.protobuf.MessageOptions
+	32, // 24: protodb.pdb:extendee -> google.protobuf.FieldOptions
+	33, // 25: protodb.pdbo:extendee -> google.protobuf.OneofOptions
+	34, // 26: protodb.pdbs:extendee -> google.protobuf.ServiceOptions
+	5,  // 27: protodb.pdbf:type_name -> protodb.PDBFile
+	6,  // 28: protodb.pdbm:type_name -> protodb.PDBMsg
+	9,  // 29: protodb.pdb:type_name -> protodb.PDBField
+	10, // 30: protodb.pdbo:type_name -> protodb.PDBOneof
+	8,  // 31: protodb.pdbs:type_name -> protodb.PDBService
+	11, // 32: protodb.ProtoDbSrv.Crud:input_type -> protodb.CrudReq
+	13, // 33: protodb.ProtoDbSrv.TableQuery:input_type -> protodb.TableQueryReq
+	15, // 34: protodb.ProtoDbSrv.Query:input_type -> protodb.QueryReq
+	16, // 35: protodb.ProtoDbSrv.DescribeSchema:input_type -> protodb.DescribeSchemaReq
+	12, // 36: protodb.ProtoDbSrv.Crud:output_type -> protodb.CrudResp
+	14, // 37: protodb.ProtoDbSrv.TableQuery:output_type -> protodb.QueryResp
+	14, // 38: protodb.ProtoDbSrv.Query:output_type -> protodb.QueryResp
+	21, // 39: protodb.ProtoDbSrv.DescribeSchema:output_type -> protodb.DescribeSchemaResp
+	36, // [36:40] is the sub-list for method output_type
+	32, // [32:36] is the sub-list for method input_type
+	27, // [27:32] is the sub-list for extension type_name
+	22, // [22:27] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
 }
 
 func init() { file_protodb_proto_init() }
@@ -1513,9 +2524,9 @@ func file_protodb_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_protodb_proto_rawDesc), len(file_protodb_proto_rawDesc)),
-			NumEnums:      4,
-			NumMessages:   14,
-			NumExtensions: 3,
+			NumEnums:      5,
+			NumMessages:   25,
+			NumExtensions: 5,
 			NumServices:   1,
 		},
 		GoTypes:           file_protodb_proto_goTypes,
@@ -12,6 +12,8 @@ const SQL_LT = " < "
 const SQL_GTE = " >= "
 const SQL_LTE = " <= "
 const SQL_LIKE = " LIKE "
+const SQL_IS_NULL = " IS NULL "
+const SQL_IS_NOT_NULL = " IS NOT NULL "
 const SQL_COMMA = " , "
 const SQL_SEMICOLON = " ; "
 const SQL_SPACE = " "
@@ -25,6 +27,7 @@ const SQL_OR = " OR "
 const SQL_ANY = " ANY "
 const SQL_1E1 = " 1 = 1 "
 const SQL_ORDER_BY = " ORDER BY "
+const SQL_DESC = " DESC"
 const SQL_INTERVAL = " INTERVAL "
 const SQL_MINUTE = " MINUTE "
 const SQL_MINUTES = " MINUTES "
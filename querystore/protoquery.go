@@ -0,0 +1,369 @@
+package querystore
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/crud"
+	"github.com/ygrpc/protodb/msgstore"
+	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/protosql"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// TProtoQuery a named query declared by PDBQuery option, parsed and validated
+type TProtoQuery struct {
+	Name       string
+	ParamType  protoreflect.MessageType
+	ResultType protoreflect.MessageType
+	Comment    []string
+
+	sql        *namedSQL
+	dialectSQL map[sqldb.TDBDialect]*namedSQL
+}
+
+// namedSQL sql split by named parameters, len(parts) == len(params)+1
+type namedSQL struct {
+	parts  []string
+	params []protoreflect.FieldDescriptor
+}
+
+// RegisterProtoQueries register the named queries declared in fd to GlobalQueryStore
+func RegisterProtoQueries(fd protoreflect.FileDescriptor) error {
+	return GlobalQueryStore.RegisterProtoQueries(fd)
+}
+
+// RegisterAllProtoQueries register the named queries of files to GlobalQueryStore
+func RegisterAllProtoQueries(files *protoregistry.Files, filter func(fd protoreflect.FileDescriptor) bool) error {
+	return GlobalQueryStore.RegisterAllProtoQueries(files, filter)
+}
+
+// RegisterProtoQueries parse the named queries declared in fd(PDBMsg.Queries, PDBService.Queries)
+// and register them, nothing is registered if any query is invalid
+func (this *TQueryStore) RegisterProtoQueries(fd protoreflect.FileDescriptor) error {
+	queries, err := ParseProtoQueries(fd)
+	if err != nil {
+		return err
+	}
+	for _, q := range queries {
+		this.RegisterQuery(q.Name, q.SqlGenerator())
+		this.setQueryInfo(TQueryInfo{
+			Name:      q.Name,
+			ParamMsg:  q.ParamType.Descriptor().FullName(),
+			ResultMsg: q.ResultType.Descriptor().FullName(),
+			Comment:   q.Comment,
+		})
+	}
+	return nil
+}
+
+// RegisterAllProtoQueries RegisterProtoQueries every file of files which imports protodb.proto,
+// files is protoregistry.GlobalFiles if nil, filter can be nil to register all of them
+func (this *TQueryStore) RegisterAllProtoQueries(files *protoregistry.Files, filter func(fd protoreflect.FileDescriptor) bool) error {
+	if files == nil {
+		files = protoregistry.GlobalFiles
+	}
+	var errs []error
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		if !msgstore.ImportsProtodb(fd) || (filter != nil && !filter(fd)) {
+			return true
+		}
+		if err := this.RegisterProtoQueries(fd); err != nil {
+			errs = append(errs, err)
+		}
+		return true
+	})
+	return errors.Join(errs...)
+}
+
+// ParseProtoQueries parse and validate the named queries declared in fd.
+// queries of a message option use the message as default param and result message
+func ParseProtoQueries(fd protoreflect.FileDescriptor) ([]*TProtoQuery, error) {
+	resolver, err := newFileResolver(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	var queries []*TProtoQuery
+	names := make(map[string]struct{})
+	add := func(pdbq *protodb.PDBQuery, defaultMsg protoreflect.MessageDescriptor, owner protoreflect.FullName) error {
+		q, err := parseProtoQuery(pdbq, defaultMsg, fd, resolver)
+		if err != nil {
+			return fmt.Errorf("query %q of %s err: %w", pdbq.Name, owner, err)
+		}
+		if _, ok := names[q.Name]; ok {
+			return fmt.Errorf("duplicate query %q in %s", q.Name, fd.Path())
+		}
+		names[q.Name] = struct{}{}
+		queries = append(queries, q)
+		return nil
+	}
+
+	msgs := fd.Messages()
+	for i := 0; i < msgs.Len(); i++ {
+		msgDesc := msgs.Get(i)
+		pdbm, _ := pdbutil.GetPDBM(msgDesc)
+		for _, pdbq := range pdbm.Queries {
+			if err := add(pdbq, msgDesc, msgDesc.FullName()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	services := fd.Services()
+	for i := 0; i < services.Len(); i++ {
+		serviceDesc := services.Get(i)
+		pdbs, _ := pdbutil.GetPDBS(serviceDesc)
+		for _, pdbq := range pdbs.Queries {
+			if err := add(pdbq, nil, serviceDesc.FullName()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return queries, nil
+}
+
+func parseProtoQuery(pdbq *protodb.PDBQuery, defaultMsg protoreflect.MessageDescriptor, fd protoreflect.FileDescriptor, resolver *protoregistry.Files) (*TProtoQuery, error) {
+	if len(pdbq.Name) == 0 {
+		return nil, errors.New("query name is empty")
+	}
+	if len(pdbq.SQL) == 0 && len(pdbq.DialectSQL) == 0 {
+		return nil, errors.New("query sql is empty")
+	}
+
+	paramDesc, err := resolveQueryMsg(pdbq.ParamMsg, defaultMsg, fd, resolver)
+	if err != nil {
+		return nil, fmt.Errorf("param msg: %w", err)
+	}
+	resultDesc, err := resolveQueryMsg(pdbq.ResultMsg, defaultMsg, fd, resolver)
+	if err != nil {
+		return nil, fmt.Errorf("result msg: %w", err)
+	}
+
+	q := &TProtoQuery{
+		Name:       pdbq.Name,
+		ParamType:  queryMsgType(paramDesc),
+		ResultType: queryMsgType(resultDesc),
+		Comment:    pdbq.Comment,
+		dialectSQL: make(map[sqldb.TDBDialect]*namedSQL),
+	}
+	if len(pdbq.SQL) > 0 {
+		if q.sql, err = parseNamedSQL(pdbq.SQL, paramDesc); err != nil {
+			return nil, err
+		}
+	}
+
+	dialectNames := make([]string, 0, len(pdbq.DialectSQL))
+	for name := range pdbq.DialectSQL {
+		dialectNames = append(dialectNames, name)
+	}
+	sort.Strings(dialectNames)
+	for _, name := range dialectNames {
+		d, ok := sqldb.GetDialectByName(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown dialect %q", name)
+		}
+		if q.dialectSQL[d.ID()], err = parseNamedSQL(pdbq.DialectSQL[name], paramDesc); err != nil {
+			return nil, fmt.Errorf("dialect %s: %w", name, err)
+		}
+	}
+	return q, nil
+}
+
+// resolveQueryMsg message of name, relative names are resolved in the package of fd
+func resolveQueryMsg(name string, defaultMsg protoreflect.MessageDescriptor, fd protoreflect.FileDescriptor, resolver *protoregistry.Files) (protoreflect.MessageDescriptor, error) {
+	if len(name) == 0 {
+		if defaultMsg == nil {
+			return nil, errors.New("message name is empty")
+		}
+		return defaultMsg, nil
+	}
+	name = strings.TrimPrefix(name, ".")
+	candidates := []protoreflect.FullName{protoreflect.FullName(name)}
+	if len(fd.Package()) > 0 {
+		candidates = append([]protoreflect.FullName{fd.Package().Append(protoreflect.Name(name))}, candidates...)
+	}
+	for _, fullName := range candidates {
+		if !fullName.IsValid() {
+			continue
+		}
+		d, err := resolver.FindDescriptorByName(fullName)
+		if err != nil {
+			continue
+		}
+		if msgDesc, ok := d.(protoreflect.MessageDescriptor); ok {
+			return msgDesc, nil
+		}
+		return nil, fmt.Errorf("%s is not a message", fullName)
+	}
+	return nil, fmt.Errorf("message %s not found in %s and its imports", name, fd.Path())
+}
+
+// newFileResolver files of fd and its imports
+func newFileResolver(fd protoreflect.FileDescriptor) (*protoregistry.Files, error) {
+	files := new(protoregistry.Files)
+	var register func(fd protoreflect.FileDescriptor) error
+	register = func(fd protoreflect.FileDescriptor) error {
+		if _, err := files.FindFileByPath(fd.Path()); err == nil {
+			return nil
+		}
+		if err := files.RegisterFile(fd); err != nil {
+			return err
+		}
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			if err := register(imports.Get(i).FileDescriptor); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return files, register(fd)
+}
+
+// queryMsgType generated go type of msgDesc if linked, else dynamicpb
+func queryMsgType(msgDesc protoreflect.MessageDescriptor) protoreflect.MessageType {
+	if msgType, err := protoregistry.GlobalTypes.FindMessageByName(msgDesc.FullName()); err == nil && msgType.Descriptor() == msgDesc {
+		return msgType
+	}
+	return dynamicpb.NewMessageType(msgDesc)
+}
+
+// parseNamedSQL split sqlStr by named parameters :name, the names must be fields of paramDesc.
+// quoted strings and identifiers, comments and '::' are not parameters
+func parseNamedSQL(sqlStr string, paramDesc protoreflect.MessageDescriptor) (*namedSQL, error) {
+	ns := &namedSQL{}
+	sb := &strings.Builder{}
+	for i := 0; i < len(sqlStr); {
+		c := sqlStr[i]
+		var next byte
+		if i+1 < len(sqlStr) {
+			next = sqlStr[i+1]
+		}
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			j := i + 1
+			for ; j < len(sqlStr); j++ {
+				if sqlStr[j] != c {
+					continue
+				}
+				// doubled quote is an escaped quote
+				if j+1 < len(sqlStr) && sqlStr[j+1] == c {
+					j++
+					continue
+				}
+				break
+			}
+			if j >= len(sqlStr) {
+				return nil, fmt.Errorf("unterminated %c quote in sql: %s", c, sqlStr)
+			}
+			sb.WriteString(sqlStr[i : j+1])
+			i = j + 1
+		case c == '-' && next == '-':
+			j := strings.IndexByte(sqlStr[i:], '\n')
+			if j < 0 {
+				j = len(sqlStr) - i
+			}
+			sb.WriteString(sqlStr[i : i+j])
+			i += j
+		case c == '/' && next == '*':
+			j := strings.Index(sqlStr[i+2:], "*/")
+			if j < 0 {
+				return nil, fmt.Errorf("unterminated comment in sql: %s", sqlStr)
+			}
+			sb.WriteString(sqlStr[i : i+j+4])
+			i += j + 4
+		case c == ':' && next == ':':
+			sb.WriteString("::")
+			i += 2
+		case c == ':' && isIdentStart(next):
+			j := i + 1
+			for j < len(sqlStr) && isIdentPart(sqlStr[j]) {
+				j++
+			}
+			name := sqlStr[i+1 : j]
+			fieldDesc, ok := pdbutil.FindFieldByColumnName(paramDesc, name)
+			if !ok {
+				return nil, fmt.Errorf("parameter :%s is not a field of %s", name, paramDesc.FullName())
+			}
+			ns.parts = append(ns.parts, sb.String())
+			ns.params = append(ns.params, fieldDesc)
+			sb.Reset()
+			i = j
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+	ns.parts = append(ns.parts, sb.String())
+	return ns, nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+// BuildSql sql of dialect with the parameters bound from param in dialect placeholder style
+func (this *TProtoQuery) BuildSql(dialect sqldb.TDBDialect, param proto.Message) (sqlStr string, sqlVals []any, err error) {
+	ns, ok := this.dialectSQL[dialect]
+	if !ok {
+		ns = this.sql
+	}
+	if ns == nil {
+		return "", nil, fmt.Errorf("query %s has no sql for dialect %s", this.Name, dialect.String())
+	}
+
+	placeholder := dialect.Placeholder()
+	sb := &strings.Builder{}
+	sqlVals = make([]any, 0, len(ns.params))
+	for i, fieldDesc := range ns.params {
+		sb.WriteString(ns.parts[i])
+		if placeholder == protosql.SQL_QUESTION {
+			sb.WriteString(string(protosql.SQL_QUESTION))
+		} else {
+			sb.WriteString(string(protosql.SQL_DOLLAR))
+			sb.WriteString(strconv.Itoa(i + 1))
+		}
+		val, err := crud.FieldSQLArg(param, fieldDesc, dialect)
+		if err != nil {
+			return "", nil, fmt.Errorf("query %s parameter :%s err: %w", this.Name, fieldDesc.Name(), err)
+		}
+		sqlVals = append(sqlVals, val)
+	}
+	sb.WriteString(ns.parts[len(ns.params)])
+	return sb.String(), sqlVals, nil
+}
+
+// SqlGenerator query generator of the Query rpc, the param message is decoded from QueryReq.WhereMsgBytes
+func (this *TProtoQuery) SqlGenerator() TfnQuerySqlGenerator {
+	staticResultMsg := this.ResultType.New().Interface()
+	fnGetResultMsg := func(new bool) proto.Message {
+		if new {
+			return this.ResultType.New().Interface()
+		}
+		return staticResultMsg
+	}
+	return func(meta http.Header, db sqldb.DB, req *protodb.QueryReq) (string, []interface{}, msgstore.TFnGetMsg, error) {
+		param := this.ParamType.New().Interface()
+		if err := crud.MsgUnmarshal(param, req.WhereMsgBytes, req.MsgFormat); err != nil {
+			return "", nil, nil, fmt.Errorf("unmarshal param msg of query %s err: %w", this.Name, err)
+		}
+		sqlStr, sqlVals, err := this.BuildSql(sqldb.GetExecutorDialect(db), param)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		return sqlStr, sqlVals, fnGetResultMsg, nil
+	}
+}
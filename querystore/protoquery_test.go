@@ -0,0 +1,202 @@
+package querystore
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func buildProtoQueryFile(t *testing.T, msgQueries []*protodb.PDBQuery, serviceQueries []*protodb.PDBQuery) protoreflect.FileDescriptor {
+	t.Helper()
+	field := func(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(number),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     typ.Enum(),
+		}
+	}
+	userOpts := &descriptorpb.MessageOptions{}
+	proto.SetExtension(userOpts, protodb.E_Pdbm, &protodb.PDBMsg{TableName: "pq_user", Queries: msgQueries})
+	serviceOpts := &descriptorpb.ServiceOptions{}
+	proto.SetExtension(serviceOpts, protodb.E_Pdbs, &protodb.PDBService{Queries: serviceQueries})
+
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Syntax:     proto.String("proto3"),
+		Name:       proto.String("pq/query.proto"),
+		Package:    proto.String("pq"),
+		Dependency: []string{"protodb.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name:    proto.String("PqUser"),
+				Options: userOpts,
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("id", 1, descriptorpb.FieldDescriptorProto_TYPE_INT64),
+					field("email", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+					field("tenant_id", 3, descriptorpb.FieldDescriptorProto_TYPE_INT64),
+				},
+			},
+			{
+				Name: proto.String("UserByEmailParam"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("email", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING),
+					field("tenant_id", 2, descriptorpb.FieldDescriptorProto_TYPE_INT64),
+				},
+			},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{Name: proto.String("PqQueries"), Options: serviceOpts}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile: %v", err)
+	}
+	return fd
+}
+
+func userByEmailQuery() *protodb.PDBQuery {
+	return &protodb.PDBQuery{
+		Name:       "pq_user_by_email",
+		SQL:        "SELECT id, email::text FROM pq_user WHERE email = :email AND tenant_id = :tenant_id AND note <> ':skip' -- :comment\n",
+		DialectSQL: map[string]string{"mysql": "SELECT id, email FROM pq_user WHERE email = :email AND tenant_id = :tenant_id"},
+		ParamMsg:   "UserByEmailParam",
+		ResultMsg:  "pq.PqUser",
+	}
+}
+
+func TestParseProtoQueries(t *testing.T) {
+	fd := buildProtoQueryFile(t,
+		[]*protodb.PDBQuery{{Name: "pq_user_by_id", SQL: "SELECT * FROM pq_user WHERE id = :id"}},
+		[]*protodb.PDBQuery{userByEmailQuery()},
+	)
+	queries, err := ParseProtoQueries(fd)
+	if err != nil {
+		t.Fatalf("ParseProtoQueries: %v", err)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("got %d queries, want 2", len(queries))
+	}
+
+	byID := queries[0]
+	if byID.ParamType.Descriptor().FullName() != "pq.PqUser" || byID.ResultType.Descriptor().FullName() != "pq.PqUser" {
+		t.Fatalf("message query should default to its message, got %s %s", byID.ParamType.Descriptor().FullName(), byID.ResultType.Descriptor().FullName())
+	}
+	user := byID.ParamType.New()
+	user.Set(user.Descriptor().Fields().ByName("id"), protoreflect.ValueOfInt64(7))
+	sqlStr, sqlVals, err := byID.BuildSql(sqldb.Postgres, user.Interface())
+	if err != nil {
+		t.Fatalf("BuildSql: %v", err)
+	}
+	if sqlStr != "SELECT * FROM pq_user WHERE id = $1" || !reflect.DeepEqual(sqlVals, []any{int64(7)}) {
+		t.Fatalf("BuildSql = %q %v", sqlStr, sqlVals)
+	}
+
+	byEmail := queries[1]
+	param := byEmail.ParamType.New()
+	param.Set(param.Descriptor().Fields().ByName("email"), protoreflect.ValueOfString("a@example.com"))
+	param.Set(param.Descriptor().Fields().ByName("tenant_id"), protoreflect.ValueOfInt64(3))
+	wantVals := []any{"a@example.com", int64(3)}
+	tests := []struct {
+		dialect sqldb.TDBDialect
+		sql     string
+	}{
+		{sqldb.Postgres, "SELECT id, email::text FROM pq_user WHERE email = $1 AND tenant_id = $2 AND note <> ':skip' -- :comment\n"},
+		{sqldb.SQLite, "SELECT id, email::text FROM pq_user WHERE email = ? AND tenant_id = ? AND note <> ':skip' -- :comment\n"},
+		{sqldb.Mysql, "SELECT id, email FROM pq_user WHERE email = ? AND tenant_id = ?"},
+	}
+	for _, tt := range tests {
+		sqlStr, sqlVals, err := byEmail.BuildSql(tt.dialect, param.Interface())
+		if err != nil {
+			t.Fatalf("%s BuildSql: %v", tt.dialect, err)
+		}
+		if sqlStr != tt.sql || !reflect.DeepEqual(sqlVals, wantVals) {
+			t.Fatalf("%s BuildSql = %q %v, want %q %v", tt.dialect, sqlStr, sqlVals, tt.sql, wantVals)
+		}
+	}
+}
+
+func TestParseProtoQueriesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   *protodb.PDBQuery
+		wantErr string
+	}{
+		{"unknown param", &protodb.PDBQuery{Name: "q", SQL: "SELECT 1 WHERE x = :missing", ParamMsg: "UserByEmailParam", ResultMsg: "PqUser"}, "parameter :missing"},
+		{"unknown dialect", &protodb.PDBQuery{Name: "q", DialectSQL: map[string]string{"oracle": "SELECT 1"}, ParamMsg: "UserByEmailParam", ResultMsg: "PqUser"}, `unknown dialect "oracle"`},
+		{"no result msg", &protodb.PDBQuery{Name: "q", SQL: "SELECT 1", ParamMsg: "UserByEmailParam"}, "result msg"},
+		{"unknown msg", &protodb.PDBQuery{Name: "q", SQL: "SELECT 1", ParamMsg: "Nope", ResultMsg: "PqUser"}, "message Nope not found"},
+		{"no sql", &protodb.PDBQuery{Name: "q", ParamMsg: "UserByEmailParam", ResultMsg: "PqUser"}, "sql is empty"},
+		{"unterminated quote", &protodb.PDBQuery{Name: "q", SQL: "SELECT 'a", ParamMsg: "UserByEmailParam", ResultMsg: "PqUser"}, "unterminated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fd := buildProtoQueryFile(t, nil, []*protodb.PDBQuery{tt.query})
+			if _, err := ParseProtoQueries(fd); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ParseProtoQueries err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	fd := buildProtoQueryFile(t, []*protodb.PDBQuery{{Name: "pq_user_by_email", SQL: "SELECT 1"}}, []*protodb.PDBQuery{userByEmailQuery()})
+	if _, err := ParseProtoQueries(fd); err == nil || !strings.Contains(err.Error(), "duplicate query") {
+		t.Fatalf("duplicate query err = %v", err)
+	}
+}
+
+func TestRegisterProtoQueries(t *testing.T) {
+	store := NewQueryStore()
+	fd := buildProtoQueryFile(t, nil, []*protodb.PDBQuery{userByEmailQuery()})
+	if err := store.RegisterProtoQueries(fd); err != nil {
+		t.Fatalf("RegisterProtoQueries: %v", err)
+	}
+	fn, ok := store.GetQuery("pq_user_by_email")
+	if !ok {
+		t.Fatalf("query not registered")
+	}
+
+	param := fd.Messages().ByName("UserByEmailParam")
+	paramMsg := queryMsgType(param).New()
+	paramMsg.Set(param.Fields().ByName("email"), protoreflect.ValueOfString("b@example.com"))
+	whereMsgBytes, err := proto.Marshal(paramMsg.Interface())
+	if err != nil {
+		t.Fatalf("proto.Marshal: %v", err)
+	}
+	sqlStr, sqlVals, fnGetResultMsg, err := fn(nil, sqldb.NewTxWithDialectType(nil, sqldb.Postgres), &protodb.QueryReq{
+		QueryName:     "pq_user_by_email",
+		WhereMsgBytes: whereMsgBytes,
+	})
+	if err != nil {
+		t.Fatalf("query fn: %v", err)
+	}
+	if !strings.Contains(sqlStr, "email = $1 AND tenant_id = $2") || !reflect.DeepEqual(sqlVals, []any{"b@example.com", int64(0)}) {
+		t.Fatalf("query fn = %q %v", sqlStr, sqlVals)
+	}
+	if got := fnGetResultMsg(true).ProtoReflect().Descriptor().FullName(); got != "pq.PqUser" {
+		t.Fatalf("result msg = %s, want pq.PqUser", got)
+	}
+}
+
+func TestProtoQueryInfo(t *testing.T) {
+	store := NewQueryStore()
+	query := userByEmailQuery()
+	query.Comment = []string{"user of tenant by email"}
+	if err := store.RegisterProtoQueries(buildProtoQueryFile(t, nil, []*protodb.PDBQuery{query})); err != nil {
+		t.Fatalf("RegisterProtoQueries: %v", err)
+	}
+	store.RegisterQuery("hand_written", nil)
+
+	want := []TQueryInfo{
+		{Name: "hand_written"},
+		{Name: "pq_user_by_email", ParamMsg: "pq.UserByEmailParam", ResultMsg: "pq.PqUser", Comment: []string{"user of tenant by email"}},
+	}
+	if got := store.Queries(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Queries() = %v, want %v", got, want)
+	}
+}
@@ -3,43 +3,104 @@ package querystore
 import (
 	"fmt"
 	"net/http"
+	"sort"
 	"sync"
 
 	"github.com/ygrpc/protodb"
 	"github.com/ygrpc/protodb/msgstore"
 	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 type TfnQuerySqlGenerator func(meta http.Header, db sqldb.DB, req *protodb.QueryReq) (sqlStr string, sqlVals []interface{},
 	fnGetResultMsg msgstore.TFnGetMsg, err error)
 
-var (
-	queryStoreMu sync.RWMutex
-	queryStore   = make(map[string]TfnQuerySqlGenerator)
-)
+// TQueryStore queries of a protodb service,
+// services in one process can use their own store instead of GlobalQueryStore
+type TQueryStore struct {
+	mu         sync.RWMutex
+	queryStore map[string]TfnQuerySqlGenerator
+	// query name -> info, only queries declared by PDBQuery have message types
+	queryInfo map[string]TQueryInfo
+}
 
-// RegisterQuery register a query to queryStore
+// TQueryInfo description of a registered query
+type TQueryInfo struct {
+	Name string
+	// empty when the query is not declared by PDBQuery
+	ParamMsg  protoreflect.FullName
+	ResultMsg protoreflect.FullName
+	Comment   []string
+}
+
+// NewQueryStore create an empty query store
+func NewQueryStore() *TQueryStore {
+	return &TQueryStore{
+		queryStore: make(map[string]TfnQuerySqlGenerator),
+		queryInfo:  make(map[string]TQueryInfo),
+	}
+}
+
+// GlobalQueryStore the default store used by the package level functions
+var GlobalQueryStore = NewQueryStore()
+
+// RegisterQuery register a query to GlobalQueryStore
 // should call in init() function or at the beginning of the program before any query is used
 // queryName is the name of the query
 // queryFn is the function to generate sql
 
 func RegisterQuery(queryName string, queryFn TfnQuerySqlGenerator) {
-	queryStoreMu.RLock()
-	oldQueryFn, ok := queryStore[queryName]
-	queryStoreMu.RUnlock()
+	GlobalQueryStore.RegisterQuery(queryName, queryFn)
+}
+
+// GetQuery get a query from GlobalQueryStore
+func GetQuery(queryName string) (TfnQuerySqlGenerator, bool) {
+	return GlobalQueryStore.GetQuery(queryName)
+}
+
+// Queries infos of the queries in GlobalQueryStore
+func Queries() []TQueryInfo {
+	return GlobalQueryStore.Queries()
+}
+
+// RegisterQuery register a query to queryStore
+func (this *TQueryStore) RegisterQuery(queryName string, queryFn TfnQuerySqlGenerator) {
+	this.mu.RLock()
+	oldQueryFn, ok := this.queryStore[queryName]
+	this.mu.RUnlock()
 	if ok {
 		fmt.Println("reregister query to queryStore:", queryName, "old:", oldQueryFn, "new:", queryFn)
 	}
-	queryStoreMu.Lock()
-	queryStore[queryName] = queryFn
-	queryStoreMu.Unlock()
+	this.mu.Lock()
+	this.queryStore[queryName] = queryFn
+	this.queryInfo[queryName] = TQueryInfo{Name: queryName}
+	this.mu.Unlock()
+}
+
+// setQueryInfo set info of a registered query
+func (this *TQueryStore) setQueryInfo(info TQueryInfo) {
+	this.mu.Lock()
+	this.queryInfo[info.Name] = info
+	this.mu.Unlock()
+}
+
+// Queries infos of the registered queries sorted by name
+func (this *TQueryStore) Queries() []TQueryInfo {
+	this.mu.RLock()
+	infos := make([]TQueryInfo, 0, len(this.queryInfo))
+	for _, info := range this.queryInfo {
+		infos = append(infos, info)
+	}
+	this.mu.RUnlock()
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
 }
 
 // GetQuery get a query from queryStore
-func GetQuery(queryName string) (TfnQuerySqlGenerator, bool) {
-	queryStoreMu.RLock()
-	queryFn, ok := queryStore[queryName]
-	queryStoreMu.RUnlock()
+func (this *TQueryStore) GetQuery(queryName string) (TfnQuerySqlGenerator, bool) {
+	this.mu.RLock()
+	queryFn, ok := this.queryStore[queryName]
+	this.mu.RUnlock()
 	if !ok {
 		return nil, false
 	}
@@ -0,0 +1,251 @@
+// Package repo typed crud helpers used by the repositories generated by protoc-gen-protodb,
+// T is a generated message type
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/crud"
+	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// crud functions have no context, ctx is checked before the statement is sent
+
+// Insert insert msg and return the inserted row
+func Insert[T proto.Message](ctx context.Context, db sqldb.DB, msg T, dbschema string) (T, error) {
+	if err := ctx.Err(); err != nil {
+		return zeroOf[T](), err
+	}
+	return typedMsg[T](crud.DbInsertReturn(db, msg, 0, dbschema))
+}
+
+// Update update all fields of msg by primary key and return the updated row
+func Update[T proto.Message](ctx context.Context, db sqldb.DB, msg T, dbschema string) (T, error) {
+	if err := ctx.Err(); err != nil {
+		return zeroOf[T](), err
+	}
+	return typedMsg[T](crud.DbUpdateReturnNew(db, msg, 0, dbschema))
+}
+
+// UpdateFields update fields of msg by primary key and return the updated row
+func UpdateFields[T proto.Message, F ~string](ctx context.Context, db sqldb.DB, msg T, dbschema string, fields ...F) (T, error) {
+	if err := ctx.Err(); err != nil {
+		return zeroOf[T](), err
+	}
+	if len(fields) == 0 {
+		return zeroOf[T](), fmt.Errorf("update %s: no fields", msg.ProtoReflect().Descriptor().Name())
+	}
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = string(field)
+	}
+	return typedMsg[T](crud.DbUpdatePartialReturnNew(db, msg, names, dbschema))
+}
+
+// Delete delete msg by primary key and return the deleted row
+func Delete[T proto.Message](ctx context.Context, db sqldb.DB, msg T, dbschema string) (T, error) {
+	if err := ctx.Err(); err != nil {
+		return zeroOf[T](), err
+	}
+	return typedMsg[T](crud.DbDeleteReturn(db, msg, dbschema))
+}
+
+// SelectOne select the row by keyFields of msg, primary key if keyFields is empty
+func SelectOne[T proto.Message](ctx context.Context, db sqldb.DB, msg T, dbschema string, keyFields ...string) (T, error) {
+	if err := ctx.Err(); err != nil {
+		return zeroOf[T](), err
+	}
+	// generated key methods only pass primary or unique keys
+	return typedMsg[T](crud.DbSelectOne(db, msg, keyFields, nil, dbschema, false))
+}
+
+func typedMsg[T proto.Message](msg proto.Message, err error) (T, error) {
+	if err != nil {
+		return zeroOf[T](), err
+	}
+	return msg.(T), nil
+}
+
+func zeroOf[T any]() T {
+	var zero T
+	return zero
+}
+
+// Query typed table query, conditions are Where2 of TableQueryReq so one condition per field
+type Query[T proto.Message] struct {
+	Req *protodb.TableQueryReq
+	err error
+}
+
+// NewQuery query of the table of T
+func NewQuery[T proto.Message](dbschema string) *Query[T] {
+	var zero T
+	return &Query[T]{
+		Req: &protodb.TableQueryReq{
+			SchemeName: dbschema,
+			TableName:  string(zero.ProtoReflect().Descriptor().Name()),
+		},
+	}
+}
+
+// Where add condition field op value, value is formatted by FormatWhereValue
+func (q *Query[T]) Where(field string, op protodb.WhereOperator, value any) *Query[T] {
+	if q.err != nil {
+		return q
+	}
+	if _, ok := q.Req.Where2[field]; ok {
+		q.err = fmt.Errorf("query %s: duplicate condition of field %s", q.Req.TableName, field)
+		return q
+	}
+	s, err := FormatWhereValue(value)
+	if err != nil {
+		q.err = fmt.Errorf("query %s field %s: %w", q.Req.TableName, field, err)
+		return q
+	}
+	if q.Req.Where2 == nil {
+		q.Req.Where2 = make(map[string]string)
+		q.Req.Where2Operator = make(map[string]protodb.WhereOperator)
+	}
+	q.Req.Where2[field] = s
+	q.Req.Where2Operator[field] = op
+	return q
+}
+
+// Columns result columns, all columns if not set
+func (q *Query[T]) Columns(fields ...string) *Query[T] {
+	q.Req.ResultColumnNames = fields
+	return q
+}
+
+func (q *Query[T]) Limit(limit int32) *Query[T] {
+	q.Req.Limit = limit
+	return q
+}
+
+func (q *Query[T]) Offset(offset int64) *Query[T] {
+	q.Req.Offset = offset
+	return q
+}
+
+// All rows of query
+func (q *Query[T]) All(ctx context.Context, db sqldb.DB) ([]T, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	var zero T
+	msgDesc := zero.ProtoReflect().Descriptor()
+	sqlStr, sqlVals, err := crud.TableQueryBuildSql(db, msgDesc, q.Req, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, sqlStr, sqlVals...)
+	if err != nil {
+		return nil, fmt.Errorf("query %s err: %w", q.Req.TableName, err)
+	}
+	defer rows.Close()
+
+	msgFieldsMap := pdbutil.BuildMsgFieldsMap(nil, msgDesc.Fields(), true)
+	var rowScanner *crud.DbRowScanner
+	var msgs []T
+	for rows.Next() {
+		msg := zero.ProtoReflect().Type().New().Interface().(T)
+		if rowScanner == nil {
+			rowScanner, err = crud.NewDbRowScanner(rows, msg, nil, msgFieldsMap)
+			if err != nil {
+				return nil, fmt.Errorf("query %s create row scanner err: %w", q.Req.TableName, err)
+			}
+		}
+		if err := rowScanner.Scan(rows, msg); err != nil {
+			return nil, fmt.Errorf("query %s scan row err: %w", q.Req.TableName, err)
+		}
+		msgs = append(msgs, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query %s err: %w", q.Req.TableName, err)
+	}
+	return msgs, nil
+}
+
+// First first row of query, sql.ErrNoRows if none. the limit of q is kept
+func (q *Query[T]) First(ctx context.Context, db sqldb.DB) (T, error) {
+	first := &Query[T]{Req: proto.Clone(q.Req).(*protodb.TableQueryReq), err: q.err}
+	first.Req.Limit = 1
+	msgs, err := first.All(ctx, db)
+	if err != nil {
+		return zeroOf[T](), err
+	}
+	if len(msgs) == 0 {
+		return zeroOf[T](), sql.ErrNoRows
+	}
+	return msgs[0], nil
+}
+
+// FormatWhereValue where value text of TableQueryReq, enums are numbers,
+// slices are json arrays(list operators), time and duration are RFC 3339 and go duration text,
+// wrapper messages are formatted by their value, other messages are protojson
+func FormatWhereValue(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int32:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case protoreflect.Enum:
+		return strconv.FormatInt(int64(v.Number()), 10), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case *timestamppb.Timestamp:
+		return v.AsTime().Format(time.RFC3339Nano), nil
+	case time.Duration:
+		return v.String(), nil
+	case *durationpb.Duration:
+		return v.AsDuration().String(), nil
+	case proto.Message:
+		msg := v.ProtoReflect()
+		if _, ok := protodb.WellKnownWrapperKind(msg.Descriptor().FullName()); ok {
+			return FormatWhereValue(msg.Get(msg.Descriptor().Fields().ByName("value")).Interface())
+		}
+		b, err := protojson.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("format where value %T: %w", value, err)
+		}
+		return string(b), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("format where value %T: %w", value, err)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,154 @@
+package repo_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/internal/example/userpb"
+	"github.com/ygrpc/protodb/repo"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestQueryAll(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	columns := []string{"id", "username", "status", "tags"}
+	mock.ExpectQuery(`SELECT \* FROM .*t_user.* WHERE .*username.* LIMIT 10`).
+		WithArgs("alice").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(int64(1), "alice", int64(1), `["a","b"]`).
+			AddRow(int64(2), "alice", int64(2), `[]`))
+
+	users, err := userpb.UserRepo{}.Query().
+		WhereUsername(protodb.WhereOperator_WOP_EQ, "alice").
+		Limit(10).
+		All(context.Background(), db)
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if len(users) != 2 || users[0].Id != 1 || users[0].Status != userpb.UserStatus_USER_STATUS_ACTIVE || len(users[0].Tags) != 2 {
+		t.Fatalf("unexpected users: %v", users)
+	}
+	if users[1].Status != userpb.UserStatus_USER_STATUS_DISABLED {
+		t.Fatalf("unexpected status: %v", users[1].Status)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("ExpectationsWereMet: %v", err)
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	_, err = userpb.UserRepo{}.Query().
+		WhereId(protodb.WhereOperator_WOP_GT, 1).
+		WhereId(protodb.WhereOperator_WOP_LT, 10).
+		All(context.Background(), db)
+	if err == nil || !strings.Contains(err.Error(), "duplicate condition of field id") {
+		t.Fatalf("expected duplicate condition error, got %v", err)
+	}
+
+	mock.ExpectQuery(`SELECT .* FROM .*t_user`).WithArgs("3").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	query := repo.NewQuery[*userpb.User]("").Where("tenant_id", protodb.WhereOperator_WOP_EQ, 3).Limit(5)
+	_, err = query.First(context.Background(), db)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected sql.ErrNoRows, got %v", err)
+	}
+	if query.Req.Limit != 5 {
+		t.Fatalf("First changed the query limit to %d", query.Req.Limit)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("ExpectationsWereMet: %v", err)
+	}
+}
+
+func TestGetByUniqueKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT .* FROM .*t_user.* WHERE .*email.* AND .*tenant_id`).
+		WithArgs("a@example.com", int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "tenant_id"}).AddRow(int64(5), "a@example.com", int64(7)))
+
+	user, err := userpb.UserRepo{}.GetByEmailTenantId(context.Background(), db, "a@example.com", 7)
+	if err != nil {
+		t.Fatalf("GetByEmailTenantId: %v", err)
+	}
+	if user.Id != 5 {
+		t.Fatalf("unexpected user: %v", user)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("ExpectationsWereMet: %v", err)
+	}
+}
+
+func TestCanceledContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := (userpb.UserRepo{}).Insert(ctx, db, &userpb.User{Username: "alice"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if _, err := (userpb.UserRepo{}).UpdateFields(ctx, db, &userpb.User{Id: 1}, userpb.UserFieldEmail); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("ExpectationsWereMet: %v", err)
+	}
+}
+
+func TestFormatWhereValue(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	cases := []struct {
+		value any
+		want  string
+	}{
+		{"a", "a"},
+		{int32(-3), "-3"},
+		{uint64(3), "3"},
+		{true, "true"},
+		{1.5, "1.5"},
+		{userpb.UserStatus_USER_STATUS_DISABLED, "2"},
+		{ts, "2024-05-06T07:08:09Z"},
+		{timestamppb.New(ts), "2024-05-06T07:08:09Z"},
+		{90 * time.Second, "1m30s"},
+		{[]string{"a", "b"}, `["a","b"]`},
+		{[]int64{1, 2}, "[1,2]"},
+		{wrapperspb.Int64(7), "7"},
+		{wrapperspb.String("x"), "x"},
+		{structpb.NewNumberValue(2), "2"},
+	}
+	for _, tc := range cases {
+		got, err := repo.FormatWhereValue(tc.value)
+		if err != nil {
+			t.Fatalf("FormatWhereValue(%v): %v", tc.value, err)
+		}
+		if got != tc.want {
+			t.Errorf("FormatWhereValue(%v) = %s, want %s", tc.value, got, tc.want)
+		}
+	}
+}
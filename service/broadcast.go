@@ -32,9 +32,14 @@ type crudBroadcastEvent struct {
 	respMsg proto.Message
 }
 
-var GlobalCrudBroadcaster *TcrudBroadcaster = &TcrudBroadcaster{
-	fnCrudBroadcastMap:       xsync.NewMapOf[string, []TfnCrudBroadcastHandler](),
-	fnCrudBroadcastByCodeMap: xsync.NewMapOf[protodb.CrudReqCode, *xsync.MapOf[string, []TfnCrudBroadcastHandler]](),
+var GlobalCrudBroadcaster *TcrudBroadcaster = NewCrudBroadcaster()
+
+// NewCrudBroadcaster create a broadcaster without handlers
+func NewCrudBroadcaster() *TcrudBroadcaster {
+	return &TcrudBroadcaster{
+		fnCrudBroadcastMap:       xsync.NewMapOf[string, []TfnCrudBroadcastHandler](),
+		fnCrudBroadcastByCodeMap: xsync.NewMapOf[protodb.CrudReqCode, *xsync.MapOf[string, []TfnCrudBroadcastHandler]](),
+	}
 }
 
 // RegisterBroadcastByCode registers a handler for one table and CRUD code.
@@ -6,17 +6,13 @@ import (
 	"testing"
 	"time"
 
-	"github.com/puzpuzpuz/xsync/v3"
 	"github.com/ygrpc/protodb"
 	"github.com/ygrpc/protodb/sqldb"
 	"google.golang.org/protobuf/proto"
 )
 
 func newTestBroadcaster() *TcrudBroadcaster {
-	return &TcrudBroadcaster{
-		fnCrudBroadcastMap:       xsync.NewMapOf[string, []TfnCrudBroadcastHandler](),
-		fnCrudBroadcastByCodeMap: xsync.NewMapOf[protodb.CrudReqCode, *xsync.MapOf[string, []TfnCrudBroadcastHandler]](),
-	}
+	return NewCrudBroadcaster()
 }
 
 func testBroadcastReq(table string, code protodb.CrudReqCode) *protodb.CrudReq {
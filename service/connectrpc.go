@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"strconv"
 
 	"connectrpc.com/connect"
 	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/proto"
 )
 
 type TconnectrpcProtoDbSrvHandlerImpl struct {
@@ -21,12 +24,28 @@ type TconnectrpcProtoDbSrvHandlerImpl struct {
 	// table name => fn
 	// must set for every table, if no fn for a table, set to nil
 	fnTableQueryPermissionMap map[string]TfnTableQueryPermission
+
+	// queries listed by DescribeSchema, nil lists no query
+	FnDescribeQueryPermission TfnDescribeQueryPermission
+
+	// messages, queries and broadcaster of the service
+	Registry *Registry
 }
 
-// NewTconnectrpcProtoDbSrvHandlerImpl create new ProtoDbSrvHandler impl in connectrpc
+// NewTconnectrpcProtoDbSrvHandlerImpl create new ProtoDbSrvHandler impl in connectrpc, use DefaultRegistry
 func NewTconnectrpcProtoDbSrvHandlerImpl(fnGetCrudDb TfnProtodbGetDb, fnCrudPermission map[string]TfnProtodbCrudPermission,
+	fnTableQueryPermission map[string]TfnTableQueryPermission) *TconnectrpcProtoDbSrvHandlerImpl {
+	return NewTconnectrpcProtoDbSrvHandlerImplWithRegistry(DefaultRegistry, fnGetCrudDb, fnCrudPermission, fnTableQueryPermission)
+}
+
+// NewTconnectrpcProtoDbSrvHandlerImplWithRegistry create new ProtoDbSrvHandler impl in connectrpc
+// serving the messages and queries of registry, DefaultRegistry if registry is nil
+func NewTconnectrpcProtoDbSrvHandlerImplWithRegistry(registry *Registry, fnGetCrudDb TfnProtodbGetDb, fnCrudPermission map[string]TfnProtodbCrudPermission,
 	fnTableQueryPermission map[string]TfnTableQueryPermission) *TconnectrpcProtoDbSrvHandlerImpl {
 	// set default value
+	if registry == nil {
+		registry = DefaultRegistry
+	}
 	if fnGetCrudDb == nil {
 		fnGetCrudDb = FnProtodbGetDbEmpty
 	}
@@ -40,9 +59,18 @@ func NewTconnectrpcProtoDbSrvHandlerImpl(fnGetCrudDb TfnProtodbGetDb, fnCrudPerm
 		FnGetDb:                   fnGetCrudDb,
 		fnCrudPermissionMap:       fnCrudPermission,
 		fnTableQueryPermissionMap: fnTableQueryPermission,
+		Registry:                  registry,
 	}
 }
 
+// registry Registry of the impl, DefaultRegistry if not set
+func (this *TconnectrpcProtoDbSrvHandlerImpl) registry() *Registry {
+	if this.Registry == nil {
+		return DefaultRegistry
+	}
+	return this.Registry
+}
+
 func (this *TconnectrpcProtoDbSrvHandlerImpl) Crud(ctx context.Context, req *connect.Request[protodb.CrudReq]) (resp *connect.Response[protodb.CrudResp], err error) {
 	meta := req.Header()
 	CrudMsg := req.Msg
@@ -61,7 +89,7 @@ func (this *TconnectrpcProtoDbSrvHandlerImpl) Crud(ctx context.Context, req *con
 		return nil, connecterr
 	}
 
-	respCrud, err := HandleCrud(ctx, meta, CrudMsg, this.FnGetDb, fnCrudPermission)
+	respCrud, err := this.registry().HandleCrud(ctx, meta, CrudMsg, this.FnGetDb, fnCrudPermission)
 	if err != nil {
 		var connecterr *connect.Error
 		if errors.As(err, &connecterr) {
@@ -141,7 +169,7 @@ func (this *TconnectrpcProtoDbSrvHandlerImpl) TableQuery(ctx context.Context, re
 		}
 		return ss.Send(resp)
 	}
-	return HandleTableQuery(ctx, meta, TableQueryReq, this.FnGetDb, permissionFn, fnSend)
+	return this.registry().HandleTableQuery(ctx, meta, TableQueryReq, this.FnGetDb, permissionFn, fnSend)
 }
 
 func (this *TconnectrpcProtoDbSrvHandlerImpl) Query(ctx context.Context, req *connect.Request[protodb.QueryReq], ss *connect.ServerStream[protodb.QueryResp]) error {
@@ -168,6 +196,43 @@ func (this *TconnectrpcProtoDbSrvHandlerImpl) Query(ctx context.Context, req *co
 		}
 		return ss.Send(resp)
 	}
-	return HandleQuery(ctx, req.Header(), req.Msg, this.FnGetDb, fnSend)
+	return this.registry().HandleQuery(ctx, req.Header(), req.Msg, this.FnGetDb, fnSend)
+
+}
+
+func (this *TconnectrpcProtoDbSrvHandlerImpl) DescribeSchema(ctx context.Context, req *connect.Request[protodb.DescribeSchemaReq]) (*connect.Response[protodb.DescribeSchemaResp], error) {
+	respDescribe, err := this.registry().HandleDescribeSchema(ctx, req.Header(), req.Msg, this.FnGetDb, this.describeTablePermission, this.FnDescribeQueryPermission)
+	if err != nil {
+		connecterr := connect.NewError(connect.CodeUnknown, err)
+		connecterr.Meta().Set("Ygrpc-Err", err.Error())
+		return nil, connecterr
+	}
+	return connect.NewResponse(respDescribe), nil
+}
 
+// describeTablePermission a table is visible if the caller can query it by TableQuery or select it by Crud
+func (this *TconnectrpcProtoDbSrvHandlerImpl) describeTablePermission(meta http.Header, schemaName string, tableName string, db sqldb.DB, dbmsg proto.Message) error {
+	names := []string{tableName, string(dbmsg.ProtoReflect().Descriptor().Name())}
+	for _, name := range names {
+		fnTableQueryPermission, ok := this.fnTableQueryPermissionMap[name]
+		if !ok {
+			continue
+		}
+		if fnTableQueryPermission == nil {
+			return nil
+		}
+		if _, _, err := fnTableQueryPermission(meta, schemaName, tableName, db, dbmsg); err == nil {
+			return nil
+		}
+	}
+	for _, name := range names {
+		fnCrudPermission := this.fnCrudPermissionMap[name]
+		if fnCrudPermission == nil {
+			continue
+		}
+		if err := fnCrudPermission(meta, schemaName, protodb.CrudReqCode_SELECTONE, db, dbmsg); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("table %s is not visible", tableName)
 }
@@ -8,12 +8,16 @@ import (
 	"connectrpc.com/connect"
 	"github.com/ygrpc/protodb"
 	"github.com/ygrpc/protodb/crud"
-	"github.com/ygrpc/protodb/msgstore"
 	"github.com/ygrpc/protodb/pdbutil"
-	"github.com/ygrpc/protodb/querystore"
 	"google.golang.org/protobuf/proto"
 )
 
+// ErrInfo prefixes of HandleTableQuery, the rest gateway maps them to http status
+const (
+	errInfoTableQueryPermission = "permission check for table"
+	errInfoTableQueryBuildSql   = "build query sql for"
+)
+
 func buildCrudResp(rowsAffected int64, oldMsg, newMsg proto.Message, msgFormat int32) (*protodb.CrudResp, error) {
 	resp := &protodb.CrudResp{
 		RowsAffected: rowsAffected,
@@ -39,14 +43,14 @@ func buildCrudResp(rowsAffected int64, oldMsg, newMsg proto.Message, msgFormat i
 	return resp, nil
 }
 
-func HandleCrud(ctx context.Context, meta http.Header, req *protodb.CrudReq, fnGetDb TfnProtodbGetDb, fnCrudPermission TfnProtodbCrudPermission) (resp *protodb.CrudResp, err error) {
+func (this *Registry) HandleCrud(ctx context.Context, meta http.Header, req *protodb.CrudReq, fnGetDb TfnProtodbGetDb, fnCrudPermission TfnProtodbCrudPermission) (resp *protodb.CrudResp, err error) {
 
 	db, err := fnGetDb(meta, req.SchemeName, req.TableName, true)
 	if err != nil {
 		return nil, err
 	}
 
-	dbmsg, ok := msgstore.GetMsg(req.TableName, true)
+	dbmsg, ok := this.Msgs.GetMsg(req.TableName, true)
 	if !ok {
 		return nil, fmt.Errorf("can not get proto msg %s err", req.TableName)
 	}
@@ -62,6 +66,7 @@ func HandleCrud(ctx context.Context, meta http.Header, req *protodb.CrudReq, fnG
 			return nil, connect.NewError(connect.CodePermissionDenied, err)
 		}
 	}
+	db = this.dbWithMsgStore(db)
 
 	switch req.Code {
 	case protodb.CrudReqCode_INSERT:
@@ -72,7 +77,7 @@ func HandleCrud(ctx context.Context, meta http.Header, req *protodb.CrudReq, fnG
 				return nil, fmt.Errorf("insert msg %s err: %w", req.TableName, err)
 			}
 			resp = dmlResult
-			GlobalCrudBroadcaster.BroadcastAsync(meta, db, req, dbmsg, resp)
+			this.Broadcaster.BroadcastAsync(meta, db, req, dbmsg, resp)
 			return resp, nil
 		case protodb.CrudResultType_NewMsg:
 			newMsg, err := crud.DbInsertReturn(db, dbmsg, req.MsgLastFieldNo, req.SchemeName)
@@ -83,7 +88,7 @@ func HandleCrud(ctx context.Context, meta http.Header, req *protodb.CrudReq, fnG
 			if err != nil {
 				return nil, fmt.Errorf("marshal msg %s err: %w", req.TableName, err)
 			}
-			GlobalCrudBroadcaster.BroadcastAsync(meta, db, req, dbmsg, resp)
+			this.Broadcaster.BroadcastAsync(meta, db, req, dbmsg, resp)
 			return resp, nil
 		}
 	case protodb.CrudReqCode_UPDATE:
@@ -95,7 +100,7 @@ func HandleCrud(ctx context.Context, meta http.Header, req *protodb.CrudReq, fnG
 			}
 			resp = dmlResult
 
-			GlobalCrudBroadcaster.BroadcastAsync(meta, db, req, dbmsg, resp)
+			this.Broadcaster.BroadcastAsync(meta, db, req, dbmsg, resp)
 
 			return resp, nil
 		case protodb.CrudResultType_NewMsg:
@@ -108,7 +113,7 @@ func HandleCrud(ctx context.Context, meta http.Header, req *protodb.CrudReq, fnG
 				return nil, fmt.Errorf("marshal new msg %s err: %w", req.TableName, err)
 			}
 
-			GlobalCrudBroadcaster.BroadcastAsync(meta, db, req, dbmsg, resp)
+			this.Broadcaster.BroadcastAsync(meta, db, req, dbmsg, resp)
 			return resp, nil
 		case protodb.CrudResultType_OldMsgAndNewMsg:
 			oldMsg, newMsg, err := crud.DbUpdateReturnOldAndNew(db, dbmsg, req.MsgLastFieldNo, req.SchemeName)
@@ -119,7 +124,7 @@ func HandleCrud(ctx context.Context, meta http.Header, req *protodb.CrudReq, fnG
 			if err != nil {
 				return nil, fmt.Errorf("marshal msg %s err: %w", req.TableName, err)
 			}
-			GlobalCrudBroadcaster.BroadcastAsync(meta, db, req, dbmsg, resp)
+			this.Broadcaster.BroadcastAsync(meta, db, req, dbmsg, resp)
 			return resp, nil
 		}
 	case protodb.CrudReqCode_PARTIALUPDATE:
@@ -131,7 +136,7 @@ func HandleCrud(ctx context.Context, meta http.Header, req *protodb.CrudReq, fnG
 			}
 			resp = dmlResult
 
-			GlobalCrudBroadcaster.BroadcastAsync(meta, db, req, dbmsg, resp)
+			this.Broadcaster.BroadcastAsync(meta, db, req, dbmsg, resp)
 
 			return resp, nil
 		case protodb.CrudResultType_NewMsg:
@@ -144,7 +149,7 @@ func HandleCrud(ctx context.Context, meta http.Header, req *protodb.CrudReq, fnG
 				return nil, fmt.Errorf("marshal new msg %s err: %w", req.TableName, err)
 			}
 
-			GlobalCrudBroadcaster.BroadcastAsync(meta, db, req, dbmsg, resp)
+			this.Broadcaster.BroadcastAsync(meta, db, req, dbmsg, resp)
 			return resp, nil
 		case protodb.CrudResultType_OldMsgAndNewMsg:
 			oldMsg, newMsg, err := crud.DbUpdatePartialReturnOldAndNew(db, dbmsg, req.PartialUpdateFields, req.SchemeName)
@@ -156,7 +161,7 @@ func HandleCrud(ctx context.Context, meta http.Header, req *protodb.CrudReq, fnG
 				return nil, fmt.Errorf("marshal msg %s err: %w", req.TableName, err)
 			}
 
-			GlobalCrudBroadcaster.BroadcastAsync(meta, db, req, dbmsg, resp)
+			this.Broadcaster.BroadcastAsync(meta, db, req, dbmsg, resp)
 			return resp, nil
 		}
 	case protodb.CrudReqCode_DELETE:
@@ -168,7 +173,7 @@ func HandleCrud(ctx context.Context, meta http.Header, req *protodb.CrudReq, fnG
 			}
 			resp = dmlResult
 
-			GlobalCrudBroadcaster.BroadcastAsync(meta, db, req, dbmsg, resp)
+			this.Broadcaster.BroadcastAsync(meta, db, req, dbmsg, resp)
 			return resp, nil
 		case protodb.CrudResultType_NewMsg:
 			newMsg, err := crud.DbDeleteReturn(db, dbmsg, req.SchemeName)
@@ -179,7 +184,7 @@ func HandleCrud(ctx context.Context, meta http.Header, req *protodb.CrudReq, fnG
 			if err != nil {
 				return nil, fmt.Errorf("marshal new msg %s err: %w", req.TableName, err)
 			}
-			GlobalCrudBroadcaster.BroadcastAsync(meta, db, req, dbmsg, resp)
+			this.Broadcaster.BroadcastAsync(meta, db, req, dbmsg, resp)
 			return resp, nil
 		}
 	case protodb.CrudReqCode_SELECTONE:
@@ -197,7 +202,7 @@ func HandleCrud(ctx context.Context, meta http.Header, req *protodb.CrudReq, fnG
 	return nil, fmt.Errorf("Unknown crud code: %s", req.Code.String())
 }
 
-func HandleTableQuery(ctx context.Context, meta http.Header, req *protodb.TableQueryReq, fnGetDb TfnProtodbGetDb, fnTableQueryPermission TfnTableQueryPermission, fnSend TfnSendQueryResp) (err error) {
+func (this *Registry) HandleTableQuery(ctx context.Context, meta http.Header, req *protodb.TableQueryReq, fnGetDb TfnProtodbGetDb, fnTableQueryPermission TfnTableQueryPermission, fnSend TfnSendQueryResp) (err error) {
 	sendErr := func(err error) error {
 		resp := &protodb.QueryResp{
 			ResponseNo:  0,
@@ -216,7 +221,7 @@ func HandleTableQuery(ctx context.Context, meta http.Header, req *protodb.TableQ
 		return sendErr(err)
 	}
 
-	dbmsg, ok := msgstore.GetMsg(TableQueryReq.TableName, false)
+	dbmsg, ok := this.Msgs.GetMsg(TableQueryReq.TableName, false)
 	if !ok {
 		return sendErr(fmt.Errorf("can not get protodb msg %s err", TableQueryReq.TableName))
 	}
@@ -228,7 +233,7 @@ func HandleTableQuery(ctx context.Context, meta http.Header, req *protodb.TableQ
 		permissionSqlStr, permissionSqlVals, err = fnTableQueryPermission(meta, TableQueryReq.SchemeName, TableQueryReq.TableName, db, dbmsg)
 
 		if err != nil {
-			return sendErr(fmt.Errorf("permission check for table %s err: %w", TableQueryReq.TableName, err))
+			return sendErr(fmt.Errorf("%s %s err: %w", errInfoTableQueryPermission, TableQueryReq.TableName, err))
 		}
 	}
 
@@ -236,7 +241,7 @@ func HandleTableQuery(ctx context.Context, meta http.Header, req *protodb.TableQ
 	sqlStr, sqlVals, err := crud.TableQueryBuildSql(db, msgDesc, TableQueryReq, permissionSqlStr, permissionSqlVals)
 
 	if err != nil {
-		return sendErr(fmt.Errorf("build query sql for %s err: %w", TableQueryReq.TableName, err))
+		return sendErr(fmt.Errorf("%s %s err: %w", errInfoTableQueryBuildSql, TableQueryReq.TableName, err))
 	}
 
 	rows, err := db.Query(sqlStr, sqlVals...)
@@ -253,7 +258,7 @@ func HandleTableQuery(ctx context.Context, meta http.Header, req *protodb.TableQ
 		fieldNames = nil
 	}
 
-	resultMsg, _ := msgstore.GetMsg(TableQueryReq.TableName, true)
+	resultMsg, _ := this.Msgs.GetMsg(TableQueryReq.TableName, true)
 
 	resultMsgDesc := resultMsg.ProtoReflect().Descriptor()
 	msgFieldsMap := pdbutil.BuildMsgFieldsMap(fieldNames, resultMsgDesc.Fields(), true)
@@ -280,6 +285,7 @@ func HandleTableQuery(ctx context.Context, meta http.Header, req *protodb.TableQ
 	if err != nil {
 		return sendErr(fmt.Errorf("tablequery %s create row scanner err: %w", TableQueryReq.TableName, err))
 	}
+	rowScanner.MsgStore = this.Msgs
 
 	for rows.Next() {
 		// reuse resultMsg
@@ -333,7 +339,7 @@ func HandleTableQuery(ctx context.Context, meta http.Header, req *protodb.TableQ
 	return nil
 }
 
-func HandleQuery(ctx context.Context, meta http.Header, req *protodb.QueryReq, fnGetDb TfnProtodbGetDb, fnSend TfnSendQueryResp) error {
+func (this *Registry) HandleQuery(ctx context.Context, meta http.Header, req *protodb.QueryReq, fnGetDb TfnProtodbGetDb, fnSend TfnSendQueryResp) error {
 
 	sendErr := func(err error) error {
 		resp := &protodb.QueryResp{
@@ -351,7 +357,7 @@ func HandleQuery(ctx context.Context, meta http.Header, req *protodb.QueryReq, f
 		return sendErr(err)
 	}
 
-	fn, ok := querystore.GetQuery(req.QueryName)
+	fn, ok := this.Queries.GetQuery(req.QueryName)
 	if !ok {
 		return sendErr(fmt.Errorf("err: can not get query fn for %s", req.QueryName))
 	}
@@ -404,6 +410,7 @@ func HandleQuery(ctx context.Context, meta http.Header, req *protodb.QueryReq, f
 	if err != nil {
 		return sendErr(fmt.Errorf("create row scanner err: %w", err))
 	}
+	rowScanner.MsgStore = this.Msgs
 
 	for rows.Next() {
 		proto.Reset(resultMsg)
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/crud"
+	"github.com/ygrpc/protodb/ddl"
+	"github.com/ygrpc/protodb/pdbutil"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// HandleDescribeSchema handle describe schema req with DefaultRegistry
+func HandleDescribeSchema(ctx context.Context, meta http.Header, req *protodb.DescribeSchemaReq, fnGetDb TfnProtodbGetDb, fnTablePermission TfnDescribeTablePermission,
+	fnQueryPermission TfnDescribeQueryPermission) (*protodb.DescribeSchemaResp, error) {
+	return DefaultRegistry.HandleDescribeSchema(ctx, meta, req, fnGetDb, fnTablePermission, fnQueryPermission)
+}
+
+// HandleDescribeSchema describe the registered messages and queries visible to the caller.
+// a table is listed only if fnTablePermission returns nil, no table is listed when fnTablePermission is nil,
+// queries are filtered by fnQueryPermission the same way.
+// req.TableNames(db table name or msg name) limit the described tables
+func (this *Registry) HandleDescribeSchema(ctx context.Context, meta http.Header, req *protodb.DescribeSchemaReq, fnGetDb TfnProtodbGetDb, fnTablePermission TfnDescribeTablePermission,
+	fnQueryPermission TfnDescribeQueryPermission) (*protodb.DescribeSchemaResp, error) {
+	wanted := make(map[string]bool, len(req.TableNames))
+	for _, name := range req.TableNames {
+		wanted[name] = true
+	}
+
+	resp := &protodb.DescribeSchemaResp{}
+	described := make(map[protoreflect.FullName]bool)
+	for _, msgName := range this.Msgs.MsgNames() {
+		dbmsg, ok := this.Msgs.GetMsg(msgName, false)
+		if !ok {
+			continue
+		}
+		msgDesc := dbmsg.ProtoReflect().Descriptor()
+		if described[msgDesc.FullName()] {
+			continue
+		}
+		described[msgDesc.FullName()] = true
+
+		tableName := pdbutil.GetTableName(msgDesc)
+		if len(wanted) > 0 && !wanted[tableName] && !wanted[msgName] && !wanted[string(msgDesc.FullName())] {
+			continue
+		}
+		if fnTablePermission == nil {
+			continue
+		}
+
+		db, err := fnGetDb(meta, req.SchemeName, tableName, false)
+		if err != nil {
+			return nil, fmt.Errorf("get db for table %s err: %w", tableName, err)
+		}
+		if err := fnTablePermission(meta, req.SchemeName, tableName, db, dbmsg); err != nil {
+			continue
+		}
+		resp.Tables = append(resp.Tables, describeTable(db, msgDesc))
+	}
+
+	if fnQueryPermission == nil {
+		return resp, nil
+	}
+	for _, query := range this.Queries.Queries() {
+		if err := fnQueryPermission(meta, req.SchemeName, query.Name); err != nil {
+			continue
+		}
+		resp.Queries = append(resp.Queries, &protodb.QueryInfo{
+			Name:      query.Name,
+			ParamMsg:  string(query.ParamMsg),
+			ResultMsg: string(query.ResultMsg),
+			Comment:   query.Comment,
+		})
+	}
+	return resp, nil
+}
+
+// describeTable table info of msgDesc, sql types and where2 operators are in the dialect of db
+func describeTable(db sqldb.DB, msgDesc protoreflect.MessageDescriptor) *protodb.TableInfo {
+	pdbm, _ := pdbutil.GetPDBM(msgDesc)
+	table := &protodb.TableInfo{
+		MsgName:   string(msgDesc.FullName()),
+		TableName: pdbutil.GetTableName(msgDesc),
+		NotDB:     pdbm.NotDB,
+		Comment:   pdbm.Comment,
+	}
+	dialect := sqldb.GetExecutorDialect(db)
+
+	uniqueKeys := make(map[string]*protodb.UniqueKeyInfo)
+	for _, column := range ddl.TableColumns(msgDesc) {
+		fieldDesc := column.Field
+		// map column is described by its value
+		valueDesc := fieldDesc
+		if fieldDesc.IsMap() {
+			valueDesc = fieldDesc.MapValue()
+		}
+		info := &protodb.ColumnInfo{
+			Name:       column.Name,
+			FieldName:  column.FieldPath,
+			ProtoKind:  valueDesc.Kind().String(),
+			IsList:     fieldDesc.IsList(),
+			IsMap:      fieldDesc.IsMap(),
+			Primary:    column.Pdb.IsPrimary(),
+			Unique:     column.Pdb.Unique,
+			UniqueName: column.Pdb.UniqueName,
+			Reference:  column.Pdb.Reference,
+			NotNull:    column.Pdb.NotNull,
+			NoUpdate:   column.Pdb.NoUpdate,
+			NoInsert:   column.Pdb.NoInsert,
+			Comment:    column.Pdb.Comment,
+		}
+		if valueDesc.Message() != nil {
+			info.TypeName = string(valueDesc.Message().FullName())
+		} else if valueDesc.Enum() != nil {
+			info.TypeName = string(valueDesc.Enum().FullName())
+		}
+		if !table.NotDB {
+			info.SQLType = ddl.ColumnSqlType(column, dialect)
+			info.Operators = crud.WhereOperators(db, fieldDesc)
+		}
+		table.Columns = append(table.Columns, info)
+
+		if info.Primary {
+			table.PrimaryKey = append(table.PrimaryKey, column.Name)
+		}
+		if info.Unique {
+			if len(info.UniqueName) == 0 {
+				table.UniqueKeys = append(table.UniqueKeys, &protodb.UniqueKeyInfo{Columns: []string{column.Name}})
+				continue
+			}
+			uniqueKey, ok := uniqueKeys[info.UniqueName]
+			if !ok {
+				uniqueKey = &protodb.UniqueKeyInfo{Name: info.UniqueName}
+				uniqueKeys[info.UniqueName] = uniqueKey
+				table.UniqueKeys = append(table.UniqueKeys, uniqueKey)
+			}
+			uniqueKey.Columns = append(uniqueKey.Columns, column.Name)
+		}
+	}
+	return table
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/internal/example/userpb"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/proto"
+)
+
+func newDescribeTestRegistry() *Registry {
+	registry := NewRegistry()
+	registry.Msgs.RegisterMsg("User", func(new bool) proto.Message { return &userpb.User{} })
+	registry.Msgs.RegisterMsg("PDBField", func(new bool) proto.Message { return &protodb.PDBField{} })
+	registry.Queries.RegisterQuery("describe_test_query", func(meta http.Header, db sqldb.DB, req *protodb.QueryReq) (string, []interface{}, func(new bool) proto.Message, error) {
+		return "", nil, nil, errors.New("not called")
+	})
+	return registry
+}
+
+func describeTestGetDb(meta http.Header, schemaName string, tableName string, writable bool) (sqldb.DB, error) {
+	return sqldb.NewTxWithDialectType(nil, sqldb.Postgres), nil
+}
+
+func TestHandleDescribeSchemaDescribesColumns(t *testing.T) {
+	registry := newDescribeTestRegistry()
+	resp, err := registry.HandleDescribeSchema(context.Background(), http.Header{}, &protodb.DescribeSchemaReq{TableNames: []string{"t_user"}}, describeTestGetDb,
+		func(meta http.Header, schemaName string, tableName string, db sqldb.DB, dbmsg proto.Message) error {
+			return nil
+		},
+		func(meta http.Header, schemaName string, queryName string) error {
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("HandleDescribeSchema err: %v", err)
+	}
+	if len(resp.Tables) != 1 {
+		t.Fatalf("tables = %d, want 1", len(resp.Tables))
+	}
+	table := resp.Tables[0]
+	if table.MsgName != "userpb.User" || table.TableName != "t_user" {
+		t.Fatalf("table = %s %s", table.MsgName, table.TableName)
+	}
+	if !slices.Equal(table.PrimaryKey, []string{"id"}) {
+		t.Fatalf("primary key = %v", table.PrimaryKey)
+	}
+	if len(table.UniqueKeys) != 2 || !slices.Equal(table.UniqueKeys[0].Columns, []string{"username"}) ||
+		table.UniqueKeys[1].Name != "tenant_email" || !slices.Equal(table.UniqueKeys[1].Columns, []string{"email", "tenant_id"}) {
+		t.Fatalf("unique keys = %v", table.UniqueKeys)
+	}
+
+	columns := map[string]*protodb.ColumnInfo{}
+	for _, column := range table.Columns {
+		columns[column.Name] = column
+	}
+	if _, ok := columns["password"]; ok {
+		t.Fatalf("NotDB field password is described as a column")
+	}
+	id := columns["id"]
+	if id.ProtoKind != "int64" || id.SQLType != "bigserial" || !id.Primary {
+		t.Fatalf("id column = %v", id)
+	}
+	if !slices.Equal(id.Operators, []protodb.WhereOperator{
+		protodb.WhereOperator_WOP_GT, protodb.WhereOperator_WOP_LT, protodb.WhereOperator_WOP_GTE, protodb.WhereOperator_WOP_LTE,
+		protodb.WhereOperator_WOP_LIKE, protodb.WhereOperator_WOP_EQ, protodb.WhereOperator_WOP_IS_NULL, protodb.WhereOperator_WOP_IS_NOT_NULL,
+	}) {
+		t.Fatalf("id operators = %v", id.Operators)
+	}
+	if status := columns["status"]; status.ProtoKind != "enum" || status.TypeName != "userpb.UserStatus" {
+		t.Fatalf("status column = %v", status)
+	}
+	tags := columns["tags"]
+	if !tags.IsList || !slices.Contains(tags.Operators, protodb.WhereOperator_WOP_CONTAINS) || slices.Contains(tags.Operators, protodb.WhereOperator_WOP_EQ) {
+		t.Fatalf("tags column = %v", tags)
+	}
+	if createdAt := columns["created_at"]; !createdAt.NoUpdate || createdAt.TypeName != "google.protobuf.Timestamp" {
+		t.Fatalf("created_at column = %v", createdAt)
+	}
+
+	if len(resp.Queries) != 1 || resp.Queries[0].Name != "describe_test_query" || resp.Queries[0].ParamMsg != "" {
+		t.Fatalf("queries = %v", resp.Queries)
+	}
+}
+
+func TestDescribeSchemaFiltersByPermission(t *testing.T) {
+	registry := newDescribeTestRegistry()
+	errDenied := errors.New("denied in test")
+	srv := NewTconnectrpcProtoDbSrvHandlerImplWithRegistry(registry, describeTestGetDb,
+		map[string]TfnProtodbCrudPermission{
+			"PDBField": func(meta http.Header, schemaName string, crudCode protodb.CrudReqCode, db sqldb.DB, dbmsg proto.Message) error {
+				return errDenied
+			},
+		},
+		map[string]TfnTableQueryPermission{
+			"t_user": FnTableQueryPermissionEmpty,
+		},
+	)
+
+	resp, err := srv.DescribeSchema(context.Background(), connect.NewRequest(&protodb.DescribeSchemaReq{}))
+	if err != nil {
+		t.Fatalf("DescribeSchema err: %v", err)
+	}
+	if len(resp.Msg.Tables) != 1 || resp.Msg.Tables[0].TableName != "t_user" {
+		t.Fatalf("tables = %v, want only t_user", resp.Msg.Tables)
+	}
+
+	if len(resp.Msg.Queries) != 0 {
+		t.Fatalf("queries without FnDescribeQueryPermission = %v, want none", resp.Msg.Queries)
+	}
+
+	resp2, err := HandleDescribeSchema(context.Background(), http.Header{}, &protodb.DescribeSchemaReq{}, describeTestGetDb, nil, nil)
+	if err != nil {
+		t.Fatalf("HandleDescribeSchema err: %v", err)
+	}
+	if len(resp2.Tables) != 0 {
+		t.Fatalf("tables without permission fn = %d, want 0", len(resp2.Tables))
+	}
+	if len(resp2.Queries) != 0 {
+		t.Fatalf("queries without permission fn = %d, want 0", len(resp2.Queries))
+	}
+}
+
+func TestDescribeSchemaFiltersQueries(t *testing.T) {
+	registry := newDescribeTestRegistry()
+	registry.Queries.RegisterQuery("describe_test_hidden", func(meta http.Header, db sqldb.DB, req *protodb.QueryReq) (string, []interface{}, func(new bool) proto.Message, error) {
+		return "", nil, nil, errors.New("not called")
+	})
+	srv := NewTconnectrpcProtoDbSrvHandlerImplWithRegistry(registry, describeTestGetDb, nil, nil)
+	srv.FnDescribeQueryPermission = func(meta http.Header, schemaName string, queryName string) error {
+		if queryName == "describe_test_hidden" {
+			return errors.New("denied in test")
+		}
+		return nil
+	}
+
+	resp, err := srv.DescribeSchema(context.Background(), connect.NewRequest(&protodb.DescribeSchemaReq{}))
+	if err != nil {
+		t.Fatalf("DescribeSchema err: %v", err)
+	}
+	if len(resp.Msg.Tables) != 0 {
+		t.Fatalf("tables = %v, want none", resp.Msg.Tables)
+	}
+	if len(resp.Msg.Queries) != 1 || resp.Msg.Queries[0].Name != "describe_test_query" {
+		t.Fatalf("queries = %v, want only describe_test_query", resp.Msg.Queries)
+	}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/msgstore"
+	"github.com/ygrpc/protodb/querystore"
+	"github.com/ygrpc/protodb/sqldb"
+)
+
+// Registry messages, queries and broadcaster of a protodb service,
+// two services in one process(public and admin, two databases) use their own registry.
+// rows are scanned with field messages of Msgs, ddl.TDdl{Msgs: registry.Msgs} resolves referenced tables by it
+type Registry struct {
+	Msgs        *msgstore.TMsgStore
+	Queries     *querystore.TQueryStore
+	Broadcaster *TcrudBroadcaster
+}
+
+// NewRegistry create an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		Msgs:        msgstore.NewMsgStore(),
+		Queries:     querystore.NewQueryStore(),
+		Broadcaster: NewCrudBroadcaster(),
+	}
+}
+
+// DefaultRegistry the package globals: msgstore.GlobalMsgStore, querystore.GlobalQueryStore and GlobalCrudBroadcaster
+var DefaultRegistry = &Registry{
+	Msgs:        msgstore.GlobalMsgStore,
+	Queries:     querystore.GlobalQueryStore,
+	Broadcaster: GlobalCrudBroadcaster,
+}
+
+// dbWithMsgStore db whose returned rows get field messages from Msgs
+func (this *Registry) dbWithMsgStore(db sqldb.DB) sqldb.DB {
+	if db == nil || this.Msgs == msgstore.GlobalMsgStore {
+		return db
+	}
+	switch e := db.(type) {
+	case *sql.DB:
+		withStore := sqldb.NewDBWithDialect(e)
+		withStore.MsgStore = this.Msgs
+		return withStore
+	case *sqldb.DBWithDialect:
+		withStore := *e
+		withStore.MsgStore = this.Msgs
+		return &withStore
+	default:
+		return &sqldb.DBWithDialect{Executor: db, Dialect: sqldb.GetExecutorDialect(db), MsgStore: this.Msgs}
+	}
+}
+
+// HandleCrud handle crud req with DefaultRegistry
+func HandleCrud(ctx context.Context, meta http.Header, req *protodb.CrudReq, fnGetDb TfnProtodbGetDb, fnCrudPermission TfnProtodbCrudPermission) (resp *protodb.CrudResp, err error) {
+	return DefaultRegistry.HandleCrud(ctx, meta, req, fnGetDb, fnCrudPermission)
+}
+
+// HandleTableQuery handle table query req with DefaultRegistry
+func HandleTableQuery(ctx context.Context, meta http.Header, req *protodb.TableQueryReq, fnGetDb TfnProtodbGetDb, fnTableQueryPermission TfnTableQueryPermission, fnSend TfnSendQueryResp) (err error) {
+	return DefaultRegistry.HandleTableQuery(ctx, meta, req, fnGetDb, fnTableQueryPermission, fnSend)
+}
+
+// HandleQuery handle query req with DefaultRegistry
+func HandleQuery(ctx context.Context, meta http.Header, req *protodb.QueryReq, fnGetDb TfnProtodbGetDb, fnSend TfnSendQueryResp) error {
+	return DefaultRegistry.HandleQuery(ctx, meta, req, fnGetDb, fnSend)
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/msgstore"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/proto"
+)
+
+const testRegistryTableName = "registry_test_msg"
+
+func TestRegistriesAreIsolated(t *testing.T) {
+	public := NewRegistry()
+	admin := NewRegistry()
+	public.Msgs.RegisterMsg(testRegistryTableName, func(new bool) proto.Message { return &protodb.PDBField{} })
+	admin.Msgs.RegisterMsg(testRegistryTableName, func(new bool) proto.Message { return &protodb.PDBMsg{} })
+
+	var got proto.Message
+	errDenied := errors.New("denied in test")
+	newSrv := func(registry *Registry) *TconnectrpcProtoDbSrvHandlerImpl {
+		return NewTconnectrpcProtoDbSrvHandlerImplWithRegistry(registry,
+			func(meta http.Header, schemaName string, tableName string, writable bool) (sqldb.DB, error) {
+				return fakeDB{}, nil
+			},
+			map[string]TfnProtodbCrudPermission{
+				testRegistryTableName: func(meta http.Header, schemaName string, crudCode protodb.CrudReqCode, db sqldb.DB, dbmsg proto.Message) error {
+					got = dbmsg
+					return errDenied
+				},
+			},
+			nil,
+		)
+	}
+	req := &protodb.CrudReq{Code: protodb.CrudReqCode_INSERT, TableName: testRegistryTableName}
+
+	if _, err := newSrv(public).Crud(context.Background(), connect.NewRequest(req)); !errors.Is(err, errDenied) {
+		t.Fatalf("public Crud err = %v, want permission error", err)
+	}
+	if _, ok := got.(*protodb.PDBField); !ok {
+		t.Fatalf("public registry resolved %T, want *protodb.PDBField", got)
+	}
+	if _, err := newSrv(admin).Crud(context.Background(), connect.NewRequest(req)); !errors.Is(err, errDenied) {
+		t.Fatalf("admin Crud err = %v, want permission error", err)
+	}
+	if _, ok := got.(*protodb.PDBMsg); !ok {
+		t.Fatalf("admin registry resolved %T, want *protodb.PDBMsg", got)
+	}
+
+	if _, ok := msgstore.GetMsg(testRegistryTableName, true); ok {
+		t.Fatalf("message of a registry leaked into the default registry")
+	}
+	_, err := HandleCrud(context.Background(), http.Header{}, req,
+		func(meta http.Header, schemaName string, tableName string, writable bool) (sqldb.DB, error) {
+			return fakeDB{}, nil
+		}, nil)
+	if err == nil || !strings.Contains(err.Error(), "can not get proto msg") {
+		t.Fatalf("default registry HandleCrud err = %v", err)
+	}
+}
+
+func TestRegistryQueryStore(t *testing.T) {
+	registry := NewRegistry()
+	registry.Queries.RegisterQuery("registry_test_query", func(meta http.Header, db sqldb.DB, req *protodb.QueryReq) (string, []interface{}, msgstore.TFnGetMsg, error) {
+		return "", nil, nil, errors.New("query from registry")
+	})
+	fnGetDb := func(meta http.Header, schemaName string, tableName string, writable bool) (sqldb.DB, error) {
+		return fakeDB{}, nil
+	}
+	var errInfo string
+	fnSend := func(resp *protodb.QueryResp) error {
+		errInfo = resp.ErrInfo
+		return nil
+	}
+	req := &protodb.QueryReq{QueryName: "registry_test_query"}
+
+	if err := registry.HandleQuery(context.Background(), http.Header{}, req, fnGetDb, fnSend); err != nil {
+		t.Fatalf("HandleQuery: %v", err)
+	}
+	if !strings.Contains(errInfo, "query from registry") {
+		t.Fatalf("registry query not used, ErrInfo = %q", errInfo)
+	}
+	if err := HandleQuery(context.Background(), http.Header{}, req, fnGetDb, fnSend); err != nil {
+		t.Fatalf("HandleQuery: %v", err)
+	}
+	if !strings.Contains(errInfo, "can not get query fn") {
+		t.Fatalf("default registry should not see the query, ErrInfo = %q", errInfo)
+	}
+}
+
+func TestRegistryDbWithMsgStore(t *testing.T) {
+	registry := NewRegistry()
+	db := &sqldb.DBWithDialect{Executor: fakeDB{}, Dialect: sqldb.Postgres}
+	withStore, ok := registry.dbWithMsgStore(db).(*sqldb.DBWithDialect)
+	if !ok || withStore.MsgStore != registry.Msgs || withStore.Dialect != sqldb.Postgres {
+		t.Fatalf("unexpected db of registry: %#v", withStore)
+	}
+	if db.MsgStore != nil {
+		t.Fatal("db of the caller is changed")
+	}
+	if got := DefaultRegistry.dbWithMsgStore(db); got != sqldb.DB(db) {
+		t.Fatalf("default registry should keep the db, got %#v", got)
+	}
+}
@@ -0,0 +1,390 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/pdbutil"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// restMaxBodyBytes max size of rest request body
+const restMaxBodyBytes = 4 << 20
+
+// restMsgFormat messages of rest gateway are protobuf json
+const restMsgFormat = 1
+
+// TrestHandler REST/JSON gateway of the tables served by srv, using its FnGetDb, permission maps and Registry.
+//
+//	GET    /tables/{table}/{pk...}  SelectOne by primary key, ?fields=a,b
+//	GET    /tables/{table}          TableQuery as NDJSON, ?field=op:value&order=a,-b&limit=&offset=&fields=a,b
+//	POST   /tables/{table}          Insert
+//	PUT    /tables/{table}/{pk...}  Update
+//	PATCH  /tables/{table}/{pk...}  PartialUpdate of the fields in body
+//	DELETE /tables/{table}/{pk...}  Delete
+//
+// composite primary key is given in field order like /tables/t/1/2, it can also be in the body when {pk...} is empty.
+// db schema is taken from the Ygrpc-Schema header, errors are json {"error": "..."} with http status
+type TrestHandler struct {
+	srv *TconnectrpcProtoDbSrvHandlerImpl
+	mux *http.ServeMux
+}
+
+// NewRestHandler create rest gateway of srv, mount it like mux.Handle("/tables/", service.NewRestHandler(srv))
+func NewRestHandler(srv *TconnectrpcProtoDbSrvHandlerImpl) *TrestHandler {
+	this := &TrestHandler{srv: srv, mux: http.NewServeMux()}
+	this.mux.HandleFunc("GET /tables/{table}", this.tableQuery)
+	this.mux.HandleFunc("GET /tables/{table}/{pk...}", this.crudHandler(protodb.CrudReqCode_SELECTONE))
+	this.mux.HandleFunc("POST /tables/{table}", this.crudHandler(protodb.CrudReqCode_INSERT))
+	for _, pattern := range []string{"/tables/{table}", "/tables/{table}/{pk...}"} {
+		this.mux.HandleFunc("PUT "+pattern, this.crudHandler(protodb.CrudReqCode_UPDATE))
+		this.mux.HandleFunc("PATCH "+pattern, this.crudHandler(protodb.CrudReqCode_PARTIALUPDATE))
+		this.mux.HandleFunc("DELETE "+pattern, this.crudHandler(protodb.CrudReqCode_DELETE))
+	}
+	return this
+}
+
+func (this *TrestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	this.mux.ServeHTTP(w, r)
+}
+
+// crudHandler handle a crud request by HandleCrud, the new row is returned
+func (this *TrestHandler) crudHandler(code protodb.CrudReqCode) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tableName := r.PathValue("table")
+
+		// Secure by Default: same as Crud rpc
+		fnCrudPermission := this.srv.fnCrudPermissionMap[tableName]
+		if fnCrudPermission == nil {
+			writeRestError(w, http.StatusForbidden, fmt.Errorf("no crudpermission function registered for table %s, operation %s denied", tableName, code.String()))
+			return
+		}
+		registry := this.srv.registry()
+		msg, ok := registry.Msgs.GetMsg(tableName, true)
+		if !ok {
+			writeRestError(w, http.StatusNotFound, fmt.Errorf("table %s not found", tableName))
+			return
+		}
+
+		var updateFields []string
+		if code != protodb.CrudReqCode_SELECTONE {
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, restMaxBodyBytes))
+			if err != nil {
+				writeRestError(w, http.StatusBadRequest, fmt.Errorf("read body err: %w", err))
+				return
+			}
+			if len(body) > 0 {
+				if err := protojson.Unmarshal(body, msg); err != nil {
+					writeRestError(w, http.StatusBadRequest, fmt.Errorf("unmarshal msg %s err: %w", tableName, err))
+					return
+				}
+			}
+			if code == protodb.CrudReqCode_PARTIALUPDATE {
+				if updateFields, err = restUpdateFields(msg.ProtoReflect().Descriptor(), body); err != nil {
+					writeRestError(w, http.StatusBadRequest, err)
+					return
+				}
+			}
+		}
+		if err := setRestPrimaryKey(msg, r.PathValue("pk")); err != nil {
+			writeRestError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		msgBytes, err := protojson.Marshal(msg)
+		if err != nil {
+			writeRestError(w, http.StatusInternalServerError, err)
+			return
+		}
+		req := &protodb.CrudReq{
+			Code:                code,
+			ResultType:          protodb.CrudResultType_NewMsg,
+			SchemeName:          r.Header.Get(YgrpcSchema),
+			TableName:           tableName,
+			MsgBytes:            msgBytes,
+			MsgFormat:           restMsgFormat,
+			PartialUpdateFields: updateFields,
+			SelectResultFields:  restListParam(r.URL.Query()["fields"]),
+		}
+		resp, err := registry.HandleCrud(r.Context(), r.Header, req, this.srv.FnGetDb, fnCrudPermission)
+		if err != nil {
+			writeRestError(w, restCrudErrStatus(err), err)
+			return
+		}
+
+		status := http.StatusOK
+		if code == protodb.CrudReqCode_INSERT {
+			status = http.StatusCreated
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write(resp.NewMsgBytes)
+	}
+}
+
+// tableQuery handle a table query by HandleTableQuery, rows are written as NDJSON when they arrive.
+// an error after rows are written is the last line as {"error": "..."}
+func (this *TrestHandler) tableQuery(w http.ResponseWriter, r *http.Request) {
+	tableName := r.PathValue("table")
+
+	permissionFn, ok := this.srv.fnTableQueryPermissionMap[tableName]
+	if !ok {
+		writeRestError(w, http.StatusForbidden, fmt.Errorf("no permission check function for table %s", tableName))
+		return
+	}
+	registry := this.srv.registry()
+	if _, ok := registry.Msgs.GetMsg(tableName, false); !ok {
+		writeRestError(w, http.StatusNotFound, fmt.Errorf("table %s not found", tableName))
+		return
+	}
+	req, err := ParseRestTableQuery(r.URL.Query())
+	if err != nil {
+		writeRestError(w, http.StatusBadRequest, err)
+		return
+	}
+	req.SchemeName = r.Header.Get(YgrpcSchema)
+	req.TableName = tableName
+
+	// started the status is written, by the first batch or the error
+	started := false
+	writeNDJSONHeader := func() {
+		started = true
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+	}
+	rc := http.NewResponseController(w)
+	fnSend := func(resp *protodb.QueryResp) error {
+		if len(resp.ErrInfo) > 0 {
+			if !started {
+				started = true
+				writeRestError(w, restTableQueryErrStatus(resp.ErrInfo), errors.New(resp.ErrInfo))
+				return nil
+			}
+			return json.NewEncoder(w).Encode(map[string]string{"error": resp.ErrInfo})
+		}
+		if !started {
+			writeNDJSONHeader()
+		}
+		for _, msgBytes := range resp.MsgBytes {
+			if _, err := w.Write(msgBytes); err != nil {
+				return err
+			}
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		_ = rc.Flush()
+		return nil
+	}
+	err = registry.HandleTableQuery(r.Context(), r.Header, req, this.srv.FnGetDb, permissionFn, fnSend)
+	if started {
+		return
+	}
+	if err != nil {
+		writeRestError(w, restTableQueryErrStatus(err.Error()), err)
+		return
+	}
+	// no rows
+	writeNDJSONHeader()
+}
+
+// ParseRestTableQuery table query of rest url query, other params than order/limit/offset/fields are
+// where2 conditions field=op:value. MsgFormat is protobuf json, SchemeName and TableName are not set
+// like age=gt:18, name=alice(WOP_EQ), deleted_at=is_null:
+func ParseRestTableQuery(values url.Values) (*protodb.TableQueryReq, error) {
+	req := &protodb.TableQueryReq{
+		MsgFormat:       restMsgFormat,
+		PreferBatchSize: 100,
+		Where2:          make(map[string]string),
+		Where2Operator:  make(map[string]protodb.WhereOperator),
+	}
+	for key, vals := range values {
+		switch key {
+		case "order":
+			req.OrderBy = restListParam(vals)
+		case "fields":
+			req.ResultColumnNames = restListParam(vals)
+		case "limit":
+			limit, err := strconv.ParseInt(vals[0], 10, 32)
+			if err != nil || limit < 0 {
+				return nil, fmt.Errorf("invalid limit %q", vals[0])
+			}
+			req.Limit = int32(limit)
+		case "offset":
+			offset, err := strconv.ParseInt(vals[0], 10, 64)
+			if err != nil || offset < 0 {
+				return nil, fmt.Errorf("invalid offset %q", vals[0])
+			}
+			req.Offset = offset
+		default:
+			if len(vals) > 1 {
+				return nil, fmt.Errorf("field %s has more than one condition", key)
+			}
+			op, value := parseRestCondition(vals[0])
+			req.Where2[key] = value
+			req.Where2Operator[key] = op
+		}
+	}
+	return req, nil
+}
+
+// parseRestCondition op:value, op is WhereOperator name without WOP_ in any case, WOP_EQ if no op
+func parseRestCondition(s string) (protodb.WhereOperator, string) {
+	if i := strings.IndexByte(s, ':'); i > 0 {
+		if op, ok := protodb.WhereOperator_value["WOP_"+strings.ToUpper(s[:i])]; ok && op != int32(protodb.WhereOperator_WOP_UNKNOWN) {
+			return protodb.WhereOperator(op), s[i+1:]
+		}
+	}
+	return protodb.WhereOperator_WOP_EQ, s
+}
+
+// restListParam comma separated values of params
+func restListParam(vals []string) []string {
+	var list []string
+	for _, val := range vals {
+		for _, item := range strings.Split(val, ",") {
+			if item = strings.TrimSpace(item); len(item) > 0 {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
+
+// restUpdateFields field names of the keys of json body, primary key fields are excluded
+func restUpdateFields(msgDesc protoreflect.MessageDescriptor, body []byte) ([]string, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return nil, fmt.Errorf("partial update body must be a json object: %w", err)
+	}
+	fields := msgDesc.Fields()
+	var updateFields []string
+	for key := range obj {
+		fd := fields.ByJSONName(key)
+		if fd == nil {
+			fd = fields.ByTextName(key)
+		}
+		if fd == nil {
+			return nil, fmt.Errorf("unknown field %s of %s", key, msgDesc.FullName())
+		}
+		if pdb, _ := pdbutil.GetPDB(fd); pdb.IsPrimary() {
+			continue
+		}
+		updateFields = append(updateFields, string(fd.Name()))
+	}
+	if len(updateFields) == 0 {
+		return nil, errors.New("no field to update")
+	}
+	return updateFields, nil
+}
+
+// setRestPrimaryKey set primary key fields of msg from path segments in field order, nothing to set if pkPath is empty
+func setRestPrimaryKey(msg proto.Message, pkPath string) error {
+	if len(pkPath) == 0 {
+		return nil
+	}
+	msgPm := msg.ProtoReflect()
+	msgDesc := msgPm.Descriptor()
+	var pkFields []protoreflect.FieldDescriptor
+	fields := msgDesc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if pdb, _ := pdbutil.GetPDB(fields.Get(i)); pdb.IsPrimary() {
+			pkFields = append(pkFields, fields.Get(i))
+		}
+	}
+	values := strings.Split(strings.TrimSuffix(pkPath, "/"), "/")
+	if len(values) != len(pkFields) {
+		return fmt.Errorf("table %s has %d primary key fields, got %d", pdbutil.GetTableName(msgDesc), len(pkFields), len(values))
+	}
+
+	keyMsg := msgPm.New()
+	for i, fd := range pkFields {
+		// decode by protojson, so path values are the same as json values: enum name or number, RFC 3339 timestamp...
+		jsonValue, _ := json.Marshal(values[i])
+		switch fd.Kind() {
+		case protoreflect.BoolKind:
+			if _, err := strconv.ParseBool(values[i]); err == nil {
+				jsonValue = []byte(strings.ToLower(values[i]))
+			}
+		case protoreflect.EnumKind:
+			if _, err := strconv.ParseInt(values[i], 10, 32); err == nil {
+				jsonValue = []byte(values[i])
+			}
+		}
+		fieldName, _ := json.Marshal(string(fd.Name()))
+		jsonMsg := "{" + string(fieldName) + ":" + string(jsonValue) + "}"
+		if err := protojson.Unmarshal([]byte(jsonMsg), keyMsg.Interface()); err != nil {
+			return fmt.Errorf("invalid primary key %s value %q: %w", fd.Name(), values[i], err)
+		}
+		msgPm.Set(fd, keyMsg.Get(fd))
+	}
+	return nil
+}
+
+// restCrudErrStatus http status of HandleCrud err
+func restCrudErrStatus(err error) int {
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		return restStatusOfCode(connectErr.Code())
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+// restTableQueryErrStatus http status of HandleTableQuery ErrInfo
+func restTableQueryErrStatus(errInfo string) int {
+	switch {
+	case strings.HasPrefix(errInfo, errInfoTableQueryPermission):
+		return http.StatusForbidden
+	case strings.HasPrefix(errInfo, errInfoTableQueryBuildSql):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// restStatusOfCode http status of connect code
+func restStatusOfCode(code connect.Code) int {
+	switch code {
+	case connect.CodeInvalidArgument, connect.CodeOutOfRange, connect.CodeFailedPrecondition:
+		return http.StatusBadRequest
+	case connect.CodeUnauthenticated:
+		return http.StatusUnauthorized
+	case connect.CodePermissionDenied:
+		return http.StatusForbidden
+	case connect.CodeNotFound:
+		return http.StatusNotFound
+	case connect.CodeAlreadyExists, connect.CodeAborted:
+		return http.StatusConflict
+	case connect.CodeResourceExhausted:
+		return http.StatusTooManyRequests
+	case connect.CodeUnimplemented:
+		return http.StatusNotImplemented
+	case connect.CodeUnavailable:
+		return http.StatusServiceUnavailable
+	case connect.CodeDeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeRestError write err as json {"error": "..."}
+func writeRestError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
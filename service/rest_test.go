@@ -0,0 +1,155 @@
+package service
+
+import (
+	"bufio"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ygrpc/protodb"
+	"github.com/ygrpc/protodb/internal/example/userpb"
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/proto"
+)
+
+func newRestTestHandler(t *testing.T, fnQueryPermission TfnTableQueryPermission) (*TrestHandler, sqlmock.Sqlmock) {
+	t.Helper()
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { mockDB.Close() })
+
+	registry := NewRegistry()
+	registry.Msgs.RegisterMsg("User", func(new bool) proto.Message { return &userpb.User{} })
+	srv := NewTconnectrpcProtoDbSrvHandlerImplWithRegistry(registry,
+		func(meta http.Header, schemaName string, tableName string, writable bool) (sqldb.DB, error) {
+			return &sqldb.DBWithDialect{Executor: mockDB, Dialect: sqldb.Postgres}, nil
+		},
+		map[string]TfnProtodbCrudPermission{"t_user": FnProtodbCrudPermissionEmpty, "Unknown": FnProtodbCrudPermissionEmpty},
+		map[string]TfnTableQueryPermission{"t_user": fnQueryPermission},
+	)
+	return NewRestHandler(srv), mock
+}
+
+func serveRest(h http.Handler, method string, target string, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return w
+}
+
+func TestRestSelectOneAndPartialUpdate(t *testing.T) {
+	h, mock := newRestTestHandler(t, nil)
+
+	mock.ExpectQuery(`SELECT \* FROM "t_user" WHERE "id" = \$1`).WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(7, "alice"))
+	w := serveRest(h, http.MethodGet, "/tables/t_user/7", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"username":"alice"`) {
+		t.Fatalf("GET = %d %s", w.Code, w.Body)
+	}
+
+	mock.ExpectQuery(`UPDATE "t_user" SET "email" = \$1 WHERE "id" = \$2 RETURNING`).WithArgs("a@example.com", int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(7, "a@example.com"))
+	w = serveRest(h, http.MethodPatch, "/tables/t_user/7", `{"email":"a@example.com"}`)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"email":"a@example.com"`) {
+		t.Fatalf("PATCH = %d %s", w.Code, w.Body)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRestTableQueryNDJSON(t *testing.T) {
+	h, mock := newRestTestHandler(t, nil)
+
+	mock.ExpectQuery(`SELECT \* FROM "t_user" WHERE "username" LIKE \$1 ORDER BY "id" DESC LIMIT 2`).WithArgs("a%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(2, "ann").AddRow(1, "alice"))
+	w := serveRest(h, http.MethodGet, "/tables/t_user?username=like:a%25&order=-id&limit=2", "")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/x-ndjson" {
+		t.Fatalf("GET = %d %s", w.Code, w.Body)
+	}
+	var lines []string
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) != 2 || !strings.Contains(lines[0], `"ann"`) || !strings.Contains(lines[1], `"alice"`) {
+		t.Fatalf("rows = %q", lines)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRestTableQueryNoRows(t *testing.T) {
+	h, mock := newRestTestHandler(t, nil)
+
+	mock.ExpectQuery(`SELECT \* FROM "t_user" WHERE "username" = \$1`).WithArgs("nobody").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}))
+	w := serveRest(h, http.MethodGet, "/tables/t_user?username=nobody", "")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/x-ndjson" || w.Body.Len() != 0 {
+		t.Fatalf("GET = %d %q %q", w.Code, w.Header().Get("Content-Type"), w.Body)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRestErrorStatus(t *testing.T) {
+	h, mock := newRestTestHandler(t, func(meta http.Header, schemaName string, tableName string, db sqldb.DB, dbmsg proto.Message) (string, []any, error) {
+		if meta.Get("X-Deny") != "" {
+			return "", nil, errors.New("denied in test")
+		}
+		return "", nil, nil
+	})
+	mock.ExpectQuery(`SELECT \* FROM "t_user"`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	denied := httptest.NewRequest(http.MethodGet, "/tables/t_user", nil)
+	denied.Header.Set("X-Deny", "1")
+	deniedResp := httptest.NewRecorder()
+	h.ServeHTTP(deniedResp, denied)
+
+	cases := []struct {
+		name string
+		w    *httptest.ResponseRecorder
+		want int
+	}{
+		{"no crud permission", serveRest(h, http.MethodDelete, "/tables/other/1", ""), http.StatusForbidden},
+		{"no query permission", serveRest(h, http.MethodGet, "/tables/other", ""), http.StatusForbidden},
+		{"query permission denied", deniedResp, http.StatusForbidden},
+		{"unknown table", serveRest(h, http.MethodGet, "/tables/Unknown/1", ""), http.StatusNotFound},
+		{"primary key count", serveRest(h, http.MethodGet, "/tables/t_user/1/2", ""), http.StatusBadRequest},
+		{"bad body", serveRest(h, http.MethodPost, "/tables/t_user", "{"), http.StatusBadRequest},
+		{"unknown where field", serveRest(h, http.MethodGet, "/tables/t_user?nope=1", ""), http.StatusBadRequest},
+		{"not found", serveRest(h, http.MethodGet, "/tables/t_user/1", ""), http.StatusNotFound},
+	}
+	for _, c := range cases {
+		if c.w.Code != c.want || !strings.Contains(c.w.Body.String(), `"error"`) {
+			t.Errorf("%s = %d %s, want %d", c.name, c.w.Code, c.w.Body, c.want)
+		}
+	}
+}
+
+func TestParseRestCondition(t *testing.T) {
+	cases := []struct {
+		in    string
+		op    protodb.WhereOperator
+		value string
+	}{
+		{"gte:18", protodb.WhereOperator_WOP_GTE, "18"},
+		{"IS_NULL:", protodb.WhereOperator_WOP_IS_NULL, ""},
+		{"alice", protodb.WhereOperator_WOP_EQ, "alice"},
+		{"eq:a:b", protodb.WhereOperator_WOP_EQ, "a:b"},
+		{"http://x", protodb.WhereOperator_WOP_EQ, "http://x"},
+	}
+	for _, c := range cases {
+		op, value := parseRestCondition(c.in)
+		if op != c.op || value != c.value {
+			t.Errorf("parseRestCondition(%q) = %v %q", c.in, op, value)
+		}
+	}
+}
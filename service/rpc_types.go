@@ -39,3 +39,9 @@ func FnTableQueryPermissionEmpty(meta http.Header, schemaName string, tableName
 }
 
 type TfnSendQueryResp func(resp *protodb.QueryResp) error
+
+// TfnDescribeTablePermission check if a table is visible to the caller of DescribeSchema, nil err to list it
+type TfnDescribeTablePermission func(meta http.Header, schemaName string, tableName string, db sqldb.DB, dbmsg proto.Message) (err error)
+
+// TfnDescribeQueryPermission check if a query is visible to the caller of DescribeSchema, nil err to list it
+type TfnDescribeQueryPermission func(meta http.Header, schemaName string, queryName string) (err error)
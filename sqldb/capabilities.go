@@ -0,0 +1,167 @@
+package sqldb
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// TDBCapabilities features of a database server, probed once per *sql.DB.
+// the crud builders choose native sql or the fallback path by it
+type TDBCapabilities struct {
+	Dialect TDBDialect
+	// Flavor server flavor, dialect name or a fork like MariaDB
+	Flavor string
+	// Version server version text, Major/Minor/Patch are parsed from it
+	Version string
+	Major   int
+	Minor   int
+	Patch   int
+	// Probed capabilities are detected from the server, otherwise they are the dialect defaults
+	Probed bool
+
+	// InsertReturning/UpdateReturning/DeleteReturning statement supports RETURNING *
+	InsertReturning bool
+	UpdateReturning bool
+	DeleteReturning bool
+	// OldNewReturning how update returns old and new rows
+	OldNewReturning TOldNewReturning
+	// JSON json functions are available, needed by list/map conditions on json text columns
+	JSON bool
+	// OnConflict UpsertClause is supported
+	OnConflict bool
+	// GeneratedColumns generated(computed) columns are supported
+	GeneratedColumns bool
+
+	CacheTime time.Time
+}
+
+// AtLeast server version >= major.minor
+func (c TDBCapabilities) AtLeast(major int, minor int) bool {
+	return c.Major > major || (c.Major == major && c.Minor >= minor)
+}
+
+// setVersion set version text and the parsed numbers
+func (c *TDBCapabilities) setVersion(version string) {
+	c.Version = version
+	c.Major, c.Minor, c.Patch = ParseServerVersion(version)
+	c.Probed = true
+}
+
+// DBCapabilitiesRetryTTL a failed probe is retried after it, the dialect defaults are used meanwhile
+var DBCapabilitiesRetryTTL = 30 * time.Second
+
+// DefaultCapabilities capabilities of dialect without asking the server,
+// a current server version is assumed
+func DefaultCapabilities(dialect TDBDialect) TDBCapabilities {
+	d := dialect.Dialect()
+	returning := d.SupportsReturning()
+	return TDBCapabilities{
+		Dialect:          dialect,
+		Flavor:           d.Name(),
+		InsertReturning:  returning,
+		UpdateReturning:  returning,
+		DeleteReturning:  returning,
+		OldNewReturning:  d.OldNewReturning(),
+		JSON:             true,
+		OnConflict:       len(d.UpsertClause([]string{"id"}, nil)) > 0,
+		GeneratedColumns: true,
+	}
+}
+
+// ProbeDBCapabilities ask the server of db for its version and features,
+// the dialect defaults are returned with the error if probing fails
+func ProbeDBCapabilities(db DB, dialect TDBDialect) (TDBCapabilities, error) {
+	caps := DefaultCapabilities(dialect)
+	if err := dialect.Dialect().ProbeCapabilities(db, &caps); err != nil {
+		return DefaultCapabilities(dialect), fmt.Errorf("probe %v capabilities: %w", dialect, err)
+	}
+	return caps, nil
+}
+
+// GetDBCapabilities get capabilities of sql.db and cache it in the dialect cache item of db.
+// a failed probe is logged and the dialect defaults are used until DBCapabilitiesRetryTTL passes
+func GetDBCapabilities(db *sql.DB) TDBCapabilities {
+	dialect, _ := GetDBDialectCache(db)
+	return getDBCapabilities(db, dialect)
+}
+
+func getDBCapabilities(db *sql.DB, dialect TDBDialect) TDBCapabilities {
+	_, item := GetDBDialectCache(db)
+	caps := item.Capabilities
+	if !caps.CacheTime.IsZero() && caps.Dialect == dialect &&
+		(item.CapabilitiesErr == nil || time.Since(caps.CacheTime) < DBCapabilitiesRetryTTL) {
+		return caps
+	}
+
+	caps, err := ProbeDBCapabilities(db, dialect)
+	if err != nil {
+		log.Printf("%v, use default capabilities", err)
+	}
+	caps.CacheTime = time.Now()
+	item.Capabilities = caps
+	item.CapabilitiesErr = err
+	dbDialectCache.Store(db, item)
+	return caps
+}
+
+// GetExecutorCapabilities gets the capabilities of the server behind a DB.
+// *sql.DB and *DBWithDialect holding a *sql.DB are probed once and cached,
+// a transaction without its *sql.DB(NewTxWithDialectType) gets the dialect defaults
+func GetExecutorCapabilities(executor DB) TDBCapabilities {
+	switch e := executor.(type) {
+	case *sql.DB:
+		return GetDBCapabilities(e)
+	case *DBWithDialect:
+		if e.SQLDB != nil {
+			return getDBCapabilities(e.SQLDB, e.Dialect)
+		}
+		if innerDB, ok := e.Executor.(*sql.DB); ok {
+			return getDBCapabilities(innerDB, e.Dialect)
+		}
+		return DefaultCapabilities(e.Dialect)
+	default:
+		return DefaultCapabilities(GetExecutorDialect(executor))
+	}
+}
+
+// ParseServerVersion parse the first version number in version text,
+// like 16.2 (Debian 16.2-1), 10.11.6-MariaDB-log, 3.45.1, v1.1.3
+func ParseServerVersion(version string) (major int, minor int, patch int) {
+	for _, tok := range strings.Fields(version) {
+		tok = strings.TrimPrefix(tok, "v")
+		if len(tok) == 0 || tok[0] < '0' || tok[0] > '9' {
+			continue
+		}
+		nums := [3]int{}
+		for i, part := range strings.SplitN(tok, ".", 3) {
+			nums[i] = atoiPrefix(part)
+		}
+		return nums[0], nums[1], nums[2]
+	}
+	return 0, 0, 0
+}
+
+// atoiPrefix converts the leading digit run of s to int
+func atoiPrefix(s string) int {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < '0' || c > '9' {
+			break
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n
+}
+
+// queryVersion scan the single text row of version query
+func queryVersion(db DB, query string) (string, error) {
+	var version string
+	if err := db.QueryRow(query).Scan(&version); err != nil {
+		return "", err
+	}
+	return version, nil
+}
@@ -0,0 +1,152 @@
+package sqldb
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestParseServerVersion(t *testing.T) {
+	cases := []struct {
+		version             string
+		major, minor, patch int
+	}{
+		{"16.2 (Debian 16.2-1.pgdg120+2)", 16, 2, 0},
+		{"10.11.6-MariaDB-1:10.11.6+maria~ubu2204-log", 10, 11, 6},
+		{"8.0.36", 8, 0, 36},
+		{"3.45.1", 3, 45, 1},
+		{"v1.1.3", 1, 1, 3},
+		{"PostgreSQL 18beta1 on x86_64", 18, 0, 0},
+		{"", 0, 0, 0},
+	}
+	for _, c := range cases {
+		major, minor, patch := ParseServerVersion(c.version)
+		if major != c.major || minor != c.minor || patch != c.patch {
+			t.Errorf("ParseServerVersion(%q) = %d.%d.%d, want %d.%d.%d", c.version, major, minor, patch, c.major, c.minor, c.patch)
+		}
+	}
+}
+
+func TestProbeDBCapabilities(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	versionRows := func(version string) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"version"}).AddRow(version)
+	}
+
+	mock.ExpectQuery(`SELECT VERSION\(\)`).WillReturnRows(versionRows("10.6.16-MariaDB"))
+	caps, err := ProbeDBCapabilities(db, Mysql)
+	if err != nil {
+		t.Fatalf("probe mariadb: %v", err)
+	}
+	if caps.Flavor != "MariaDB" || !caps.Probed || !caps.InsertReturning || !caps.DeleteReturning || caps.UpdateReturning || !caps.JSON {
+		t.Fatalf("unexpected mariadb capabilities: %+v", caps)
+	}
+
+	mock.ExpectQuery(`SELECT VERSION\(\)`).WillReturnRows(versionRows("5.6.51-log"))
+	caps, err = ProbeDBCapabilities(db, Mysql)
+	if err != nil {
+		t.Fatalf("probe mysql: %v", err)
+	}
+	if caps.Flavor != "Mysql" || caps.InsertReturning || caps.JSON || caps.GeneratedColumns || !caps.OnConflict {
+		t.Fatalf("unexpected mysql capabilities: %+v", caps)
+	}
+
+	mock.ExpectQuery(`SELECT sqlite_version\(\)`).WillReturnRows(versionRows("3.31.1"))
+	mock.ExpectQuery(`SELECT json_valid`).WillReturnError(errors.New("no such function: json_valid"))
+	caps, err = ProbeDBCapabilities(db, SQLite)
+	if err != nil {
+		t.Fatalf("probe sqlite: %v", err)
+	}
+	if caps.InsertReturning || caps.UpdateReturning || !caps.OnConflict || !caps.GeneratedColumns || caps.JSON {
+		t.Fatalf("unexpected sqlite capabilities: %+v", caps)
+	}
+
+	mock.ExpectQuery(`SHOW server_version`).WillReturnRows(versionRows("18.0"))
+	caps, err = ProbeDBCapabilities(db, Postgres)
+	if err != nil {
+		t.Fatalf("probe postgres: %v", err)
+	}
+	if caps.OldNewReturning != OldNewNative || !caps.GeneratedColumns {
+		t.Fatalf("unexpected postgres capabilities: %+v", caps)
+	}
+
+	mock.ExpectQuery(`SHOW server_version`).WillReturnError(sql.ErrConnDone)
+	caps, err = ProbeDBCapabilities(db, Postgres)
+	if err == nil {
+		t.Fatal("expected probe error")
+	}
+	if caps.Probed || caps.OldNewReturning != OldNewSelfJoin || !caps.UpdateReturning {
+		t.Fatalf("expected postgres defaults: %+v", caps)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestGetExecutorCapabilitiesCache(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+	defer ClearDBDialectCache(db)
+
+	mock.ExpectQuery(`SELECT VERSION\(\)`).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("11.4.2-MariaDB"))
+
+	executor := &DBWithDialect{Executor: db, Dialect: Mysql, SQLDB: db}
+	first := GetExecutorCapabilities(executor)
+	second := GetExecutorCapabilities(executor)
+	if !first.InsertReturning || first.CacheTime != second.CacheTime {
+		t.Fatalf("capabilities not cached: %+v %+v", first, second)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+
+	tx := &DBWithDialect{Executor: executor, Dialect: Mysql}
+	if caps := GetExecutorCapabilities(tx); caps.Probed || caps.InsertReturning {
+		t.Fatalf("expected mysql defaults without *sql.DB: %+v", caps)
+	}
+}
+
+func TestGetExecutorCapabilitiesRetryFailedProbe(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+	defer ClearDBDialectCache(db)
+
+	mock.ExpectQuery(`SHOW server_version`).WillReturnError(errors.New("connection refused"))
+	mock.ExpectQuery(`SHOW server_version`).WillReturnRows(sqlmock.NewRows([]string{"server_version"}).AddRow("16.2"))
+
+	executor := &DBWithDialect{Executor: db, Dialect: Postgres, SQLDB: db}
+	if caps := GetExecutorCapabilities(executor); caps.Probed {
+		t.Fatalf("expected defaults after failed probe: %+v", caps)
+	}
+	if caps := GetExecutorCapabilities(executor); caps.Probed {
+		t.Fatalf("failed probe retried before ttl: %+v", caps)
+	}
+	_, item := GetDBDialectCache(db)
+	if item.CapabilitiesErr == nil {
+		t.Fatal("probe error is not recorded in the dialect cache item")
+	}
+
+	item.Capabilities.CacheTime = time.Now().Add(-DBCapabilitiesRetryTTL)
+	dbDialectCache.Store(db, item)
+	if caps := GetExecutorCapabilities(executor); !caps.Probed || caps.Major != 16 {
+		t.Fatalf("failed probe is not retried after ttl: %+v", caps)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
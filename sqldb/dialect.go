@@ -0,0 +1,397 @@
+package sqldb
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/puzpuzpuz/xsync/v3"
+	"github.com/ygrpc/protodb/protosql"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Dialect sql dialect of a database, Postgres, Mysql and SQLite are built in.
+// other databases can be supported by implementing Dialect(embed BaseDialect for defaults)
+// and RegisterDialect it with a TDBDialect id >= DialectCustomBase
+type Dialect interface {
+	// ID dialect id used in TDBDialect
+	ID() TDBDialect
+	// Name dialect name, like Postgres
+	Name() string
+	// DriverNames type names of database/sql drivers of the dialect, like *pq.Driver
+	DriverNames() []string
+
+	// Placeholder sql placeholder of args
+	Placeholder() protosql.SQLPlaceholder
+	// QuoteIdentifier quote table or column name in sql text
+	QuoteIdentifier(name string) string
+	// FoldIdentifier the name of identifier in db catalog
+	FoldIdentifier(name string) string
+	// TableName table name in db catalog with schema
+	TableName(tableName string, dbschema string) string
+	// QuotedTableName table name with schema in sql text
+	QuotedTableName(tableName string, dbschema string) string
+
+	// ColumnType db column type for ddl, empty if not supported
+	ColumnType(t ColumnType) string
+
+	// SupportsReturning insert/update/delete support RETURNING *
+	SupportsReturning() bool
+	// OldNewReturning how update returns old and new rows
+	OldNewReturning() TOldNewReturning
+	// UpsertClause clause appended to INSERT to update on conflict, empty if not supported.
+	// no updateColumns means do nothing on conflict
+	UpsertClause(conflictColumns []string, updateColumns []string) string
+
+	// NativeArrays repeated scalar fields are stored as native db arrays, otherwise json text.
+	// native arrays of unsigned ints must be bigint arrays
+	NativeArrays() bool
+	// NativeMaps map fields are stored as native db maps, otherwise json text
+	NativeMaps() bool
+	// MapArg expr(placeholder) of a native map column arg, the arg is json object text
+	MapArg(expr string) string
+	// EncodeTime sql arg of timestamp
+	EncodeTime(t time.Time) any
+	// DurationAsInterval duration is stored as interval text, otherwise bigint nanoseconds
+	DurationAsInterval() bool
+	// NumericCast cast expr(placeholder) to numeric for comparing, precision 0 means unconstrained
+	NumericCast(expr string, precision int32, scale int32) string
+	// NumericColumn numeric column expression for comparing
+	NumericColumn(column string) string
+	// ListCondition where condition on repeated column, op is one of ListOp*, ok is false if not supported
+	ListCondition(column string, elemKind protoreflect.Kind, op string, placeholder string) (cond string, arg TListArg, ok bool)
+	// MapCondition where condition on map column, op is one of MapOp*, the arg is the value as is
+	MapCondition(column string, op string, placeholder string) (cond string, ok bool)
+
+	// TableExistsQuery query returns one row with a bool if table exists
+	TableExistsQuery(dbschema string, tableName string) (query string, args []any)
+	// ColumnsQuery query returns column names of table
+	ColumnsQuery(dbschema string, tableName string) (query string, args []any)
+
+	// ProbeCapabilities refine caps(the dialect defaults) by server version and features of db
+	ProbeCapabilities(db DB, caps *TDBCapabilities) error
+}
+
+// DialectCustomBase first TDBDialect id for dialects registered outside protodb
+const DialectCustomBase TDBDialect = 100
+
+// TOldNewReturning how update returns both old and new rows
+type TOldNewReturning int
+
+const (
+	// OldNewSelect select old row before update, then update returning new row
+	OldNewSelect TOldNewReturning = 0
+	// OldNewSelfJoin update ... from old table returning old.*,new.*
+	OldNewSelfJoin TOldNewReturning = 1
+	// OldNewNative update ... returning OLD.*,NEW.*
+	OldNewNative TOldNewReturning = 2
+)
+
+// TListArg the arg of ListCondition
+type TListArg int
+
+const (
+	// ListArgText the where value as is(json text)
+	ListArgText TListArg = 0
+	// ListArgScalar the where value parsed as one element
+	ListArgScalar TListArg = 1
+	// ListArgArray the where value(json array) parsed as a native array
+	ListArgArray TListArg = 2
+	// ListArgLength the where value parsed as an integer length
+	ListArgLength TListArg = 3
+)
+
+// list and map operators, same as protodb.WhereOperator names without WOP_
+const (
+	ListOpContains    = "CONTAINS"
+	ListOpOverlap     = "OVERLAP"
+	ListOpContainsAll = "CONTAINS_ALL"
+	ListOpLenGT       = "LEN_GT"
+	ListOpLenGTE      = "LEN_GTE"
+	ListOpLenLT       = "LEN_LT"
+	ListOpLenLTE      = "LEN_LTE"
+
+	MapOpHasKey   = "HAS_KEY"
+	MapOpContains = "CONTAINS"
+)
+
+// ColumnType column type asked from dialect
+type ColumnType struct {
+	// Kind proto kind of field, element kind for repeated fields
+	Kind protoreflect.Kind
+	// WellKnown full name of well-known message type like google.protobuf.Timestamp
+	WellKnown protoreflect.FullName
+	// DbType name of protodb.FieldDbType like INT64, empty for AutoMatch
+	DbType string
+	// SerialSize 2/4/8 for auto increment column
+	SerialSize int32
+	// Precision and Scale of NUMERIC
+	Precision int32
+	Scale     int32
+	// MaxLength max length of short text like oneof discriminator, 0 for text
+	MaxLength int32
+	// EnumName and EnumValues of native enum column
+	EnumName   string
+	EnumValues []string
+	Repeated   bool
+	Map        bool
+	// MapKeyKind and MapValueKind of map field
+	MapKeyKind   protoreflect.Kind
+	MapValueKind protoreflect.Kind
+}
+
+// IsNumeric exact decimal column
+func (t ColumnType) IsNumeric() bool {
+	return t.DbType == "NUMERIC"
+}
+
+var registeredDialects = xsync.NewMapOf[TDBDialect, Dialect]()
+
+var driverDialects = xsync.NewMapOf[string, TDBDialect]()
+
+// RegisterDialect register a dialect, a registered dialect with same id is replaced
+func RegisterDialect(d Dialect) {
+	registeredDialects.Store(d.ID(), d)
+	for _, driverName := range d.DriverNames() {
+		driverDialects.Store(driverName, d.ID())
+	}
+}
+
+// GetDialect get registered dialect by id
+func GetDialect(id TDBDialect) (Dialect, bool) {
+	return registeredDialects.Load(id)
+}
+
+// GetDialectByName get registered dialect by name, case insensitive
+func GetDialectByName(name string) (Dialect, bool) {
+	var found Dialect
+	registeredDialects.Range(func(_ TDBDialect, d Dialect) bool {
+		if strings.EqualFold(d.Name(), name) {
+			found = d
+			return false
+		}
+		return true
+	})
+	return found, found != nil
+}
+
+// getDriverDialect dialect id of database/sql driver type name
+func getDriverDialect(driverName string) (TDBDialect, bool) {
+	return driverDialects.Load(driverName)
+}
+
+// Dialect the registered implementation of dialect, BaseDialect for Unknown or unregistered
+func (this TDBDialect) Dialect() Dialect {
+	if d, ok := registeredDialects.Load(this); ok {
+		return d
+	}
+	return unknownDialect{id: this}
+}
+
+type unknownDialect struct {
+	BaseDialect
+	id TDBDialect
+}
+
+func (d unknownDialect) ID() TDBDialect {
+	return d.id
+}
+
+// SupportsReturning unknown db keeps the generic sql with RETURNING
+func (unknownDialect) SupportsReturning() bool {
+	return true
+}
+
+// TableName Oracle joins schema and table with ".", others use schema as prefix
+func (d unknownDialect) TableName(tableName string, dbschema string) string {
+	if d.id == Oracle && len(dbschema) > 0 {
+		return dbschema + "." + tableName
+	}
+	return d.BaseDialect.TableName(tableName, dbschema)
+}
+
+func (d unknownDialect) QuotedTableName(tableName string, dbschema string) string {
+	return d.TableName(tableName, dbschema)
+}
+
+// OldNewReturning Oracle returns OLD/NEW natively, others keep the generic self join
+func (d unknownDialect) OldNewReturning() TOldNewReturning {
+	if d.id == Oracle {
+		return OldNewNative
+	}
+	return OldNewSelfJoin
+}
+
+// BaseDialect default behavior of Dialect, embed it to implement a dialect with few methods:
+// question placeholder, no quoting, no RETURNING, json text for repeated and map fields
+type BaseDialect struct{}
+
+func (BaseDialect) ID() TDBDialect {
+	return Unknown
+}
+
+func (BaseDialect) Name() string {
+	return "Unknown"
+}
+
+func (BaseDialect) DriverNames() []string {
+	return nil
+}
+
+func (BaseDialect) Placeholder() protosql.SQLPlaceholder {
+	return protosql.SQL_QUESTION
+}
+
+func (BaseDialect) QuoteIdentifier(name string) string {
+	return name
+}
+
+func (BaseDialect) FoldIdentifier(name string) string {
+	return name
+}
+
+// TableName schema is used as table name prefix
+func (BaseDialect) TableName(tableName string, dbschema string) string {
+	return dbschema + tableName
+}
+
+func (BaseDialect) QuotedTableName(tableName string, dbschema string) string {
+	return dbschema + tableName
+}
+
+// ColumnType empty for unknown database
+func (BaseDialect) ColumnType(t ColumnType) string {
+	return ""
+}
+
+func (BaseDialect) SupportsReturning() bool {
+	return false
+}
+
+func (BaseDialect) OldNewReturning() TOldNewReturning {
+	return OldNewSelect
+}
+
+func (BaseDialect) UpsertClause(conflictColumns []string, updateColumns []string) string {
+	return ""
+}
+
+func (BaseDialect) NativeArrays() bool {
+	return false
+}
+
+func (BaseDialect) NativeMaps() bool {
+	return false
+}
+
+func (BaseDialect) MapArg(expr string) string {
+	return expr
+}
+
+func (BaseDialect) EncodeTime(t time.Time) any {
+	return t
+}
+
+func (BaseDialect) DurationAsInterval() bool {
+	return false
+}
+
+func (BaseDialect) NumericCast(expr string, precision int32, scale int32) string {
+	return "CAST(" + expr + " AS NUMERIC)"
+}
+
+func (BaseDialect) NumericColumn(column string) string {
+	return column
+}
+
+func (BaseDialect) ListCondition(column string, elemKind protoreflect.Kind, op string, placeholder string) (string, TListArg, bool) {
+	return "", ListArgText, false
+}
+
+func (BaseDialect) MapCondition(column string, op string, placeholder string) (string, bool) {
+	return "", false
+}
+
+// TableExistsQuery information_schema of current database
+func (BaseDialect) TableExistsQuery(dbschema string, tableName string) (string, []any) {
+	return "SELECT EXISTS (SELECT table_name FROM information_schema.tables WHERE table_name = ?)", []any{dbschema + tableName}
+}
+
+// ColumnsQuery information_schema of current database
+func (BaseDialect) ColumnsQuery(dbschema string, tableName string) (string, []any) {
+	return "SELECT column_name FROM information_schema.columns WHERE table_name = ?", []any{dbschema + tableName}
+}
+
+// ProbeCapabilities nothing is probed, the defaults are kept
+func (BaseDialect) ProbeCapabilities(db DB, caps *TDBCapabilities) error {
+	return nil
+}
+
+// quoteIdentifierWith quote name and escape the quote char by doubling it
+func quoteIdentifierWith(quote string, name string) string {
+	return quote + strings.ReplaceAll(name, quote, quote+quote) + quote
+}
+
+// lenOpCmp comparison of list length operators
+func lenOpCmp(op string) (string, bool) {
+	switch op {
+	case ListOpLenGT:
+		return protosql.SQL_GT, true
+	case ListOpLenGTE:
+		return protosql.SQL_GTE, true
+	case ListOpLenLT:
+		return protosql.SQL_LT, true
+	case ListOpLenLTE:
+		return protosql.SQL_LTE, true
+	}
+	return "", false
+}
+
+// well-known message types with own column type
+const (
+	wktTimestamp protoreflect.FullName = "google.protobuf.Timestamp"
+	wktDuration  protoreflect.FullName = "google.protobuf.Duration"
+	wktStruct    protoreflect.FullName = "google.protobuf.Struct"
+	wktValue     protoreflect.FullName = "google.protobuf.Value"
+	wktListValue protoreflect.FullName = "google.protobuf.ListValue"
+)
+
+// numericTypeArgs numeric type args like (12,2), empty when precision is not set
+func numericTypeArgs(precision int32, scale int32) string {
+	if precision <= 0 {
+		return ""
+	}
+	return "(" + itoa32(precision) + "," + itoa32(scale) + ")"
+}
+
+func itoa32(v int32) string {
+	return strconv.Itoa(int(v))
+}
+
+// onConflictClause ON CONFLICT upsert of postgres and sqlite
+func onConflictClause(d Dialect, conflictColumns []string, updateColumns []string) string {
+	sb := strings.Builder{}
+	sb.WriteString(" ON CONFLICT (")
+	for i, column := range conflictColumns {
+		if i > 0 {
+			sb.WriteString(protosql.SQL_COMMA)
+		}
+		sb.WriteString(d.QuoteIdentifier(column))
+	}
+	sb.WriteString(")")
+	if len(updateColumns) == 0 {
+		sb.WriteString(" DO NOTHING")
+		return sb.String()
+	}
+	sb.WriteString(" DO UPDATE SET ")
+	for i, column := range updateColumns {
+		if i > 0 {
+			sb.WriteString(protosql.SQL_COMMA)
+		}
+		quoted := d.QuoteIdentifier(column)
+		sb.WriteString(quoted)
+		sb.WriteString(" = EXCLUDED.")
+		sb.WriteString(quoted)
+	}
+	return sb.String()
+}
@@ -0,0 +1,266 @@
+package sqldb
+
+import (
+	"log"
+	"time"
+
+	"github.com/ygrpc/protodb/protosql"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	duckdbTableExistsSQL = "SELECT EXISTS (SELECT table_name FROM information_schema.tables WHERE table_schema = ? AND table_name = ?)"
+	duckdbColumnsSQL     = "SELECT column_name FROM information_schema.columns WHERE table_schema = ? AND table_name = ?"
+)
+
+type duckdbDialect struct {
+	BaseDialect
+}
+
+func (duckdbDialect) ID() TDBDialect {
+	return DuckDB
+}
+
+func (duckdbDialect) Name() string {
+	return "DuckDB"
+}
+
+// DriverNames marcboeker/go-duckdb and duckdb/duckdb-go register Driver{} by value
+func (duckdbDialect) DriverNames() []string {
+	return []string{"duckdb.Driver", "*duckdb.Driver"}
+}
+
+func (duckdbDialect) Placeholder() protosql.SQLPlaceholder {
+	return protosql.SQL_QUESTION
+}
+
+// QuoteIdentifier duckdb identifiers are case insensitive and keep the case as created
+func (duckdbDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifierWith(`"`, name)
+}
+
+func (duckdbDialect) TableName(tableName string, dbschema string) string {
+	if len(dbschema) == 0 {
+		return tableName
+	}
+	return dbschema + "." + tableName
+}
+
+func (d duckdbDialect) QuotedTableName(tableName string, dbschema string) string {
+	if len(dbschema) == 0 {
+		return d.QuoteIdentifier(tableName)
+	}
+	return d.QuoteIdentifier(dbschema) + "." + d.QuoteIdentifier(tableName)
+}
+
+func (d duckdbDialect) ColumnType(t ColumnType) string {
+	if t.Map {
+		return "MAP(" + duckdbKindType(t.MapKeyKind) + ", " + duckdbElemType(t.MapValueKind) + ")"
+	}
+	if t.Repeated {
+		if t.Kind == protoreflect.MessageKind {
+			return "JSON"
+		}
+		return duckdbElemType(t.Kind) + "[]"
+	}
+	if len(t.EnumValues) > 0 {
+		return "ENUM(" + quoteEnumLabels(t.EnumValues) + ")"
+	}
+	if t.MaxLength > 0 {
+		return "VARCHAR"
+	}
+
+	switch t.DbType {
+	case "":
+		switch t.SerialSize {
+		case 0:
+		case 2:
+			return "SMALLINT"
+		case 4:
+			return "INTEGER"
+		case 8:
+			return "BIGINT"
+		default:
+			log.Printf("todo: ColumnType unknown serial type: %d", t.SerialSize)
+			return "VARCHAR"
+		}
+		switch t.WellKnown {
+		case "":
+			return duckdbKindType(t.Kind)
+		case wktTimestamp:
+			return "TIMESTAMPTZ"
+		case wktDuration:
+			return "BIGINT"
+		case wktStruct, wktValue, wktListValue:
+			return "JSON"
+		default:
+			return "VARCHAR"
+		}
+	case "BOOL":
+		return "BOOLEAN"
+	case "INT32":
+		return "INTEGER"
+	case "UINT32":
+		return "UINTEGER"
+	case "INT64":
+		return "BIGINT"
+	case "FLOAT":
+		return "FLOAT"
+	case "DOUBLE":
+		return "DOUBLE"
+	case "TEXT":
+		return "VARCHAR"
+	case "JSONB":
+		return "JSON"
+	case "UUID":
+		return "UUID"
+	case "TIMESTAMP":
+		return "TIMESTAMP"
+	case "DATE":
+		return "DATE"
+	case "BYTEA":
+		return "BLOB"
+	case "INET":
+		return "VARCHAR"
+	case "NUMERIC":
+		// duckdb default DECIMAL(18,3) drops fraction digits, 38 is the max precision
+		if t.Precision <= 0 {
+			return "DECIMAL(38,10)"
+		}
+		return "DECIMAL" + numericTypeArgs(t.Precision, t.Scale)
+	default:
+		log.Printf("todo: ColumnType unknown db type: %s", t.DbType)
+		return ""
+	}
+}
+
+func duckdbKindType(kind protoreflect.Kind) string {
+	switch kind {
+	case protoreflect.BoolKind:
+		return "BOOLEAN"
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return "INTEGER"
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return "BIGINT"
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return "UINTEGER"
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return "UBIGINT"
+	case protoreflect.FloatKind:
+		return "FLOAT"
+	case protoreflect.DoubleKind:
+		return "DOUBLE"
+	case protoreflect.StringKind:
+		return "VARCHAR"
+	case protoreflect.BytesKind:
+		return "BLOB"
+	case protoreflect.EnumKind:
+		return "INTEGER"
+	case protoreflect.MessageKind:
+		return "JSON"
+	default:
+		return "VARCHAR"
+	}
+}
+
+// duckdbElemType element type of LIST and value type of MAP, unsigned ints are BIGINT like the native arrays of postgres
+func duckdbElemType(kind protoreflect.Kind) string {
+	switch kind {
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind, protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return "BIGINT"
+	}
+	return duckdbKindType(kind)
+}
+
+func (duckdbDialect) SupportsReturning() bool {
+	return true
+}
+
+func (duckdbDialect) OldNewReturning() TOldNewReturning {
+	return OldNewSelect
+}
+
+func (d duckdbDialect) UpsertClause(conflictColumns []string, updateColumns []string) string {
+	return onConflictClause(d, conflictColumns, updateColumns)
+}
+
+func (duckdbDialect) NativeArrays() bool {
+	return true
+}
+
+func (duckdbDialect) NativeMaps() bool {
+	return true
+}
+
+// MapArg drivers only bind their own map types, json text is cast to the MAP column
+func (duckdbDialect) MapArg(expr string) string {
+	return "CAST(" + expr + " AS JSON)"
+}
+
+func (duckdbDialect) EncodeTime(t time.Time) any {
+	return t
+}
+
+func (d duckdbDialect) NumericCast(expr string, precision int32, scale int32) string {
+	return "CAST(" + expr + " AS " + d.ColumnType(ColumnType{DbType: "NUMERIC", Precision: precision, Scale: scale}) + ")"
+}
+
+func (duckdbDialect) ListCondition(column string, elemKind protoreflect.Kind, op string, placeholder string) (string, TListArg, bool) {
+	if cmp, ok := lenOpCmp(op); ok {
+		if elemKind == protoreflect.MessageKind {
+			return "json_array_length(" + column + ")" + cmp + placeholder, ListArgLength, true
+		}
+		return "len(" + column + ")" + cmp + placeholder, ListArgLength, true
+	}
+	if elemKind == protoreflect.MessageKind {
+		switch op {
+		case ListOpContains, ListOpContainsAll:
+			return "json_contains(" + column + ", " + placeholder + ")", ListArgText, true
+		}
+		return "", ListArgText, false
+	}
+	switch op {
+	case ListOpContains:
+		return "list_contains(" + column + ", " + placeholder + ")", ListArgScalar, true
+	case ListOpOverlap:
+		return "list_has_any(" + column + ", " + placeholder + ")", ListArgArray, true
+	case ListOpContainsAll:
+		return "list_has_all(" + column + ", " + placeholder + ")", ListArgArray, true
+	}
+	return "", ListArgText, false
+}
+
+// MapCondition only key lookup, duckdb has no containment operator of MAP
+func (duckdbDialect) MapCondition(column string, op string, placeholder string) (string, bool) {
+	switch op {
+	case MapOpHasKey:
+		return "map_contains(" + column + ", " + placeholder + ")", true
+	}
+	return "", false
+}
+
+// TableExistsQuery empty schema is main
+func (duckdbDialect) TableExistsQuery(dbschema string, tableName string) (string, []any) {
+	if len(dbschema) == 0 {
+		dbschema = "main"
+	}
+	return duckdbTableExistsSQL, []any{dbschema, tableName}
+}
+
+// ColumnsQuery empty schema is main
+func (duckdbDialect) ColumnsQuery(dbschema string, tableName string) (string, []any) {
+	if len(dbschema) == 0 {
+		dbschema = "main"
+	}
+	return duckdbColumnsSQL, []any{dbschema, tableName}
+}
+
+// ProbeCapabilities version only, every supported duckdb release has the features
+func (duckdbDialect) ProbeCapabilities(db DB, caps *TDBCapabilities) error {
+	version, err := queryVersion(db, "SELECT version()")
+	if err != nil {
+		return err
+	}
+	caps.setVersion(version)
+	return nil
+}
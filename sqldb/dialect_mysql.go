@@ -0,0 +1,256 @@
+package sqldb
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ygrpc/protodb/protosql"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	mysqlTableExistsSQL = "SELECT EXISTS (SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?)"
+	mysqlColumnsSQL     = "SELECT column_name FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ?"
+)
+
+type mysqlDialect struct {
+	BaseDialect
+}
+
+func (mysqlDialect) ID() TDBDialect {
+	return Mysql
+}
+
+func (mysqlDialect) Name() string {
+	return "Mysql"
+}
+
+// DriverNames github.com/go-sql-driver/mysql
+func (mysqlDialect) DriverNames() []string {
+	return []string{"*mysql.MySQLDriver"}
+}
+
+func (mysqlDialect) Placeholder() protosql.SQLPlaceholder {
+	return protosql.SQL_QUESTION
+}
+
+func (mysqlDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifierWith("`", name)
+}
+
+// QuotedTableName schema is the table name prefix
+func (d mysqlDialect) QuotedTableName(tableName string, dbschema string) string {
+	return d.QuoteIdentifier(d.TableName(tableName, dbschema))
+}
+
+func (mysqlDialect) ColumnType(t ColumnType) string {
+	if t.Map || t.Repeated {
+		return "json"
+	}
+	if len(t.EnumValues) > 0 {
+		return "enum(" + quoteEnumLabels(t.EnumValues) + ")"
+	}
+	if t.MaxLength > 0 {
+		return "varchar(" + itoa32(t.MaxLength) + ")"
+	}
+
+	switch t.DbType {
+	case "":
+		switch t.SerialSize {
+		case 0:
+		case 2:
+			return "smallint AUTO_INCREMENT"
+		case 4:
+			return "int AUTO_INCREMENT"
+		case 8:
+			return "bigint AUTO_INCREMENT"
+		default:
+			log.Printf("todo: ColumnType unknown serial type: %d", t.SerialSize)
+			return "text"
+		}
+		switch t.WellKnown {
+		case "":
+			return mysqlKindType(t.Kind)
+		case wktTimestamp:
+			return "datetime(6)"
+		case wktDuration:
+			return "bigint"
+		case wktStruct, wktValue, wktListValue:
+			return "json"
+		default:
+			return "text"
+		}
+	case "BOOL":
+		return "boolean"
+	case "INT32":
+		return "int"
+	case "UINT32":
+		return "int unsigned"
+	case "INT64":
+		return "bigint"
+	case "FLOAT":
+		return "float"
+	case "DOUBLE":
+		return "double"
+	case "TEXT":
+		return "text"
+	case "JSONB":
+		return "json"
+	case "UUID":
+		return "binary(16)"
+	case "TIMESTAMP":
+		return "timestamp"
+	case "DATE":
+		return "date"
+	case "BYTEA":
+		return "blob"
+	case "INET":
+		return "text"
+	case "NUMERIC":
+		// mysql default decimal(10,0) drops fraction digits
+		if t.Precision <= 0 {
+			return "decimal(65,30)"
+		}
+		return "decimal" + numericTypeArgs(t.Precision, t.Scale)
+	default:
+		log.Printf("todo: ColumnType unknown db type: %s", t.DbType)
+		return ""
+	}
+}
+
+func mysqlKindType(kind protoreflect.Kind) string {
+	switch kind {
+	case protoreflect.BoolKind:
+		return "boolean"
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return "int"
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return "bigint"
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return "int unsigned"
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return "bigint unsigned"
+	case protoreflect.FloatKind:
+		return "float"
+	case protoreflect.DoubleKind:
+		return "double"
+	case protoreflect.StringKind:
+		return "text"
+	case protoreflect.BytesKind:
+		return "blob"
+	case protoreflect.EnumKind:
+		return "int"
+	case protoreflect.MessageKind:
+		return "json"
+	default:
+		return "text"
+	}
+}
+
+// SupportsReturning mysql has no RETURNING, rows are selected after write.
+// mariadb 10.5+ is detected by ProbeCapabilities
+func (mysqlDialect) SupportsReturning() bool {
+	return false
+}
+
+func (mysqlDialect) OldNewReturning() TOldNewReturning {
+	return OldNewSelect
+}
+
+func (d mysqlDialect) UpsertClause(conflictColumns []string, updateColumns []string) string {
+	sb := strings.Builder{}
+	sb.WriteString(" ON DUPLICATE KEY UPDATE ")
+	if len(updateColumns) == 0 {
+		// no-op update keeps the existing row
+		updateColumns = conflictColumns[:1]
+	}
+	for i, column := range updateColumns {
+		if i > 0 {
+			sb.WriteString(protosql.SQL_COMMA)
+		}
+		quoted := d.QuoteIdentifier(column)
+		sb.WriteString(quoted)
+		sb.WriteString(" = VALUES(")
+		sb.WriteString(quoted)
+		sb.WriteString(")")
+	}
+	return sb.String()
+}
+
+func (mysqlDialect) EncodeTime(t time.Time) any {
+	return t
+}
+
+func (mysqlDialect) NumericCast(expr string, precision int32, scale int32) string {
+	return "CAST(" + expr + " AS " + mysqlDialect{}.ColumnType(ColumnType{DbType: "NUMERIC", Precision: precision, Scale: scale}) + ")"
+}
+
+func (mysqlDialect) ListCondition(column string, elemKind protoreflect.Kind, op string, placeholder string) (string, TListArg, bool) {
+	if cmp, ok := lenOpCmp(op); ok {
+		return "JSON_LENGTH(" + column + ")" + cmp + placeholder, ListArgLength, true
+	}
+	switch op {
+	case ListOpContains:
+		if elemKind == protoreflect.MessageKind {
+			return "JSON_CONTAINS(" + column + ", JSON_ARRAY(CAST(" + placeholder + " AS JSON)))", ListArgText, true
+		}
+		return "JSON_CONTAINS(" + column + ", JSON_ARRAY(" + placeholder + "))", ListArgScalar, true
+	case ListOpOverlap:
+		return "JSON_OVERLAPS(" + column + ", CAST(" + placeholder + " AS JSON))", ListArgText, true
+	case ListOpContainsAll:
+		return "JSON_CONTAINS(" + column + ", CAST(" + placeholder + " AS JSON))", ListArgText, true
+	}
+	return "", ListArgText, false
+}
+
+func (mysqlDialect) MapCondition(column string, op string, placeholder string) (string, bool) {
+	switch op {
+	case MapOpHasKey:
+		// NOTE: assumes map keys can be addressed as $.<key>.
+		return "JSON_CONTAINS_PATH(" + column + ", 'one', CONCAT('$.' , " + placeholder + "))", true
+	case MapOpContains:
+		return "JSON_CONTAINS(" + column + ", CAST(" + placeholder + " AS JSON))", true
+	}
+	return "", false
+}
+
+// TableExistsQuery schema is the table name prefix in current database
+func (d mysqlDialect) TableExistsQuery(dbschema string, tableName string) (string, []any) {
+	return mysqlTableExistsSQL, []any{d.TableName(tableName, dbschema)}
+}
+
+// ColumnsQuery schema is the table name prefix in current database
+func (d mysqlDialect) ColumnsQuery(dbschema string, tableName string) (string, []any) {
+	return mysqlColumnsSQL, []any{d.TableName(tableName, dbschema)}
+}
+
+// quoteEnumLabels 'A','B' with single quotes escaped
+func quoteEnumLabels(labels []string) string {
+	quoted := make([]string, 0, len(labels))
+	for _, label := range labels {
+		quoted = append(quoted, "'"+strings.ReplaceAll(label, "'", "''")+"'")
+	}
+	return strings.Join(quoted, ",")
+}
+
+// ProbeCapabilities mariadb 10.5+ has INSERT/DELETE RETURNING but no UPDATE RETURNING,
+// json functions and generated columns need mysql 5.7+ or mariadb 10.2+
+func (mysqlDialect) ProbeCapabilities(db DB, caps *TDBCapabilities) error {
+	version, err := queryVersion(db, "SELECT VERSION()")
+	if err != nil {
+		return err
+	}
+	caps.setVersion(version)
+	if strings.Contains(strings.ToLower(version), "mariadb") {
+		caps.Flavor = "MariaDB"
+		caps.InsertReturning = caps.AtLeast(10, 5)
+		caps.DeleteReturning = caps.AtLeast(10, 5)
+		caps.JSON = caps.AtLeast(10, 2)
+		caps.GeneratedColumns = caps.AtLeast(10, 2)
+		return nil
+	}
+	caps.JSON = caps.AtLeast(5, 7)
+	caps.GeneratedColumns = caps.AtLeast(5, 7)
+	return nil
+}
@@ -0,0 +1,260 @@
+package sqldb
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ygrpc/protodb/protosql"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	postgresTableExistsSQL = "SELECT EXISTS (SELECT table_name FROM information_schema.tables WHERE table_schema = $1 AND table_name = $2)"
+	postgresColumnsSQL     = "SELECT column_name FROM information_schema.columns WHERE table_schema = $1 AND table_name = $2"
+)
+
+type postgresDialect struct {
+	BaseDialect
+}
+
+func (postgresDialect) ID() TDBDialect {
+	return Postgres
+}
+
+func (postgresDialect) Name() string {
+	return "Postgres"
+}
+
+// DriverNames pgx stdlib and lib/pq
+func (postgresDialect) DriverNames() []string {
+	return []string{"*stdlib.Driver", "*pq.Driver"}
+}
+
+func (postgresDialect) Placeholder() protosql.SQLPlaceholder {
+	return protosql.SQL_DOLLAR
+}
+
+// QuoteIdentifier postgres folds unquoted names to lowercase, so the quoted name is lowercased too and still
+// matches tables created without quotes and the lowercase names in information_schema
+func (postgresDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifierWith(`"`, strings.ToLower(name))
+}
+
+func (postgresDialect) FoldIdentifier(name string) string {
+	return strings.ToLower(name)
+}
+
+func (postgresDialect) TableName(tableName string, dbschema string) string {
+	if len(dbschema) == 0 {
+		return tableName
+	}
+	return dbschema + "." + tableName
+}
+
+func (d postgresDialect) QuotedTableName(tableName string, dbschema string) string {
+	if len(dbschema) == 0 {
+		return d.QuoteIdentifier(tableName)
+	}
+	return d.QuoteIdentifier(dbschema) + "." + d.QuoteIdentifier(tableName)
+}
+
+func (d postgresDialect) ColumnType(t ColumnType) string {
+	if t.Map {
+		return "jsonb"
+	}
+	if t.Repeated {
+		if t.Kind == protoreflect.MessageKind {
+			return "jsonb"
+		}
+		return postgresKindType(t.Kind) + "[]"
+	}
+	if len(t.EnumValues) > 0 {
+		return t.EnumName
+	}
+	if t.MaxLength > 0 {
+		return "text"
+	}
+
+	switch t.DbType {
+	case "":
+		switch t.SerialSize {
+		case 0:
+		case 2:
+			return "smallserial"
+		case 4:
+			return "serial"
+		case 8:
+			return "bigserial"
+		default:
+			log.Printf("todo: ColumnType unknown serial type: %d", t.SerialSize)
+			return "text"
+		}
+		switch t.WellKnown {
+		case "":
+			return postgresKindType(t.Kind)
+		case wktTimestamp:
+			return "timestamptz"
+		case wktDuration:
+			return "interval"
+		case wktStruct, wktValue, wktListValue:
+			return "jsonb"
+		default:
+			return "text"
+		}
+	case "BOOL":
+		return "boolean"
+	case "INT32":
+		return "integer"
+	case "UINT32", "INT64":
+		return "bigint"
+	case "FLOAT":
+		return "real"
+	case "DOUBLE":
+		return "double precision"
+	case "TEXT":
+		return "text"
+	case "JSONB":
+		return "jsonb"
+	case "UUID":
+		return "uuid"
+	case "TIMESTAMP":
+		return "timestamp"
+	case "DATE":
+		return "date"
+	case "BYTEA":
+		return "bytea"
+	case "INET":
+		return "inet"
+	case "NUMERIC":
+		return "numeric" + numericTypeArgs(t.Precision, t.Scale)
+	default:
+		log.Printf("todo: ColumnType unknown db type: %s", t.DbType)
+		return ""
+	}
+}
+
+func postgresKindType(kind protoreflect.Kind) string {
+	switch kind {
+	case protoreflect.BoolKind:
+		return "boolean"
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return "integer"
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return "bigint"
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return "bigint"
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return "bigint"
+	case protoreflect.FloatKind:
+		return "real"
+	case protoreflect.DoubleKind:
+		return "double precision"
+	case protoreflect.StringKind:
+		return "text"
+	case protoreflect.BytesKind:
+		return "bytea"
+	case protoreflect.EnumKind:
+		return "integer"
+	case protoreflect.MessageKind:
+		return "jsonb"
+	default:
+		return "text"
+	}
+}
+
+func (postgresDialect) SupportsReturning() bool {
+	return true
+}
+
+// OldNewReturning postgres 18+ supports RETURNING OLD.*,NEW.*, ProbeCapabilities checks server version
+func (postgresDialect) OldNewReturning() TOldNewReturning {
+	return OldNewSelfJoin
+}
+
+func (d postgresDialect) UpsertClause(conflictColumns []string, updateColumns []string) string {
+	return onConflictClause(d, conflictColumns, updateColumns)
+}
+
+func (postgresDialect) NativeArrays() bool {
+	return true
+}
+
+func (postgresDialect) EncodeTime(t time.Time) any {
+	return t
+}
+
+func (postgresDialect) DurationAsInterval() bool {
+	return true
+}
+
+func (postgresDialect) NumericCast(expr string, precision int32, scale int32) string {
+	return expr + "::numeric"
+}
+
+func (postgresDialect) ListCondition(column string, elemKind protoreflect.Kind, op string, placeholder string) (string, TListArg, bool) {
+	if cmp, ok := lenOpCmp(op); ok {
+		if elemKind == protoreflect.MessageKind {
+			return "jsonb_array_length(" + column + ")" + cmp + placeholder, ListArgLength, true
+		}
+		return "cardinality(" + column + ")" + cmp + placeholder, ListArgLength, true
+	}
+	if elemKind == protoreflect.MessageKind {
+		switch op {
+		case ListOpContains, ListOpContainsAll:
+			return column + " @> " + placeholder + "::jsonb", ListArgText, true
+		}
+		return "", ListArgText, false
+	}
+	switch op {
+	case ListOpContains:
+		return column + " @> ARRAY[" + placeholder + "]::" + postgresKindType(elemKind) + "[]", ListArgScalar, true
+	case ListOpOverlap:
+		return column + " && " + placeholder, ListArgArray, true
+	case ListOpContainsAll:
+		return column + " @> " + placeholder, ListArgArray, true
+	}
+	return "", ListArgText, false
+}
+
+func (postgresDialect) MapCondition(column string, op string, placeholder string) (string, bool) {
+	switch op {
+	case MapOpHasKey:
+		return column + " ? " + placeholder, true
+	case MapOpContains:
+		return column + " @> " + placeholder + "::jsonb", true
+	}
+	return "", false
+}
+
+// TableExistsQuery empty schema is public
+func (d postgresDialect) TableExistsQuery(dbschema string, tableName string) (string, []any) {
+	if len(dbschema) == 0 {
+		dbschema = "public"
+	}
+	return postgresTableExistsSQL, []any{d.FoldIdentifier(dbschema), d.FoldIdentifier(tableName)}
+}
+
+// ColumnsQuery empty schema is public
+func (d postgresDialect) ColumnsQuery(dbschema string, tableName string) (string, []any) {
+	if len(dbschema) == 0 {
+		dbschema = "public"
+	}
+	return postgresColumnsSQL, []any{d.FoldIdentifier(dbschema), d.FoldIdentifier(tableName)}
+}
+
+// ProbeCapabilities on conflict 9.5+, generated columns 12+, RETURNING OLD.*,NEW.* 18+
+func (postgresDialect) ProbeCapabilities(db DB, caps *TDBCapabilities) error {
+	version, err := queryVersion(db, "SHOW server_version")
+	if err != nil {
+		return err
+	}
+	caps.setVersion(version)
+	caps.JSON = caps.AtLeast(9, 4)
+	caps.OnConflict = caps.AtLeast(9, 5)
+	caps.GeneratedColumns = caps.AtLeast(12, 0)
+	if caps.AtLeast(18, 0) {
+		caps.OldNewReturning = OldNewNative
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package sqldb
+
+import (
+	"log"
+	"time"
+
+	"github.com/ygrpc/protodb/protosql"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	sqliteTableExistsSQL = "SELECT EXISTS (SELECT name FROM sqlite_master WHERE type='table' AND name=?)"
+	sqliteTableInfoSQL   = "SELECT name FROM pragma_table_info(?)"
+)
+
+// SQLiteTimestampLayout sqlite has no time type, timestamps are stored as sortable text
+const SQLiteTimestampLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+type sqliteDialect struct {
+	BaseDialect
+}
+
+func (sqliteDialect) ID() TDBDialect {
+	return SQLite
+}
+
+func (sqliteDialect) Name() string {
+	return "SQLite"
+}
+
+// DriverNames mattn/go-sqlite3 and modernc.org/sqlite
+func (sqliteDialect) DriverNames() []string {
+	return []string{"*sqlite3.SQLiteDriver", "*sqlite.Driver"}
+}
+
+func (sqliteDialect) Placeholder() protosql.SQLPlaceholder {
+	return protosql.SQL_QUESTION
+}
+
+func (sqliteDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifierWith(`"`, name)
+}
+
+// QuotedTableName schema is the table name prefix
+func (d sqliteDialect) QuotedTableName(tableName string, dbschema string) string {
+	return d.QuoteIdentifier(d.TableName(tableName, dbschema))
+}
+
+func (sqliteDialect) ColumnType(t ColumnType) string {
+	if t.Map || t.Repeated || len(t.EnumValues) > 0 || t.MaxLength > 0 {
+		return "text"
+	}
+
+	switch t.DbType {
+	case "":
+		switch t.SerialSize {
+		case 0:
+		case 2, 4, 8:
+			return "integer"
+		default:
+			log.Printf("todo: ColumnType unknown serial type: %d", t.SerialSize)
+			return "text"
+		}
+		switch t.WellKnown {
+		case "":
+			return sqliteKindType(t.Kind)
+		case wktTimestamp:
+			return "timestamp"
+		case wktDuration:
+			return "integer"
+		default:
+			return "text"
+		}
+	case "BOOL", "INT32", "UINT32", "INT64":
+		return "integer"
+	case "FLOAT":
+		return "real"
+	case "DOUBLE":
+		return "double precision"
+	case "TEXT":
+		return "text"
+	case "JSONB":
+		return "jsonb"
+	case "UUID":
+		return "uuid"
+	case "TIMESTAMP":
+		return "timestamp"
+	case "DATE":
+		return "date"
+	case "BYTEA":
+		return "blob"
+	case "INET":
+		return "inet"
+	case "NUMERIC":
+		// stored as canonical decimal text
+		return "text"
+	default:
+		log.Printf("todo: ColumnType unknown db type: %s", t.DbType)
+		return ""
+	}
+}
+
+func sqliteKindType(kind protoreflect.Kind) string {
+	switch kind {
+	case protoreflect.BoolKind,
+		protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind,
+		protoreflect.EnumKind:
+		return "integer"
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return "real"
+	case protoreflect.StringKind:
+		return "text"
+	default:
+		return "blob"
+	}
+}
+
+func (sqliteDialect) SupportsReturning() bool {
+	return true
+}
+
+func (sqliteDialect) OldNewReturning() TOldNewReturning {
+	return OldNewSelect
+}
+
+func (d sqliteDialect) UpsertClause(conflictColumns []string, updateColumns []string) string {
+	return onConflictClause(d, conflictColumns, updateColumns)
+}
+
+func (sqliteDialect) EncodeTime(t time.Time) any {
+	return t.UTC().Format(SQLiteTimestampLayout)
+}
+
+// NumericColumn numeric is stored as text, cast it before comparing
+func (sqliteDialect) NumericColumn(column string) string {
+	return "CAST(" + column + " AS NUMERIC)"
+}
+
+func (sqliteDialect) ListCondition(column string, elemKind protoreflect.Kind, op string, placeholder string) (string, TListArg, bool) {
+	if cmp, ok := lenOpCmp(op); ok {
+		return "json_array_length(" + column + ")" + cmp + placeholder, ListArgLength, true
+	}
+	switch op {
+	case ListOpContains:
+		return "EXISTS (SELECT 1 FROM json_each(" + column + ") WHERE value = " + placeholder + ")", ListArgScalar, true
+	case ListOpOverlap:
+		return "EXISTS (SELECT 1 FROM json_each(" + column + ") a JOIN json_each(" + placeholder + ") b ON a.value = b.value)", ListArgText, true
+	case ListOpContainsAll:
+		return "NOT EXISTS (SELECT 1 FROM json_each(" + placeholder + ") b WHERE NOT EXISTS (SELECT 1 FROM json_each(" + column + ") a WHERE a.value = b.value))", ListArgText, true
+	}
+	return "", ListArgText, false
+}
+
+func (sqliteDialect) MapCondition(column string, op string, placeholder string) (string, bool) {
+	switch op {
+	case MapOpHasKey:
+		return "EXISTS (SELECT 1 FROM json_each(" + column + ") WHERE key = " + placeholder + ")", true
+	case MapOpContains:
+		// the value must be a JSON object string
+		return "NOT EXISTS (SELECT 1 FROM json_each(" + placeholder + ") b WHERE NOT EXISTS (SELECT 1 FROM json_each(" + column + ") a WHERE a.key = b.key AND a.value = b.value))", true
+	}
+	return "", false
+}
+
+func (d sqliteDialect) TableExistsQuery(dbschema string, tableName string) (string, []any) {
+	return sqliteTableExistsSQL, []any{d.TableName(tableName, dbschema)}
+}
+
+func (d sqliteDialect) ColumnsQuery(dbschema string, tableName string) (string, []any) {
+	return sqliteTableInfoSQL, []any{d.TableName(tableName, dbschema)}
+}
+
+// ProbeCapabilities RETURNING 3.35+, upsert 3.24+, generated columns 3.31+,
+// json1 is built in since 3.38 and a compile option before, so it is tried
+func (sqliteDialect) ProbeCapabilities(db DB, caps *TDBCapabilities) error {
+	version, err := queryVersion(db, "SELECT sqlite_version()")
+	if err != nil {
+		return err
+	}
+	caps.setVersion(version)
+	returning := caps.AtLeast(3, 35)
+	caps.InsertReturning = returning
+	caps.UpdateReturning = returning
+	caps.DeleteReturning = returning
+	caps.OnConflict = caps.AtLeast(3, 24)
+	caps.GeneratedColumns = caps.AtLeast(3, 31)
+	var valid bool
+	caps.JSON = db.QueryRow("SELECT json_valid('[]')").Scan(&valid) == nil && valid
+	return nil
+}
@@ -0,0 +1,149 @@
+package sqldb
+
+import (
+	"testing"
+
+	"github.com/ygrpc/protodb/protosql"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type testDuckDialect struct {
+	BaseDialect
+}
+
+func (testDuckDialect) ID() TDBDialect {
+	return DialectCustomBase
+}
+
+func (testDuckDialect) Name() string {
+	return "TestDuck"
+}
+
+func (testDuckDialect) DriverNames() []string {
+	return []string{"*testduck.Driver"}
+}
+
+func (testDuckDialect) Placeholder() protosql.SQLPlaceholder {
+	return protosql.SQL_DOLLAR
+}
+
+func (testDuckDialect) QuoteIdentifier(name string) string {
+	return quoteIdentifierWith(`"`, name)
+}
+
+func (d testDuckDialect) QuotedTableName(tableName string, dbschema string) string {
+	return d.QuoteIdentifier(d.TableName(tableName, dbschema))
+}
+
+func (testDuckDialect) ColumnType(t ColumnType) string {
+	if t.Kind == protoreflect.Int64Kind {
+		return "BIGINT"
+	}
+	return "VARCHAR"
+}
+
+func TestRegisterDialect(t *testing.T) {
+	RegisterDialect(testDuckDialect{})
+
+	d, ok := GetDialect(DialectCustomBase)
+	if !ok || d.Name() != "TestDuck" {
+		t.Fatalf("GetDialect = %v, %v", d, ok)
+	}
+	if d, ok := GetDialectByName("testduck"); !ok || d.ID() != DialectCustomBase {
+		t.Fatalf("GetDialectByName = %v, %v", d, ok)
+	}
+	if id, ok := getDriverDialect("*testduck.Driver"); !ok || id != DialectCustomBase {
+		t.Fatalf("getDriverDialect = %v, %v", id, ok)
+	}
+
+	dialect := DialectCustomBase
+	if dialect.String() != "TestDuck" {
+		t.Fatalf("String() = %q", dialect.String())
+	}
+	if dialect.Placeholder() != protosql.SQL_DOLLAR {
+		t.Fatalf("Placeholder() = %q", dialect.Placeholder())
+	}
+	if got := BuildQuotedDbTableName("user", "app_", dialect); got != `"app_user"` {
+		t.Fatalf("BuildQuotedDbTableName = %s", got)
+	}
+	if got := dialect.Dialect().ColumnType(ColumnType{Kind: protoreflect.Int64Kind}); got != "BIGINT" {
+		t.Fatalf("ColumnType = %s", got)
+	}
+	if dialect.Dialect().SupportsReturning() {
+		t.Fatal("BaseDialect should not support RETURNING")
+	}
+}
+
+func TestUnregisteredDialect(t *testing.T) {
+	dialect := DialectCustomBase + 99
+	if _, ok := GetDialect(dialect); ok {
+		t.Fatal("unexpected registered dialect")
+	}
+	d := dialect.Dialect()
+	if d.ID() != dialect {
+		t.Fatalf("ID() = %v", d.ID())
+	}
+	if d.QuoteIdentifier("order") != "order" {
+		t.Fatalf("QuoteIdentifier = %s", d.QuoteIdentifier("order"))
+	}
+}
+
+func TestOracleTableName(t *testing.T) {
+	if got := BuildDbTableName("user", "app", Oracle); got != "app.user" {
+		t.Fatalf("BuildDbTableName = %s", got)
+	}
+	if got := BuildQuotedDbTableName("user", "app", Oracle); got != "app.user" {
+		t.Fatalf("BuildQuotedDbTableName = %s", got)
+	}
+	if got := BuildDbTableName("user", "", Oracle); got != "user" {
+		t.Fatalf("BuildDbTableName = %s", got)
+	}
+	if got := BuildDbTableName("user", "app_", Unknown); got != "app_user" {
+		t.Fatalf("BuildDbTableName = %s", got)
+	}
+}
+
+func TestBuiltinColumnType(t *testing.T) {
+	cases := []struct {
+		dialect TDBDialect
+		t       ColumnType
+		want    string
+	}{
+		{Postgres, ColumnType{Kind: protoreflect.Int64Kind}, "bigint"},
+		{Postgres, ColumnType{Kind: protoreflect.StringKind, Repeated: true}, "text[]"},
+		{Postgres, ColumnType{Kind: protoreflect.MessageKind, Repeated: true}, "jsonb"},
+		{Postgres, ColumnType{SerialSize: 8}, "bigserial"},
+		{Postgres, ColumnType{DbType: "NUMERIC", Precision: 12, Scale: 2}, "numeric(12,2)"},
+		{Postgres, ColumnType{Kind: protoreflect.MessageKind, WellKnown: wktTimestamp}, "timestamptz"},
+		{Postgres, ColumnType{Kind: protoreflect.EnumKind, EnumName: "color", EnumValues: []string{"RED"}}, "color"},
+		{Mysql, ColumnType{Kind: protoreflect.Uint64Kind}, "bigint unsigned"},
+		{Mysql, ColumnType{Kind: protoreflect.StringKind, Map: true}, "json"},
+		{Mysql, ColumnType{DbType: "NUMERIC"}, "decimal(65,30)"},
+		{Mysql, ColumnType{Kind: protoreflect.StringKind, MaxLength: 255}, "varchar(255)"},
+		{Mysql, ColumnType{Kind: protoreflect.EnumKind, EnumName: "color", EnumValues: []string{"RED", "O'K"}}, "enum('RED','O''K')"},
+		{SQLite, ColumnType{Kind: protoreflect.BoolKind}, "integer"},
+		{SQLite, ColumnType{Kind: protoreflect.Int32Kind, Repeated: true}, "text"},
+		{SQLite, ColumnType{DbType: "NUMERIC"}, "text"},
+		{Unknown, ColumnType{Kind: protoreflect.Int64Kind}, ""},
+	}
+	for _, c := range cases {
+		if got := c.dialect.Dialect().ColumnType(c.t); got != c.want {
+			t.Errorf("%v ColumnType(%+v) = %q, want %q", c.dialect, c.t, got, c.want)
+		}
+	}
+}
+
+func TestUpsertClause(t *testing.T) {
+	got := Postgres.Dialect().UpsertClause([]string{"id"}, []string{"name", "Order"})
+	if want := ` ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name" , "order" = EXCLUDED."order"`; got != want {
+		t.Fatalf("postgres upsert = %s, want %s", got, want)
+	}
+	got = SQLite.Dialect().UpsertClause([]string{"id"}, nil)
+	if want := ` ON CONFLICT ("id") DO NOTHING`; got != want {
+		t.Fatalf("sqlite upsert = %s, want %s", got, want)
+	}
+	got = Mysql.Dialect().UpsertClause([]string{"id"}, []string{"name"})
+	if want := " ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)"; got != want {
+		t.Fatalf("mysql upsert = %s, want %s", got, want)
+	}
+}
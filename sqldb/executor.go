@@ -3,6 +3,8 @@ package sqldb
 import (
 	"context"
 	"database/sql"
+
+	"google.golang.org/protobuf/proto"
 )
 
 // DB is an interface that abstracts the common methods of *sql.DB and *sql.Tx.
@@ -68,6 +70,16 @@ type DB interface {
 type DBWithDialect struct {
 	Executor DB
 	Dialect  TDBDialect
+	// SQLDB the *sql.DB of Executor, server capabilities are probed and cached on it, may be nil
+	SQLDB *sql.DB
+	// MsgStore field messages of scanned rows are got from it, msgstore.GlobalMsgStore if nil
+	MsgStore MsgStore
+}
+
+// MsgStore messages by table or message name, implemented by *msgstore.TMsgStore
+type MsgStore interface {
+	GetMsg(msgName string, new bool) (proto.Message, bool)
+	GetFieldMsg(msgName string, new bool) (proto.Message, bool)
 }
 
 // NewDBWithDialect creates a new DBWithDialect from a *sql.DB.
@@ -77,6 +89,7 @@ func NewDBWithDialect(db *sql.DB) *DBWithDialect {
 	return &DBWithDialect{
 		Executor: db,
 		Dialect:  dialect,
+		SQLDB:    db,
 	}
 }
 
@@ -87,11 +100,13 @@ func NewTxWithDialect(tx *sql.Tx, db *sql.DB) *DBWithDialect {
 	return &DBWithDialect{
 		Executor: tx,
 		Dialect:  dialect,
+		SQLDB:    db,
 	}
 }
 
 // NewTxWithDialectType creates a new DBWithDialect from a *sql.Tx and a known dialect type.
 // Use this when you already know the dialect and don't want to make an additional call.
+// Without the *sql.DB the dialect default capabilities are used.
 func NewTxWithDialectType(tx *sql.Tx, dialect TDBDialect) *DBWithDialect {
 	return &DBWithDialect{
 		Executor: tx,
@@ -20,6 +20,7 @@ const (
 	Mysql    TDBDialect = 2
 	SQLite   TDBDialect = 3
 	Oracle   TDBDialect = 4
+	DuckDB   TDBDialect = 5
 )
 
 type TDBDialectCacheItem struct {
@@ -27,32 +28,40 @@ type TDBDialectCacheItem struct {
 	Dialect     TDBDialect
 	Placeholder protosql.SQLPlaceholder
 	CacheTime   time.Time
+	// Capabilities probed server capabilities, zero CacheTime when not probed yet
+	Capabilities TDBCapabilities
+	// CapabilitiesErr error of the last probe, Capabilities are the dialect defaults then
+	CapabilitiesErr error
 }
 
 var dbDialectCache *xsync.MapOf[*sql.DB, TDBDialectCacheItem] = xsync.NewMapOf[*sql.DB, TDBDialectCacheItem]()
 
+func init() {
+	RegisterDialect(postgresDialect{})
+	RegisterDialect(mysqlDialect{})
+	RegisterDialect(sqliteDialect{})
+	RegisterDialect(duckdbDialect{})
+}
+
 // Placeholder get placeholder of db dialect
 func (this TDBDialect) Placeholder() protosql.SQLPlaceholder {
-	switch this {
-	case Postgres:
-		return protosql.SQL_DOLLAR
-	case Mysql:
-		return protosql.SQL_QUESTION
-	case SQLite:
-		return protosql.SQL_QUESTION
-	}
-	return protosql.SQL_QUESTION
+	return this.Dialect().Placeholder()
+}
 
+// QuoteIdentifier quote table or column name, postgres/sqlite: "name", mysql: `name`, unknown unquoted.
+// postgres folds unquoted names to lowercase, so the quoted name is lowercased too
+func (this TDBDialect) QuoteIdentifier(name string) string {
+	return this.Dialect().QuoteIdentifier(name)
+}
+
+// FoldIdentifier the name of identifier in db catalog(information_schema), postgres lowercase, others unchanged
+func (this TDBDialect) FoldIdentifier(name string) string {
+	return this.Dialect().FoldIdentifier(name)
 }
 
 func (this TDBDialect) String() string {
-	switch this {
-	case Postgres:
-		return "Postgres"
-	case Mysql:
-		return "Mysql"
-	case SQLite:
-		return "SQLite"
+	if d, ok := GetDialect(this); ok {
+		return d.Name()
 	}
 	return "Unknown TDBDialect:%d" + strconv.Itoa(int(this))
 
@@ -63,22 +72,8 @@ func GetDBDialect(db *sql.DB) (dialect TDBDialect) {
 
 	driver := GetDBDriverName(db)
 
-	switch driver {
-	case "*stdlib.Driver":
-		//pgx stdlib driver,
-		return Postgres
-	case "*pq.Driver":
-		//lib/pq driver
-		return Postgres
-	case "*mysql.MySQLDriver":
-		// github.com/go-sql-driver/mysql
-		return Mysql
-	case "*sqlite3.SQLiteDriver":
-		//mattn/go-sqlite3 driver
-		return SQLite
-	case "*sqlite.Driver":
-		//modernc.org/sqlite driver
-		return SQLite
+	if dialect, ok := getDriverDialect(driver); ok {
+		return dialect
 	}
 	//todo: compare driver string to known driver names
 
@@ -133,7 +128,7 @@ func GetDBDialectCache(db *sql.DB) (TDBDialect, TDBDialectCacheItem) {
 	return dialect, item
 }
 
-// clear db dialect cache
+// clear db dialect cache and the probed capabilities of db
 func ClearDBDialectCache(db *sql.DB) bool {
 	_, ok := dbDialectCache.LoadAndDelete(db)
 	return ok
@@ -147,18 +142,16 @@ func GetDBDriverName(db *sql.DB) string {
 
 // BuildDbTableName build db table name
 func BuildDbTableName(tableName string, dbschema string, dbdialect TDBDialect) string {
-	dbtableName := tableName
 	if len(dbschema) == 0 {
 		//use default table name
-	} else {
-		switch dbdialect {
-		case Postgres, Oracle:
-			dbtableName = dbschema + "." + tableName
-		default:
-			dbtableName = dbschema + tableName
-		}
+		return tableName
 	}
-	return dbtableName
+	return dbdialect.Dialect().TableName(tableName, dbschema)
+}
+
+// BuildQuotedDbTableName build quoted db table name for sql text, BuildDbTableName is the name in db catalog
+func BuildQuotedDbTableName(tableName string, dbschema string, dbdialect TDBDialect) string {
+	return dbdialect.Dialect().QuotedTableName(tableName, dbschema)
 }
 
 // GetExecutorDialect gets the dialect from a DB.
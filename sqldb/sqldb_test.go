@@ -98,3 +98,33 @@ func discardLogOutput() func() {
 		log.SetOutput(out)
 	}
 }
+
+func TestQuoteIdentifier(t *testing.T) {
+	cases := []struct {
+		dialect TDBDialect
+		name    string
+		want    string
+	}{
+		{Postgres, "Order", `"order"`},
+		{Postgres, `a"b`, `"a""b"`},
+		{Mysql, "Order", "`Order`"},
+		{Mysql, "a`b", "`a``b`"},
+		{SQLite, "Order", `"Order"`},
+		{Unknown, "Order", "Order"},
+	}
+	for _, c := range cases {
+		if got := c.dialect.QuoteIdentifier(c.name); got != c.want {
+			t.Fatalf("%s QuoteIdentifier(%q) = %s, want %s", c.dialect, c.name, got, c.want)
+		}
+	}
+
+	if got := BuildQuotedDbTableName("Group", "app", Postgres); got != `"app"."group"` {
+		t.Fatalf("unexpected postgres table name: %s", got)
+	}
+	if got := BuildQuotedDbTableName("Group", "app_", Mysql); got != "`app_Group`" {
+		t.Fatalf("unexpected mysql table name: %s", got)
+	}
+	if got := Postgres.FoldIdentifier("Group"); got != "group" {
+		t.Fatalf("unexpected postgres folded name: %s", got)
+	}
+}
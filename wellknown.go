@@ -0,0 +1,76 @@
+package protodb
+
+import (
+	"github.com/ygrpc/protodb/sqldb"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// well-known message types with native db mapping
+const (
+	WktTimestamp   protoreflect.FullName = "google.protobuf.Timestamp"
+	WktDuration    protoreflect.FullName = "google.protobuf.Duration"
+	WktStruct      protoreflect.FullName = "google.protobuf.Struct"
+	WktValue       protoreflect.FullName = "google.protobuf.Value"
+	WktListValue   protoreflect.FullName = "google.protobuf.ListValue"
+	WktFieldMask   protoreflect.FullName = "google.protobuf.FieldMask"
+	WktDoubleValue protoreflect.FullName = "google.protobuf.DoubleValue"
+	WktFloatValue  protoreflect.FullName = "google.protobuf.FloatValue"
+	WktInt64Value  protoreflect.FullName = "google.protobuf.Int64Value"
+	WktUInt64Value protoreflect.FullName = "google.protobuf.UInt64Value"
+	WktInt32Value  protoreflect.FullName = "google.protobuf.Int32Value"
+	WktUInt32Value protoreflect.FullName = "google.protobuf.UInt32Value"
+	WktBoolValue   protoreflect.FullName = "google.protobuf.BoolValue"
+	WktStringValue protoreflect.FullName = "google.protobuf.StringValue"
+	WktBytesValue  protoreflect.FullName = "google.protobuf.BytesValue"
+)
+
+// wrapper type -> kind of the value field
+var wktWrapperKinds = map[protoreflect.FullName]protoreflect.Kind{
+	WktDoubleValue: protoreflect.DoubleKind,
+	WktFloatValue:  protoreflect.FloatKind,
+	WktInt64Value:  protoreflect.Int64Kind,
+	WktUInt64Value: protoreflect.Uint64Kind,
+	WktInt32Value:  protoreflect.Int32Kind,
+	WktUInt32Value: protoreflect.Uint32Kind,
+	WktBoolValue:   protoreflect.BoolKind,
+	WktStringValue: protoreflect.StringKind,
+	WktBytesValue:  protoreflect.BytesKind,
+}
+
+// IsWellKnownMsg is the message a well-known type with native db mapping
+func IsWellKnownMsg(msgName protoreflect.FullName) bool {
+	switch msgName {
+	case WktTimestamp, WktDuration, WktStruct, WktValue, WktListValue, WktFieldMask:
+		return true
+	}
+	_, ok := wktWrapperKinds[msgName]
+	return ok
+}
+
+// WellKnownWrapperKind get the value kind of wrapper types like google.protobuf.Int64Value
+func WellKnownWrapperKind(msgName protoreflect.FullName) (protoreflect.Kind, bool) {
+	kind, ok := wktWrapperKinds[msgName]
+	return kind, ok
+}
+
+// GetWellKnownDBType get db type of well-known message type
+// Timestamp -> timestamptz/datetime(6), Duration -> interval/bigint(nanoseconds),
+// wrappers -> nullable scalar, Struct/Value/ListValue -> jsonb/json, FieldMask -> text
+func GetWellKnownDBType(msgName protoreflect.FullName, dialect sqldb.TDBDialect) (string, bool) {
+	if !IsWellKnownMsg(msgName) {
+		return "", false
+	}
+	dbType := dialect.Dialect().ColumnType(wellKnownColumnType(msgName))
+	return dbType, len(dbType) > 0
+}
+
+// wellKnownColumnType column type of message type, wrappers use the value kind
+func wellKnownColumnType(msgName protoreflect.FullName) sqldb.ColumnType {
+	if kind, ok := wktWrapperKinds[msgName]; ok {
+		return sqldb.ColumnType{Kind: kind}
+	}
+	if IsWellKnownMsg(msgName) {
+		return sqldb.ColumnType{Kind: protoreflect.MessageKind, WellKnown: msgName}
+	}
+	return sqldb.ColumnType{Kind: protoreflect.MessageKind}
+}